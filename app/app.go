@@ -2,7 +2,21 @@ package app
 
 import (
 	"context"
+	"github.com/HBeserra/GoShop/internal/address"
+	"github.com/HBeserra/GoShop/internal/cart"
 	"github.com/HBeserra/GoShop/internal/catalog"
+	"github.com/HBeserra/GoShop/internal/checkout"
+	"github.com/HBeserra/GoShop/internal/customer"
+	"github.com/HBeserra/GoShop/internal/exchange"
+	"github.com/HBeserra/GoShop/internal/invoice"
+	"github.com/HBeserra/GoShop/internal/media"
+	"github.com/HBeserra/GoShop/internal/orders"
+	"github.com/HBeserra/GoShop/internal/payment"
+	"github.com/HBeserra/GoShop/internal/pricing"
+	"github.com/HBeserra/GoShop/internal/privacy"
+	"github.com/HBeserra/GoShop/internal/promotion"
+	"github.com/HBeserra/GoShop/internal/shipping"
+	"github.com/HBeserra/GoShop/internal/tax"
 )
 
 type shutdownFn struct {
@@ -11,6 +25,21 @@ type shutdownFn struct {
 }
 
 type app struct {
-	shutdownFn []shutdownFn
-	productSvc *catalog.ProductService
+	shutdownFn   []shutdownFn
+	reloaders    []namedReloader
+	productSvc   *catalog.ProductService
+	pricingSvc   *pricing.Service
+	promotionSvc *promotion.Service
+	cartSvc      *cart.Service
+	orderSvc     *orders.Service
+	checkoutSvc  *checkout.Service
+	paymentSvc   *payment.Service
+	exchangeSvc  *exchange.Service
+	taxSvc       *tax.Service
+	invoiceSvc   *invoice.Service
+	shippingSvc  *shipping.Service
+	addressSvc   *address.Service
+	customerSvc  *customer.Service
+	privacySvc   *privacy.Service
+	mediaSvc     *media.Service
 }
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/address"
+	"github.com/HBeserra/GoShop/internal/checkout"
+	"github.com/HBeserra/GoShop/internal/exchange"
+	"github.com/HBeserra/GoShop/internal/invoice"
+	"github.com/HBeserra/GoShop/internal/media"
+	"github.com/HBeserra/GoShop/internal/privacy"
+	"github.com/HBeserra/GoShop/internal/shipping"
+	"github.com/HBeserra/GoShop/internal/tax"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/caarlos0/env/v11"
+	"reflect"
+)
+
+// config is read from the environment, the fields missing there take their envDefault.
+type config struct {
+	Installments currency.InstallmentPolicy
+	Checkout     checkout.Config
+	Address      address.Config
+	Exchange     exchange.Config
+	Invoice      invoice.Config
+	Media        media.Config
+	Privacy      privacy.Config
+	Shipping     shipping.Config
+	Tax          tax.Config
+}
+
+func loadConfig() (*config, error) {
+	var cfg config
+	err := env.ParseWithOptions(&cfg, env.Options{
+		FuncMap: map[reflect.Type]env.ParserFunc{
+			reflect.TypeFor[domain.ByteSize](): func(v string) (any, error) {
+				return domain.ParseByteSize(v)
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
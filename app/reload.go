@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// reloader is a data source read from a file, such as the tax rules, that can be read again while running.
+type reloader interface {
+	Reload() error
+}
+
+// reloadFunc adapts a function to a reloader, for the sources which need more than a Reload call.
+type reloadFunc func() error
+
+func (f reloadFunc) Reload() error {
+	return f()
+}
+
+type namedReloader struct {
+	Name     string
+	Reloader reloader
+}
+
+func (a *app) addReloader(name string, r reloader) {
+	a.reloaders = append(a.reloaders, namedReloader{
+		Name:     name,
+		Reloader: r,
+	})
+}
+
+// watchReload reloads every source on SIGHUP, a source failing to reload keeps its current data.
+func (a *app) watchReload(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	a.addShutdownFn("reload", func(ctx context.Context) error {
+		signal.Stop(hup)
+		close(done)
+		return nil
+	})
+
+	go func() {
+		for {
+			select {
+			case <-hup:
+				a.reload(ctx)
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+func (a *app) reload(ctx context.Context) {
+	for _, r := range a.reloaders {
+		if err := r.Reloader.Reload(); err != nil {
+			slog.ErrorContext(ctx, "reload error", "error", err, "source", r.Name)
+			continue
+		}
+		slog.InfoContext(ctx, "reloaded", "source", r.Name)
+	}
+}
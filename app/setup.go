@@ -2,8 +2,27 @@ package app
 
 import (
 	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/address"
+	"github.com/HBeserra/GoShop/internal/cart"
 	"github.com/HBeserra/GoShop/internal/catalog"
+	"github.com/HBeserra/GoShop/internal/checkout"
+	"github.com/HBeserra/GoShop/internal/customer"
+	"github.com/HBeserra/GoShop/internal/exchange"
+	"github.com/HBeserra/GoShop/internal/invoice"
+	"github.com/HBeserra/GoShop/internal/media"
+	"github.com/HBeserra/GoShop/internal/orders"
+	"github.com/HBeserra/GoShop/internal/payment"
+	"github.com/HBeserra/GoShop/internal/pricing"
+	"github.com/HBeserra/GoShop/internal/privacy"
+	"github.com/HBeserra/GoShop/internal/promotion"
+	"github.com/HBeserra/GoShop/internal/shipping"
+	"github.com/HBeserra/GoShop/internal/tax"
+	"github.com/HBeserra/GoShop/pkg/nfe"
 	"log/slog"
+	"os"
+	"path/filepath"
 )
 
 func New(ctx context.Context) {
@@ -14,6 +33,9 @@ func New(ctx context.Context) {
 	 *	Get the config
 	 */
 
+	cfg, err := loadConfig()
+	a.ifErrShutdown(ctx, err)
+
 	/*
 	 *	Set up the repositories
 	 */
@@ -22,14 +44,126 @@ func New(ctx context.Context) {
 	 *	Set up the Services
 	 */
 
+	// Set up the Exchange Service, the display prices are disabled without rates
+	if cfg.Exchange.RatesPath != "" {
+		rates, err := exchange.NewFileProvider(os.DirFS(filepath.Dir(cfg.Exchange.RatesPath)), filepath.Base(cfg.Exchange.RatesPath))
+		a.ifErrShutdown(ctx, err)
+		a.addReloader("exchange rates", rates)
+		a.exchangeSvc = exchange.NewService(&cfg.Exchange, rates, nil)
+	}
+
 	// Set up the Product Catalog Service
-	prodSvc, err := catalog.NewProductService(nil, nil, nil)
+	prodSvc, err := catalog.NewProductService(nil, nil, nil, nil, nil)
 	a.ifErrShutdown(ctx, err)
+	prodSvc.SetInstallmentPolicy(&cfg.Installments)
+	if a.exchangeSvc != nil {
+		prodSvc.SetExchange(a.exchangeSvc)
+	}
 	a.productSvc = prodSvc
 
+	// Set up the Pricing Service
+	a.pricingSvc = pricing.NewService(nil, prodSvc, nil, nil)
+
+	// Set up the Promotion Service
+	a.promotionSvc = promotion.NewService(nil, nil)
+
+	// Set up the Address Service
+	var addresses *address.Dataset
+	if cfg.Address.DatasetPath != "" {
+		addresses, err = address.NewDataset(os.DirFS(filepath.Dir(cfg.Address.DatasetPath)), filepath.Base(cfg.Address.DatasetPath))
+		a.ifErrShutdown(ctx, err)
+	} else {
+		addresses, err = address.NewEmbeddedDataset()
+		a.ifErrShutdown(ctx, err)
+	}
+	a.addressSvc = address.NewService(&cfg.Address, addresses)
+	if cfg.Address.DatasetPath != "" {
+		// the cached addresses would hide the reloaded ones until they expire
+		a.addReloader("address dataset", reloadFunc(func() error {
+			if err := addresses.Reload(); err != nil {
+				return err
+			}
+			a.addressSvc.ClearCache()
+			return nil
+		}))
+	}
+
+	// Set up the Customer Service
+	a.customerSvc = customer.NewService(nil, nil, a.addressSvc, nil, nil)
+
+	// Set up the Media Service
+	var storage media.FileStorage
+	if cfg.Media.Storage == "s3" {
+		storage, err = media.NewS3Storage(&cfg.Media.S3)
+	} else {
+		storage, err = media.NewLocalStorage(cfg.Media.StoragePath, cfg.Media.StorageURL)
+	}
+	a.ifErrShutdown(ctx, err)
+	a.mediaSvc = media.NewService(&cfg.Media, storage, nil, nil, nil)
+
+	// Set up the Privacy Service, the exports are stored apart from the media and are not served
+	exports, err := media.NewLocalStorage(cfg.Privacy.ExportPath, "")
+	a.ifErrShutdown(ctx, err)
+	a.privacySvc = privacy.NewService(&cfg.Privacy, nil, nil, nil, nil, nil, nil, nil, storage, exports, nil, nil)
+
+	// Set up the Shipping Service
+	var shippingProviders []shipping.ShippingProvider
+	if cfg.Shipping.FakeProvider {
+		shippingProviders = append(shippingProviders, shipping.NewFakeProvider())
+	}
+	if cfg.Shipping.RatesPath != "" {
+		table, err := shipping.NewTableProvider("table", os.DirFS(filepath.Dir(cfg.Shipping.RatesPath)), filepath.Base(cfg.Shipping.RatesPath))
+		a.ifErrShutdown(ctx, err)
+		a.addReloader("shipping rates", table)
+		shippingProviders = append(shippingProviders, table)
+	}
+	a.shippingSvc = shipping.NewService(&cfg.Shipping, prodSvc, a.addressSvc, nil, shippingProviders...)
+
+	// Set up the Cart Service
+	a.cartSvc = cart.NewService(&cart.Config{}, nil, nil, nil)
+	a.cartSvc.SetShipping(a.shippingSvc)
+
+	// Set up the Order Service
+	a.orderSvc = orders.NewService(nil, prodSvc, nil, nil)
+
+	// Set up the Payment Service
+	a.paymentSvc = payment.NewService(&payment.Config{}, nil, map[domain.PaymentMethod]payment.PaymentGateway{
+		domain.PaymentMethodPix:    payment.NewPixGateway(&payment.PixConfig{}, nil),
+		domain.PaymentMethodBoleto: payment.NewBoletoGateway(&payment.BoletoConfig{}, nil),
+	}, a.orderSvc, nil, nil)
+
+	// Set up the Tax Service
+	var taxRules *tax.FileRules
+	if cfg.Tax.RulesPath != "" {
+		taxRules, err = tax.NewFileRules(os.DirFS(cfg.Tax.RulesPath), ".")
+	} else {
+		taxRules, err = tax.NewEmbeddedRules()
+	}
+	a.ifErrShutdown(ctx, err)
+	a.addReloader("tax rules", taxRules)
+	a.taxSvc = tax.NewService(&cfg.Tax, taxRules, prodSvc)
+	a.taxSvc.SetAddresses(a.addressSvc)
+
+	// Set up the Invoice Service, no client of the SEFAZ web services is implemented yet: the FakeSefaz only
+	// authorizes the invoices of homologation, which have no fiscal value
+	if cfg.Invoice.Environment == nfe.EnvironmentProduction {
+		a.ifErrShutdown(ctx, errors.New("invoice: the production environment requires a SEFAZ client"))
+	}
+	var cert *nfe.Certificate
+	if cfg.Invoice.CertificatePath != "" {
+		cert, err = nfe.LoadCertificate(cfg.Invoice.CertificatePath, cfg.Invoice.CertificatePassword)
+		a.ifErrShutdown(ctx, err)
+	}
+	a.invoiceSvc = invoice.NewService(&cfg.Invoice, nil, a.orderSvc, a.customerSvc, a.taxSvc, prodSvc, invoice.NewFakeSefaz(), cert, nil, nil)
+
+	// Set up the Checkout Service
+	a.checkoutSvc = checkout.NewService(&cfg.Checkout, nil, a.cartSvc, prodSvc, a.promotionSvc, nil, a.orderSvc, a.paymentSvc, nil)
+
 	/*
 	 *	Start the controllers
 	 */
+
+	a.watchReload(ctx)
 }
 
 func (a *app) Shutdown(ctx context.Context) error {
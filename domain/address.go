@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CEP is a Brazilian postal code, 8 digits without punctuation. See ParseCEP.
+type CEP string
+
+// ParseCEP normalizes a CEP written with or without punctuation, such as "01310-100" or "01.310-100".
+func ParseCEP(s string) (CEP, error) {
+	cep := CEP(strings.NewReplacer("-", "", ".", "", " ", "").Replace(strings.TrimSpace(s)))
+	if !cep.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCEP, s)
+	}
+	return cep, nil
+}
+
+// MustParseCEP is like ParseCEP but panics on an invalid CEP, for constants and tests.
+func MustParseCEP(s string) CEP {
+	cep, err := ParseCEP(s)
+	if err != nil {
+		panic(err)
+	}
+	return cep
+}
+
+// Valid reports whether the CEP is normalized: 8 digits, not all zeros.
+func (c CEP) Valid() bool {
+	return len(c) == 8 && strings.Trim(string(c), "0123456789") == "" && c != "00000000"
+}
+
+// String returns the CEP formatted as "01310-100".
+func (c CEP) String() string {
+	if !c.Valid() {
+		return string(c)
+	}
+	return string(c[:5]) + "-" + string(c[5:])
+}
+
+// MarshalText returns the normalized CEP.
+func (c CEP) MarshalText() ([]byte, error) {
+	return []byte(c), nil
+}
+
+// UnmarshalText normalizes the CEP, an empty text is the empty CEP.
+func (c *CEP) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*c = ""
+		return nil
+	}
+	cep, err := ParseCEP(string(text))
+	if err != nil {
+		return err
+	}
+	*c = cep
+	return nil
+}
+
+// cepRanges are the CEP ranges of the states, by their first 5 digits.
+var cepRanges = []struct {
+	from, to string
+	state    string
+}{
+	{"01000", "19999", "SP"},
+	{"20000", "28999", "RJ"},
+	{"29000", "29999", "ES"},
+	{"30000", "39999", "MG"},
+	{"40000", "48999", "BA"},
+	{"49000", "49999", "SE"},
+	{"50000", "56999", "PE"},
+	{"57000", "57999", "AL"},
+	{"58000", "58999", "PB"},
+	{"59000", "59999", "RN"},
+	{"60000", "63999", "CE"},
+	{"64000", "64999", "PI"},
+	{"65000", "65999", "MA"},
+	{"66000", "68899", "PA"},
+	{"68900", "68999", "AP"},
+	{"69000", "69299", "AM"},
+	{"69300", "69399", "RR"},
+	{"69400", "69899", "AM"},
+	{"69900", "69999", "AC"},
+	{"70000", "72799", "DF"},
+	{"72800", "72999", "GO"},
+	{"73000", "73699", "DF"},
+	{"73700", "76799", "GO"},
+	{"76800", "76999", "RO"},
+	{"77000", "77999", "TO"},
+	{"78000", "78899", "MT"},
+	{"79000", "79999", "MS"},
+	{"80000", "87999", "PR"},
+	{"88000", "89999", "SC"},
+	{"90000", "99999", "RS"},
+}
+
+// State returns the abbreviation of the state of the CEP from the ranges assigned by the Correios,
+// false when the CEP is invalid or unassigned.
+func (c CEP) State() (string, bool) {
+	if !c.Valid() {
+		return "", false
+	}
+	prefix := string(c[:5])
+	for _, r := range cepRanges {
+		if r.from <= prefix && prefix <= r.to {
+			return r.state, true
+		}
+	}
+	return "", false
+}
+
+// Address is a Brazilian postal address.
+type Address struct {
+	CEP        CEP    `json:"cep"`
+	Street     string `json:"street"`
+	Number     string `json:"number"`
+	Complement string `json:"complement"`
+	District   string `json:"district"`
+	City       string `json:"city"`
+	// State is the abbreviation of the state, such as "SP".
+	State string `json:"state"`
+	// CityCode is the IBGE code of the city, 7 digits.
+	CityCode string `json:"city_code"`
+}
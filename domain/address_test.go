@@ -0,0 +1,55 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"testing"
+)
+
+func TestParseCEP(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected domain.CEP
+		state    string
+		err      error
+	}{
+		{"01310-100", "01310100", "SP", nil},
+		{"01.310-100", "01310100", "SP", nil},
+		{" 40060000 ", "40060000", "BA", nil},
+		{"69301-000", "69301000", "RR", nil},
+		{"73010-000", "73010000", "DF", nil},
+		{"0131010", "", "", domain.ErrInvalidCEP},
+		{"01310-10a", "", "", domain.ErrInvalidCEP},
+		{"00000-000", "", "", domain.ErrInvalidCEP},
+		{"", "", "", domain.ErrInvalidCEP},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			cep, err := domain.ParseCEP(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if cep != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, cep)
+			}
+			if state, _ := cep.State(); state != tt.state {
+				t.Errorf("expected state %q, got %q", tt.state, state)
+			}
+		})
+	}
+}
+
+func TestCEP_JSON(t *testing.T) {
+	var address domain.Address
+	if err := json.Unmarshal([]byte(`{"cep": "01310-100"}`), &address); err != nil {
+		t.Fatal(err)
+	}
+	if address.CEP != "01310100" || address.CEP.String() != "01310-100" {
+		t.Errorf("expected the normalized CEP, got %q", address.CEP)
+	}
+
+	if err := json.Unmarshal([]byte(`{"cep": "1310-100"}`), &address); !errors.Is(err, domain.ErrInvalidCEP) {
+		t.Errorf("expected %v, got %v", domain.ErrInvalidCEP, err)
+	}
+}
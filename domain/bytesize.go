@@ -17,14 +17,23 @@ func (b *ByteSize) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	val, err := units.FromHumanSize(str)
+	val, err := ParseByteSize(str)
 	if err != nil {
 		return err
 	}
-	*b = ByteSize(val)
+	*b = val
 	return nil
 }
 
+// ParseByteSize reads a human-readable size such as "5mb", the units are decimal.
+func ParseByteSize(s string) (ByteSize, error) {
+	val, err := units.FromHumanSize(s)
+	if err != nil {
+		return 0, err
+	}
+	return ByteSize(val), nil
+}
+
 func (b ByteSize) String() string {
 	return units.BytesSize(float64(b))
 }
@@ -82,3 +82,28 @@ func TestByteSize_String(t *testing.T) {
 		})
 	}
 }
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    domain.ByteSize
+		expectError bool
+	}{
+		{"Megabytes", "5mb", domain.ByteSize(5 * 1000 * 1000), false},
+		{"Bytes", "512", domain.ByteSize(512), false},
+		{"Invalid", "five", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := domain.ParseByteSize(tt.input)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("expected error: %v, got: %v", tt.expectError, err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
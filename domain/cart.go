@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Cart holds the items a customer intends to buy. Anonymous carts have no UserID and are identified by their ID only.
+type Cart struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_cart"`
+	Namespace string    `json:"namespace" gorm:"index:idx_cart"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// UserID is the owner of the cart, uuid.Nil for anonymous carts.
+	UserID uuid.UUID  `json:"user_id" gorm:"index:idx_cart"`
+	Items  []CartItem `json:"items" gorm:"serializer:json"`
+	// Coupons are the promotion codes entered by the customer.
+	Coupons []string `json:"coupons" gorm:"serializer:json"`
+	// ExpiresAt is renewed on every change, abandoned carts are purged after it.
+	ExpiresAt time.Time `json:"expires_at" gorm:"index:idx_cart"`
+
+	// ShippingCEP is the CEP the shipping is quoted to, and ShippingOption the code of the option chosen
+	// by the customer, see ShippingOption.Code.
+	ShippingCEP    CEP    `json:"shipping_cep"`
+	ShippingOption string `json:"shipping_option"`
+
+	// Subtotal is the sum of the purchasable items, it is computed on read and not persisted.
+	Subtotal currency.BRL `json:"subtotal" gorm:"-"`
+	// ShippingRequired is true when the store ships its orders, an option must then be chosen before the checkout.
+	ShippingRequired bool `json:"shipping_required" gorm:"-"`
+	// ShippingOptions are quoted to the ShippingCEP on read, Shipping is the price of the chosen one.
+	ShippingOptions []ShippingOption `json:"shipping_options,omitempty" gorm:"-"`
+	Shipping        currency.BRL     `json:"shipping" gorm:"-"`
+	// Total is the subtotal plus the shipping, before the promotions.
+	Total currency.BRL `json:"total" gorm:"-"`
+}
+
+// SelectedShipping returns the quoted option chosen by the customer.
+func (c *Cart) SelectedShipping() (ShippingOption, bool) {
+	for _, o := range c.ShippingOptions {
+		if o.Code() == c.ShippingOption {
+			return o, true
+		}
+	}
+	return ShippingOption{}, false
+}
+
+// IsAnonymous reports whether the cart belongs to a guest.
+func (c *Cart) IsAnonymous() bool {
+	return c.UserID == uuid.Nil
+}
+
+// IsExpired reports whether the cart was abandoned for longer than its TTL.
+func (c *Cart) IsExpired(t time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !t.Before(c.ExpiresAt)
+}
+
+// HasBlockingIssues reports whether any item cannot be purchased as is.
+func (c *Cart) HasBlockingIssues() bool {
+	for _, item := range c.Items {
+		if !item.Purchasable() {
+			return true
+		}
+	}
+	return false
+}
+
+// CartItem is a product, or one of its variants, added to a cart.
+type CartItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	// VariantID is uuid.Nil when the product itself was added.
+	VariantID uuid.UUID    `json:"variant_id"`
+	SKU       string       `json:"sku"`
+	Title     string       `json:"title"`
+	Quantity  int64        `json:"quantity"`
+	UnitPrice currency.BRL `json:"unit_price"`
+	// Categories are copied from the product, so promotions can be evaluated without reloading it.
+	Categories []string `json:"categories"`
+	// Issues are found when the cart is re-validated against the catalog.
+	Issues []CartIssue `json:"issues,omitempty"`
+}
+
+// Purchasable reports whether the item has no blocking issue.
+func (i CartItem) Purchasable() bool {
+	for _, issue := range i.Issues {
+		if issue.Blocking() {
+			return false
+		}
+	}
+	return true
+}
+
+// Total returns the item total.
+func (i CartItem) Total() currency.BRL {
+	return i.UnitPrice.MulQuantity(i.Quantity)
+}
+
+type CartIssue string
+
+const (
+	// CartIssuePriceChanged is informative, the item is still purchasable at the new price.
+	CartIssuePriceChanged CartIssue = "price_changed"
+	// CartIssueUnavailable means the product was removed from the catalog or is not available for sale.
+	CartIssueUnavailable CartIssue = "unavailable"
+	// CartIssueInsufficientStock means the stock is lower than the item quantity.
+	CartIssueInsufficientStock CartIssue = "insufficient_stock"
+)
+
+// Blocking reports whether the issue prevents the item from being purchased.
+func (i CartIssue) Blocking() bool {
+	return i != CartIssuePriceChanged
+}
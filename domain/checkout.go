@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// CheckoutAttempt records a checkout by its idempotency key, so a submission sent twice places a single order.
+type CheckoutAttempt struct {
+	Key        string         `json:"key" gorm:"primaryKey"`
+	Namespace  string         `json:"namespace" gorm:"primaryKey"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	CartID     uuid.UUID      `json:"cart_id"`
+	CustomerID uuid.UUID      `json:"customer_id"`
+	Status     CheckoutStatus `json:"status"`
+
+	// The progress of the checkout, recorded after each step so an interrupted one can be compensated.
+	// Reserved are the items whose stock was reserved, Promotions the promotions redeemed for the order.
+	Reserved   []CartItem  `json:"reserved" gorm:"serializer:json"`
+	OrderID    uuid.UUID   `json:"order_id"`
+	Promotions []uuid.UUID `json:"promotions" gorm:"serializer:json"`
+	PaymentID  uuid.UUID   `json:"payment_id"`
+}
+
+type CheckoutStatus string
+
+const (
+	CheckoutStatusInProgress CheckoutStatus = "in_progress"
+	CheckoutStatusCompleted  CheckoutStatus = "completed"
+)
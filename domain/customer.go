@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// Customer is the profile of a User buying in the namespace: its tax document, contacts, addresses and consents.
+// A user has at most one customer profile per namespace.
+type Customer struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_customer"`
+	Namespace string    `json:"namespace" gorm:"index:idx_customer;uniqueIndex:idx_customer_user;uniqueIndex:idx_customer_document"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// UserID is the User the profile belongs to.
+	UserID uuid.UUID `json:"user_id" gorm:"uniqueIndex:idx_customer_user"`
+	// Document is the CPF of a person or the CNPJ of a company, normalized. See ParseDocument.
+	// It is optional but unique in the namespace, the profiles without a document, or anonymized, share
+	// the empty document which the unique index leaves out.
+	Document Document `json:"document" gorm:"uniqueIndex:idx_customer_document,where:document <> ''"`
+	Phone    Phone    `json:"phone"`
+	// BirthDate is the date of birth, nil when unknown.
+	BirthDate *time.Time `json:"birth_date"`
+
+	Addresses []CustomerAddress `json:"addresses" gorm:"serializer:json"`
+	Consents  Consents          `json:"consents" gorm:"embedded;embeddedPrefix:consent_"`
+
+	// AnonymizedAt is set once the personal data was erased, the profile can no longer be changed.
+	AnonymizedAt *time.Time `json:"anonymized_at"`
+}
+
+// AddressKind tells what an address of the customer is used for.
+type AddressKind string
+
+const (
+	AddressKindShipping AddressKind = "shipping"
+	AddressKindBilling  AddressKind = "billing"
+)
+
+// CustomerAddress is an address saved by the customer. Each kind has a single default address.
+type CustomerAddress struct {
+	ID uuid.UUID `json:"id"`
+	// Label names the address for the customer, such as "Home" or "Office".
+	Label   string      `json:"label"`
+	Kind    AddressKind `json:"kind"`
+	Default bool        `json:"default"`
+	// Recipient is the name of the person receiving the deliveries, the customer name when empty.
+	Recipient string `json:"recipient"`
+	Address
+}
+
+// DefaultAddress returns the default address of the kind, false when the customer has none.
+func (c *Customer) DefaultAddress(kind AddressKind) (*CustomerAddress, bool) {
+	for i := range c.Addresses {
+		if c.Addresses[i].Kind == kind && c.Addresses[i].Default {
+			return &c.Addresses[i], true
+		}
+	}
+	return nil, false
+}
+
+// Anonymize erases the personal data of the profile: the document, phone, birth date and addresses,
+// and withdraws every consent. The profile itself is kept, as the orders of the user reference it.
+func (c *Customer) Anonymize(now time.Time) {
+	c.Document = ""
+	c.Phone = ""
+	c.BirthDate = nil
+	c.Addresses = nil
+	c.Consents = Consents{UpdatedAt: now}
+	c.AnonymizedAt = &now
+	c.UpdatedAt = now
+}
+
+// Consents are the marketing communications the customer opted in to. They are all off by default, as required by the LGPD.
+type Consents struct {
+	Email    bool `json:"email"`
+	SMS      bool `json:"sms"`
+	WhatsApp bool `json:"whatsapp"`
+	// UpdatedAt is when the customer last changed the consents, kept as evidence of the opt-in.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Document is a Brazilian tax identification number: the CPF of a person (11 digits) or the CNPJ of a
+// company (14 characters), without punctuation. See ParseDocument.
+type Document string
+
+// ParseDocument normalizes a CPF or CNPJ written with or without punctuation, such as "123.456.789-09"
+// or "12.345.678/0001-95", and checks its verification digits.
+func ParseDocument(s string) (Document, error) {
+	doc := Document(strings.ToUpper(strings.NewReplacer(".", "", "-", "", "/", "", " ", "").Replace(strings.TrimSpace(s))))
+	if !doc.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidDocument, s)
+	}
+	return doc, nil
+}
+
+// IsCPF reports whether the document is a valid CPF.
+func (d Document) IsCPF() bool {
+	if len(d) != 11 || strings.Trim(string(d), "0123456789") != "" || repeated(string(d)) {
+		return false
+	}
+	return checkDigit(string(d[:9]), 10) == d[9] && checkDigit(string(d[:10]), 11) == d[10]
+}
+
+// IsCNPJ reports whether the document is a valid CNPJ. The alphanumeric CNPJ, issued since July 2026, is
+// supported: its first 12 characters are digits or uppercase letters, the verification digits are numeric.
+func (d Document) IsCNPJ() bool {
+	if len(d) != 14 || strings.Trim(string(d[:12]), "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" ||
+		strings.Trim(string(d[12:]), "0123456789") != "" || repeated(string(d)) {
+		return false
+	}
+	return checkDigit(string(d[:12]), 0) == d[12] && checkDigit(string(d[:13]), 0) == d[13]
+}
+
+// Valid reports whether the document is a normalized CPF or CNPJ with valid verification digits.
+func (d Document) Valid() bool {
+	return d.IsCPF() || d.IsCNPJ()
+}
+
+// String returns the document formatted as "123.456.789-09" or "12.345.678/0001-95".
+func (d Document) String() string {
+	switch {
+	case d.IsCPF():
+		return fmt.Sprintf("%s.%s.%s-%s", d[:3], d[3:6], d[6:9], d[9:])
+	case d.IsCNPJ():
+		return fmt.Sprintf("%s.%s.%s/%s-%s", d[:2], d[2:5], d[5:8], d[8:12], d[12:])
+	}
+	return string(d)
+}
+
+// MarshalText returns the normalized document.
+func (d Document) MarshalText() ([]byte, error) {
+	return []byte(d), nil
+}
+
+// UnmarshalText normalizes the document, an empty text is the empty document.
+func (d *Document) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = ""
+		return nil
+	}
+	doc, err := ParseDocument(string(text))
+	if err != nil {
+		return err
+	}
+	*d = doc
+	return nil
+}
+
+// checkDigit computes the modulo 11 verification digit of the characters, valued as their ASCII code minus 48.
+// The CPF weights start at first and decrease to 2, the CNPJ ones (first = 0) cycle from 9 down to 2.
+func checkDigit(s string, first int) byte {
+	sum := 0
+	for i := range len(s) {
+		weight := first - i
+		if first == 0 {
+			weight = 2 + (len(s)-1-i)%8
+		}
+		sum += int(s[i]-'0') * weight
+	}
+	if r := sum % 11; r >= 2 {
+		return byte('0' + 11 - r)
+	}
+	return '0'
+}
+
+// repeated reports whether every character is the same, such documents pass the check digits but are invalid.
+func repeated(s string) bool {
+	return strings.Count(s, s[:1]) == len(s)
+}
+
+// Phone is a Brazilian phone number in the E.164 format, such as "+5511987654321". See ParsePhone.
+type Phone string
+
+// ParsePhone normalizes a Brazilian phone number written with or without the country code and punctuation,
+// such as "(11) 98765-4321" or "+55 11 3333-4444". The area code is required.
+func ParsePhone(s string) (Phone, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+	if len(digits) == 12 || len(digits) == 13 {
+		digits = strings.TrimPrefix(digits, "55")
+	}
+	phone := Phone("+55" + digits)
+	if !phone.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPhone, s)
+	}
+	return phone, nil
+}
+
+// Valid reports whether the phone is normalized: a mobile number (9 digits starting with 9) or a landline
+// (8 digits starting with 2 to 5), after an area code without zeros.
+func (p Phone) Valid() bool {
+	n := strings.TrimPrefix(string(p), "+55")
+	if n == string(p) || strings.Trim(n, "0123456789") != "" || strings.ContainsRune(n[:min(2, len(n))], '0') {
+		return false
+	}
+	switch len(n) {
+	case 11:
+		return n[2] == '9'
+	case 10:
+		return n[2] >= '2' && n[2] <= '5'
+	}
+	return false
+}
+
+// Mobile reports whether the phone is a mobile number.
+func (p Phone) Mobile() bool {
+	return p.Valid() && len(p) == 14
+}
+
+// String returns the phone formatted as "(11) 98765-4321".
+func (p Phone) String() string {
+	if !p.Valid() {
+		return string(p)
+	}
+	n := string(p[3:])
+	return fmt.Sprintf("(%s) %s-%s", n[:2], n[2:len(n)-4], n[len(n)-4:])
+}
+
+// MarshalText returns the normalized phone.
+func (p Phone) MarshalText() ([]byte, error) {
+	return []byte(p), nil
+}
+
+// UnmarshalText normalizes the phone, an empty text is the empty phone.
+func (p *Phone) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*p = ""
+		return nil
+	}
+	phone, err := ParsePhone(string(text))
+	if err != nil {
+		return err
+	}
+	*p = phone
+	return nil
+}
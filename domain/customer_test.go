@@ -0,0 +1,83 @@
+package domain_test
+
+import (
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"testing"
+)
+
+func TestParseDocument(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  domain.Document
+		formatted string
+		cpf       bool
+		err       error
+	}{
+		{"529.982.247-25", "52998224725", "529.982.247-25", true, nil},
+		{"52998224725", "52998224725", "529.982.247-25", true, nil},
+		{"11.222.333/0001-81", "11222333000181", "11.222.333/0001-81", false, nil},
+		{"12.abc.345/01de-35", "12ABC34501DE35", "12.ABC.345/01DE-35", false, nil},
+		{"529.982.247-24", "", "", false, domain.ErrInvalidDocument},
+		{"11.222.333/0001-80", "", "", false, domain.ErrInvalidDocument},
+		{"111.111.111-11", "", "", false, domain.ErrInvalidDocument},
+		{"12.ABC.345/01DE-3A", "", "", false, domain.ErrInvalidDocument},
+		{"5299822472", "", "", false, domain.ErrInvalidDocument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			doc, err := domain.ParseDocument(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if doc != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, doc)
+			}
+			if err != nil {
+				return
+			}
+			if doc.String() != tt.formatted {
+				t.Errorf("expected %q, got %q", tt.formatted, doc.String())
+			}
+			if doc.IsCPF() != tt.cpf || doc.IsCNPJ() == tt.cpf {
+				t.Errorf("expected CPF %v", tt.cpf)
+			}
+		})
+	}
+}
+
+func TestParsePhone(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  domain.Phone
+		formatted string
+		mobile    bool
+		err       error
+	}{
+		{"(11) 98765-4321", "+5511987654321", "(11) 98765-4321", true, nil},
+		{"+55 11 3333-4444", "+551133334444", "(11) 3333-4444", false, nil},
+		{"5555987654321", "+5555987654321", "(55) 98765-4321", true, nil},
+		{"55 98765-4321", "+5555987654321", "(55) 98765-4321", true, nil},
+		{"98765-4321", "", "", false, domain.ErrInvalidPhone},
+		{"(01) 98765-4321", "", "", false, domain.ErrInvalidPhone},
+		{"(11) 88765-4321", "", "", false, domain.ErrInvalidPhone},
+		{"(11) 7333-4444", "", "", false, domain.ErrInvalidPhone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			phone, err := domain.ParsePhone(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if phone != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, phone)
+			}
+			if err != nil {
+				return
+			}
+			if phone.String() != tt.formatted || phone.Mobile() != tt.mobile {
+				t.Errorf("expected %q (mobile %v), got %q (mobile %v)", tt.formatted, tt.mobile, phone.String(), phone.Mobile())
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/google/uuid"
+	"time"
+)
+
+type OrderFilter struct {
+	CustomerID uuid.UUID            `json:"customer_id"`
+	Status     []domain.OrderStatus `json:"status"`
+
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Offset int       `json:"offset"`
+	Limit  int       `json:"limit"`
+}
@@ -2,12 +2,19 @@ package dto
 
 import (
 	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
 	"github.com/google/uuid"
 	"time"
 )
 
 type ProductFilter struct {
 	IDs []uuid.UUID `json:"ids"`
+	// SKUs matches products whose SKU, or the SKU of one of their variants, is in the list.
+	SKUs []string `json:"skus"`
+	// Slugs matches products whose slug is in the list.
+	Slugs []string `json:"slugs"`
+	// Currencies lists the foreign currencies the prices are also displayed in, see domain.DisplayPrice.
+	Currencies []currency.Currency `json:"currencies"`
 }
 
 type ProductLogFilter struct {
@@ -21,3 +28,23 @@ type ProductLogFilter struct {
 	Offset int       `json:"offset"`
 	Limit  int       `json:"limit"`
 }
+
+type PriceHistoryFilter struct {
+	SKU   string    `json:"sku"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type PriceListFilter struct {
+	// Groups matches the price lists assigned to any of the customer groups.
+	Groups []string `json:"groups"`
+	// At matches the price lists valid at the given time, ignored when zero.
+	At time.Time `json:"at"`
+}
+
+type ExchangeRateFilter struct {
+	Base  currency.Currency `json:"base"`
+	Quote currency.Currency `json:"quote"`
+	Start time.Time         `json:"start"`
+	End   time.Time         `json:"end"`
+}
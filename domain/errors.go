@@ -9,15 +9,146 @@ var (
 // Product related errors
 var (
 	ErrProductNotFound       = errors.New("product not found")
+	ErrProductUnavailable    = errors.New("product unavailable")
 	ErrInvalidProductPrice   = errors.New("invalid product price")
 	ErrInvalidProductTitle   = errors.New("invalid product title")
 	ErrInvalidProductStatus  = errors.New("invalid product status")
 	ErrFailedToCreateProduct = errors.New("failed to create product")
+	ErrSlugInUse             = errors.New("product slug already in use")
 	ErrInvalidMedia          = errors.New("invalid media")
+	ErrTemplateNotFound      = errors.New("product template not found")
+	ErrInvalidTemplateName   = errors.New("invalid product template name")
+	ErrInvalidBundle         = errors.New("invalid product bundle")
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrVariantNotFound       = errors.New("product variant not found")
+	ErrPriceHistoryNotFound  = errors.New("price history not found")
+	ErrInvalidPricePeriod    = errors.New("invalid price period")
+	ErrInvalidSalePrice      = errors.New("invalid sale price")
 )
 
 // Media related errors
 var (
 	ErrInvalidMediaType = errors.New("invalid media type")
 	ErrFileTooLarge     = errors.New("file too large")
+	ErrFileNotFound     = errors.New("file not found")
+	ErrInvalidFilename  = errors.New("invalid filename")
+)
+
+// Pricing related errors
+var (
+	ErrPriceListNotFound = errors.New("price list not found")
+	ErrInvalidPriceList  = errors.New("invalid price list")
+	ErrInvalidQuantity   = errors.New("invalid quantity")
+	ErrSKUNotFound       = errors.New("sku not found")
+)
+
+// Promotion related errors
+var (
+	ErrInvalidPromotion     = errors.New("invalid promotion")
+	ErrPromotionNotFound    = errors.New("promotion not found")
+	ErrCouponNotFound       = errors.New("coupon not found")
+	ErrCouponNotApplicable  = errors.New("coupon not applicable")
+	ErrCouponUsageLimit     = errors.New("coupon usage limit reached")
+	ErrPromotionNotCombined = errors.New("promotion cannot be combined")
+)
+
+// Cart related errors
+var (
+	ErrCartNotFound     = errors.New("cart not found")
+	ErrCartExpired      = errors.New("cart expired")
+	ErrCartItemNotFound = errors.New("cart item not found")
+)
+
+// Order related errors
+var (
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrInvalidOrder           = errors.New("invalid order")
+	ErrInvalidOrderTransition = errors.New("invalid order status transition")
+)
+
+// Checkout related errors
+var (
+	ErrEmptyCart             = errors.New("cart is empty")
+	ErrCartHasIssues         = errors.New("cart has items that cannot be purchased")
+	ErrPriceChanged          = errors.New("price changed")
+	ErrCheckoutInProgress    = errors.New("checkout in progress")
+	ErrIdempotencyKeyReused  = errors.New("idempotency key reused with a different request")
+	ErrMissingIdempotencyKey = errors.New("missing idempotency key")
+)
+
+// Payment related errors
+var (
+	ErrPaymentNotFound          = errors.New("payment not found")
+	ErrPaymentDeclined          = errors.New("payment declined")
+	ErrInvalidPaymentOperation  = errors.New("invalid payment operation")
+	ErrInvalidPaymentAmount     = errors.New("invalid payment amount")
+	ErrUnsupportedPaymentMethod = errors.New("unsupported payment method")
+	ErrInvalidWebhookSignature  = errors.New("invalid webhook signature")
+)
+
+// Exchange rate related errors
+var (
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+	ErrExchangeRateExpired  = errors.New("exchange rate expired")
+	ErrInvalidExchangeRate  = errors.New("invalid exchange rate")
+	ErrExchangeRatesFixed   = errors.New("exchange rates cannot be changed")
+)
+
+// Tax related errors
+var (
+	ErrInvalidNCM        = errors.New("invalid NCM code")
+	ErrInvalidTaxOrigin  = errors.New("invalid tax origin")
+	ErrInvalidState      = errors.New("invalid state")
+	ErrTaxRulesNotFound  = errors.New("tax rules not found")
+	ErrInvalidTaxRules   = errors.New("invalid tax rules")
+	ErrTaxRateNotDefined = errors.New("tax rate not defined")
+)
+
+// Invoice related errors
+var (
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrInvoiceAlreadyIssued = errors.New("invoice already issued")
+	ErrInvoiceRejected      = errors.New("invoice rejected")
+	ErrOrderNotPaid         = errors.New("order not paid")
+	ErrInvalidCFOP          = errors.New("invalid CFOP")
+	ErrRecipientMismatch    = errors.New("recipient does not match the customer")
+)
+
+// Shipping related errors
+var (
+	ErrInvalidWeight          = errors.New("invalid weight")
+	ErrInvalidDimensions      = errors.New("invalid dimensions")
+	ErrInvalidShippingRates   = errors.New("invalid shipping rates")
+	ErrShippingUnavailable    = errors.New("shipping unavailable")
+	ErrShippingOptionNotFound = errors.New("shipping option not found")
+	ErrShippingNotSelected    = errors.New("shipping option not selected")
+)
+
+// Address related errors
+var (
+	ErrInvalidCEP         = errors.New("invalid CEP")
+	ErrAddressNotFound    = errors.New("address not found")
+	ErrInvalidAddress     = errors.New("invalid address")
+	ErrInvalidAddressData = errors.New("invalid address dataset")
+)
+
+// Customer related errors
+var (
+	ErrCustomerNotFound   = errors.New("customer not found")
+	ErrCustomerExists     = errors.New("customer profile already exists")
+	ErrCustomerAnonymized = errors.New("customer anonymized")
+	ErrInvalidCustomer    = errors.New("invalid customer")
+	ErrInvalidDocument    = errors.New("invalid CPF or CNPJ")
+	ErrDocumentInUse      = errors.New("document already in use")
+	ErrInvalidPhone       = errors.New("invalid phone")
+	ErrInvalidBirthDate   = errors.New("invalid birth date")
+	ErrUserNotFound       = errors.New("user not found")
+)
+
+// Privacy related errors
+var (
+	ErrDataRequestNotFound  = errors.New("data subject request not found")
+	ErrDataRequestCompleted = errors.New("data subject request already completed")
+	ErrInvalidDataRequest   = errors.New("invalid data subject request")
+	ErrExportNotFound       = errors.New("export not found")
 )
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CustomerCreated struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedOn time.Time `json:"created_on"`
+	CreatedBy uuid.UUID `json:"created_by"`
+}
+
+type CustomerUpdated struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	UpdatedOn time.Time `json:"updated_on"`
+	UpdatedBy uuid.UUID `json:"updated_by"`
+}
+
+// CustomerConsentsChanged carries the new consents so the marketing tools can honor an opt-out right away.
+type CustomerConsentsChanged struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Email     bool      `json:"email"`
+	SMS       bool      `json:"sms"`
+	WhatsApp  bool      `json:"whatsapp"`
+	ChangedOn time.Time `json:"changed_on"`
+}
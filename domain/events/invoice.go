@@ -0,0 +1,16 @@
+package events
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// InvoiceAuthorized is published when SEFAZ authorizes the NF-e of an order.
+type InvoiceAuthorized struct {
+	ID           uuid.UUID `json:"id"`
+	OrderID      uuid.UUID `json:"order_id"`
+	AccessKey    string    `json:"access_key"`
+	Protocol     string    `json:"protocol"`
+	AuthorizedAt time.Time `json:"authorized_at"`
+	IssuedBy     uuid.UUID `json:"issued_by"`
+}
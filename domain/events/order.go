@@ -0,0 +1,25 @@
+package events
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"time"
+)
+
+type OrderCreated struct {
+	ID         uuid.UUID    `json:"id"`
+	CustomerID uuid.UUID    `json:"customer_id"`
+	Total      currency.BRL `json:"total"`
+	CreatedOn  time.Time    `json:"created_on"`
+	CreatedBy  uuid.UUID    `json:"created_by"`
+}
+
+// OrderStatusChanged is published on the topic of the new status, e.g. "order:paid".
+type OrderStatusChanged struct {
+	ID        uuid.UUID `json:"id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedOn time.Time `json:"changed_on"`
+	ChangedBy uuid.UUID `json:"changed_by"`
+}
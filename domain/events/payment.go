@@ -0,0 +1,17 @@
+package events
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"time"
+)
+
+// PaymentStatusChanged is published on the topic of the new status, e.g. "payment:captured".
+type PaymentStatusChanged struct {
+	ID        uuid.UUID    `json:"id"`
+	OrderID   uuid.UUID    `json:"order_id"`
+	From      string       `json:"from"`
+	To        string       `json:"to"`
+	Amount    currency.BRL `json:"amount"`
+	ChangedOn time.Time    `json:"changed_on"`
+}
@@ -0,0 +1,23 @@
+package events
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type DataSubjectRequestOpened struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Type        string    `json:"type"`
+	Deadline    time.Time `json:"deadline"`
+	RequestedBy uuid.UUID `json:"requested_by"`
+}
+
+// DataSubjectRequestCompleted is published once the export is ready or the data erased.
+type DataSubjectRequestCompleted struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Type        string    `json:"type"`
+	CompletedOn time.Time `json:"completed_on"`
+	CompletedBy uuid.UUID `json:"completed_by"`
+}
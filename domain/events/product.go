@@ -17,3 +17,19 @@ type ProductUpdated struct {
 	UpdatedOn time.Time `json:"updated_on"`
 	UpdatedBy uuid.UUID `json:"updated_by"`
 }
+
+type ProductDuplicated struct {
+	ID        uuid.UUID `json:"id"`
+	SourceID  uuid.UUID `json:"source_id"`
+	CreatedOn time.Time `json:"created_on"`
+	CreatedBy uuid.UUID `json:"created_by"`
+}
+
+type ProductStockUpdated struct {
+	ID        uuid.UUID `json:"id"`
+	VariantID uuid.UUID `json:"variant_id"`
+	Delta     int64     `json:"delta"`
+	Stock     int64     `json:"stock"`
+	UpdatedOn time.Time `json:"updated_on"`
+	UpdatedBy uuid.UUID `json:"updated_by"`
+}
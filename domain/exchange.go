@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ExchangeRate is the price of one unit of the Base currency in the Quote currency, effective from EffectiveAt
+// until the next rate of the same pair. The rates are kept as a history and never updated in place.
+type ExchangeRate struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	Namespace string    `json:"namespace" gorm:"index:idx_exchange_rate"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Base  currency.Currency `json:"base" gorm:"index:idx_exchange_rate"`
+	Quote currency.Currency `json:"quote" gorm:"index:idx_exchange_rate"`
+	Rate  currency.Rate     `json:"rate"`
+	// EffectiveAt is the moment the rate starts to be used.
+	EffectiveAt time.Time `json:"effective_at" gorm:"index:idx_exchange_rate"`
+	// Source describes where the rate comes from, such as "ptax" or "manual".
+	Source string `json:"source"`
+}
+
+// DisplayPrice is a price converted to a foreign currency for display only, the orders are always charged in BRL.
+type DisplayPrice struct {
+	Price currency.Money `json:"price"`
+	// Rate and RateDate identify the exchange rate used in the conversion.
+	Rate     currency.Rate `json:"rate"`
+	RateDate time.Time     `json:"rate_date"`
+}
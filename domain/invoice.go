@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Invoice is the NF-e issued for an order.
+type Invoice struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_invoice"`
+	Namespace string    `json:"namespace" gorm:"index:idx_invoice"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	OrderID uuid.UUID     `json:"order_id" gorm:"index:idx_invoice"`
+	Status  InvoiceStatus `json:"status"`
+	Series  int           `json:"series"`
+	Number  int64         `json:"number"`
+	// AccessKey is the chave de acesso, the 44 digits identifying the NF-e.
+	AccessKey string `json:"access_key" gorm:"index"`
+	// XML is the signed NF-e sent to SEFAZ.
+	XML string `json:"xml"`
+
+	// StatusCode and Reason are the cStat and xMotivo answered by SEFAZ.
+	StatusCode int    `json:"status_code"`
+	Reason     string `json:"reason"`
+	// Protocol is the authorization protocol, empty unless the invoice is authorized.
+	Protocol     string    `json:"protocol"`
+	AuthorizedAt time.Time `json:"authorized_at"`
+}
+
+type InvoiceStatus string
+
+const (
+	// InvoiceStatusPending is an invoice submitted to SEFAZ without an answer yet, its XML is submitted
+	// again unchanged since SEFAZ may have authorized it.
+	InvoiceStatusPending    InvoiceStatus = "pending"
+	InvoiceStatusAuthorized InvoiceStatus = "authorized"
+	InvoiceStatusRejected   InvoiceStatus = "rejected"
+)
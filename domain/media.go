@@ -14,10 +14,15 @@ type Media struct {
 	UpdatedAt time.Time `json:"updated_at"`
 
 	Filename string `json:"filename"`
-	// Url is the public link for the media
+	// Url is the permanent link of the media, which needs a public storage to be readable. See media.Service.GetURL
+	// for a link to the media of a private storage.
 	Url string `json:"url"`
 	// Type indicates the media's content type, such as image, video, or document.
 	Type string `json:"type" gorm:"index:idx_media"`
 	// Size represents the size of the media in bytes.
 	Size int `json:"size"`
+	// Checksum is the hex-encoded SHA-256 of the stored content.
+	Checksum string `json:"checksum"`
+	// UploadedBy is the user who uploaded the media.
+	UploadedBy uuid.UUID `json:"uploaded_by" gorm:"index:idx_media_uploader"`
 }
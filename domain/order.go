@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"slices"
+	"time"
+)
+
+// Order is placed at checkout. Its lines are a snapshot of the catalog, later product changes do not affect it.
+type Order struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_order"`
+	Namespace string    `json:"namespace" gorm:"index:idx_order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	CustomerID uuid.UUID   `json:"customer_id" gorm:"index:idx_order"`
+	Status     OrderStatus `json:"status" gorm:"index:idx_order"`
+	Lines      []OrderLine `json:"lines" gorm:"serializer:json"`
+	// Coupons are the promotion codes applied to the order.
+	Coupons []string `json:"coupons" gorm:"serializer:json"`
+
+	// ShippingCEP is the CEP the order is delivered to and ShippingOption the code of the shipping option.
+	ShippingCEP    CEP    `json:"shipping_cep"`
+	ShippingOption string `json:"shipping_option"`
+
+	Subtotal         currency.BRL `json:"subtotal"`
+	Discount         currency.BRL `json:"discount"`
+	Shipping         currency.BRL `json:"shipping"`
+	ShippingDiscount currency.BRL `json:"shipping_discount"`
+	Total            currency.BRL `json:"total"`
+
+	// History records every status transition, oldest first.
+	History []OrderTransition `json:"history" gorm:"serializer:json"`
+}
+
+// OrderLine is the snapshot of a product, or one of its variants, at the time the order was placed.
+type OrderLine struct {
+	ProductID uuid.UUID `json:"product_id"`
+	// VariantID is uuid.Nil when the product itself was ordered.
+	VariantID uuid.UUID    `json:"variant_id"`
+	SKU       string       `json:"sku"`
+	Title     string       `json:"title"`
+	UnitPrice currency.BRL `json:"unit_price"`
+	Quantity  int64        `json:"quantity"`
+	// Discount is the share of the promotions allocated to the line.
+	Discount currency.BRL `json:"discount"`
+}
+
+// Subtotal returns the line total before discounts.
+func (l OrderLine) Subtotal() currency.BRL {
+	return l.UnitPrice.MulQuantity(l.Quantity)
+}
+
+// Total returns the line total after discounts.
+func (l OrderLine) Total() currency.BRL {
+	return l.Subtotal().Sub(l.Discount)
+}
+
+// ComputeTotals sets the order totals from its lines and shipping.
+func (o *Order) ComputeTotals() {
+	o.Subtotal, o.Discount = 0, 0
+	for _, l := range o.Lines {
+		o.Subtotal = o.Subtotal.Add(l.Subtotal())
+		o.Discount = o.Discount.Add(l.Discount)
+	}
+	o.Total = o.Subtotal.Sub(o.Discount).Add(o.Shipping).Sub(o.ShippingDiscount)
+}
+
+// OrderTransition is an entry of the order status history.
+type OrderTransition struct {
+	From   OrderStatus `json:"from"`
+	To     OrderStatus `json:"to"`
+	At     time.Time   `json:"at"`
+	UserID uuid.UUID   `json:"user_id"`
+	Reason string      `json:"reason,omitempty"`
+}
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusFulfilled OrderStatus = "fulfilled"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRefunded  OrderStatus = "refunded"
+)
+
+// orderTransitions lists the statuses reachable from each status. An order is cancelled before the payment
+// and refunded after it, cancelled and refunded orders are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusFulfilled, OrderStatusRefunded},
+	OrderStatusFulfilled: {OrderStatusDelivered, OrderStatusRefunded},
+	OrderStatusDelivered: {OrderStatusRefunded},
+}
+
+// CanTransitionTo reports whether the status can change to the given one.
+func (s OrderStatus) CanTransitionTo(to OrderStatus) bool {
+	return slices.Contains(orderTransitions[s], to)
+}
+
+// IsFinal reports whether no transition is possible from the status.
+func (s OrderStatus) IsFinal() bool {
+	return len(orderTransitions[s]) == 0
+}
+
+// Transition changes the order status and records it in the history.
+// It returns ErrInvalidOrderTransition if the state machine does not allow the change.
+func (o *Order) Transition(to OrderStatus, userID uuid.UUID, reason string, at time.Time) error {
+	if !o.Status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidOrderTransition, o.Status, to)
+	}
+	o.History = append(o.History, OrderTransition{
+		From:   o.Status,
+		To:     to,
+		At:     at,
+		UserID: userID,
+		Reason: reason,
+	})
+	o.Status = to
+	o.UpdatedAt = at
+	return nil
+}
@@ -0,0 +1,71 @@
+package domain_test
+
+import (
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestOrderTransition(t *testing.T) {
+	tests := []struct {
+		from  domain.OrderStatus
+		to    domain.OrderStatus
+		valid bool
+	}{
+		{domain.OrderStatusPending, domain.OrderStatusPaid, true},
+		{domain.OrderStatusPending, domain.OrderStatusCancelled, true},
+		{domain.OrderStatusPending, domain.OrderStatusFulfilled, false},
+		{domain.OrderStatusPending, domain.OrderStatusRefunded, false},
+		{domain.OrderStatusPaid, domain.OrderStatusFulfilled, true},
+		{domain.OrderStatusPaid, domain.OrderStatusRefunded, true},
+		{domain.OrderStatusPaid, domain.OrderStatusCancelled, false},
+		{domain.OrderStatusFulfilled, domain.OrderStatusDelivered, true},
+		{domain.OrderStatusFulfilled, domain.OrderStatusRefunded, true},
+		{domain.OrderStatusDelivered, domain.OrderStatusRefunded, true},
+		{domain.OrderStatusDelivered, domain.OrderStatusPaid, false},
+		{domain.OrderStatusCancelled, domain.OrderStatusPaid, false},
+		{domain.OrderStatusRefunded, domain.OrderStatusPaid, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			order := &domain.Order{Status: tt.from}
+			at := time.Now()
+			userID := uuid.New()
+
+			err := order.Transition(tt.to, userID, "reason", at)
+			if !tt.valid {
+				if !errors.Is(err, domain.ErrInvalidOrderTransition) {
+					t.Fatalf("expected ErrInvalidOrderTransition, got %v", err)
+				}
+				if order.Status != tt.from || len(order.History) != 0 {
+					t.Errorf("a rejected transition must not change the order")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if order.Status != tt.to {
+				t.Errorf("status = %s, want %s", order.Status, tt.to)
+			}
+			want := domain.OrderTransition{From: tt.from, To: tt.to, At: at, UserID: userID, Reason: "reason"}
+			if len(order.History) != 1 || order.History[0] != want {
+				t.Errorf("history = %+v, want %+v", order.History, want)
+			}
+		})
+	}
+}
+
+func TestOrderFinalStatus(t *testing.T) {
+	for _, s := range []domain.OrderStatus{domain.OrderStatusCancelled, domain.OrderStatusRefunded} {
+		if !s.IsFinal() {
+			t.Errorf("%s should be final", s)
+		}
+	}
+	if domain.OrderStatusDelivered.IsFinal() {
+		t.Errorf("delivered orders can still be refunded")
+	}
+}
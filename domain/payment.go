@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Payment is a payment of an order through a payment gateway.
+type Payment struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_payment"`
+	Namespace string    `json:"namespace" gorm:"index:idx_payment"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	OrderID    uuid.UUID     `json:"order_id" gorm:"index:idx_payment"`
+	CustomerID uuid.UUID     `json:"customer_id"`
+	Method     PaymentMethod `json:"method"`
+	Status     PaymentStatus `json:"status"`
+	Amount     currency.BRL  `json:"amount"`
+	Captured   currency.BRL  `json:"captured"`
+	Refunded   currency.BRL  `json:"refunded"`
+
+	// TransactionID identifies the payment in the gateway, status updates refer to it.
+	TransactionID string `json:"transaction_id" gorm:"index:idx_payment_transaction"`
+	// PixPayload is the "copia e cola" code of Pix payments.
+	PixPayload string `json:"pix_payload,omitempty"`
+	// BoletoBarcode and BoletoLine are the barcode digits and the linha digitável of boleto payments.
+	BoletoBarcode string `json:"boleto_barcode,omitempty"`
+	BoletoLine    string `json:"boleto_line,omitempty"`
+	// ExpiresAt is the deadline of the Pix or boleto payments.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+type PaymentMethod string
+
+const (
+	PaymentMethodCreditCard PaymentMethod = "credit_card"
+	PaymentMethodPix        PaymentMethod = "pix"
+	PaymentMethodBoleto     PaymentMethod = "boleto"
+)
+
+type PaymentStatus string
+
+const (
+	// PaymentStatusPending waits for the customer to pay, e.g. a Pix or boleto not paid yet.
+	PaymentStatusPending    PaymentStatus = "pending"
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	PaymentStatusCaptured   PaymentStatus = "captured"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+	PaymentStatusVoided     PaymentStatus = "voided"
+	PaymentStatusFailed     PaymentStatus = "failed"
+)
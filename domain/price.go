@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// PriceChange records a change to the price or the sale prices of a product or variant, used as evidence of
+// prior prices.
+type PriceChange struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	Namespace string    `json:"namespace" gorm:"index:idx_price_change"`
+	// SKU identifies the product or variant whose price changed.
+	SKU       string    `json:"sku" gorm:"index:idx_price_change"`
+	ProductID uuid.UUID `json:"product_id"`
+	// VariantID is uuid.Nil when the change refers to the product price.
+	VariantID uuid.UUID `json:"variant_id"`
+
+	Price         currency.BRL `json:"price"`
+	PreviousPrice currency.BRL `json:"previous_price"`
+	// Sales are the sale prices scheduled from ChangedAt until the next change, see EffectivePriceAt.
+	Sales []SalePrice `json:"sales" gorm:"serializer:json"`
+	// ChangedAt is the moment the new price became effective.
+	ChangedAt time.Time `json:"changed_at" gorm:"index:idx_price_change"`
+	// UserID is the actor responsible for the change.
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// SalePrice is a promotional price valid within a time window.
+type SalePrice struct {
+	Price currency.BRL `json:"price"`
+	// Start is inclusive, a zero value means the sale is already active.
+	Start time.Time `json:"start"`
+	// End is exclusive, a zero value means the sale has no end.
+	End time.Time `json:"end"`
+}
+
+// Equal reports whether both sales have the same price and window.
+func (s SalePrice) Equal(other SalePrice) bool {
+	return s.Price == other.Price && s.Start.Equal(other.Start) && s.End.Equal(other.End)
+}
+
+// Overlaps reports whether the sale is valid at some moment of the period [from, to).
+func (s SalePrice) Overlaps(from, to time.Time) bool {
+	return (s.Start.IsZero() || s.Start.Before(to)) && (s.End.IsZero() || s.End.After(from))
+}
+
+// ActiveAt reports whether the sale is valid at the given time.
+func (s SalePrice) ActiveAt(t time.Time) bool {
+	return (s.Start.IsZero() || !t.Before(s.Start)) && (s.End.IsZero() || t.Before(s.End))
+}
+
+// ListPriceOf returns the compare-at price when set, otherwise the regular price.
+func ListPriceOf(price, compareAt currency.BRL) currency.BRL {
+	if compareAt > price {
+		return compareAt
+	}
+	return price
+}
+
+// EffectivePriceAt returns the price at time t: the lowest active sale price, or the regular price when
+// no sale is active. When windows overlap the lowest price wins, so the resolution does not depend on the
+// order of the sales. A sale above the regular price is never applied.
+func EffectivePriceAt(price currency.BRL, sales []SalePrice, t time.Time) currency.BRL {
+	effective := price
+	for _, sale := range sales {
+		if sale.ActiveAt(t) && sale.Price < effective {
+			effective = sale.Price
+		}
+	}
+	return effective
+}
+
+// EffectivePriceAt returns the product price at time t.
+func (p *Product) EffectivePriceAt(t time.Time) currency.BRL {
+	return EffectivePriceAt(p.Price, p.Sales, t)
+}
+
+// EffectivePriceAt returns the variant price at time t.
+func (v *ProductVariant) EffectivePriceAt(t time.Time) currency.BRL {
+	return EffectivePriceAt(v.Price, v.Sales, t)
+}
+
+// ResolvePrices fills the ListPrice and EffectivePrice of the product and its variants at time t.
+func (p *Product) ResolvePrices(t time.Time) {
+	p.ListPrice = ListPriceOf(p.Price, p.CompareAtPrice)
+	p.EffectivePrice = p.EffectivePriceAt(t)
+	for i := range p.Variants {
+		v := &p.Variants[i]
+		v.ListPrice = ListPriceOf(v.Price, v.CompareAtPrice)
+		v.EffectivePrice = v.EffectivePriceAt(t)
+	}
+}
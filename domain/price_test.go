@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"testing"
+	"time"
+)
+
+func TestEffectivePriceAt(t *testing.T) {
+	base := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	sales := []domain.SalePrice{
+		{Price: 9000, Start: base, End: base.Add(10 * day)},
+		{Price: 8000, Start: base.Add(5 * day), End: base.Add(7 * day)},
+		{Price: 9500, Start: base.Add(20 * day)},
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected currency.BRL
+	}{
+		{"before any sale", base.Add(-time.Second), 10000},
+		{"start is inclusive", base, 9000},
+		{"overlapping windows use the lowest price", base.Add(6 * day), 8000},
+		{"end is exclusive", base.Add(7 * day), 9000},
+		{"between sales", base.Add(15 * day), 10000},
+		{"open ended sale", base.Add(365 * day), 9500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := domain.EffectivePriceAt(10000, sales, tt.at); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProduct_ResolvePrices(t *testing.T) {
+	now := time.Now()
+	product := &domain.Product{
+		Price:          10000,
+		CompareAtPrice: 12000,
+		Sales:          []domain.SalePrice{{Price: 7000, Start: now.Add(-time.Hour), End: now.Add(time.Hour)}},
+		Variants: []domain.ProductVariant{
+			{Price: 11000},
+		},
+	}
+
+	product.ResolvePrices(now)
+
+	if product.ListPrice != 12000 || product.EffectivePrice != 7000 {
+		t.Errorf("unexpected product prices: list %v, effective %v", product.ListPrice, product.EffectivePrice)
+	}
+	if product.Variants[0].ListPrice != 11000 || product.Variants[0].EffectivePrice != 11000 {
+		t.Errorf("unexpected variant prices: list %v, effective %v", product.Variants[0].ListPrice, product.Variants[0].EffectivePrice)
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// PriceList holds wholesale prices assigned to customer groups, such as B2B resellers.
+type PriceList struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_price_list"`
+	Namespace string    `json:"namespace" gorm:"index:idx_price_list"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name string `json:"name"`
+	// CustomerGroups lists the groups the price list applies to.
+	CustomerGroups []string `json:"customer_groups" gorm:"serializer:json"`
+	// Priority decides between price lists matching the same SKU, the highest wins.
+	Priority int `json:"priority"`
+	// Start and End bound the validity of the price list, zero values mean unbounded.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	Entries []PriceListEntry `json:"entries" gorm:"serializer:json"`
+}
+
+// PriceListEntry defines the quantity tiers of a SKU, which may be either a product or a variant SKU.
+type PriceListEntry struct {
+	SKU   string      `json:"sku"`
+	Tiers []PriceTier `json:"tiers"`
+}
+
+// PriceTier is the unit price applied from MinQuantity units on, until the next tier.
+// For example the tiers 1, 10 and 50 define the ranges 1–9, 10–49 and 50+.
+type PriceTier struct {
+	MinQuantity int64        `json:"min_quantity"`
+	Price       currency.BRL `json:"price"`
+}
+
+// ActiveAt reports whether the price list is valid at the given time.
+func (l *PriceList) ActiveAt(t time.Time) bool {
+	return (l.Start.IsZero() || !t.Before(l.Start)) && (l.End.IsZero() || t.Before(l.End))
+}
+
+// TierFor returns the tier of the SKU applicable to the quantity, false when the SKU has no such tier.
+func (l *PriceList) TierFor(sku string, quantity int64) (PriceTier, bool) {
+	var best PriceTier
+	found := false
+	for _, entry := range l.Entries {
+		if entry.SKU != sku {
+			continue
+		}
+		for _, tier := range entry.Tiers {
+			if tier.MinQuantity <= quantity && (!found || tier.MinQuantity > best.MinQuantity) {
+				best, found = tier, true
+			}
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// DataSubjectRequest is a request of a user, the data subject, exercising its rights under the LGPD (Lei 13.709/2018, art. 18):
+// the access to its personal data or their erasure. It must be answered before the Deadline.
+type DataSubjectRequest struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_data_subject_request"`
+	Namespace string    `json:"namespace" gorm:"index:idx_data_subject_request"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// UserID is the data subject.
+	UserID uuid.UUID                `json:"user_id" gorm:"index:idx_data_subject_request"`
+	Type   DataSubjectRequestType   `json:"type"`
+	Status DataSubjectRequestStatus `json:"status" gorm:"index:idx_data_subject_request"`
+	// RequestedBy is the user who opened the request, the data subject or the staff on its behalf.
+	RequestedBy uuid.UUID  `json:"requested_by"`
+	Deadline    time.Time  `json:"deadline"`
+	CompletedAt *time.Time `json:"completed_at"`
+	// ExportFile is the file holding the export of an access request.
+	ExportFile string `json:"export_file,omitempty"`
+	// Error is the reason the last processing failed.
+	Error string `json:"error,omitempty"`
+}
+
+type DataSubjectRequestType string
+
+const (
+	// DataSubjectRequestAccess exports every personal data of the user.
+	DataSubjectRequestAccess DataSubjectRequestType = "access"
+	// DataSubjectRequestErasure erases the personal data of the user, the financial records are kept
+	// with the user pseudonymized, as their retention is a legal obligation (art. 16, I).
+	DataSubjectRequestErasure DataSubjectRequestType = "erasure"
+)
+
+type DataSubjectRequestStatus string
+
+const (
+	DataSubjectRequestPending   DataSubjectRequestStatus = "pending"
+	DataSubjectRequestCompleted DataSubjectRequestStatus = "completed"
+	// DataSubjectRequestFailed requests can be processed again.
+	DataSubjectRequestFailed DataSubjectRequestStatus = "failed"
+)
+
+// Open reports whether the request is still to be answered.
+func (r *DataSubjectRequest) Open() bool {
+	return r.Status != DataSubjectRequestCompleted
+}
+
+// Overdue reports whether the request is still open after its deadline.
+func (r *DataSubjectRequest) Overdue(now time.Time) bool {
+	return r.Open() && now.After(r.Deadline)
+}
@@ -10,17 +10,52 @@ import (
 // Product represents a product entity with details such as ID, name, price, stock, and associated media and variants.
 type Product struct {
 	gorm.Model
-	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_product"` // ID is the primary key for the Product entity and is uniquely indexed.
-	Namespace string    `json:"namespace" gorm:"index:idx_product"`     // Namespace specifies the logical grouping in the multi-tenant system
-	CreatedAt time.Time `json:"created_at"`                             // CreatedAt indicates the timestamp when the entity was created, stored as a string in the JSON response.
-	UpdatedAt time.Time `json:"updated_at"`                             // UpdatedAt indicates the last time the entity was modified
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_product"`                          // ID is the primary key for the Product entity and is uniquely indexed.
+	Namespace string    `json:"namespace" gorm:"index:idx_product;uniqueIndex:idx_product_slug"` // Namespace specifies the logical grouping in the multi-tenant system
+	CreatedAt time.Time `json:"created_at"`                                                      // CreatedAt indicates the timestamp when the entity was created, stored as a string in the JSON response.
+	UpdatedAt time.Time `json:"updated_at"`                                                      // UpdatedAt indicates the last time the entity was modified
 
 	Title  string        `json:"title"`
 	Price  currency.BRL  `json:"price"`
 	Stock  int64         `json:"stock"`
 	Status ProductStatus `json:"status"`
+	// CompareAtPrice is the optional list price shown struck through, zero when the product has none.
+	CompareAtPrice currency.BRL `json:"compare_at_price"`
+	// Sales holds the scheduled sale prices, see EffectivePriceAt.
+	Sales []SalePrice `json:"sales" gorm:"serializer:json"`
+	// ListPrice and EffectivePrice are resolved by the catalog on read and are not persisted.
+	ListPrice      currency.BRL `json:"list_price" gorm:"-"`
+	EffectivePrice currency.BRL `json:"effective_price" gorm:"-"`
+	// Installments is the best installment plan of the effective price, resolved by the catalog on read.
+	Installments *currency.InstallmentPlan `json:"installments,omitempty" gorm:"-"`
+	// DisplayPrices is the effective price converted to the currencies requested on read, for display only.
+	DisplayPrices []DisplayPrice `json:"display_prices,omitempty" gorm:"-"`
+	// Type distinguishes simple products from bundles, an empty value means ProductTypeSimple.
+	Type ProductType `json:"type"`
+	// Bundle lists the components of a bundle product, it is nil for simple products.
+	Bundle *Bundle `json:"bundle,omitempty" gorm:"serializer:json"`
 	// SKU is the stock-keeping unit, a unique identifier for inventory tracking.
 	SKU string `json:"sku" gorm:"index:idx_product"`
+	// Slug is the URL friendly identifier of the product, unique within the namespace.
+	Slug string `json:"slug" gorm:"index:idx_product;uniqueIndex:idx_product_slug,where:slug <> ''"`
+	// Categories lists the category slugs the product belongs to.
+	Categories []string `json:"categories" gorm:"serializer:json"`
+	// Attributes holds free-form product attributes, such as brand or material.
+	Attributes map[string]string `json:"attributes" gorm:"serializer:json"`
+	// NCM is the Mercosur tax classification code of the goods, 8 digits, used to compute the taxes.
+	NCM string `json:"ncm"`
+	// Origin is the origin of the goods for the ICMS, see TaxOrigin.
+	Origin TaxOrigin `json:"origin"`
+	// CFOP is the fiscal operation code of the sales within the state, such as "5102" for goods bought from
+	// third parties, the default, or "5101" for goods of own production. See SaleCFOP.
+	CFOP string `json:"cfop"`
+	// Shipping holds the weight, dimensions and packaging rules used to quote the shipping.
+	Shipping ShippingProfile `json:"shipping" gorm:"embedded;embeddedPrefix:shipping_"`
+
+	ShortDesc string `json:"short_desc"`
+	HtmlDesc  string `json:"html_desc"`
+	TextDesc  string `json:"text_desc"`
+
 	// Medias represents a collection of associated media objects for a product, stored with many-to-many relationship mapping.
 	Medias []uuid.UUID `json:"medias"`
 	// Variants represents a collection of associated variant objects for a product, such as size or color options.
@@ -41,10 +76,23 @@ type ProductVariant struct {
 	ProductID uuid.UUID `json:"product_id" gorm:"index:idx_product_variant"`
 	// Title specifies the name of the product variant.
 	Title string `json:"title"`
+	// SKU is the stock-keeping unit of the variant.
+	SKU string `json:"sku" gorm:"index:idx_product_variant"`
 	// Price represents the cost of the product variant as a floating-point number.
 	Price currency.BRL `json:"price"`
+	// CompareAtPrice is the optional list price shown struck through, zero when the variant has none.
+	CompareAtPrice currency.BRL `json:"compare_at_price"`
+	// Sales holds the scheduled sale prices, see EffectivePriceAt.
+	Sales []SalePrice `json:"sales" gorm:"serializer:json"`
+	// ListPrice and EffectivePrice are resolved by the catalog on read and are not persisted.
+	ListPrice      currency.BRL `json:"list_price" gorm:"-"`
+	EffectivePrice currency.BRL `json:"effective_price" gorm:"-"`
+	// DisplayPrices is the effective price converted to the currencies requested on read, for display only.
+	DisplayPrices []DisplayPrice `json:"display_prices,omitempty" gorm:"-"`
 	// Stock indicates the quantity of this product variant available in inventory.
 	Stock int64 `json:"stock"`
+	// Shipping overrides the shipping profile of the product when set.
+	Shipping ShippingProfile `json:"shipping" gorm:"embedded;embeddedPrefix:shipping_"`
 	// Medias represents a collection of associated media for the product variant, using a many-to-many relationship.
 	Medias []uuid.UUID `json:"medias"`
 
@@ -71,6 +119,45 @@ const (
 	ProductStatusAvailable  ProductStatus = "available"
 )
 
+type ProductType string
+
+const (
+	ProductTypeSimple ProductType = "simple"
+	ProductTypeBundle ProductType = "bundle"
+)
+
+// IsBundle reports whether the product is a bundle of other products.
+func (p *Product) IsBundle() bool {
+	return p.Type == ProductTypeBundle
+}
+
+// Bundle describes a kit sold as a single product, such as a camera plus a lens plus a bag.
+// The bundle stock is derived from its components.
+type Bundle struct {
+	Components []BundleComponent `json:"components"`
+	PriceMode  BundlePriceMode   `json:"price_mode"`
+	// DiscountBps is the discount applied over the components price in basis points (1000 = 10%),
+	// used only with BundlePriceComputed.
+	DiscountBps int64 `json:"discount_bps"`
+}
+
+// BundleComponent references a product, or one of its variants, included in a bundle.
+type BundleComponent struct {
+	ProductID uuid.UUID `json:"product_id"`
+	// VariantID is optional, when set the variant price and stock are used instead of the product ones.
+	VariantID uuid.UUID `json:"variant_id"`
+	Quantity  int64     `json:"quantity"`
+}
+
+type BundlePriceMode string
+
+const (
+	// BundlePriceFixed uses the bundle Product.Price as is.
+	BundlePriceFixed BundlePriceMode = "fixed"
+	// BundlePriceComputed sums the components price and applies Bundle.DiscountBps.
+	BundlePriceComputed BundlePriceMode = "computed"
+)
+
 type ProductEvent string
 
 const (
@@ -79,4 +166,29 @@ const (
 	ProductDeleted     ProductEvent = "product.deleted"
 	ProductRestored    ProductEvent = "product.restored"
 	ProductStockUpdate ProductEvent = "product.stock.update"
+	ProductDuplicated  ProductEvent = "product.duplicated"
 )
+
+// ProductTemplate holds reusable defaults that a new product can start from.
+type ProductTemplate struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_product_template"`
+	Namespace string    `json:"namespace" gorm:"index:idx_product_template"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Name identifies the template for the merchandisers, it is not copied to the product.
+	Name string `json:"name"`
+
+	Title      string            `json:"title"`
+	Price      currency.BRL      `json:"price"`
+	Status     ProductStatus     `json:"status"`
+	Attributes map[string]string `json:"attributes" gorm:"serializer:json"`
+	Medias     []uuid.UUID       `json:"medias" gorm:"serializer:json"`
+	// Variants are copied with new identifiers to every product created from the template.
+	Variants []ProductVariant `json:"variants" gorm:"serializer:json"`
+
+	ShortDesc string `json:"short_desc"`
+	HtmlDesc  string `json:"html_desc"`
+	TextDesc  string `json:"text_desc"`
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Promotion is a discount rule, either applied automatically or unlocked by a coupon code.
+type Promotion struct {
+	gorm.Model
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;index:idx_promotion"`
+	Namespace string    `json:"namespace" gorm:"index:idx_promotion"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name string `json:"name"`
+	// Code is the coupon code unlocking the promotion, an empty code makes the promotion automatic.
+	Code   string `json:"code" gorm:"index:idx_promotion"`
+	Active bool   `json:"active"`
+	// Start and End bound the validity of the promotion, zero values mean unbounded.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	Conditions PromotionConditions `json:"conditions" gorm:"serializer:json"`
+	Action     PromotionAction     `json:"action" gorm:"serializer:json"`
+
+	// Priority orders the evaluation of the promotions, the highest first.
+	Priority int `json:"priority"`
+	// Exclusive promotions are never combined with other promotions.
+	Exclusive bool `json:"exclusive"`
+
+	// UsageLimit is the maximum number of redemptions, zero means unlimited.
+	UsageLimit int64 `json:"usage_limit"`
+	// UsageLimitPerCustomer is the maximum number of redemptions by the same customer, zero means unlimited.
+	UsageLimitPerCustomer int64 `json:"usage_limit_per_customer"`
+}
+
+// IsCoupon reports whether the promotion requires a coupon code.
+func (p *Promotion) IsCoupon() bool {
+	return p.Code != ""
+}
+
+// ActiveAt reports whether the promotion is enabled and valid at the given time.
+func (p *Promotion) ActiveAt(t time.Time) bool {
+	return p.Active && (p.Start.IsZero() || !t.Before(p.Start)) && (p.End.IsZero() || t.Before(p.End))
+}
+
+// PromotionConditions restricts when a promotion applies, empty conditions always match.
+type PromotionConditions struct {
+	// MinSubtotal is the minimum subtotal of the items before discounts.
+	MinSubtotal currency.BRL `json:"min_subtotal"`
+	// ProductIDs and Categories restrict the items eligible to the discount.
+	ProductIDs []uuid.UUID `json:"product_ids"`
+	Categories []string    `json:"categories"`
+	// CustomerGroups restricts the promotion to the customers of at least one of the groups.
+	CustomerGroups []string `json:"customer_groups"`
+}
+
+type PromotionActionType string
+
+const (
+	// PromotionPercentage discounts PercentBps of the eligible items.
+	PromotionPercentage PromotionActionType = "percentage"
+	// PromotionFixedAmount discounts Amount from the eligible items.
+	PromotionFixedAmount PromotionActionType = "fixed_amount"
+	// PromotionBuyXGetY gives GetQuantity units for free for every BuyQuantity units of the eligible items,
+	// the cheapest units are the free ones.
+	PromotionBuyXGetY PromotionActionType = "buy_x_get_y"
+	// PromotionFreeShipping discounts the whole shipping price.
+	PromotionFreeShipping PromotionActionType = "free_shipping"
+)
+
+// PromotionAction describes the discount granted by a promotion.
+type PromotionAction struct {
+	Type PromotionActionType `json:"type"`
+	// PercentBps is the percentage in basis points (1000 = 10%).
+	PercentBps  int64        `json:"percent_bps"`
+	Amount      currency.BRL `json:"amount"`
+	BuyQuantity int64        `json:"buy_quantity"`
+	GetQuantity int64        `json:"get_quantity"`
+}
+
+// PromotionUsage records a redemption of a promotion, used to enforce the usage limits.
+type PromotionUsage struct {
+	gorm.Model
+	ID          uuid.UUID `json:"id" gorm:"primaryKey"`
+	Namespace   string    `json:"namespace" gorm:"index:idx_promotion_usage"`
+	PromotionID uuid.UUID `json:"promotion_id" gorm:"index:idx_promotion_usage"`
+	CustomerID  uuid.UUID `json:"customer_id" gorm:"index:idx_promotion_usage"`
+	// OrderID is the order which redeemed the promotion.
+	OrderID    uuid.UUID    `json:"order_id"`
+	Discount   currency.BRL `json:"discount"`
+	RedeemedAt time.Time    `json:"redeemed_at"`
+}
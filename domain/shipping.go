@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+// ShippingProfile describes how a product, or one of its variants, is shipped.
+type ShippingProfile struct {
+	// Weight is the weight of a unit in its packaging, in grams.
+	Weight     int64      `json:"weight"`
+	Dimensions Dimensions `json:"dimensions" gorm:"embedded;embeddedPrefix:dimensions_"`
+	// ShipsAlone is true when the unit is shipped in its own packaging, such as the box of a TV,
+	// instead of being packed in a shipping box with other items.
+	ShipsAlone bool `json:"ships_alone"`
+	// KeepUpright is true when the unit may not be laid on its side, its height is kept when packing.
+	KeepUpright bool `json:"keep_upright"`
+}
+
+// IsZero reports whether the profile is not set, a variant without a profile ships as its product.
+func (p ShippingProfile) IsZero() bool {
+	return p.Weight == 0 && p.Dimensions.IsZero()
+}
+
+// Dimensions are the outer sizes of a unit or a package, or the inner sizes of a shipping box, in millimeters.
+type Dimensions struct {
+	Length int64 `json:"length"`
+	Width  int64 `json:"width"`
+	Height int64 `json:"height"`
+}
+
+// IsZero reports whether the dimensions are unknown.
+func (d Dimensions) IsZero() bool {
+	return d.Length == 0 && d.Width == 0 && d.Height == 0
+}
+
+// Valid reports whether the dimensions are either unknown or all positive.
+func (d Dimensions) Valid() bool {
+	return d.IsZero() || d.Length > 0 && d.Width > 0 && d.Height > 0
+}
+
+// Volume returns the volume in cubic millimeters.
+func (d Dimensions) Volume() int64 {
+	return d.Length * d.Width * d.Height
+}
+
+// Fits reports whether d fits in other, rotated as needed. When upright, the height is kept and only the
+// length and width may be swapped.
+func (d Dimensions) Fits(other Dimensions, upright bool) bool {
+	if upright {
+		if d.Height > other.Height {
+			return false
+		}
+		return fitsSorted([]int64{d.Length, d.Width}, []int64{other.Length, other.Width})
+	}
+	return fitsSorted([]int64{d.Length, d.Width, d.Height}, []int64{other.Length, other.Width, other.Height})
+}
+
+func fitsSorted(a, b []int64) bool {
+	slices.Sort(a)
+	slices.Sort(b)
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ShippingProfile returns the shipping profile of the product, or of its variant when set.
+func (p *Product) ShippingProfile(variantID uuid.UUID) ShippingProfile {
+	if variantID != uuid.Nil {
+		idx := slices.IndexFunc(p.Variants, func(v ProductVariant) bool { return v.ID == variantID })
+		if idx >= 0 && !p.Variants[idx].Shipping.IsZero() {
+			return p.Variants[idx].Shipping
+		}
+	}
+	return p.Shipping
+}
+
+// ShippingBox is a box the items are packed in.
+type ShippingBox struct {
+	Name string `json:"name"`
+	// Inner are the inner sizes of the box, Outer the sizes of the package once closed.
+	Inner Dimensions `json:"inner"`
+	Outer Dimensions `json:"outer"`
+	// Weight is the weight of the empty box and MaxWeight the maximum weight of its contents, in grams.
+	Weight    int64 `json:"weight"`
+	MaxWeight int64 `json:"max_weight"`
+}
+
+// Package is a parcel handed to the carrier.
+type Package struct {
+	// Box is the name of the shipping box, empty when the items ship in their own packaging.
+	Box        string     `json:"box,omitempty"`
+	Dimensions Dimensions `json:"dimensions"`
+	// Weight is the weight of the parcel, in grams.
+	Weight int64         `json:"weight"`
+	Items  []PackageItem `json:"items"`
+}
+
+// PackageItem is a quantity of a product, or one of its variants, in a package.
+type PackageItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	VariantID uuid.UUID `json:"variant_id"`
+	Quantity  int64     `json:"quantity"`
+}
+
+// BillableWeight returns the weight the carriers charge for, in grams: the largest of the weight and of
+// the volumetric weight, the volume in cubic centimeters divided by divisor in kilograms.
+// A divisor of 6000 is the usual one.
+func (p Package) BillableWeight(divisor int64) int64 {
+	if divisor <= 0 {
+		return p.Weight
+	}
+	// cm³ / divisor kg = mm³ / 1000 / divisor * 1000 g
+	volumetric := (p.Dimensions.Volume() + divisor - 1) / divisor
+	return max(p.Weight, volumetric)
+}
+
+// ShippingOption is a shipping service quoted for a destination.
+type ShippingOption struct {
+	// Provider is the name of the ShippingProvider quoting the option.
+	Provider string `json:"provider"`
+	// Service identifies the option among those of the provider, such as "sedex".
+	Service string       `json:"service"`
+	Name    string       `json:"name"`
+	Price   currency.BRL `json:"price"`
+	// DeliveryDays is the number of business days between the order and the delivery, handling included.
+	DeliveryDays      int       `json:"delivery_days"`
+	EstimatedDelivery time.Time `json:"estimated_delivery"`
+}
+
+// Code identifies the option across the providers, as "provider:service".
+func (o ShippingOption) Code() string {
+	return o.Provider + ":" + o.Service
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"slices"
+)
+
+// TaxOrigin is the origin of the goods of the NF-e "orig" field, it selects the 4% interstate ICMS rate
+// of the imported goods (Resolução do Senado Federal 13/2012).
+type TaxOrigin int
+
+const (
+	TaxOriginNational                 TaxOrigin = 0 // national goods
+	TaxOriginForeignDirectImport      TaxOrigin = 1 // foreign goods imported directly
+	TaxOriginForeignDomesticMarket    TaxOrigin = 2 // foreign goods bought in the domestic market
+	TaxOriginNationalImportAbove40    TaxOrigin = 3 // national goods with more than 40% and up to 70% of imported content
+	TaxOriginNationalBasicProcess     TaxOrigin = 4 // national goods made under the basic production processes
+	TaxOriginNationalImportUpTo40     TaxOrigin = 5 // national goods with up to 40% of imported content
+	TaxOriginForeignDirectNoSimilar   TaxOrigin = 6 // foreign goods imported directly, without a national similar
+	TaxOriginForeignDomesticNoSimilar TaxOrigin = 7 // foreign goods bought in the domestic market, without a national similar
+	TaxOriginNationalImportAbove70    TaxOrigin = 8 // national goods with more than 70% of imported content
+)
+
+// Valid reports whether the origin is one of the NF-e origins.
+func (o TaxOrigin) Valid() bool {
+	return o >= TaxOriginNational && o <= TaxOriginNationalImportAbove70
+}
+
+// Imported reports whether the interstate operations of the goods use the imported goods ICMS rate.
+func (o TaxOrigin) Imported() bool {
+	switch o {
+	case TaxOriginForeignDirectImport, TaxOriginForeignDomesticMarket, TaxOriginNationalImportAbove40, TaxOriginNationalImportAbove70:
+		return true
+	}
+	return false
+}
+
+// States lists the abbreviations (UF) of the Brazilian states and the Federal District.
+var States = []string{
+	"AC", "AL", "AM", "AP", "BA", "CE", "DF", "ES", "GO", "MA", "MG", "MS", "MT", "PA",
+	"PB", "PE", "PI", "PR", "RJ", "RN", "RO", "RR", "RS", "SC", "SE", "SP", "TO",
+}
+
+// ValidState reports whether uf is the abbreviation of a Brazilian state, such as "SP".
+func ValidState(uf string) bool {
+	return slices.Contains(States, uf)
+}
+
+// ValidNCM reports whether ncm is a well-formed NCM code (Nomenclatura Comum do Mercosul) of 8 digits.
+func ValidNCM(ncm string) bool {
+	if len(ncm) != 8 {
+		return false
+	}
+	for _, c := range ncm {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultCFOP is the CFOP of the sales of goods bought from third parties within the state.
+const DefaultCFOP = "5102"
+
+// ValidCFOP reports whether cfop is the CFOP of a sale within the state, 4 digits starting with 5.
+func ValidCFOP(cfop string) bool {
+	return len(cfop) == 4 && cfop[0] == '5' && ValidNCM(cfop+"0000")
+}
+
+// SaleCFOP returns the CFOP of a sale with the intrastate CFOP of the product, DefaultCFOP when empty.
+// The interstate sales use the 6xxx code, and the interstate sales to final consumers who are not ICMS
+// taxpayers use 6107 and 6108 instead of 6101 and 6102.
+func SaleCFOP(cfop string, interstate, contributor bool) string {
+	if cfop == "" {
+		cfop = DefaultCFOP
+	}
+	if !interstate {
+		return cfop
+	}
+	cfop = "6" + cfop[1:]
+	if !contributor {
+		switch cfop {
+		case "6101":
+			return "6107"
+		case "6102":
+			return "6108"
+		}
+	}
+	return cfop
+}
+
+// TaxAmount is a tax computed on a base with a rate.
+type TaxAmount struct {
+	Base   currency.BRL  `json:"base"`
+	Rate   currency.Rate `json:"rate"`
+	Amount currency.BRL  `json:"amount"`
+}
+
+// LineTax is the tax breakdown of an order line.
+type LineTax struct {
+	ProductID uuid.UUID `json:"product_id"`
+	VariantID uuid.UUID `json:"variant_id"`
+	NCM       string    `json:"ncm"`
+	Origin    TaxOrigin `json:"origin"`
+	// Value is the line total after discounts, including its share of the freight.
+	Value currency.BRL `json:"value"`
+
+	ICMS TaxAmount `json:"icms"`
+	// DIFAL is the ICMS due to the destination state on interstate sales to final consumers.
+	DIFAL  TaxAmount `json:"difal"`
+	IPI    TaxAmount `json:"ipi"`
+	PIS    TaxAmount `json:"pis"`
+	COFINS TaxAmount `json:"cofins"`
+}
+
+// Total returns the sum of the taxes of the line.
+func (l LineTax) Total() currency.BRL {
+	return l.ICMS.Amount + l.DIFAL.Amount + l.IPI.Amount + l.PIS.Amount + l.COFINS.Amount
+}
+
+// TaxBreakdown is the tax of an order, per line and in total. The totals are the sums of the rounded
+// line amounts, as required by the NF-e.
+type TaxBreakdown struct {
+	// RulesVersion identifies the rule table used in the calculation.
+	RulesVersion     string    `json:"rules_version"`
+	OriginState      string    `json:"origin_state"`
+	DestinationState string    `json:"destination_state"`
+	Lines            []LineTax `json:"lines"`
+
+	ICMS   currency.BRL `json:"icms"`
+	DIFAL  currency.BRL `json:"difal"`
+	IPI    currency.BRL `json:"ipi"`
+	PIS    currency.BRL `json:"pis"`
+	COFINS currency.BRL `json:"cofins"`
+	Total  currency.BRL `json:"total"`
+}
@@ -20,3 +20,10 @@ type User struct {
 	Name  string `json:"name"`
 	Email string `json:"email" gorm:"uniqueIndex;index:idx_user"`
 }
+
+// Anonymize erases the name and e-mail of the user. The e-mail stays unique, under the reserved ".invalid" domain.
+func (u *User) Anonymize(now time.Time) {
+	u.Name = ""
+	u.Email = "anonymized-" + u.ID.String() + "@anonymized.invalid"
+	u.UpdatedAt = now
+}
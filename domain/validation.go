@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validation codes are machine-readable identifiers for a single violation.
+const (
+	ValidationCodeLength   = "length"    // the value length is outside the allowed range
+	ValidationCodeMin      = "min"       // the value is below the allowed minimum
+	ValidationCodeMax      = "max"       // the value is above the allowed maximum
+	ValidationCodeRange    = "range"     // the value is outside the allowed range
+	ValidationCodeRatio    = "ratio"     // the ratio between related values is too large
+	ValidationCodeOneOf    = "one_of"    // the value is not in the list of allowed values
+	ValidationCodeNotFound = "not_found" // the referenced entity does not exist
+	ValidationCodeRequired = "required"  // the value is missing
+	ValidationCodeInvalid  = "invalid"   // the value is malformed
+)
+
+// FieldViolation describes a single invalid field.
+type FieldViolation struct {
+	// Field is the path of the offending field, such as "title" or "variants[2].price".
+	Field string `json:"field"`
+	// Code is a machine-readable identifier of the broken rule.
+	Code string `json:"code"`
+	// Params holds the rule parameters, such as the allowed minimum and maximum.
+	Params map[string]any `json:"params,omitempty"`
+	// Err is the sentinel error matching the violation, such as ErrInvalidProductTitle.
+	Err error `json:"-"`
+}
+
+func (v FieldViolation) Error() string {
+	if v.Err == nil {
+		return fmt.Sprintf("%s: %s", v.Field, v.Code)
+	}
+	return fmt.Sprintf("%s: %s (%s)", v.Field, v.Err, v.Code)
+}
+
+// ValidationError aggregates every violation found while validating an entity.
+// errors.Is matches any of the sentinel errors carried by its violations.
+type ValidationError struct {
+	Violations []FieldViolation `json:"violations"`
+}
+
+// Add records a new violation for the given field.
+func (e *ValidationError) Add(field, code string, err error, params map[string]any) {
+	e.Violations = append(e.Violations, FieldViolation{
+		Field:  field,
+		Code:   code,
+		Params: params,
+		Err:    err,
+	})
+}
+
+// Merge appends the violations of other, prefixing their fields with prefix (e.g. "variants[0]").
+func (e *ValidationError) Merge(prefix string, other *ValidationError) {
+	if other == nil {
+		return
+	}
+	for _, v := range other.Violations {
+		if prefix != "" {
+			v.Field = prefix + "." + v.Field
+		}
+		e.Violations = append(e.Violations, v)
+	}
+}
+
+// HasViolations reports whether at least one violation was recorded.
+func (e *ValidationError) HasViolations() bool {
+	return e != nil && len(e.Violations) > 0
+}
+
+// Fields returns the paths of all invalid fields, in the order they were recorded.
+func (e *ValidationError) Fields() []string {
+	fields := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		fields = append(fields, v.Field)
+	}
+	return fields
+}
+
+// Err returns the ValidationError as an error, or nil when no violation was recorded.
+func (e *ValidationError) Err() error {
+	if !e.HasViolations() {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Error())
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap exposes the sentinel errors of the violations to errors.Is and errors.As.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		if v.Err != nil {
+			errs = append(errs, v.Err)
+		}
+	}
+	return errs
+}
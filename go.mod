@@ -3,19 +3,24 @@ module github.com/HBeserra/GoShop
 go 1.24
 
 require (
+	github.com/aws/aws-sdk-go v1.55.5
+	github.com/caarlos0/env/v11 v11.3.1
 	github.com/docker/go-units v0.5.0
 	github.com/google/uuid v1.6.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/stretchr/testify v1.10.0
+	github.com/terminalstatic/go-xsd-validate v0.1.6
 	github.com/u2takey/ffmpeg-go v0.5.0
 	go.opentelemetry.io/otel v1.35.0
 	go.opentelemetry.io/otel/trace v1.35.0
 	go.uber.org/mock v0.5.1
 	golang.org/x/image v0.0.0-20191009234506-e7c1f5e7dbb8
+	golang.org/x/text v0.24.0
 	gorm.io/gorm v1.25.12
+	software.sslmate.com/src/go-pkcs12 v0.7.0
 )
 
 require (
-	github.com/aws/aws-sdk-go v1.55.5 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/go-logr/logr v1.4.2 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
@@ -26,6 +31,6 @@ require (
 	github.com/u2takey/go-utils v0.3.1 // indirect
 	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
 	go.opentelemetry.io/otel/metric v1.35.0 // indirect
-	golang.org/x/text v0.24.0 // indirect
+	golang.org/x/crypto v0.11.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
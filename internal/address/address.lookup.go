@@ -0,0 +1,121 @@
+package address
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"slices"
+	"time"
+)
+
+// Lookup returns the address of the CEP from the cache or the source, without number nor complement.
+func (s *Service) Lookup(ctx context.Context, cep domain.CEP) (*domain.Address, error) {
+
+	ctx, span := observability.StartSpan(ctx, "address.Lookup")
+	defer span.End()
+
+	if !cep.Valid() {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidCEP, string(cep))
+	}
+
+	now := time.Now()
+	if address, ok := s.cache.get(cep, now); ok {
+		return &address, nil
+	}
+
+	address, err := s.source.Lookup(ctx, cep)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.put(cep, *address, now)
+	return address, nil
+}
+
+// State returns the abbreviation of the state of the CEP. The CEPs missing from the source are located
+// by the CEP ranges of the states.
+func (s *Service) State(ctx context.Context, cep domain.CEP) (string, error) {
+	address, err := s.Lookup(ctx, cep)
+	if err == nil {
+		return address.State, nil
+	}
+	if !errors.Is(err, domain.ErrAddressNotFound) {
+		return "", err
+	}
+	state, ok := cep.State()
+	if !ok {
+		return "", fmt.Errorf("%w: %s is not assigned to a state", domain.ErrInvalidCEP, cep)
+	}
+	return state, nil
+}
+
+// Validate completes the address with the street, district, city, state and IBGE code of its CEP when
+// they are empty, and checks it. Every violation is returned as a *domain.ValidationError.
+func (s *Service) Validate(ctx context.Context, address *domain.Address) error {
+
+	ctx, span := observability.StartSpan(ctx, "address.Validate")
+	defer span.End()
+
+	verr := new(domain.ValidationError)
+
+	found, err := s.Lookup(ctx, address.CEP)
+	switch {
+	case errors.Is(err, domain.ErrInvalidCEP):
+		verr.Add("cep", domain.ValidationCodeInvalid, domain.ErrInvalidCEP, nil)
+	case errors.Is(err, domain.ErrAddressNotFound):
+		// the address is kept as entered, its state must still match the CEP
+		if state, ok := address.CEP.State(); ok && address.State != "" && address.State != state {
+			verr.Add("state", domain.ValidationCodeOneOf, domain.ErrInvalidState, map[string]any{"allowed": []string{state}})
+		}
+	case err != nil:
+		span.RecordError(err)
+		return err
+	default:
+		complete(address, found)
+		if address.State != found.State {
+			verr.Add("state", domain.ValidationCodeOneOf, domain.ErrInvalidState, map[string]any{"allowed": []string{found.State}})
+		}
+		if address.CityCode != found.CityCode {
+			verr.Add("city_code", domain.ValidationCodeOneOf, domain.ErrInvalidAddress, map[string]any{"allowed": []string{found.CityCode}})
+		}
+	}
+
+	for _, f := range []struct{ field, value string }{
+		{"street", address.Street},
+		{"number", address.Number},
+		{"city", address.City},
+	} {
+		if f.value == "" {
+			verr.Add(f.field, domain.ValidationCodeRequired, domain.ErrInvalidAddress, nil)
+		}
+	}
+	if !domain.ValidState(address.State) && !slices.ContainsFunc(verr.Violations, func(v domain.FieldViolation) bool { return v.Field == "state" }) {
+		verr.Add("state", domain.ValidationCodeOneOf, domain.ErrInvalidState, map[string]any{"allowed": domain.States})
+	}
+
+	return verr.Err()
+}
+
+// complete fills the empty fields of the address from the one found for its CEP.
+func complete(address, found *domain.Address) {
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&address.Street, found.Street},
+		{&address.District, found.District},
+		{&address.City, found.City},
+		{&address.State, found.State},
+		{&address.CityCode, found.CityCode},
+	} {
+		if *f.dst == "" {
+			*f.dst = f.src
+		}
+	}
+}
+
+// ClearCache drops the cached addresses, after the source was updated.
+func (s *Service) ClearCache() {
+	s.cache.clear()
+}
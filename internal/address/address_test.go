@@ -0,0 +1,160 @@
+package address_test
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/address"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDataset(t *testing.T) {
+	dataset, err := address.NewEmbeddedDataset()
+	require.NoError(t, err)
+
+	tests := []struct {
+		cep      domain.CEP
+		expected *domain.Address
+		err      error
+	}{
+		{
+			cep:      "01310100",
+			expected: &domain.Address{CEP: "01310100", Street: "Avenida Paulista", District: "Bela Vista", City: "São Paulo", State: "SP", CityCode: "3550308"},
+		},
+		{
+			cep:      "40060000",
+			expected: &domain.Address{CEP: "40060000", City: "Salvador", State: "BA", CityCode: "2927408"},
+		},
+		{cep: "29000000", err: domain.ErrAddressNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.cep), func(t *testing.T) {
+			got, err := dataset.Lookup(context.Background(), tt.cep)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestDataset_Reload(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "ceps.csv")
+	write := func(data string) {
+		require.NoError(t, os.WriteFile(name, []byte("cep,cep_end,street,district,city,state,city_code\n"+data), 0o644))
+	}
+
+	write("40000000,48999999,,,Bahia,BA,2927408\n")
+	dataset, err := address.NewDataset(os.DirFS(dir), "ceps.csv")
+	require.NoError(t, err)
+
+	// the narrowest range wins
+	write("40000000,48999999,,,Bahia,BA,2927408\n40000000,42499999,,,Salvador,BA,2927408\n")
+	require.NoError(t, dataset.Reload())
+	got, err := dataset.Lookup(context.Background(), "40060000")
+	require.NoError(t, err)
+	assert.Equal(t, "Salvador", got.City)
+
+	// an invalid file keeps the current dataset
+	for _, invalid := range []string{
+		"40000000,,Rua A,Centro,Salvador,SP,2927408\n",
+		"40000000,,Rua A,Centro,Salvador,BA,29274\n",
+		"40000000,39999999,,,Salvador,BA,2927408\n",
+		"4000000,,Rua A,Centro,Salvador,BA,2927408\n",
+	} {
+		write(invalid)
+		assert.ErrorIs(t, dataset.Reload(), domain.ErrInvalidAddressData, invalid)
+	}
+	got, err = dataset.Lookup(context.Background(), "40060000")
+	require.NoError(t, err)
+	assert.Equal(t, "Salvador", got.City)
+}
+
+func TestLookup_Cache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	source := NewMockAddressSource(ctrl)
+	svc := address.NewService(&address.Config{CacheSize: 1, CacheTTL: time.Hour}, source)
+
+	salvador := &domain.Address{CEP: "40060000", City: "Salvador", State: "BA", CityCode: "2927408"}
+	recife := &domain.Address{CEP: "50010000", City: "Recife", State: "PE", CityCode: "2611606"}
+	gomock.InOrder(
+		source.EXPECT().Lookup(gomock.Any(), salvador.CEP).Return(salvador, nil),
+		source.EXPECT().Lookup(gomock.Any(), recife.CEP).Return(recife, nil),
+		// evicted by the lookup of Recife
+		source.EXPECT().Lookup(gomock.Any(), salvador.CEP).Return(salvador, nil),
+	)
+
+	for _, cep := range []domain.CEP{salvador.CEP, salvador.CEP, recife.CEP, salvador.CEP} {
+		_, err := svc.Lookup(context.Background(), cep)
+		require.NoError(t, err)
+	}
+
+	_, err := svc.Lookup(context.Background(), "123")
+	assert.ErrorIs(t, err, domain.ErrInvalidCEP)
+}
+
+func TestState(t *testing.T) {
+	dataset, err := address.NewEmbeddedDataset()
+	require.NoError(t, err)
+	svc := address.NewService(&address.Config{}, dataset)
+
+	state, err := svc.State(context.Background(), "70150900")
+	require.NoError(t, err)
+	assert.Equal(t, "DF", state)
+
+	// missing from the dataset, located by the ranges of the states
+	state, err = svc.State(context.Background(), "29000000")
+	require.NoError(t, err)
+	assert.Equal(t, "ES", state)
+}
+
+func TestValidate(t *testing.T) {
+	dataset, err := address.NewEmbeddedDataset()
+	require.NoError(t, err)
+	svc := address.NewService(&address.Config{}, dataset)
+
+	tests := []struct {
+		name           string
+		address        domain.Address
+		expected       domain.Address
+		expectedFields []string
+	}{
+		{
+			name:     "completed from the CEP",
+			address:  domain.Address{CEP: "01310100", Number: "1000"},
+			expected: domain.Address{CEP: "01310100", Street: "Avenida Paulista", Number: "1000", District: "Bela Vista", City: "São Paulo", State: "SP", CityCode: "3550308"},
+		},
+		{
+			name:           "state of another CEP",
+			address:        domain.Address{CEP: "40060000", Street: "Avenida Sete de Setembro", Number: "10", City: "Salvador", State: "SP"},
+			expectedFields: []string{"state"},
+		},
+		{
+			name:     "CEP missing from the dataset",
+			address:  domain.Address{CEP: "29000000", Street: "Rua A", Number: "1", City: "Vitória", State: "ES"},
+			expected: domain.Address{CEP: "29000000", Street: "Rua A", Number: "1", City: "Vitória", State: "ES"},
+		},
+		{
+			name:           "invalid CEP",
+			address:        domain.Address{CEP: "2900", Street: "Rua A", City: "Vitória", State: "XX"},
+			expectedFields: []string{"cep", "number", "state"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.Validate(context.Background(), &tt.address)
+			if len(tt.expectedFields) == 0 {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, tt.address)
+				return
+			}
+			var verr *domain.ValidationError
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, tt.expectedFields, verr.Fields())
+		})
+	}
+}
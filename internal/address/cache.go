@@ -0,0 +1,74 @@
+package address
+
+import (
+	"container/list"
+	"github.com/HBeserra/GoShop/domain"
+	"sync"
+	"time"
+)
+
+// cache is a least recently used cache of the addresses by CEP, whose entries expire after a TTL.
+type cache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	order   *list.List // of *entry, most recently used first
+	entries map[domain.CEP]*list.Element
+}
+
+type entry struct {
+	cep     domain.CEP
+	address domain.Address
+	expires time.Time
+}
+
+// newCache returns a cache of size entries, nothing is cached when size is not positive.
+func newCache(size int, ttl time.Duration) *cache {
+	return &cache{size: size, ttl: ttl, order: list.New(), entries: make(map[domain.CEP]*list.Element)}
+}
+
+func (c *cache) get(cep domain.CEP, now time.Time) (domain.Address, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[cep]
+	if !ok {
+		return domain.Address{}, false
+	}
+	e := el.Value.(*entry)
+	if c.ttl > 0 && !now.Before(e.expires) {
+		c.order.Remove(el)
+		delete(c.entries, cep)
+		return domain.Address{}, false
+	}
+	c.order.MoveToFront(el)
+	return e.address, true
+}
+
+func (c *cache) put(cep domain.CEP, address domain.Address, now time.Time) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[cep]; ok {
+		c.order.Remove(el)
+	}
+	c.entries[cep] = c.order.PushFront(&entry{cep: cep, address: address, expires: now.Add(c.ttl)})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).cep)
+	}
+}
+
+// clear removes every entry, after the dataset is updated.
+func (c *cache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.entries)
+}
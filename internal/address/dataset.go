@@ -0,0 +1,187 @@
+package address
+
+import (
+	"cmp"
+	"context"
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"io"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed data/ceps.csv
+var embedded embed.FS
+
+// header is the header of the dataset files.
+var header = []string{"cep", "cep_end", "street", "district", "city", "state", "city_code"}
+
+// Dataset is an AddressSource loaded from a CSV file, such as
+//
+//	cep,cep_end,street,district,city,state,city_code
+//	01310100,,Avenida Paulista,Bela Vista,São Paulo,SP,3550308
+//	01000000,05999999,,,São Paulo,SP,3550308
+//
+// A row without cep_end is the address of a single CEP. A row with cep_end covers a range of CEPs, such as
+// the towns with a single CEP or the CEPs of a city missing from the file, and gives their city only.
+// The narrowest range is used when they overlap. A newer dataset published to the same file is
+// loaded with Reload.
+type Dataset struct {
+	fsys fs.FS
+	name string
+
+	mu     sync.RWMutex
+	exact  map[domain.CEP]domain.Address
+	ranges []cepRange
+}
+
+type cepRange struct {
+	from, to domain.CEP
+	address  domain.Address
+}
+
+// NewEmbeddedDataset loads the dataset shipped with the application, a sample of the main cities
+// meant to be replaced by a full dataset with Config.DatasetPath.
+func NewEmbeddedDataset() (*Dataset, error) {
+	return NewDataset(embedded, "data/ceps.csv")
+}
+
+// NewDataset loads the dataset of the named file.
+func NewDataset(fsys fs.FS, name string) (*Dataset, error) {
+	d := &Dataset{fsys: fsys, name: name}
+	if err := d.Reload(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Reload indexes the CSV again. A malformed row aborts it, the CEPs are then still looked up in the
+// dataset loaded before.
+func (d *Dataset) Reload() error {
+	f, err := d.fsys.Open(d.name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = len(header)
+	r.ReuseRecord = true
+
+	first, err := r.Read()
+	if err != nil || !slices.Equal(first, header) {
+		return fmt.Errorf("%w: %s: the header must be %v", domain.ErrInvalidAddressData, d.name, header)
+	}
+
+	exact := map[domain.CEP]domain.Address{}
+	var ranges []cepRange
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", domain.ErrInvalidAddressData, d.name, err)
+		}
+
+		address, end, err := parseRecord(record)
+		if err != nil {
+			return fmt.Errorf("%w: %s:%d: %w", domain.ErrInvalidAddressData, d.name, line, err)
+		}
+		if end == "" {
+			exact[address.CEP] = address
+			continue
+		}
+		ranges = append(ranges, cepRange{from: address.CEP, to: end, address: address})
+	}
+
+	// the narrowest ranges first
+	slices.SortStableFunc(ranges, func(a, b cepRange) int {
+		return cmp.Compare(a.width(), b.width())
+	})
+
+	d.mu.Lock()
+	d.exact, d.ranges = exact, ranges
+	d.mu.Unlock()
+	return nil
+}
+
+// parseRecord returns the address of a record and the end of its range.
+func parseRecord(record []string) (domain.Address, domain.CEP, error) {
+	cep, err := domain.ParseCEP(record[0])
+	if err != nil {
+		return domain.Address{}, "", err
+	}
+	var end domain.CEP
+	if record[1] != "" {
+		if end, err = domain.ParseCEP(record[1]); err != nil {
+			return domain.Address{}, "", err
+		}
+		if end < cep {
+			return domain.Address{}, "", fmt.Errorf("the range %s to %s is empty", cep, end)
+		}
+	}
+
+	address := domain.Address{
+		CEP:      cep,
+		Street:   record[2],
+		District: record[3],
+		City:     record[4],
+		State:    record[5],
+		CityCode: record[6],
+	}
+	if err := checkAddress(address); err != nil {
+		return domain.Address{}, "", err
+	}
+	if state, _ := end.State(); end != "" && state != address.State {
+		return domain.Address{}, "", fmt.Errorf("%w: %s is not in %s", domain.ErrInvalidState, end, address.State)
+	}
+	return address, end, nil
+}
+
+// checkAddress checks the city, state and IBGE code of an address, and that its CEP is in the state.
+func checkAddress(address domain.Address) error {
+	if address.City == "" {
+		return fmt.Errorf("%w: missing city", domain.ErrInvalidAddress)
+	}
+	if !domain.ValidState(address.State) {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidState, address.State)
+	}
+	if state, _ := address.CEP.State(); state != address.State {
+		return fmt.Errorf("%w: %s is not in %s", domain.ErrInvalidState, address.CEP, address.State)
+	}
+	if len(address.CityCode) != 7 || strings.Trim(address.CityCode, "0123456789") != "" {
+		return fmt.Errorf("%w: invalid city code %q", domain.ErrInvalidAddress, address.CityCode)
+	}
+	return nil
+}
+
+// width returns the number of CEPs of the range, minus one.
+func (r cepRange) width() int {
+	from, _ := strconv.Atoi(string(r.from))
+	to, _ := strconv.Atoi(string(r.to))
+	return to - from
+}
+
+func (d *Dataset) Lookup(_ context.Context, cep domain.CEP) (*domain.Address, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if address, ok := d.exact[cep]; ok {
+		return &address, nil
+	}
+	for _, r := range d.ranges {
+		if r.from <= cep && cep <= r.to {
+			address := r.address
+			address.CEP = cep
+			return &address, nil
+		}
+	}
+	return nil, domain.ErrAddressNotFound
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination mock_test.go --package address_test
+//
+
+// Package address_test is a generated GoMock package.
+package address_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/HBeserra/GoShop/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAddressSource is a mock of AddressSource interface.
+type MockAddressSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockAddressSourceMockRecorder is the mock recorder for MockAddressSource.
+type MockAddressSourceMockRecorder struct {
+	mock *MockAddressSource
+}
+
+// NewMockAddressSource creates a new mock instance.
+func NewMockAddressSource(ctrl *gomock.Controller) *MockAddressSource {
+	mock := &MockAddressSource{ctrl: ctrl}
+	mock.recorder = &MockAddressSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddressSource) EXPECT() *MockAddressSourceMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockAddressSource) Lookup(ctx context.Context, cep domain.CEP) (*domain.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", ctx, cep)
+	ret0, _ := ret[0].(*domain.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockAddressSourceMockRecorder) Lookup(ctx, cep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockAddressSource)(nil).Lookup), ctx, cep)
+}
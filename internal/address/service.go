@@ -0,0 +1,40 @@
+package address
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"time"
+)
+
+type Config struct {
+	// CacheSize is the number of addresses kept in memory, the least recently used are evicted first.
+	CacheSize int `env:"ADDRESS_CACHE_SIZE" envDefault:"10000"`
+	// CacheTTL is how long a looked up address is kept, so the dataset updates are picked up.
+	CacheTTL time.Duration `env:"ADDRESS_CACHE_TTL" envDefault:"24h"`
+	// DatasetPath is a CSV file replacing the embedded dataset, see Dataset.
+	DatasetPath string `env:"ADDRESS_DATASET"`
+}
+
+// AddressSource finds the address of a CEP, such as the local Dataset or a postal web service.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  address_test
+type AddressSource interface {
+
+	// Lookup returns the address of the CEP, without number nor complement, or domain.ErrAddressNotFound.
+	Lookup(ctx context.Context, cep domain.CEP) (*domain.Address, error)
+}
+
+// Service looks up and validates the Brazilian postal addresses.
+type Service struct {
+	config *Config
+	source AddressSource
+	cache  *cache
+}
+
+func NewService(config *Config, source AddressSource) *Service {
+	return &Service{
+		config: config,
+		source: source,
+		cache:  newCache(config.CacheSize, config.CacheTTL),
+	}
+}
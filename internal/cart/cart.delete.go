@@ -0,0 +1,21 @@
+package cart
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+)
+
+// Delete removes the cart, for instance once it was turned into an order.
+func (s *Service) Delete(ctx context.Context, namespace string, cartID uuid.UUID) error {
+
+	ctx, span := observability.StartSpan(ctx, "cart.Delete")
+	defer span.End()
+
+	cart, err := s.load(ctx, namespace, cartID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, namespace, cart.ID)
+}
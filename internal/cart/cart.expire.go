@@ -0,0 +1,27 @@
+package cart
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"log/slog"
+	"time"
+)
+
+// PurgeExpired removes the abandoned carts whose TTL elapsed, it is meant to run periodically.
+func (s *Service) PurgeExpired(ctx context.Context, namespace string) (int64, error) {
+
+	ctx, span := observability.StartSpan(ctx, "cart.PurgeExpired")
+	defer span.End()
+
+	removed, err := s.repo.DeleteExpired(ctx, namespace, time.Now())
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "expired carts purged",
+		"namespace", namespace,
+		"removed", removed,
+	)
+	return removed, nil
+}
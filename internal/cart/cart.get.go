@@ -0,0 +1,55 @@
+package cart
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Get returns the cart re-validated against the current catalog prices, status and stock.
+func (s *Service) Get(ctx context.Context, namespace string, cartID uuid.UUID) (*domain.Cart, error) {
+
+	ctx, span := observability.StartSpan(ctx, "cart.Get")
+	defer span.End()
+
+	cart, err := s.load(ctx, namespace, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.refresh(ctx, namespace, cart)
+}
+
+// load retrieves the cart and checks that it is not expired and that it belongs to the current user.
+// Anonymous carts are accessible by anyone knowing their ID.
+func (s *Service) load(ctx context.Context, namespace string, cartID uuid.UUID) (*domain.Cart, error) {
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.repo.GetByID(ctx, namespace, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cart.IsExpired(time.Now()) {
+		return nil, domain.ErrCartExpired
+	}
+
+	if !cart.IsAnonymous() && cart.UserID != userID {
+		return nil, domain.ErrUnauthorized
+	}
+
+	return cart, nil
+}
+
+// save renews the cart expiration and stores it.
+func (s *Service) save(ctx context.Context, namespace string, cart *domain.Cart) error {
+	now := time.Now()
+	cart.UpdatedAt = now
+	cart.ExpiresAt = now.Add(s.config.TTL)
+	return s.repo.Save(ctx, namespace, cart)
+}
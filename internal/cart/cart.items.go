@@ -0,0 +1,133 @@
+package cart
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+// AddItem adds the quantity of the product, or of its variant when variantID is not uuid.Nil, to the cart.
+func (s *Service) AddItem(ctx context.Context, namespace string, cartID, productID, variantID uuid.UUID, quantity int64) (*domain.Cart, error) {
+
+	ctx, span := observability.StartSpan(ctx, "cart.AddItem")
+	defer span.End()
+
+	if quantity < 1 {
+		return nil, domain.ErrInvalidQuantity
+	}
+
+	cart, err := s.load(ctx, namespace, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := indexOf(cart, productID, variantID)
+	added := idx < 0
+	if added {
+		cart.Items = append(cart.Items, domain.CartItem{ProductID: productID, VariantID: variantID})
+		idx = len(cart.Items) - 1
+	}
+	cart.Items[idx].Quantity += quantity
+
+	cart, err = s.saveItems(ctx, namespace, cart, idx)
+	if err != nil {
+		return nil, err
+	}
+	if added {
+		// the price of a new item is set by the re-validation and did not change for the customer
+		cart.Items[idx].Issues = slices.DeleteFunc(cart.Items[idx].Issues, func(issue domain.CartIssue) bool {
+			return issue == domain.CartIssuePriceChanged
+		})
+	}
+	return cart, nil
+}
+
+// UpdateItem sets the quantity of an item, a zero quantity removes it.
+func (s *Service) UpdateItem(ctx context.Context, namespace string, cartID, productID, variantID uuid.UUID, quantity int64) (*domain.Cart, error) {
+
+	ctx, span := observability.StartSpan(ctx, "cart.UpdateItem")
+	defer span.End()
+
+	if quantity < 0 {
+		return nil, domain.ErrInvalidQuantity
+	}
+	if quantity == 0 {
+		return s.RemoveItem(ctx, namespace, cartID, productID, variantID)
+	}
+
+	cart, err := s.load(ctx, namespace, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := indexOf(cart, productID, variantID)
+	if idx < 0 {
+		return nil, domain.ErrCartItemNotFound
+	}
+	cart.Items[idx].Quantity = quantity
+
+	return s.saveItems(ctx, namespace, cart, idx)
+}
+
+// RemoveItem removes the product, or its variant, from the cart.
+func (s *Service) RemoveItem(ctx context.Context, namespace string, cartID, productID, variantID uuid.UUID) (*domain.Cart, error) {
+
+	ctx, span := observability.StartSpan(ctx, "cart.RemoveItem")
+	defer span.End()
+
+	cart, err := s.load(ctx, namespace, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := indexOf(cart, productID, variantID)
+	if idx < 0 {
+		return nil, domain.ErrCartItemNotFound
+	}
+	cart.Items = slices.Delete(cart.Items, idx, idx+1)
+
+	return s.saveItems(ctx, namespace, cart, -1)
+}
+
+// saveItems re-validates and stores the cart. The item at index changed is rejected if it cannot be purchased,
+// so the customer never adds an unavailable product or more units than in stock.
+func (s *Service) saveItems(ctx context.Context, namespace string, cart *domain.Cart, changed int) (*domain.Cart, error) {
+	products, err := s.findProducts(ctx, namespace, cart.Items)
+	if err != nil {
+		return nil, err
+	}
+	return s.store(ctx, namespace, cart, products, changed)
+}
+
+// store re-validates the cart against the products and stores it, see saveItems.
+func (s *Service) store(ctx context.Context, namespace string, cart *domain.Cart, products map[uuid.UUID]*domain.Product, changed int) (*domain.Cart, error) {
+	revalidate(cart, products, time.Now())
+
+	if changed >= 0 {
+		for _, issue := range cart.Items[changed].Issues {
+			switch issue {
+			case domain.CartIssueUnavailable:
+				return nil, domain.ErrProductUnavailable
+			case domain.CartIssueInsufficientStock:
+				return nil, domain.ErrInsufficientStock
+			}
+		}
+	}
+
+	s.updateShipping(ctx, namespace, cart)
+
+	err := s.save(ctx, namespace, cart)
+	if err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+func indexOf(cart *domain.Cart, productID, variantID uuid.UUID) int {
+	return slices.IndexFunc(cart.Items, func(item domain.CartItem) bool {
+		return item.ProductID == productID && item.VariantID == variantID
+	})
+}
@@ -0,0 +1,121 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Merge moves the guest cart into the cart of the user who just logged in. The quantities of the items present
+// in both carts are summed, up to the stock. When the user has no cart yet, or an expired one, the guest cart
+// is assigned to the user and the expired cart removed.
+func (s *Service) Merge(ctx context.Context, namespace string, guestCartID uuid.UUID) (*domain.Cart, error) {
+
+	ctx, span := observability.StartSpan(ctx, "cart.Merge")
+	defer span.End()
+
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userID == uuid.Nil {
+		return nil, domain.ErrUnauthorized
+	}
+
+	guest, err := s.repo.GetByID(ctx, namespace, guestCartID)
+	if err != nil {
+		return nil, err
+	}
+	if !guest.IsAnonymous() {
+		return nil, domain.ErrUnauthorized
+	}
+
+	now := time.Now()
+	cart, err := s.repo.GetByUser(ctx, namespace, userID)
+	if errors.Is(err, domain.ErrCartNotFound) || (err == nil && cart.IsExpired(now)) {
+		guest.UserID = userID
+		if guest.IsExpired(now) {
+			guest.Items = nil
+		}
+		guest, err = s.saveItems(ctx, namespace, guest, -1)
+		if err != nil {
+			return nil, err
+		}
+		if cart != nil {
+			s.deleteMerged(ctx, namespace, cart)
+		}
+		return guest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var summed map[int]int64
+	if !guest.IsExpired(now) {
+		summed = mergeItems(cart, guest)
+	}
+
+	products, err := s.findProducts(ctx, namespace, cart.Items)
+	if err != nil {
+		return nil, err
+	}
+	capStock(cart, products, summed)
+
+	cart, err = s.store(ctx, namespace, cart, products, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	s.deleteMerged(ctx, namespace, guest)
+	return cart, nil
+}
+
+// deleteMerged removes the cart replaced by the merge, a failure is logged as the cart expires anyway.
+func (s *Service) deleteMerged(ctx context.Context, namespace string, cart *domain.Cart) {
+	err := s.repo.Delete(ctx, namespace, cart.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete merged cart",
+			"cart_id", cart.ID,
+			"error", err,
+		)
+	}
+}
+
+// mergeItems adds the guest items and coupons to the cart. It returns the items present in both carts,
+// by index, with their quantity in the cart before the merge.
+func mergeItems(cart, guest *domain.Cart) map[int]int64 {
+	summed := map[int]int64{}
+	for _, item := range guest.Items {
+		idx := indexOf(cart, item.ProductID, item.VariantID)
+		if idx < 0 {
+			cart.Items = append(cart.Items, item)
+			continue
+		}
+		summed[idx] = cart.Items[idx].Quantity
+		cart.Items[idx].Quantity += item.Quantity
+	}
+
+	for _, code := range guest.Coupons {
+		if !slices.Contains(cart.Coupons, code) {
+			cart.Coupons = append(cart.Coupons, code)
+		}
+	}
+	return summed
+}
+
+// capStock limits the summed quantities to the stock, but never below the quantity the user had before the
+// merge: the guest units which cannot be purchased are dropped.
+func capStock(cart *domain.Cart, products map[uuid.UUID]*domain.Product, summed map[int]int64) {
+	for idx, own := range summed {
+		item := &cart.Items[idx]
+		stock, ok := stockOf(products[item.ProductID], item.VariantID)
+		if ok && item.Quantity > stock {
+			item.Quantity = max(stock, own)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Create returns a new anonymous cart for guests, or the cart of the authenticated user creating it if needed.
+func (s *Service) Create(ctx context.Context, namespace string) (*domain.Cart, error) {
+
+	ctx, span := observability.StartSpan(ctx, "cart.Create")
+	defer span.End()
+
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID != uuid.Nil {
+		cart, err := s.repo.GetByUser(ctx, namespace, userID)
+		if err == nil && !cart.IsExpired(time.Now()) {
+			return s.refresh(ctx, namespace, cart)
+		}
+		if err != nil && !errors.Is(err, domain.ErrCartNotFound) {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	cart := &domain.Cart{
+		ID:        uuid.New(),
+		Namespace: namespace,
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    userID,
+		ExpiresAt: now.Add(s.config.TTL),
+	}
+
+	err = s.repo.Save(ctx, namespace, cart)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return cart, nil
+}
@@ -0,0 +1,50 @@
+package cart
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"time"
+)
+
+// SelectShipping sets the CEP the shipping of the cart is quoted to, with or without punctuation, and the chosen option, identified by
+// its code. An empty option only quotes the shipping, the customer choosing among the cart ShippingOptions.
+func (s *Service) SelectShipping(ctx context.Context, namespace string, cartID uuid.UUID, cep, option string) (*domain.Cart, error) {
+
+	ctx, span := observability.StartSpan(ctx, "cart.SelectShipping")
+	defer span.End()
+
+	if s.shipping == nil {
+		return nil, domain.ErrShippingUnavailable
+	}
+	destination, err := domain.ParseCEP(cep)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.load(ctx, namespace, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.findProducts(ctx, namespace, cart.Items)
+	if err != nil {
+		return nil, err
+	}
+	revalidate(cart, products, time.Now())
+
+	cart.ShippingCEP, cart.ShippingOption = destination, option
+	if err := s.quoteShipping(ctx, namespace, cart); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if _, ok := cart.SelectedShipping(); option != "" && !ok {
+		return nil, domain.ErrShippingOptionNotFound
+	}
+
+	if err := s.save(ctx, namespace, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
@@ -0,0 +1,527 @@
+package cart_test
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/cart"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestGet_Revalidate(t *testing.T) {
+	variantID := uuid.New()
+	available := &domain.Product{
+		ID:     uuid.New(),
+		SKU:    "MUG",
+		Title:  "Coffee mug",
+		Price:  currency.NewFromFloat(20),
+		Status: domain.ProductStatusAvailable,
+		Stock:  3,
+		Variants: []domain.ProductVariant{
+			{ID: variantID, SKU: "MUG-BLUE", Title: "Blue", Price: currency.NewFromFloat(25), Stock: 1},
+		},
+	}
+	archived := &domain.Product{ID: uuid.New(), Price: currency.NewFromFloat(10), Status: domain.ProductStatusOutOfStock}
+	removedID := uuid.New()
+
+	c := &domain.Cart{
+		ID:        uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+		Items: []domain.CartItem{
+			{ProductID: available.ID, Quantity: 2, UnitPrice: currency.NewFromFloat(18)},
+			{ProductID: available.ID, VariantID: variantID, Quantity: 2, UnitPrice: currency.NewFromFloat(25)},
+			{ProductID: archived.ID, Quantity: 1, UnitPrice: currency.NewFromFloat(10)},
+			{ProductID: removedID, Quantity: 1, UnitPrice: currency.NewFromFloat(5)},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockCartRepository(ctrl)
+	mockCatalog := NewMockProductCatalog(ctrl)
+	mockAuth := NewMockAuthService(ctrl)
+	service := cart.NewService(&cart.Config{TTL: time.Hour}, mockRepo, mockCatalog, mockAuth)
+
+	mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, nil)
+	mockRepo.EXPECT().GetByID(gomock.Any(), "ns", c.ID).Return(c, nil)
+	mockCatalog.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{available, archived}, nil)
+
+	got, err := service.Get(context.Background(), "ns", c.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.CartIssue{domain.CartIssuePriceChanged}, got.Items[0].Issues)
+	assert.Equal(t, currency.NewFromFloat(20), got.Items[0].UnitPrice)
+	assert.Equal(t, "MUG", got.Items[0].SKU)
+
+	assert.Equal(t, []domain.CartIssue{domain.CartIssueInsufficientStock}, got.Items[1].Issues)
+	assert.Equal(t, "MUG-BLUE", got.Items[1].SKU)
+	assert.Equal(t, "Coffee mug - Blue", got.Items[1].Title)
+
+	assert.Equal(t, []domain.CartIssue{domain.CartIssueUnavailable}, got.Items[2].Issues)
+	assert.Equal(t, []domain.CartIssue{domain.CartIssueUnavailable}, got.Items[3].Issues)
+
+	assert.True(t, got.HasBlockingIssues())
+	assert.Equal(t, currency.NewFromFloat(40), got.Subtotal, "only purchasable items are summed")
+}
+
+func TestGet_Errors(t *testing.T) {
+
+	type setupParams struct {
+		repoService *MockCartRepository
+		authService *MockAuthService
+	}
+
+	owner := uuid.New()
+	tests := []struct {
+		name  string
+		cart  *domain.Cart
+		setup func(t setupParams, cart *domain.Cart)
+		err   error
+	}{
+		{
+			name: "expired cart",
+			cart: &domain.Cart{ID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)},
+			setup: func(t setupParams, cart *domain.Cart) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", cart.ID).Return(cart, nil)
+			},
+			err: domain.ErrCartExpired,
+		},
+		{
+			name: "cart of another user",
+			cart: &domain.Cart{ID: uuid.New(), UserID: owner, ExpiresAt: time.Now().Add(time.Hour)},
+			setup: func(t setupParams, cart *domain.Cart) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", cart.ID).Return(cart, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name: "guest reading a user cart",
+			cart: &domain.Cart{ID: uuid.New(), UserID: owner, ExpiresAt: time.Now().Add(time.Hour)},
+			setup: func(t setupParams, cart *domain.Cart) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", cart.ID).Return(cart, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockCartRepository(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			service := cart.NewService(&cart.Config{TTL: time.Hour}, mockRepo, NewMockProductCatalog(ctrl), mockAuth)
+
+			tt.setup(setupParams{repoService: mockRepo, authService: mockAuth}, tt.cart)
+
+			_, err := service.Get(context.Background(), "ns", tt.cart.ID)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestAddItem(t *testing.T) {
+
+	type setupParams struct {
+		repoService    *MockCartRepository
+		catalogService *MockProductCatalog
+		authService    *MockAuthService
+	}
+
+	product := &domain.Product{
+		ID:     uuid.New(),
+		Price:  currency.NewFromFloat(20),
+		Status: domain.ProductStatusAvailable,
+		Stock:  5,
+	}
+	draft := &domain.Product{ID: uuid.New(), Price: currency.NewFromFloat(20), Status: domain.ProductStatusDraft, Stock: 5}
+
+	expectRead := func(t setupParams, cart *domain.Cart, product *domain.Product) {
+		t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, nil)
+		t.repoService.EXPECT().GetByID(gomock.Any(), "ns", cart.ID).Return(cart, nil)
+		t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+	}
+
+	tests := []struct {
+		name        string
+		items       []domain.CartItem
+		product     *domain.Product
+		quantity    int64
+		setup       func(t setupParams, cart *domain.Cart)
+		err         error
+		expectedQty int64
+	}{
+		{
+			name:     "new item",
+			product:  product,
+			quantity: 2,
+			setup: func(t setupParams, cart *domain.Cart) {
+				expectRead(t, cart, product)
+				t.repoService.EXPECT().Save(gomock.Any(), "ns", cart).Return(nil)
+			},
+			expectedQty: 2,
+		},
+		{
+			name:     "merges with the existing item",
+			items:    []domain.CartItem{{ProductID: product.ID, Quantity: 2, UnitPrice: product.Price}},
+			product:  product,
+			quantity: 3,
+			setup: func(t setupParams, cart *domain.Cart) {
+				expectRead(t, cart, product)
+				t.repoService.EXPECT().Save(gomock.Any(), "ns", cart).Return(nil)
+			},
+			expectedQty: 5,
+		},
+		{
+			name:     "more than in stock",
+			items:    []domain.CartItem{{ProductID: product.ID, Quantity: 4, UnitPrice: product.Price}},
+			product:  product,
+			quantity: 2,
+			setup: func(t setupParams, cart *domain.Cart) {
+				expectRead(t, cart, product)
+			},
+			err: domain.ErrInsufficientStock,
+		},
+		{
+			name:     "product not available for sale",
+			product:  draft,
+			quantity: 1,
+			setup: func(t setupParams, cart *domain.Cart) {
+				expectRead(t, cart, draft)
+			},
+			err: domain.ErrProductUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &domain.Cart{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute), Items: tt.items}
+
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockCartRepository(ctrl)
+			mockCatalog := NewMockProductCatalog(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			service := cart.NewService(&cart.Config{TTL: time.Hour}, mockRepo, mockCatalog, mockAuth)
+
+			tt.setup(setupParams{repoService: mockRepo, catalogService: mockCatalog, authService: mockAuth}, c)
+
+			got, err := service.AddItem(context.Background(), "ns", c.ID, tt.product.ID, uuid.Nil, tt.quantity)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got.Items, 1)
+			assert.Equal(t, tt.expectedQty, got.Items[0].Quantity)
+			assert.Empty(t, got.Items[0].Issues)
+			assert.True(t, got.ExpiresAt.After(time.Now().Add(59*time.Minute)), "the TTL is renewed")
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+
+	type setupParams struct {
+		repoService    *MockCartRepository
+		catalogService *MockProductCatalog
+		authService    *MockAuthService
+	}
+
+	userID := uuid.New()
+	product := &domain.Product{ID: uuid.New(), Price: currency.NewFromFloat(10), Status: domain.ProductStatusAvailable, Stock: 10}
+	other := &domain.Product{ID: uuid.New(), Price: currency.NewFromFloat(5), Status: domain.ProductStatusAvailable, Stock: 10}
+	scarce := &domain.Product{ID: uuid.New(), Price: currency.NewFromFloat(10), Status: domain.ProductStatusAvailable, Stock: 4}
+
+	tests := []struct {
+		name     string
+		guest    *domain.Cart
+		userCart *domain.Cart
+		setup    func(t setupParams, guest, userCart *domain.Cart)
+		err      error
+		expected func(t *testing.T, got, guest *domain.Cart)
+	}{
+		{
+			name: "sums the quantities and deletes the guest cart",
+			guest: &domain.Cart{
+				ID:        uuid.New(),
+				ExpiresAt: time.Now().Add(time.Hour),
+				Coupons:   []string{"WELCOME", "SALE10"},
+				Items: []domain.CartItem{
+					{ProductID: product.ID, Quantity: 2, UnitPrice: product.Price},
+					{ProductID: other.ID, Quantity: 1, UnitPrice: other.Price},
+				},
+			},
+			userCart: &domain.Cart{
+				ID:        uuid.New(),
+				UserID:    userID,
+				ExpiresAt: time.Now().Add(time.Hour),
+				Coupons:   []string{"SALE10"},
+				Items:     []domain.CartItem{{ProductID: product.ID, Quantity: 1, UnitPrice: product.Price}},
+			},
+			setup: func(t setupParams, guest, userCart *domain.Cart) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", guest.ID).Return(guest, nil)
+				t.repoService.EXPECT().GetByUser(gomock.Any(), "ns", userID).Return(userCart, nil)
+				t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product, other}, nil)
+				t.repoService.EXPECT().Save(gomock.Any(), "ns", userCart).Return(nil)
+				t.repoService.EXPECT().Delete(gomock.Any(), "ns", guest.ID).Return(nil)
+			},
+			expected: func(t *testing.T, got, guest *domain.Cart) {
+				require.Len(t, got.Items, 2)
+				assert.Equal(t, int64(3), got.Items[0].Quantity)
+				assert.Equal(t, int64(1), got.Items[1].Quantity)
+				assert.Equal(t, []string{"SALE10", "WELCOME"}, got.Coupons)
+				assert.Equal(t, currency.NewFromFloat(35), got.Subtotal)
+			},
+		},
+		{
+			name: "summed quantities are capped to the stock",
+			guest: &domain.Cart{
+				ID:        uuid.New(),
+				ExpiresAt: time.Now().Add(time.Hour),
+				Items:     []domain.CartItem{{ProductID: scarce.ID, Quantity: 3, UnitPrice: scarce.Price}},
+			},
+			userCart: &domain.Cart{
+				ID:        uuid.New(),
+				UserID:    userID,
+				ExpiresAt: time.Now().Add(time.Hour),
+				Items:     []domain.CartItem{{ProductID: scarce.ID, Quantity: 2, UnitPrice: scarce.Price}},
+			},
+			setup: func(t setupParams, guest, userCart *domain.Cart) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", guest.ID).Return(guest, nil)
+				t.repoService.EXPECT().GetByUser(gomock.Any(), "ns", userID).Return(userCart, nil)
+				t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{scarce}, nil)
+				t.repoService.EXPECT().Save(gomock.Any(), "ns", userCart).Return(nil)
+				t.repoService.EXPECT().Delete(gomock.Any(), "ns", guest.ID).Return(nil)
+			},
+			expected: func(t *testing.T, got, guest *domain.Cart) {
+				require.Len(t, got.Items, 1)
+				assert.Equal(t, int64(4), got.Items[0].Quantity)
+				assert.Empty(t, got.Items[0].Issues)
+			},
+		},
+		{
+			name: "the user quantity is kept when the stock is already short",
+			guest: &domain.Cart{
+				ID:        uuid.New(),
+				ExpiresAt: time.Now().Add(time.Hour),
+				Items:     []domain.CartItem{{ProductID: scarce.ID, Quantity: 1, UnitPrice: scarce.Price}},
+			},
+			userCart: &domain.Cart{
+				ID:        uuid.New(),
+				UserID:    userID,
+				ExpiresAt: time.Now().Add(time.Hour),
+				Items:     []domain.CartItem{{ProductID: scarce.ID, Quantity: 5, UnitPrice: scarce.Price}},
+			},
+			setup: func(t setupParams, guest, userCart *domain.Cart) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", guest.ID).Return(guest, nil)
+				t.repoService.EXPECT().GetByUser(gomock.Any(), "ns", userID).Return(userCart, nil)
+				t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{scarce}, nil)
+				t.repoService.EXPECT().Save(gomock.Any(), "ns", userCart).Return(nil)
+				t.repoService.EXPECT().Delete(gomock.Any(), "ns", guest.ID).Return(nil)
+			},
+			expected: func(t *testing.T, got, guest *domain.Cart) {
+				assert.Equal(t, int64(5), got.Items[0].Quantity)
+				assert.Equal(t, []domain.CartIssue{domain.CartIssueInsufficientStock}, got.Items[0].Issues)
+			},
+		},
+		{
+			name: "assigns the guest cart when the user has none",
+			guest: &domain.Cart{
+				ID:        uuid.New(),
+				ExpiresAt: time.Now().Add(time.Hour),
+				Items:     []domain.CartItem{{ProductID: product.ID, Quantity: 2, UnitPrice: product.Price}},
+			},
+			setup: func(t setupParams, guest, userCart *domain.Cart) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", guest.ID).Return(guest, nil)
+				t.repoService.EXPECT().GetByUser(gomock.Any(), "ns", userID).Return(nil, domain.ErrCartNotFound)
+				t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+				t.repoService.EXPECT().Save(gomock.Any(), "ns", guest).Return(nil)
+			},
+			expected: func(t *testing.T, got, guest *domain.Cart) {
+				assert.Equal(t, guest.ID, got.ID)
+				assert.Equal(t, userID, got.UserID)
+			},
+		},
+		{
+			name: "replaces and deletes an expired user cart",
+			guest: &domain.Cart{
+				ID:        uuid.New(),
+				ExpiresAt: time.Now().Add(time.Hour),
+				Items:     []domain.CartItem{{ProductID: product.ID, Quantity: 2, UnitPrice: product.Price}},
+			},
+			userCart: &domain.Cart{
+				ID:        uuid.New(),
+				UserID:    userID,
+				ExpiresAt: time.Now().Add(-time.Minute),
+				Items:     []domain.CartItem{{ProductID: other.ID, Quantity: 1, UnitPrice: other.Price}},
+			},
+			setup: func(t setupParams, guest, userCart *domain.Cart) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", guest.ID).Return(guest, nil)
+				t.repoService.EXPECT().GetByUser(gomock.Any(), "ns", userID).Return(userCart, nil)
+				t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+				t.repoService.EXPECT().Save(gomock.Any(), "ns", guest).Return(nil)
+				t.repoService.EXPECT().Delete(gomock.Any(), "ns", userCart.ID).Return(nil)
+			},
+			expected: func(t *testing.T, got, guest *domain.Cart) {
+				assert.Equal(t, guest.ID, got.ID)
+				assert.Equal(t, userID, got.UserID)
+				require.Len(t, got.Items, 1)
+				assert.Equal(t, product.ID, got.Items[0].ProductID)
+			},
+		},
+		{
+			name:  "guests cannot merge",
+			guest: &domain.Cart{ID: uuid.New()},
+			setup: func(t setupParams, guest, userCart *domain.Cart) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockCartRepository(ctrl)
+			mockCatalog := NewMockProductCatalog(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			service := cart.NewService(&cart.Config{TTL: time.Hour}, mockRepo, mockCatalog, mockAuth)
+
+			tt.setup(setupParams{repoService: mockRepo, catalogService: mockCatalog, authService: mockAuth}, tt.guest, tt.userCart)
+
+			got, err := service.Merge(context.Background(), "ns", tt.guest.ID)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			tt.expected(t, got, tt.guest)
+		})
+	}
+}
+
+func TestPurgeExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockCartRepository(ctrl)
+	service := cart.NewService(&cart.Config{TTL: time.Hour}, mockRepo, NewMockProductCatalog(ctrl), NewMockAuthService(ctrl))
+
+	mockRepo.EXPECT().DeleteExpired(gomock.Any(), "ns", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, before time.Time) (int64, error) {
+			assert.WithinDuration(t, time.Now(), before, time.Second)
+			return 4, nil
+		})
+
+	removed, err := service.PurgeExpired(context.Background(), "ns")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), removed)
+}
+
+func TestSelectShipping(t *testing.T) {
+
+	type setupParams struct {
+		repoService     *MockCartRepository
+		catalogService  *MockProductCatalog
+		authService     *MockAuthService
+		shippingService *MockShipping
+	}
+
+	product := &domain.Product{ID: uuid.New(), Price: currency.NewFromFloat(20), Status: domain.ProductStatusAvailable, Stock: 5}
+	options := []domain.ShippingOption{
+		{Provider: "table", Service: "pac", Price: currency.NewFromFloat(15)},
+		{Provider: "table", Service: "sedex", Price: currency.NewFromFloat(25)},
+	}
+
+	expectQuote := func(t setupParams, cart *domain.Cart, quoteErr error) {
+		t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, nil)
+		t.repoService.EXPECT().GetByID(gomock.Any(), "ns", cart.ID).Return(cart, nil)
+		t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+		t.shippingService.EXPECT().Quote(gomock.Any(), "ns", domain.CEP("40060000"), gomock.Len(1)).Return(options, quoteErr)
+	}
+
+	tests := []struct {
+		name          string
+		option        string
+		setup         func(t setupParams, cart *domain.Cart)
+		err           error
+		expectedTotal currency.BRL
+	}{
+		{
+			name: "quote only",
+			setup: func(t setupParams, cart *domain.Cart) {
+				expectQuote(t, cart, nil)
+				t.repoService.EXPECT().Save(gomock.Any(), "ns", cart).Return(nil)
+			},
+			expectedTotal: currency.NewFromFloat(40),
+		},
+		{
+			name:   "option chosen",
+			option: "table:sedex",
+			setup: func(t setupParams, cart *domain.Cart) {
+				expectQuote(t, cart, nil)
+				t.repoService.EXPECT().Save(gomock.Any(), "ns", cart).Return(nil)
+			},
+			expectedTotal: currency.NewFromFloat(65),
+		},
+		{
+			name:   "unknown option",
+			option: "table:express",
+			setup: func(t setupParams, cart *domain.Cart) {
+				expectQuote(t, cart, nil)
+			},
+			err: domain.ErrShippingOptionNotFound,
+		},
+		{
+			name: "no option to the CEP",
+			setup: func(t setupParams, cart *domain.Cart) {
+				expectQuote(t, cart, domain.ErrShippingUnavailable)
+			},
+			err: domain.ErrShippingUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &domain.Cart{
+				ID:        uuid.New(),
+				ExpiresAt: time.Now().Add(time.Hour),
+				Items:     []domain.CartItem{{ProductID: product.ID, Quantity: 2, UnitPrice: currency.NewFromFloat(20)}},
+			}
+
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockCartRepository(ctrl)
+			mockCatalog := NewMockProductCatalog(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			mockShipping := NewMockShipping(ctrl)
+			service := cart.NewService(&cart.Config{TTL: time.Hour}, mockRepo, mockCatalog, mockAuth)
+			service.SetShipping(mockShipping)
+
+			tt.setup(setupParams{
+				repoService:     mockRepo,
+				catalogService:  mockCatalog,
+				authService:     mockAuth,
+				shippingService: mockShipping,
+			}, c)
+
+			got, err := service.SelectShipping(context.Background(), "ns", c.ID, "40060-000", tt.option)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, got.ShippingRequired)
+			assert.Equal(t, options, got.ShippingOptions)
+			assert.Equal(t, tt.expectedTotal, got.Total)
+		})
+	}
+}
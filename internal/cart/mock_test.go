@@ -0,0 +1,235 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination mock_test.go --package cart_test
+//
+
+// Package cart_test is a generated GoMock package.
+package cart_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/HBeserra/GoShop/domain"
+	dto "github.com/HBeserra/GoShop/domain/dto"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCartRepository is a mock of CartRepository interface.
+type MockCartRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCartRepositoryMockRecorder is the mock recorder for MockCartRepository.
+type MockCartRepositoryMockRecorder struct {
+	mock *MockCartRepository
+}
+
+// NewMockCartRepository creates a new mock instance.
+func NewMockCartRepository(ctrl *gomock.Controller) *MockCartRepository {
+	mock := &MockCartRepository{ctrl: ctrl}
+	mock.recorder = &MockCartRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartRepository) EXPECT() *MockCartRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCartRepository) Delete(ctx context.Context, namespace string, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, namespace, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCartRepositoryMockRecorder) Delete(ctx, namespace, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCartRepository)(nil).Delete), ctx, namespace, id)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockCartRepository) DeleteExpired(ctx context.Context, namespace string, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, namespace, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockCartRepositoryMockRecorder) DeleteExpired(ctx, namespace, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockCartRepository)(nil).DeleteExpired), ctx, namespace, before)
+}
+
+// GetByID mocks base method.
+func (m *MockCartRepository) GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, namespace, id)
+	ret0, _ := ret[0].(*domain.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCartRepositoryMockRecorder) GetByID(ctx, namespace, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCartRepository)(nil).GetByID), ctx, namespace, id)
+}
+
+// GetByUser mocks base method.
+func (m *MockCartRepository) GetByUser(ctx context.Context, namespace string, userID uuid.UUID) (*domain.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, namespace, userID)
+	ret0, _ := ret[0].(*domain.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockCartRepositoryMockRecorder) GetByUser(ctx, namespace, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockCartRepository)(nil).GetByUser), ctx, namespace, userID)
+}
+
+// Save mocks base method.
+func (m *MockCartRepository) Save(ctx context.Context, namespace string, cart *domain.Cart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, namespace, cart)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockCartRepositoryMockRecorder) Save(ctx, namespace, cart any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCartRepository)(nil).Save), ctx, namespace, cart)
+}
+
+// MockProductCatalog is a mock of ProductCatalog interface.
+type MockProductCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductCatalogMockRecorder
+	isgomock struct{}
+}
+
+// MockProductCatalogMockRecorder is the mock recorder for MockProductCatalog.
+type MockProductCatalogMockRecorder struct {
+	mock *MockProductCatalog
+}
+
+// NewMockProductCatalog creates a new mock instance.
+func NewMockProductCatalog(ctrl *gomock.Controller) *MockProductCatalog {
+	mock := &MockProductCatalog{ctrl: ctrl}
+	mock.recorder = &MockProductCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductCatalog) EXPECT() *MockProductCatalogMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockProductCatalog) Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", ctx, namespace, filter)
+	ret0, _ := ret[0].([]*domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockProductCatalogMockRecorder) Lookup(ctx, namespace, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockProductCatalog)(nil).Lookup), ctx, namespace, filter)
+}
+
+// MockShipping is a mock of Shipping interface.
+type MockShipping struct {
+	ctrl     *gomock.Controller
+	recorder *MockShippingMockRecorder
+	isgomock struct{}
+}
+
+// MockShippingMockRecorder is the mock recorder for MockShipping.
+type MockShippingMockRecorder struct {
+	mock *MockShipping
+}
+
+// NewMockShipping creates a new mock instance.
+func NewMockShipping(ctrl *gomock.Controller) *MockShipping {
+	mock := &MockShipping{ctrl: ctrl}
+	mock.recorder = &MockShippingMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShipping) EXPECT() *MockShippingMockRecorder {
+	return m.recorder
+}
+
+// Quote mocks base method.
+func (m *MockShipping) Quote(ctx context.Context, namespace string, destination domain.CEP, items []domain.CartItem) ([]domain.ShippingOption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", ctx, namespace, destination, items)
+	ret0, _ := ret[0].([]domain.ShippingOption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockShippingMockRecorder) Quote(ctx, namespace, destination, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockShipping)(nil).Quote), ctx, namespace, destination, items)
+}
+
+// MockAuthService is a mock of AuthService interface.
+type MockAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthServiceMockRecorder is the mock recorder for MockAuthService.
+type MockAuthServiceMockRecorder struct {
+	mock *MockAuthService
+}
+
+// NewMockAuthService creates a new mock instance.
+func NewMockAuthService(ctrl *gomock.Controller) *MockAuthService {
+	mock := &MockAuthService{ctrl: ctrl}
+	mock.recorder = &MockAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// GetUserID mocks base method.
+func (m *MockAuthService) GetUserID(ctx context.Context) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserID", ctx)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserID indicates an expected call of GetUserID.
+func (mr *MockAuthServiceMockRecorder) GetUserID(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserID", reflect.TypeOf((*MockAuthService)(nil).GetUserID), ctx)
+}
@@ -0,0 +1,146 @@
+package cart
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/google/uuid"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// refresh re-validates the cart items against the catalog, quotes the shipping and computes the totals.
+func (s *Service) refresh(ctx context.Context, namespace string, cart *domain.Cart) (*domain.Cart, error) {
+	products, err := s.findProducts(ctx, namespace, cart.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	revalidate(cart, products, time.Now())
+	s.updateShipping(ctx, namespace, cart)
+	return cart, nil
+}
+
+// updateShipping quotes the shipping of the cart, a failed quote leaves the cart without shipping options
+// so the customer may still change the items or the CEP.
+func (s *Service) updateShipping(ctx context.Context, namespace string, cart *domain.Cart) {
+	if err := s.quoteShipping(ctx, namespace, cart); err != nil {
+		slog.WarnContext(ctx, "failed to quote cart shipping",
+			"cart_id", cart.ID,
+			"shipping_cep", cart.ShippingCEP,
+			"error", err,
+		)
+	}
+}
+
+// quoteShipping quotes the shipping of the purchasable items to the cart CEP, sets the price of the chosen
+// option and computes the cart total.
+func (s *Service) quoteShipping(ctx context.Context, namespace string, cart *domain.Cart) error {
+	cart.ShippingRequired, cart.ShippingOptions, cart.Shipping = s.shipping != nil, nil, 0
+	defer func() {
+		cart.Total = cart.Subtotal.Add(cart.Shipping)
+	}()
+
+	if s.shipping == nil || cart.ShippingCEP == "" {
+		return nil
+	}
+	items := slices.DeleteFunc(slices.Clone(cart.Items), func(item domain.CartItem) bool {
+		return !item.Purchasable()
+	})
+	if len(items) == 0 {
+		return nil
+	}
+
+	options, err := s.shipping.Quote(ctx, namespace, cart.ShippingCEP, items)
+	if err != nil {
+		return err
+	}
+	cart.ShippingOptions = options
+	if option, ok := cart.SelectedShipping(); ok {
+		cart.Shipping = option.Price
+	}
+	return nil
+}
+
+func (s *Service) findProducts(ctx context.Context, namespace string, items []domain.CartItem) (map[uuid.UUID]*domain.Product, error) {
+	products := make(map[uuid.UUID]*domain.Product, len(items))
+	if len(items) == 0 {
+		return products, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		if !slices.Contains(ids, item.ProductID) {
+			ids = append(ids, item.ProductID)
+		}
+	}
+
+	found, err := s.catalog.Lookup(ctx, namespace, dto.ProductFilter{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range found {
+		products[p.ID] = p
+	}
+	return products, nil
+}
+
+// revalidate refreshes every item from its product and flags the items that changed or cannot be purchased.
+// The subtotal only includes the purchasable items.
+func revalidate(cart *domain.Cart, products map[uuid.UUID]*domain.Product, now time.Time) {
+	cart.Subtotal = 0
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		item.Issues = nil
+
+		product, ok := products[item.ProductID]
+		if !ok || product.Status != domain.ProductStatusAvailable {
+			item.Issues = append(item.Issues, domain.CartIssueUnavailable)
+			continue
+		}
+
+		sku, title, price, stock := product.SKU, product.Title, product.EffectivePriceAt(now), product.Stock
+		if item.VariantID != uuid.Nil {
+			idx := slices.IndexFunc(product.Variants, func(v domain.ProductVariant) bool { return v.ID == item.VariantID })
+			if idx < 0 {
+				item.Issues = append(item.Issues, domain.CartIssueUnavailable)
+				continue
+			}
+			variant := &product.Variants[idx]
+			sku, title, price, stock = variant.SKU, product.Title+" - "+variant.Title, variant.EffectivePriceAt(now), variant.Stock
+		}
+
+		item.SKU = sku
+		item.Title = title
+		item.Categories = product.Categories
+
+		if item.UnitPrice != price {
+			item.Issues = append(item.Issues, domain.CartIssuePriceChanged)
+			item.UnitPrice = price
+		}
+
+		if stock < item.Quantity {
+			item.Issues = append(item.Issues, domain.CartIssueInsufficientStock)
+		}
+
+		if item.Purchasable() {
+			cart.Subtotal = cart.Subtotal.Add(item.Total())
+		}
+	}
+}
+
+// stockOf returns the stock of the product, or of its variant when variantID is not uuid.Nil.
+func stockOf(product *domain.Product, variantID uuid.UUID) (int64, bool) {
+	if product == nil {
+		return 0, false
+	}
+	if variantID == uuid.Nil {
+		return product.Stock, true
+	}
+	idx := slices.IndexFunc(product.Variants, func(v domain.ProductVariant) bool { return v.ID == variantID })
+	if idx < 0 {
+		return 0, false
+	}
+	return product.Variants[idx].Stock, true
+}
@@ -0,0 +1,76 @@
+package cart
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Config struct {
+	// TTL is how long a cart is kept after its last change.
+	TTL time.Duration `env:"CART_TTL" envDefault:"720h"`
+}
+
+// CartRepository defines an interface for managing the carts.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  cart_test
+type CartRepository interface {
+
+	// GetByID retrieves a cart by its unique identifier, returns domain.ErrCartNotFound if missing.
+	GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.Cart, error)
+
+	// GetByUser retrieves the cart of the user, returns domain.ErrCartNotFound if the user has none.
+	GetByUser(ctx context.Context, namespace string, userID uuid.UUID) (*domain.Cart, error)
+
+	// Save creates or updates the cart.
+	Save(ctx context.Context, namespace string, cart *domain.Cart) error
+
+	// Delete removes a cart by the provided UUID.
+	Delete(ctx context.Context, namespace string, id uuid.UUID) error
+
+	// DeleteExpired removes the carts expired before the given time and returns how many were removed.
+	DeleteExpired(ctx context.Context, namespace string, before time.Time) (int64, error)
+}
+
+// ProductCatalog gives access to the catalog products. The catalog ProductService should be used,
+// so the bundles price and stock are derived from their components.
+type ProductCatalog interface {
+	// Lookup returns the products matching the filter without checking the permissions of the user,
+	// the guests have none.
+	Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error)
+}
+
+// Shipping quotes the shipping of the cart items.
+type Shipping interface {
+	Quote(ctx context.Context, namespace string, destination domain.CEP, items []domain.CartItem) ([]domain.ShippingOption, error)
+}
+
+// AuthService returns information about the current command
+type AuthService interface {
+	// GetUserID retrieves the unique identifier (UUID) of the user from the provided context, uuid.Nil for guests.
+	GetUserID(ctx context.Context) (uuid.UUID, error)
+}
+
+type Service struct {
+	config   *Config
+	repo     CartRepository
+	catalog  ProductCatalog
+	auth     AuthService
+	shipping Shipping
+}
+
+func NewService(config *Config, repo CartRepository, catalog ProductCatalog, auth AuthService) *Service {
+	return &Service{
+		config:  config,
+		repo:    repo,
+		catalog: catalog,
+		auth:    auth,
+	}
+}
+
+// SetShipping sets the service quoting the shipping, the carts have no shipping options without it.
+func (s *Service) SetShipping(shipping Shipping) {
+	s.shipping = shipping
+}
@@ -0,0 +1,149 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"math"
+	"slices"
+)
+
+const maxDiscountBps = 10_000
+
+// bundleComponent is a bundle component resolved against the catalog.
+type bundleComponent struct {
+	domain.BundleComponent
+	price currency.BRL
+	stock int64
+}
+
+// resolveComponent loads the price and stock of a bundle component from its product or variant.
+func (s *ProductService) resolveComponent(ctx context.Context, namespace string, c domain.BundleComponent) (*bundleComponent, error) {
+	if c.Quantity < 1 {
+		return nil, fmt.Errorf("%w: component %s has quantity %d", domain.ErrInvalidBundle, c.ProductID, c.Quantity)
+	}
+
+	product, err := s.repo.GetByID(ctx, namespace, c.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsBundle() {
+		return nil, fmt.Errorf("%w: component %s is a bundle", domain.ErrInvalidBundle, c.ProductID)
+	}
+
+	if c.VariantID == uuid.Nil {
+		return &bundleComponent{BundleComponent: c, price: product.Price, stock: product.Stock}, nil
+	}
+
+	idx := slices.IndexFunc(product.Variants, func(v domain.ProductVariant) bool {
+		return v.ID == c.VariantID
+	})
+	if idx < 0 {
+		return nil, domain.ErrVariantNotFound
+	}
+	variant := product.Variants[idx]
+	return &bundleComponent{BundleComponent: c, price: variant.Price, stock: variant.Stock}, nil
+}
+
+// bundleStock returns how many bundles can be assembled with the components stock.
+func bundleStock(components []*bundleComponent) int64 {
+	if len(components) == 0 {
+		return 0
+	}
+	stock := int64(math.MaxInt64)
+	for _, c := range components {
+		stock = min(stock, max(c.stock, 0)/c.Quantity)
+	}
+	return stock
+}
+
+// bundlePrice returns the bundle price, either the fixed one or the sum of the components with the discount applied.
+func bundlePrice(bundle *domain.Product, components []*bundleComponent) currency.BRL {
+	if bundle.Bundle.PriceMode != domain.BundlePriceComputed {
+		return bundle.Price
+	}
+
+	var total currency.BRL
+	for _, c := range components {
+		total = total.Add(c.price.MulQuantity(c.Quantity))
+	}
+
+	// Round the discount half-up to the nearest cent
+	discount := (total.Cents()*bundle.Bundle.DiscountBps + maxDiscountBps/2) / maxDiscountBps
+	return total.Sub(currency.BRL(discount))
+}
+
+// deriveBundle sets the price and stock of the bundle from its components. It fails when a component cannot
+// be resolved, such as a deleted product.
+func (s *ProductService) deriveBundle(ctx context.Context, namespace string, product *domain.Product) error {
+	if !product.IsBundle() || product.Bundle == nil {
+		return nil
+	}
+
+	components := make([]*bundleComponent, 0, len(product.Bundle.Components))
+	for _, c := range product.Bundle.Components {
+		resolved, err := s.resolveComponent(ctx, namespace, c)
+		if err != nil {
+			return err
+		}
+		components = append(components, resolved)
+	}
+
+	product.Price = bundlePrice(product, components)
+	product.Stock = bundleStock(components)
+	return nil
+}
+
+// validateBundle checks the bundle configuration and returns the bundle price derived from the components,
+// the product price when it cannot be derived. The product is left unchanged, see deriveBundle.
+func (s *ProductService) validateBundle(ctx context.Context, namespace string, product *domain.Product, verr *domain.ValidationError) currency.BRL {
+	violations := len(verr.Violations)
+
+	if product.Bundle == nil || len(product.Bundle.Components) == 0 {
+		verr.Add("bundle.components", domain.ValidationCodeRequired, domain.ErrInvalidBundle, nil)
+		return product.Price
+	}
+
+	if !slices.Contains([]domain.BundlePriceMode{domain.BundlePriceFixed, domain.BundlePriceComputed}, product.Bundle.PriceMode) {
+		verr.Add("bundle.price_mode", domain.ValidationCodeOneOf, domain.ErrInvalidBundle, map[string]any{
+			"allowed": []domain.BundlePriceMode{domain.BundlePriceFixed, domain.BundlePriceComputed},
+		})
+	}
+
+	if product.Bundle.DiscountBps < 0 || product.Bundle.DiscountBps > maxDiscountBps {
+		verr.Add("bundle.discount_bps", domain.ValidationCodeRange, domain.ErrInvalidBundle, map[string]any{
+			"min": 0,
+			"max": maxDiscountBps,
+		})
+	}
+
+	components := make([]*bundleComponent, 0, len(product.Bundle.Components))
+	for i, c := range product.Bundle.Components {
+		field := fmt.Sprintf("bundle.components[%d]", i)
+		if c.Quantity < 1 {
+			verr.Add(field+".quantity", domain.ValidationCodeMin, domain.ErrInvalidBundle, map[string]any{"min": 1})
+			continue
+		}
+		if c.ProductID == product.ID {
+			verr.Add(field+".product_id", domain.ValidationCodeInvalid, domain.ErrInvalidBundle, nil)
+			continue
+		}
+
+		resolved, err := s.resolveComponent(ctx, namespace, c)
+		if err != nil {
+			verr.Add(field, domain.ValidationCodeNotFound, domain.ErrInvalidBundle, map[string]any{
+				"product_id": c.ProductID,
+				"variant_id": c.VariantID,
+			})
+			continue
+		}
+		components = append(components, resolved)
+	}
+
+	if len(verr.Violations) > violations {
+		return product.Price
+	}
+	return bundlePrice(product, components)
+}
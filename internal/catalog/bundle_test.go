@@ -0,0 +1,190 @@
+package catalog_test
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/internal/catalog"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+
+	camera := &domain.Product{ID: uuid.New(), Title: "Camera", Price: currency.NewFromFloat(2000), Stock: 10}
+	lens := &domain.Product{ID: uuid.New(), Title: "Lens", Price: currency.NewFromFloat(500), Stock: 100}
+	bag := &domain.Product{
+		ID:    uuid.New(),
+		Title: "Bag",
+		Variants: []domain.ProductVariant{
+			{ID: uuid.New(), Title: "Black", Price: currency.NewFromFloat(99.99), Stock: 3},
+		},
+	}
+
+	newKit := func(mode domain.BundlePriceMode) *domain.Product {
+		return &domain.Product{
+			ID:     uuid.New(),
+			Title:  "Camera Starter Kit",
+			Price:  currency.NewFromFloat(2000),
+			Status: domain.ProductStatusAvailable,
+			Type:   domain.ProductTypeBundle,
+			Bundle: &domain.Bundle{
+				PriceMode:   mode,
+				DiscountBps: 1000,
+				Components: []domain.BundleComponent{
+					{ProductID: camera.ID, Quantity: 1},
+					{ProductID: lens.ID, Quantity: 2},
+					{ProductID: bag.ID, VariantID: bag.Variants[0].ID, Quantity: 1},
+				},
+			},
+		}
+	}
+
+	setup := func(t *testing.T) (*catalog.ProductService, *MockProductRepository, *MockAuthService, *MockEventBus) {
+		ctrl := gomock.NewController(t)
+		mockRepo := NewMockProductRepository(ctrl)
+		mockAuth := NewMockAuthService(ctrl)
+		mockBus := NewMockEventBus(ctrl)
+		service, err := catalog.NewProductService(mockRepo, mockBus, mockAuth, NewMockMediaCtrl(ctrl), NewMockPriceHistoryRepository(ctrl))
+		require.NoError(t, err)
+
+		for _, p := range []*domain.Product{camera, lens, bag} {
+			mockRepo.EXPECT().GetByID(gomock.Any(), "ns", p.ID).Return(p, nil).AnyTimes()
+		}
+		return service, mockRepo, mockAuth, mockBus
+	}
+
+	find := func(t *testing.T, service *catalog.ProductService, mockRepo *MockProductRepository, mockAuth *MockAuthService, kit *domain.Product) *domain.Product {
+		userID := uuid.New()
+		mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+		mockAuth.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "product:read").Return(true, nil)
+		mockRepo.EXPECT().Find(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{kit}, nil)
+
+		products, err := service.Find(context.Background(), "ns", dto.ProductFilter{IDs: []uuid.UUID{kit.ID}})
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		return products[0]
+	}
+
+	t.Run("computed price and derived stock", func(t *testing.T) {
+		service, mockRepo, mockAuth, _ := setup(t)
+		kit := find(t, service, mockRepo, mockAuth, newKit(domain.BundlePriceComputed))
+
+		// (2000 + 2*500 + 99.99) - 10%
+		assert.Equal(t, currency.BRL(278999), kit.Price)
+		// limited by the 3 bags in stock
+		assert.Equal(t, int64(3), kit.Stock)
+	})
+
+	t.Run("fixed price", func(t *testing.T) {
+		service, mockRepo, mockAuth, _ := setup(t)
+		kit := find(t, service, mockRepo, mockAuth, newKit(domain.BundlePriceFixed))
+
+		assert.Equal(t, currency.NewFromFloat(2000), kit.Price)
+	})
+
+	t.Run("validation leaves the bundle unchanged", func(t *testing.T) {
+		service, _, _, _ := setup(t)
+		kit := newKit(domain.BundlePriceComputed)
+
+		err := service.Validate(context.Background(), "ns", kit)
+		require.NoError(t, err)
+		assert.Equal(t, currency.NewFromFloat(2000), kit.Price)
+		assert.Zero(t, kit.Stock)
+	})
+
+	t.Run("unresolvable bundle is unavailable", func(t *testing.T) {
+		service, mockRepo, mockAuth, _ := setup(t)
+		deleted := uuid.New()
+		mockRepo.EXPECT().GetByID(gomock.Any(), "ns", deleted).Return(nil, domain.ErrProductNotFound)
+
+		missing := newKit(domain.BundlePriceComputed)
+		missing.Stock = 5
+		missing.Bundle.Components = append(missing.Bundle.Components, domain.BundleComponent{ProductID: deleted, Quantity: 1})
+		kit := find(t, service, mockRepo, mockAuth, missing)
+		assert.Equal(t, domain.ProductStatusOutOfStock, kit.Status)
+		assert.Zero(t, kit.Stock)
+
+		// a stored component quantity of zero is rejected instead of dividing the stock by it
+		zero := newKit(domain.BundlePriceComputed)
+		zero.Bundle.Components[0].Quantity = 0
+		kit = find(t, service, mockRepo, mockAuth, zero)
+		assert.Equal(t, domain.ProductStatusOutOfStock, kit.Status)
+		assert.Zero(t, kit.Stock)
+	})
+
+	t.Run("invalid components", func(t *testing.T) {
+		service, mockRepo, _, _ := setup(t)
+		missing := uuid.New()
+		mockRepo.EXPECT().GetByID(gomock.Any(), "ns", missing).Return(nil, domain.ErrProductNotFound)
+
+		kit := newKit(domain.BundlePriceComputed)
+		kit.Bundle.Components[1].Quantity = 0
+		kit.Bundle.Components = append(kit.Bundle.Components, domain.BundleComponent{ProductID: missing, Quantity: 1})
+
+		err := service.Validate(context.Background(), "ns", kit)
+
+		var verr *domain.ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.ErrorIs(t, err, domain.ErrInvalidBundle)
+		assert.Equal(t, []string{"bundle.components[1].quantity", "bundle.components[3]"}, verr.Fields())
+	})
+
+	t.Run("selling decrements the components", func(t *testing.T) {
+		service, mockRepo, mockAuth, mockBus := setup(t)
+		kit := newKit(domain.BundlePriceComputed)
+		mockRepo.EXPECT().GetByID(gomock.Any(), "ns", kit.ID).Return(kit, nil)
+		mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
+		mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), "ns", "product:stock").Return(true, nil)
+
+		gomock.InOrder(
+			mockRepo.EXPECT().AdjustStock(gomock.Any(), "ns", camera.ID, uuid.Nil, int64(-2)).Return(int64(8), nil),
+			mockRepo.EXPECT().AdjustStock(gomock.Any(), "ns", lens.ID, uuid.Nil, int64(-4)).Return(int64(96), nil),
+			mockRepo.EXPECT().AdjustStock(gomock.Any(), "ns", bag.ID, bag.Variants[0].ID, int64(-2)).Return(int64(1), nil),
+		)
+		mockBus.EXPECT().Publish(gomock.Any(), "product:stock.updated", gomock.Any()).Return(nil).Times(3)
+
+		err := service.AdjustStock(context.Background(), "ns", kit.ID, uuid.Nil, -2)
+		assert.NoError(t, err)
+	})
+
+	t.Run("checkout moves the stock without permission", func(t *testing.T) {
+		service, mockRepo, _, mockBus := setup(t)
+		kit := newKit(domain.BundlePriceComputed)
+		mockRepo.EXPECT().GetByID(gomock.Any(), "ns", kit.ID).Return(kit, nil)
+		mockRepo.EXPECT().AdjustStock(gomock.Any(), "ns", gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(3)
+		mockBus.EXPECT().Publish(gomock.Any(), "product:stock.updated", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, event interface{}) error {
+				assert.Equal(t, uuid.Nil, event.(events.ProductStockUpdated).UpdatedBy)
+				return nil
+			}).Times(3)
+
+		err := service.MoveStock(context.Background(), "ns", kit.ID, uuid.Nil, -1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("insufficient component stock reverts the others", func(t *testing.T) {
+		service, mockRepo, mockAuth, mockBus := setup(t)
+		kit := newKit(domain.BundlePriceComputed)
+		mockRepo.EXPECT().GetByID(gomock.Any(), "ns", kit.ID).Return(kit, nil)
+		mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
+		mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), "ns", "product:stock").Return(true, nil)
+
+		gomock.InOrder(
+			mockRepo.EXPECT().AdjustStock(gomock.Any(), "ns", camera.ID, uuid.Nil, int64(-5)).Return(int64(5), nil),
+			mockRepo.EXPECT().AdjustStock(gomock.Any(), "ns", lens.ID, uuid.Nil, int64(-10)).Return(int64(90), nil),
+			mockRepo.EXPECT().AdjustStock(gomock.Any(), "ns", bag.ID, bag.Variants[0].ID, int64(-5)).Return(int64(0), domain.ErrInsufficientStock),
+			mockRepo.EXPECT().AdjustStock(gomock.Any(), "ns", camera.ID, uuid.Nil, int64(5)).Return(int64(10), nil),
+			mockRepo.EXPECT().AdjustStock(gomock.Any(), "ns", lens.ID, uuid.Nil, int64(10)).Return(int64(100), nil),
+		)
+		mockBus.EXPECT().Publish(gomock.Any(), "product:stock.updated", gomock.Any()).Return(nil).Times(2)
+
+		err := service.AdjustStock(context.Background(), "ns", kit.ID, uuid.Nil, -5)
+		assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+	})
+}
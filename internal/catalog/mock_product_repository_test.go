@@ -12,9 +12,11 @@ package catalog_test
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/HBeserra/GoShop/domain"
 	dto "github.com/HBeserra/GoShop/domain/dto"
+	currency "github.com/HBeserra/GoShop/pkg/currency"
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -43,6 +45,35 @@ func (m *MockProductRepository) EXPECT() *MockProductRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddProductLog mocks base method.
+func (m *MockProductRepository) AddProductLog(ctx context.Context, entry *domain.ProductLogEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddProductLog", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddProductLog indicates an expected call of AddProductLog.
+func (mr *MockProductRepositoryMockRecorder) AddProductLog(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProductLog", reflect.TypeOf((*MockProductRepository)(nil).AddProductLog), ctx, entry)
+}
+
+// AdjustStock mocks base method.
+func (m *MockProductRepository) AdjustStock(ctx context.Context, namespace string, productID, variantID uuid.UUID, delta int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustStock", ctx, namespace, productID, variantID, delta)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustStock indicates an expected call of AdjustStock.
+func (mr *MockProductRepositoryMockRecorder) AdjustStock(ctx, namespace, productID, variantID, delta any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustStock", reflect.TypeOf((*MockProductRepository)(nil).AdjustStock), ctx, namespace, productID, variantID, delta)
+}
+
 // Create mocks base method.
 func (m *MockProductRepository) Create(ctx context.Context, namespace string, product *domain.Product) error {
 	m.ctrl.T.Helper()
@@ -57,6 +88,20 @@ func (mr *MockProductRepositoryMockRecorder) Create(ctx, namespace, product any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductRepository)(nil).Create), ctx, namespace, product)
 }
 
+// CreateTemplate mocks base method.
+func (m *MockProductRepository) CreateTemplate(ctx context.Context, namespace string, template *domain.ProductTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplate", ctx, namespace, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTemplate indicates an expected call of CreateTemplate.
+func (mr *MockProductRepositoryMockRecorder) CreateTemplate(ctx, namespace, template any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplate", reflect.TypeOf((*MockProductRepository)(nil).CreateTemplate), ctx, namespace, template)
+}
+
 // Delete mocks base method.
 func (m *MockProductRepository) Delete(ctx context.Context, namespace string, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -71,6 +116,20 @@ func (mr *MockProductRepositoryMockRecorder) Delete(ctx, namespace, id any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductRepository)(nil).Delete), ctx, namespace, id)
 }
 
+// DeleteTemplate mocks base method.
+func (m *MockProductRepository) DeleteTemplate(ctx context.Context, namespace string, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplate", ctx, namespace, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplate indicates an expected call of DeleteTemplate.
+func (mr *MockProductRepositoryMockRecorder) DeleteTemplate(ctx, namespace, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplate", reflect.TypeOf((*MockProductRepository)(nil).DeleteTemplate), ctx, namespace, id)
+}
+
 // Find mocks base method.
 func (m *MockProductRepository) Find(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +145,21 @@ func (mr *MockProductRepositoryMockRecorder) Find(ctx, namespace, filter any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockProductRepository)(nil).Find), ctx, namespace, filter)
 }
 
+// FindTemplates mocks base method.
+func (m *MockProductRepository) FindTemplates(ctx context.Context, namespace string) ([]*domain.ProductTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTemplates", ctx, namespace)
+	ret0, _ := ret[0].([]*domain.ProductTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTemplates indicates an expected call of FindTemplates.
+func (mr *MockProductRepositoryMockRecorder) FindTemplates(ctx, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTemplates", reflect.TypeOf((*MockProductRepository)(nil).FindTemplates), ctx, namespace)
+}
+
 // GetByID mocks base method.
 func (m *MockProductRepository) GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.Product, error) {
 	m.ctrl.T.Helper()
@@ -116,6 +190,21 @@ func (mr *MockProductRepositoryMockRecorder) GetProductLog(ctx, filter any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductLog", reflect.TypeOf((*MockProductRepository)(nil).GetProductLog), ctx, filter)
 }
 
+// GetTemplateByID mocks base method.
+func (m *MockProductRepository) GetTemplateByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.ProductTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateByID", ctx, namespace, id)
+	ret0, _ := ret[0].(*domain.ProductTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateByID indicates an expected call of GetTemplateByID.
+func (mr *MockProductRepositoryMockRecorder) GetTemplateByID(ctx, namespace, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateByID", reflect.TypeOf((*MockProductRepository)(nil).GetTemplateByID), ctx, namespace, id)
+}
+
 // Restore mocks base method.
 func (m *MockProductRepository) Restore(ctx context.Context, namespace string, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -144,6 +233,79 @@ func (mr *MockProductRepositoryMockRecorder) Update(ctx, namespace, product any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductRepository)(nil).Update), ctx, namespace, product)
 }
 
+// MockPriceHistoryRepository is a mock of PriceHistoryRepository interface.
+type MockPriceHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceHistoryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPriceHistoryRepositoryMockRecorder is the mock recorder for MockPriceHistoryRepository.
+type MockPriceHistoryRepositoryMockRecorder struct {
+	mock *MockPriceHistoryRepository
+}
+
+// NewMockPriceHistoryRepository creates a new mock instance.
+func NewMockPriceHistoryRepository(ctrl *gomock.Controller) *MockPriceHistoryRepository {
+	mock := &MockPriceHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockPriceHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceHistoryRepository) EXPECT() *MockPriceHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// LastBefore mocks base method.
+func (m *MockPriceHistoryRepository) LastBefore(ctx context.Context, namespace, sku string, t time.Time) (*domain.PriceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastBefore", ctx, namespace, sku, t)
+	ret0, _ := ret[0].(*domain.PriceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastBefore indicates an expected call of LastBefore.
+func (mr *MockPriceHistoryRepositoryMockRecorder) LastBefore(ctx, namespace, sku, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastBefore", reflect.TypeOf((*MockPriceHistoryRepository)(nil).LastBefore), ctx, namespace, sku, t)
+}
+
+// Record mocks base method.
+func (m *MockPriceHistoryRepository) Record(ctx context.Context, namespace string, changes ...*domain.PriceChange) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, namespace}
+	for _, a := range changes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Record", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockPriceHistoryRepositoryMockRecorder) Record(ctx, namespace any, changes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, namespace}, changes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockPriceHistoryRepository)(nil).Record), varargs...)
+}
+
+// Timeline mocks base method.
+func (m *MockPriceHistoryRepository) Timeline(ctx context.Context, namespace string, filter dto.PriceHistoryFilter) ([]*domain.PriceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeline", ctx, namespace, filter)
+	ret0, _ := ret[0].([]*domain.PriceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Timeline indicates an expected call of Timeline.
+func (mr *MockPriceHistoryRepositoryMockRecorder) Timeline(ctx, namespace, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeline", reflect.TypeOf((*MockPriceHistoryRepository)(nil).Timeline), ctx, namespace, filter)
+}
+
 // MockEventBus is a mock of EventBus interface.
 type MockEventBus struct {
 	ctrl     *gomock.Controller
@@ -310,16 +472,59 @@ func (mr *MockMediaCtrlMockRecorder) GetPublicURL(ctx, namespace, id any) *gomoc
 }
 
 // Save mocks base method.
-func (m *MockMediaCtrl) Save(ctx context.Context, namespace string, file []byte, mediaType string) (uuid.UUID, error) {
+func (m *MockMediaCtrl) Save(ctx context.Context, namespace string, file []byte, filename string) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", ctx, namespace, file, mediaType)
+	ret := m.ctrl.Call(m, "Save", ctx, namespace, file, filename)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockMediaCtrlMockRecorder) Upload(ctx, namespace, file, mediaType any) *gomock.Call {
+func (mr *MockMediaCtrlMockRecorder) Save(ctx, namespace, file, filename any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockMediaCtrl)(nil).Save), ctx, namespace, file, filename)
+}
+
+// MockExchange is a mock of Exchange interface.
+type MockExchange struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeMockRecorder
+	isgomock struct{}
+}
+
+// MockExchangeMockRecorder is the mock recorder for MockExchange.
+type MockExchangeMockRecorder struct {
+	mock *MockExchange
+}
+
+// NewMockExchange creates a new mock instance.
+func NewMockExchange(ctrl *gomock.Controller) *MockExchange {
+	mock := &MockExchange{ctrl: ctrl}
+	mock.recorder = &MockExchangeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchange) EXPECT() *MockExchangeMockRecorder {
+	return m.recorder
+}
+
+// DisplayPrices mocks base method.
+func (m *MockExchange) DisplayPrices(ctx context.Context, namespace string, amount currency.BRL, at time.Time, currencies ...currency.Currency) []domain.DisplayPrice {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, namespace, amount, at}
+	for _, a := range currencies {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DisplayPrices", varargs...)
+	ret0, _ := ret[0].([]domain.DisplayPrice)
+	return ret0
+}
+
+// DisplayPrices indicates an expected call of DisplayPrices.
+func (mr *MockExchangeMockRecorder) DisplayPrices(ctx, namespace, amount, at any, currencies ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockMediaCtrl)(nil).Save), ctx, namespace, file, mediaType)
+	varargs := append([]any{ctx, namespace, amount, at}, currencies...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayPrices", reflect.TypeOf((*MockExchange)(nil).DisplayPrices), varargs...)
 }
@@ -0,0 +1,172 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// PriceTimeline returns every price change of the SKU, oldest first.
+func (s *ProductService) PriceTimeline(ctx context.Context, namespace string, sku string) ([]*domain.PriceChange, error) {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.PriceTimeline")
+	defer span.End()
+
+	if err := s.checkPermission(ctx, namespace, "product:read"); err != nil {
+		return nil, err
+	}
+
+	return s.history.Timeline(ctx, namespace, dto.PriceHistoryFilter{SKU: sku, End: time.Now()})
+}
+
+// LowestPrice returns the lowest price the SKU had in the last days, including the price in effect when
+// the period started. It is the reference required to justify a strikethrough price. The period is at least one day.
+func (s *ProductService) LowestPrice(ctx context.Context, namespace string, sku string, days int) (currency.BRL, error) {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.LowestPrice")
+	defer span.End()
+
+	if err := s.checkPermission(ctx, namespace, "product:read"); err != nil {
+		return 0, err
+	}
+	if days < 1 {
+		verr := new(domain.ValidationError)
+		verr.Add("days", domain.ValidationCodeMin, domain.ErrInvalidPricePeriod, map[string]any{"min": 1})
+		return 0, verr
+	}
+
+	end := time.Now()
+	start := end.AddDate(0, 0, -days)
+
+	before, err := s.history.LastBefore(ctx, namespace, sku, start)
+	if err != nil && !errors.Is(err, domain.ErrPriceHistoryNotFound) {
+		return 0, err
+	}
+
+	changes, err := s.history.Timeline(ctx, namespace, dto.PriceHistoryFilter{SKU: sku, Start: start, End: end})
+	if err != nil {
+		return 0, err
+	}
+
+	lowest, ok := lowestPrice(before, changes, start, end)
+	if !ok {
+		return 0, domain.ErrPriceHistoryNotFound
+	}
+	return lowest, nil
+}
+
+// lowestPrice returns the lowest effective price of the period [start, end) among the price in effect at the
+// period start and the changes within it. Each change holds from its ChangedAt until the next one, its sales
+// apply when their window overlaps that span.
+func lowestPrice(before *domain.PriceChange, changes []*domain.PriceChange, start, end time.Time) (currency.BRL, bool) {
+	if before != nil {
+		changes = append([]*domain.PriceChange{before}, changes...)
+	}
+
+	var lowest currency.BRL
+	found := false
+	for i, c := range changes {
+		price := c.Price
+		from, to := c.ChangedAt, end
+		if from.Before(start) {
+			from = start
+		}
+		if i+1 < len(changes) {
+			to = changes[i+1].ChangedAt
+		}
+		for _, sale := range c.Sales {
+			if sale.Price < price && sale.Overlaps(from, to) {
+				price = sale.Price
+			}
+		}
+		if !found || price < lowest {
+			lowest, found = price, true
+		}
+	}
+	return lowest, found
+}
+
+// priceChanges lists the price differences between the previous and the current state of the product.
+// previous is nil when the product has just been created.
+func priceChanges(namespace string, previous, current *domain.Product, userID uuid.UUID, now time.Time) []*domain.PriceChange {
+	var changes []*domain.PriceChange
+	add := func(sku string, variantID uuid.UUID, previousPrice, price currency.BRL, sales []domain.SalePrice) {
+		changes = append(changes, &domain.PriceChange{
+			ID:            uuid.New(),
+			Namespace:     namespace,
+			SKU:           sku,
+			ProductID:     current.ID,
+			VariantID:     variantID,
+			Price:         price,
+			PreviousPrice: previousPrice,
+			Sales:         slices.Clone(sales),
+			ChangedAt:     now,
+			UserID:        userID,
+		})
+	}
+
+	var previousPrice currency.BRL
+	var previousSales []domain.SalePrice
+	previousVariants := map[uuid.UUID]domain.ProductVariant{}
+	if previous != nil {
+		previousPrice, previousSales = previous.Price, previous.Sales
+		for _, v := range previous.Variants {
+			previousVariants[v.ID] = v
+		}
+	}
+
+	if previous == nil || previousPrice != current.Price || !salesEqual(previousSales, current.Sales) {
+		add(productSKU(current), uuid.Nil, previousPrice, current.Price, current.Sales)
+	}
+
+	for _, v := range current.Variants {
+		old, ok := previousVariants[v.ID]
+		if !ok || old.Price != v.Price || !salesEqual(old.Sales, v.Sales) {
+			add(variantSKU(v), v.ID, old.Price, v.Price, v.Sales)
+		}
+	}
+
+	return changes
+}
+
+func salesEqual(a, b []domain.SalePrice) bool {
+	return slices.EqualFunc(a, b, domain.SalePrice.Equal)
+}
+
+// recordPriceChanges stores the price changes, a failure is logged but does not abort the calling operation.
+func (s *ProductService) recordPriceChanges(ctx context.Context, namespace string, changes []*domain.PriceChange) {
+	if len(changes) == 0 {
+		return
+	}
+	err := s.history.Record(ctx, namespace, changes...)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record price changes",
+			"product_id", changes[0].ProductID,
+			"changes", len(changes),
+			"error", err,
+		)
+	}
+}
+
+// productSKU returns the SKU of the product, falling back to its ID when it has none.
+func productSKU(p *domain.Product) string {
+	if p.SKU != "" {
+		return p.SKU
+	}
+	return p.ID.String()
+}
+
+// variantSKU returns the SKU of the variant, falling back to its ID when it has none.
+func variantSKU(v domain.ProductVariant) string {
+	if v.SKU != "" {
+		return v.SKU
+	}
+	return v.ID.String()
+}
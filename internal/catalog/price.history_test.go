@@ -0,0 +1,124 @@
+package catalog_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/internal/catalog"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestLowestPrice(t *testing.T) {
+
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		before        *domain.PriceChange
+		beforeErr     error
+		changes       []*domain.PriceChange
+		expected      currency.BRL
+		expectedError error
+	}{
+		{
+			name:   "price in effect at the period start is the lowest",
+			before: &domain.PriceChange{Price: 8000},
+			changes: []*domain.PriceChange{
+				{Price: 10000},
+				{Price: 9000},
+			},
+			expected: 8000,
+		},
+		{
+			name:   "lowest change within the period",
+			before: &domain.PriceChange{Price: 10000},
+			changes: []*domain.PriceChange{
+				{Price: 7500},
+				{Price: 12000},
+			},
+			expected: 7500,
+		},
+		{
+			name:      "product created within the period",
+			beforeErr: domain.ErrPriceHistoryNotFound,
+			changes: []*domain.PriceChange{
+				{Price: 12000},
+			},
+			expected: 12000,
+		},
+		{
+			name: "sale within the period",
+			before: &domain.PriceChange{Price: 10000, Sales: []domain.SalePrice{
+				{Price: 6000, End: now.AddDate(0, 0, -40)},
+				{Price: 7000, Start: now.AddDate(0, 0, -10), End: now.AddDate(0, 0, -5)},
+			}},
+			expected: 7000,
+		},
+		{
+			name:   "sale removed before its start",
+			before: &domain.PriceChange{Price: 10000},
+			changes: []*domain.PriceChange{
+				{Price: 10000, ChangedAt: now.AddDate(0, 0, -20), Sales: []domain.SalePrice{{Price: 5000, Start: now.AddDate(0, 0, -10)}}},
+				{Price: 10000, ChangedAt: now.AddDate(0, 0, -15)},
+			},
+			expected: 10000,
+		},
+		{
+			name:          "no history",
+			beforeErr:     domain.ErrPriceHistoryNotFound,
+			expectedError: domain.ErrPriceHistoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAuth := NewMockAuthService(ctrl)
+			mockHistory := NewMockPriceHistoryRepository(ctrl)
+			service, _ := catalog.NewProductService(NewMockProductRepository(ctrl), NewMockEventBus(ctrl), mockAuth, NewMockMediaCtrl(ctrl), mockHistory)
+
+			mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
+			mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), "ns", "product:read").Return(true, nil)
+			mockHistory.EXPECT().LastBefore(gomock.Any(), "ns", "SKU-1", gomock.Any()).Return(tt.before, tt.beforeErr)
+			mockHistory.EXPECT().Timeline(gomock.Any(), "ns", gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, filter dto.PriceHistoryFilter) ([]*domain.PriceChange, error) {
+					assert.Equal(t, "SKU-1", filter.SKU)
+					assert.True(t, filter.Start.Equal(filter.End.AddDate(0, 0, -30)))
+					return tt.changes, nil
+				})
+
+			lowest, err := service.LowestPrice(context.Background(), "ns", "SKU-1", 30)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expected, lowest)
+		})
+	}
+}
+
+func TestLowestPrice_InvalidPeriod(t *testing.T) {
+	for _, days := range []int{0, -7} {
+		t.Run(fmt.Sprint(days), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAuth := NewMockAuthService(ctrl)
+			// the history is not read
+			service, _ := catalog.NewProductService(NewMockProductRepository(ctrl), NewMockEventBus(ctrl), mockAuth, NewMockMediaCtrl(ctrl), NewMockPriceHistoryRepository(ctrl))
+
+			mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
+			mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), "ns", "product:read").Return(true, nil)
+
+			_, err := service.LowestPrice(context.Background(), "ns", "SKU-1", days)
+
+			var verr *domain.ValidationError
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, []string{"days"}, verr.Fields())
+			assert.ErrorIs(t, err, domain.ErrInvalidPricePeriod)
+		})
+	}
+}
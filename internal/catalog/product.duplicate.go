@@ -0,0 +1,173 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+)
+
+// DuplicateProduct deep-copies the source product and its variants into a new draft product.
+// The copy gets new IDs, SKU and slug, while the media references are shared with the source.
+func (s *ProductService) DuplicateProduct(ctx context.Context, namespace string, sourceID uuid.UUID) (*domain.Product, error) {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.DuplicateProduct")
+	defer span.End()
+
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if userID == uuid.Nil {
+		return nil, domain.ErrUnauthorized
+	}
+
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, "product:read", "product:create")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return nil, domain.ErrUnauthorized
+	}
+
+	source, err := s.repo.GetByID(ctx, namespace, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	product := copyProduct(source, namespace, time.Now())
+
+	err = s.Validate(ctx, namespace, product)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkSlug(ctx, namespace, product)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.deriveBundle(ctx, namespace, product)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Create(ctx, namespace, product)
+	if err != nil {
+		span.RecordError(err)
+		return nil, domain.ErrFailedToCreateProduct
+	}
+
+	s.recordPriceChanges(ctx, namespace, priceChanges(namespace, nil, product, userID, product.CreatedAt))
+
+	err = s.repo.AddProductLog(ctx, &domain.ProductLogEvent{
+		Namespace: namespace,
+		ProductID: product.ID,
+		Timestamp: product.CreatedAt,
+		Event:     domain.ProductDuplicated,
+		Data:      map[string]any{"source_id": source.ID},
+		UserID:    userID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to add product log",
+			"product_id", product.ID,
+			"source_id", source.ID,
+			"error", err)
+	}
+
+	err = s.bus.Publish(ctx, "product:duplicated", events.ProductDuplicated{
+		ID:        product.ID,
+		SourceID:  source.ID,
+		CreatedOn: product.CreatedAt,
+		CreatedBy: userID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to publish product:duplicated event",
+			"product_id", product.ID,
+			"source_id", source.ID,
+			"error", err)
+	}
+
+	slog.InfoContext(ctx, "product duplicated",
+		"product_id", product.ID,
+		"source_id", source.ID,
+		"created_by", userID,
+	)
+
+	return product, nil
+}
+
+// copyProduct returns a deep copy of the source with fresh identifiers and a draft status.
+// The stock is not copied since the copy is a distinct inventory item, the bundles share the components of the
+// source and derive their price and stock from them.
+func copyProduct(source *domain.Product, namespace string, now time.Time) *domain.Product {
+	product := &domain.Product{
+		ID:             uuid.New(),
+		Namespace:      namespace,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Title:          source.Title,
+		Price:          source.Price,
+		CompareAtPrice: source.CompareAtPrice,
+		Sales:          slices.Clone(source.Sales),
+		Status:         domain.ProductStatusDraft,
+		Type:           source.Type,
+		Attributes:     maps.Clone(source.Attributes),
+		Categories:     slices.Clone(source.Categories),
+		NCM:            source.NCM,
+		Origin:         source.Origin,
+		CFOP:           source.CFOP,
+		Shipping:       source.Shipping,
+		Medias:         slices.Clone(source.Medias),
+		ShortDesc:      source.ShortDesc,
+		HtmlDesc:       source.HtmlDesc,
+		TextDesc:       source.TextDesc,
+	}
+
+	if source.Bundle != nil {
+		bundle := *source.Bundle
+		bundle.Components = slices.Clone(source.Bundle.Components)
+		product.Bundle = &bundle
+	}
+
+	suffix := shortID(product.ID)
+	product.Slug = slugify(source.Title) + "-" + suffix
+	if source.SKU != "" {
+		product.SKU = source.SKU + "-" + suffix
+	}
+
+	product.Variants = make([]domain.ProductVariant, 0, len(source.Variants))
+	for _, v := range source.Variants {
+		variantSKU := ""
+		if v.SKU != "" {
+			variantSKU = v.SKU + "-" + suffix
+		}
+		product.Variants = append(product.Variants, domain.ProductVariant{
+			ID:             uuid.New(),
+			Namespace:      namespace,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			ProductID:      product.ID,
+			Title:          v.Title,
+			SKU:            variantSKU,
+			Price:          v.Price,
+			CompareAtPrice: v.CompareAtPrice,
+			Sales:          slices.Clone(v.Sales),
+			Shipping:       v.Shipping,
+			Medias:         slices.Clone(v.Medias),
+			ShortDesc:      v.ShortDesc,
+			HtmlDesc:       v.HtmlDesc,
+			TextDesc:       v.TextDesc,
+		})
+	}
+
+	return product
+}
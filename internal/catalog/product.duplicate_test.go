@@ -0,0 +1,194 @@
+package catalog_test
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/catalog"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestDuplicateProduct(t *testing.T) {
+
+	newSource := func() *domain.Product {
+		return &domain.Product{
+			ID:             uuid.New(),
+			Title:          "Câmera Digital 4K",
+			Price:          currency.NewFromFloat(1500),
+			CompareAtPrice: currency.NewFromFloat(1800),
+			Sales: []domain.SalePrice{
+				{Price: currency.NewFromFloat(1300), Start: time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)},
+			},
+			Stock:      10,
+			Status:     domain.ProductStatusAvailable,
+			SKU:        "CAM-4K",
+			Slug:       "camera-digital-4k",
+			Attributes: map[string]string{"brand": "Acme"},
+			Categories: []string{"cameras", "eletronicos"},
+			Medias:     []uuid.UUID{uuid.New()},
+			Variants: []domain.ProductVariant{
+				{ID: uuid.New(), Title: "Preta", Price: currency.NewFromFloat(1500), Stock: 3, CompareAtPrice: currency.NewFromFloat(1800),
+					Sales: []domain.SalePrice{{Price: currency.NewFromFloat(1250)}}},
+				{ID: uuid.New(), Title: "Prata", Price: currency.NewFromFloat(1600), Stock: 7},
+			},
+		}
+	}
+
+	t.Run("copies the product", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAuth := NewMockAuthService(ctrl)
+		mockRepo := NewMockProductRepository(ctrl)
+		mockBus := NewMockEventBus(ctrl)
+		mockMedia := NewMockMediaCtrl(ctrl)
+		mockHistory := NewMockPriceHistoryRepository(ctrl)
+		service, err := catalog.NewProductService(mockRepo, mockBus, mockAuth, mockMedia, mockHistory)
+		require.NoError(t, err)
+
+		source := newSource()
+		userID := uuid.New()
+
+		mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+		mockAuth.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "product:read", "product:create").Return(true, nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), "ns", source.ID).Return(source, nil)
+		mockMedia.EXPECT().GetByID(gomock.Any(), "ns", source.Medias[0]).Return(&domain.Media{}, nil)
+		mockRepo.EXPECT().Find(gomock.Any(), "ns", gomock.Any()).Return(nil, nil)
+		mockRepo.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).Return(nil)
+		mockHistory.EXPECT().Record(gomock.Any(), "ns", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockRepo.EXPECT().AddProductLog(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, entry *domain.ProductLogEvent) error {
+				assert.Equal(t, domain.ProductDuplicated, entry.Event)
+				assert.Equal(t, userID, entry.UserID)
+				assert.Equal(t, map[string]any{"source_id": source.ID}, entry.Data)
+				return nil
+			})
+		mockBus.EXPECT().Publish(gomock.Any(), "product:duplicated", gomock.Any()).Return(nil)
+
+		copied, err := service.DuplicateProduct(context.Background(), "ns", source.ID)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, source.ID, copied.ID)
+		assert.Equal(t, domain.ProductStatusDraft, copied.Status)
+		assert.Equal(t, source.Title, copied.Title)
+		assert.Equal(t, source.Medias, copied.Medias)
+		assert.Equal(t, source.Attributes, copied.Attributes)
+		assert.Equal(t, source.Categories, copied.Categories)
+		assert.Equal(t, source.CompareAtPrice, copied.CompareAtPrice)
+		assert.Equal(t, source.Sales, copied.Sales)
+		assert.Zero(t, copied.Stock)
+		assert.NotEqual(t, source.SKU, copied.SKU)
+		assert.Contains(t, copied.SKU, source.SKU+"-")
+		assert.NotEqual(t, source.Slug, copied.Slug)
+		assert.Contains(t, copied.Slug, "camera-digital-4k-")
+
+		require.Len(t, copied.Variants, 2)
+		for i, v := range copied.Variants {
+			assert.NotEqual(t, source.Variants[i].ID, v.ID)
+			assert.Equal(t, copied.ID, v.ProductID)
+			assert.Equal(t, source.Variants[i].Title, v.Title)
+			assert.Equal(t, source.Variants[i].Price, v.Price)
+			assert.Equal(t, source.Variants[i].CompareAtPrice, v.CompareAtPrice)
+			assert.Equal(t, source.Variants[i].Sales, v.Sales)
+		}
+
+		// The copy must not share memory with the source
+		copied.Attributes["brand"] = "Other"
+		assert.Equal(t, "Acme", source.Attributes["brand"])
+		copied.Categories[0] = "other"
+		assert.Equal(t, "cameras", source.Categories[0])
+		copied.Sales[0].Price = currency.NewFromFloat(1)
+		assert.Equal(t, currency.NewFromFloat(1300), source.Sales[0].Price)
+		copied.Variants[0].Sales[0].Price = currency.NewFromFloat(1)
+		assert.Equal(t, currency.NewFromFloat(1250), source.Variants[0].Sales[0].Price)
+	})
+
+	t.Run("copies a bundle", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAuth := NewMockAuthService(ctrl)
+		mockRepo := NewMockProductRepository(ctrl)
+		mockBus := NewMockEventBus(ctrl)
+		mockHistory := NewMockPriceHistoryRepository(ctrl)
+		service, err := catalog.NewProductService(mockRepo, mockBus, mockAuth, NewMockMediaCtrl(ctrl), mockHistory)
+		require.NoError(t, err)
+
+		camera := &domain.Product{ID: uuid.New(), Title: "Camera", Price: currency.NewFromFloat(2000), Stock: 10}
+		lens := &domain.Product{ID: uuid.New(), Title: "Lens", Price: currency.NewFromFloat(500), Stock: 4}
+		source := &domain.Product{
+			ID:     uuid.New(),
+			Title:  "Camera Starter Kit",
+			Price:  currency.NewFromFloat(1),
+			Stock:  99,
+			Status: domain.ProductStatusAvailable,
+			Type:   domain.ProductTypeBundle,
+			Bundle: &domain.Bundle{
+				PriceMode:   domain.BundlePriceComputed,
+				DiscountBps: 1000,
+				Components: []domain.BundleComponent{
+					{ProductID: camera.ID, Quantity: 1},
+					{ProductID: lens.ID, Quantity: 2},
+				},
+			},
+		}
+		userID := uuid.New()
+
+		mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+		mockAuth.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "product:read", "product:create").Return(true, nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), "ns", source.ID).Return(source, nil)
+		// the components are resolved by the validation, then to derive the price and stock
+		mockRepo.EXPECT().GetByID(gomock.Any(), "ns", camera.ID).Return(camera, nil).Times(2)
+		mockRepo.EXPECT().GetByID(gomock.Any(), "ns", lens.ID).Return(lens, nil).Times(2)
+		mockRepo.EXPECT().Find(gomock.Any(), "ns", gomock.Any()).Return(nil, nil)
+		mockRepo.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).Return(nil)
+		mockHistory.EXPECT().Record(gomock.Any(), "ns", gomock.Any()).Return(nil)
+		mockRepo.EXPECT().AddProductLog(gomock.Any(), gomock.Any()).Return(nil)
+		mockBus.EXPECT().Publish(gomock.Any(), "product:duplicated", gomock.Any()).Return(nil)
+
+		copied, err := service.DuplicateProduct(context.Background(), "ns", source.ID)
+		require.NoError(t, err)
+
+		assert.True(t, copied.IsBundle())
+		require.NotNil(t, copied.Bundle)
+		assert.Equal(t, source.Bundle.Components, copied.Bundle.Components)
+		assert.Equal(t, domain.BundlePriceComputed, copied.Bundle.PriceMode)
+		// (2000 + 2*500) - 10%, limited by the 4 lenses
+		assert.Equal(t, currency.NewFromFloat(2700), copied.Price)
+		assert.Equal(t, int64(2), copied.Stock)
+
+		// The components must not share memory with the source
+		copied.Bundle.Components[0].Quantity = 5
+		assert.Equal(t, int64(1), source.Bundle.Components[0].Quantity)
+	})
+
+	t.Run("source not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAuth := NewMockAuthService(ctrl)
+		mockRepo := NewMockProductRepository(ctrl)
+		service, err := catalog.NewProductService(mockRepo, NewMockEventBus(ctrl), mockAuth, NewMockMediaCtrl(ctrl), NewMockPriceHistoryRepository(ctrl))
+		require.NoError(t, err)
+
+		mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
+		mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.ErrProductNotFound)
+
+		_, err = service.DuplicateProduct(context.Background(), "ns", uuid.New())
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAuth := NewMockAuthService(ctrl)
+		service, err := catalog.NewProductService(NewMockProductRepository(ctrl), NewMockEventBus(ctrl), mockAuth, NewMockMediaCtrl(ctrl), NewMockPriceHistoryRepository(ctrl))
+		require.NoError(t, err)
+
+		mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
+		mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, errors.New("any error"))
+
+		_, err = service.DuplicateProduct(context.Background(), "ns", uuid.New())
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	})
+}
@@ -2,9 +2,13 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"github.com/HBeserra/GoShop/domain"
 	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/currency"
 	"github.com/HBeserra/GoShop/pkg/observability"
+	"log/slog"
+	"time"
 )
 
 func (s *ProductService) Find(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error) {
@@ -25,5 +29,69 @@ func (s *ProductService) Find(ctx context.Context, namespace string, filter dto.
 		return nil, domain.ErrUnauthorized
 	}
 
-	return s.repo.Find(ctx, namespace, filter)
+	return s.Lookup(ctx, namespace, filter)
+}
+
+// Lookup returns the products matching the filter with their bundle price and stock derived and their prices
+// resolved, as Find does, without checking the permissions of the user. It serves the reads the other services
+// make on behalf of any shopper, such as the pricing of a guest cart.
+func (s *ProductService) Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error) {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.Lookup")
+	defer span.End()
+
+	products, err := s.repo.Find(ctx, namespace, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// The bundles price and stock depend on the current state of their components
+	now := time.Now()
+	for _, product := range products {
+		if err := s.deriveBundle(ctx, namespace, product); err != nil {
+			if !unresolvable(err) {
+				return nil, err
+			}
+			// a missing or invalid component makes the bundle unavailable, not the whole listing
+			slog.WarnContext(ctx, "bundle unavailable",
+				"product_id", product.ID,
+				"error", err)
+			product.Stock = 0
+			product.Status = domain.ProductStatusOutOfStock
+		}
+		product.ResolvePrices(now)
+		s.resolveInstallments(product)
+		s.resolveDisplayPrices(ctx, namespace, product, filter.Currencies, now)
+	}
+
+	return products, nil
+}
+
+// unresolvable reports whether the bundle error is due to its components, such as a deleted product,
+// rather than to the repository.
+func unresolvable(err error) bool {
+	return errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrVariantNotFound) ||
+		errors.Is(err, domain.ErrInvalidBundle)
+}
+
+// resolveInstallments sets the best installment plan of the product effective price.
+func (s *ProductService) resolveInstallments(product *domain.Product) {
+	if s.installments == nil {
+		return
+	}
+	if plan, ok := s.installments.Best(product.EffectivePrice); ok {
+		product.Installments = &plan
+	}
+}
+
+// resolveDisplayPrices converts the effective prices of the product and its variants to the currencies.
+func (s *ProductService) resolveDisplayPrices(ctx context.Context, namespace string, product *domain.Product, currencies []currency.Currency, now time.Time) {
+	if s.exchange == nil || len(currencies) == 0 {
+		return
+	}
+	product.DisplayPrices = s.exchange.DisplayPrices(ctx, namespace, product.EffectivePrice, now, currencies...)
+	for i := range product.Variants {
+		v := &product.Variants[i]
+		v.DisplayPrices = s.exchange.DisplayPrices(ctx, namespace, v.EffectivePrice, now, currencies...)
+	}
 }
@@ -0,0 +1,106 @@
+package catalog_test
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/internal/catalog"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestFind_Installments(t *testing.T) {
+	userID := uuid.New()
+	product := &domain.Product{
+		ID:    uuid.New(),
+		Price: currency.NewFromFloat(1200),
+		Sales: []domain.SalePrice{{Price: currency.NewFromFloat(600), Start: time.Now().Add(-time.Hour)}},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockProductRepository(ctrl)
+	mockAuth := NewMockAuthService(ctrl)
+	service, err := catalog.NewProductService(mockRepo, NewMockEventBus(ctrl), mockAuth, NewMockMediaCtrl(ctrl), NewMockPriceHistoryRepository(ctrl))
+	require.NoError(t, err)
+	service.SetInstallmentPolicy(&currency.InstallmentPolicy{
+		MaxInstallments: 12,
+		InterestFree:    10,
+		MonthlyRate:     0.0199,
+		MinInstallment:  currency.NewFromFloat(5),
+	})
+
+	mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+	mockAuth.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "product:read").Return(true, nil)
+	mockRepo.EXPECT().Find(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+
+	products, err := service.Find(context.Background(), "ns", dto.ProductFilter{})
+	require.NoError(t, err)
+	require.NotNil(t, products[0].Installments)
+
+	// the plan is computed on the sale price
+	assert.Equal(t, 10, products[0].Installments.Count)
+	assert.True(t, products[0].Installments.InterestFree())
+	assert.Equal(t, currency.NewFromFloat(60), products[0].Installments.Installment())
+}
+
+func TestFind_DisplayPrices(t *testing.T) {
+	userID := uuid.New()
+	product := &domain.Product{
+		ID:       uuid.New(),
+		Price:    currency.NewFromFloat(100),
+		Variants: []domain.ProductVariant{{ID: uuid.New(), Price: currency.NewFromFloat(120)}},
+	}
+	usd := []domain.DisplayPrice{{Price: currency.New(2090, currency.CurrencyUSD)}}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockProductRepository(ctrl)
+	mockAuth := NewMockAuthService(ctrl)
+	mockExchange := NewMockExchange(ctrl)
+	service, err := catalog.NewProductService(mockRepo, NewMockEventBus(ctrl), mockAuth, NewMockMediaCtrl(ctrl), NewMockPriceHistoryRepository(ctrl))
+	require.NoError(t, err)
+	service.SetExchange(mockExchange)
+
+	mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil).Times(2)
+	mockAuth.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "product:read").Return(true, nil).Times(2)
+	mockRepo.EXPECT().Find(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil).Times(2)
+	mockExchange.EXPECT().DisplayPrices(gomock.Any(), "ns", currency.NewFromFloat(100), gomock.Any(), currency.CurrencyUSD).Return(usd)
+	mockExchange.EXPECT().DisplayPrices(gomock.Any(), "ns", currency.NewFromFloat(120), gomock.Any(), currency.CurrencyUSD).Return(usd)
+
+	products, err := service.Find(context.Background(), "ns", dto.ProductFilter{Currencies: []currency.Currency{currency.CurrencyUSD}})
+	require.NoError(t, err)
+	assert.Equal(t, usd, products[0].DisplayPrices)
+	assert.Equal(t, usd, products[0].Variants[0].DisplayPrices)
+	// the canonical price is kept
+	assert.Equal(t, currency.NewFromFloat(100), products[0].EffectivePrice)
+
+	// no conversion without requested currencies
+	product.DisplayPrices, product.Variants[0].DisplayPrices = nil, nil
+	products, err = service.Find(context.Background(), "ns", dto.ProductFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, products[0].DisplayPrices)
+}
+
+func TestLookup(t *testing.T) {
+	product := &domain.Product{
+		ID:    uuid.New(),
+		Price: currency.NewFromFloat(100),
+		Sales: []domain.SalePrice{{Price: currency.NewFromFloat(80)}},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockProductRepository(ctrl)
+	service, err := catalog.NewProductService(mockRepo, NewMockEventBus(ctrl), NewMockAuthService(ctrl), NewMockMediaCtrl(ctrl), NewMockPriceHistoryRepository(ctrl))
+	require.NoError(t, err)
+
+	// no user nor permission is required
+	mockRepo.EXPECT().Find(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+
+	products, err := service.Lookup(context.Background(), "ns", dto.ProductFilter{IDs: []uuid.UUID{product.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, currency.NewFromFloat(80), products[0].EffectivePrice)
+}
@@ -2,14 +2,13 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/HBeserra/GoShop/domain"
 	"github.com/HBeserra/GoShop/domain/events"
-	"github.com/HBeserra/GoShop/pkg/currency"
 	"github.com/HBeserra/GoShop/pkg/observability"
 	"github.com/google/uuid"
 	"log/slog"
-	"slices"
 	"time"
 )
 
@@ -39,62 +38,48 @@ func (s *ProductService) CreateProduct(
 	}
 
 	product.ID = uuid.New()
+	product.Namespace = namespace
 	product.CreatedAt = time.Now()
 	product.UpdatedAt = time.Now()
 
-	if len(product.Title) < 10 || len(product.Title) > 100 {
-		return domain.ErrInvalidProductTitle
+	generatedSlug := product.Slug == ""
+	if generatedSlug {
+		product.Slug = slugify(product.Title)
 	}
 
-	if product.Price.LessOrEqual(currency.NewFromFloat(1.00)) {
-		return domain.ErrInvalidProductPrice
-	}
-
-	var minPrice, maxPrice float64
-	for _, variant := range product.Variants {
-		if variant.Price.LessOrEqual(currency.NewFromFloat(1.00)) {
-			return domain.ErrInvalidProductPrice
-		}
-
-		if variant.Price.Float64() < minPrice {
-			minPrice = variant.Price.Float64()
-		}
-		if variant.Price.Float64() > maxPrice {
-			maxPrice = variant.Price.Float64()
+	for i := range product.Variants {
+		if product.Variants[i].ID == uuid.Nil {
+			product.Variants[i].ID = uuid.New()
 		}
+		product.Variants[i].ProductID = product.ID
+		product.Variants[i].Namespace = namespace
+		product.Variants[i].CreatedAt = product.CreatedAt
+		product.Variants[i].UpdatedAt = product.UpdatedAt
 	}
 
-	if maxPrice/minPrice > 5 {
-		return domain.ErrInvalidProductPrice
+	err = s.Validate(ctx, namespace, product)
+	if err != nil {
+		return err
 	}
 
-	if product.Stock < 0 {
-		product.Stock = 0
+	// A slug generated from the title of another product gets the suffix of the copies, only a slug
+	// given by the client is rejected
+	err = s.checkSlug(ctx, namespace, product)
+	if errors.Is(err, domain.ErrSlugInUse) && generatedSlug {
+		product.Slug += "-" + shortID(product.ID)
+		err = s.checkSlug(ctx, namespace, product)
 	}
-
-	if !slices.Contains([]domain.ProductStatus{
-		domain.ProductStatusDraft,
-		domain.ProductStatusAvailable,
-		domain.ProductStatusOutOfStock,
-	}, product.Status) {
-		return domain.ErrInvalidProductStatus
+	if err != nil {
+		return err
 	}
 
-	// Validate the medias
-	for _, mediaID := range product.Medias {
-		_, err := s.media.GetByID(ctx, namespace, mediaID)
-		if err != nil {
-			return domain.ErrInvalidMedia
-		}
+	// The stock never goes negative, and the bundles derive their price and stock from the components
+	if product.Stock < 0 {
+		product.Stock = 0
 	}
-
-	for _, variant := range product.Variants {
-		for _, mediaID := range variant.Medias {
-			_, err := s.media.GetByID(ctx, namespace, mediaID)
-			if err != nil {
-				return domain.ErrInvalidMedia
-			}
-		}
+	err = s.deriveBundle(ctx, namespace, product)
+	if err != nil {
+		return err
 	}
 
 	err = s.repo.Create(ctx, namespace, product)
@@ -103,6 +88,8 @@ func (s *ProductService) CreateProduct(
 		return domain.ErrFailedToCreateProduct
 	}
 
+	s.recordPriceChanges(ctx, namespace, priceChanges(namespace, nil, product, userID, product.CreatedAt))
+
 	err = s.bus.Publish(ctx, "product:created", events.ProductCreated{
 		ID:        product.ID,
 		Title:     product.Title,
@@ -3,6 +3,8 @@ package catalog_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain/dto"
 	"github.com/HBeserra/GoShop/internal/catalog"
 	"github.com/HBeserra/GoShop/pkg/currency"
 	"github.com/google/uuid"
@@ -17,9 +19,10 @@ import (
 func TestCreateProduct(t *testing.T) {
 
 	type setupParams struct {
-		busService  *MockEventBus
-		repoService *MockProductRepository
-		authService *MockAuthService
+		busService     *MockEventBus
+		repoService    *MockProductRepository
+		authService    *MockAuthService
+		historyService *MockPriceHistoryRepository
 	}
 
 	type productTest struct {
@@ -94,7 +97,7 @@ func TestCreateProduct(t *testing.T) {
 				Variants: []domain.ProductVariant{
 					{
 						Title: "Variant 1",
-						Price: currency.NewFromFloat(5),
+						Price: currency.NewFromFloat(0.5),
 					},
 				},
 			},
@@ -126,6 +129,46 @@ func TestCreateProduct(t *testing.T) {
 			},
 			expectedError: domain.ErrInvalidProductPrice,
 		},
+		{
+			name: "slug in use",
+			product: &domain.Product{
+				Title:  "Valid Product Title",
+				Slug:   "valid-product-title",
+				Price:  currency.NewFromFloat(50),
+				Status: domain.ProductStatusAvailable,
+			},
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				t.repoService.EXPECT().Find(gomock.Any(), gomock.Any(), dto.ProductFilter{Slugs: []string{"valid-product-title"}}).
+					Return([]*domain.Product{{ID: uuid.New(), Slug: "valid-product-title"}}, nil)
+			},
+			expectedError: domain.ErrSlugInUse,
+		},
+		{
+			name: "generated slug in use",
+			product: &domain.Product{
+				Title:  "Valid Product Title",
+				Price:  currency.NewFromFloat(50),
+				Status: domain.ProductStatusAvailable,
+			},
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				t.repoService.EXPECT().Find(gomock.Any(), gomock.Any(), dto.ProductFilter{Slugs: []string{"valid-product-title"}}).
+					Return([]*domain.Product{{ID: uuid.New(), Slug: "valid-product-title"}}, nil)
+				t.repoService.EXPECT().Find(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				t.repoService.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, p *domain.Product) error {
+					if p.Slug != "valid-product-title-"+p.ID.String()[:8] {
+						return fmt.Errorf("unexpected slug %q", p.Slug)
+					}
+					return nil
+				})
+				t.historyService.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedError: nil,
+		},
 		{
 			name: "repository error",
 			product: &domain.Product{
@@ -136,6 +179,7 @@ func TestCreateProduct(t *testing.T) {
 			setup: func(t setupParams) {
 				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
 				t.authService.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				t.repoService.EXPECT().Find(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
 				t.repoService.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("repository error"))
 			},
 			expectedError: domain.ErrFailedToCreateProduct,
@@ -150,7 +194,9 @@ func TestCreateProduct(t *testing.T) {
 			setup: func(t setupParams) {
 				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
 				t.authService.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				t.repoService.EXPECT().Find(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
 				t.repoService.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				t.historyService.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 				t.busService.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("event publish error"))
 			},
 			expectedError: nil,
@@ -165,7 +211,9 @@ func TestCreateProduct(t *testing.T) {
 			setup: func(t setupParams) {
 				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
 				t.authService.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				t.repoService.EXPECT().Find(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
 				t.repoService.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				t.historyService.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 				t.busService.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
 			expectedError: nil,
@@ -190,14 +238,16 @@ func TestCreateProduct(t *testing.T) {
 			mockRepo := NewMockProductRepository(ctrl)
 			mockBus := NewMockEventBus(ctrl)
 			mockMedia := NewMockMediaCtrl(ctrl)
-			service, err := catalog.NewProductService(mockRepo, mockBus, mockAuth, mockMedia)
+			mockHistory := NewMockPriceHistoryRepository(ctrl)
+			service, err := catalog.NewProductService(mockRepo, mockBus, mockAuth, mockMedia, mockHistory)
 			assert.NoError(t, err)
 
 			if tt.setup != nil {
 				tt.setup(setupParams{
-					busService:  mockBus,
-					repoService: mockRepo,
-					authService: mockAuth,
+					busService:     mockBus,
+					repoService:    mockRepo,
+					authService:    mockAuth,
+					historyService: mockHistory,
 				})
 			}
 
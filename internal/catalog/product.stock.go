@@ -0,0 +1,127 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// AdjustStock adds delta (negative when selling) to the stock of the product, or of its variant when variantID
+// is not uuid.Nil. For bundles the movement is applied to every component multiplied by its quantity, and the
+// components already moved are reverted if one of them fails.
+func (s *ProductService) AdjustStock(ctx context.Context, namespace string, productID, variantID uuid.UUID, delta int64) error {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.AdjustStock")
+	defer span.End()
+
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, "product:stock")
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return domain.ErrUnauthorized
+	}
+
+	err = s.moveStock(ctx, namespace, productID, variantID, delta, userID)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return err
+}
+
+// MoveStock adjusts the stock like AdjustStock. It is called by the checkout to reserve and release the stock
+// of the orders, so no permission is checked and the movement is recorded without a user.
+func (s *ProductService) MoveStock(ctx context.Context, namespace string, productID, variantID uuid.UUID, delta int64) error {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.MoveStock")
+	defer span.End()
+
+	err := s.moveStock(ctx, namespace, productID, variantID, delta, uuid.Nil)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return err
+}
+
+// moveStock applies the stock movement, see AdjustStock, on behalf of the user.
+func (s *ProductService) moveStock(ctx context.Context, namespace string, productID, variantID uuid.UUID, delta int64, userID uuid.UUID) error {
+	product, err := s.repo.GetByID(ctx, namespace, productID)
+	if err != nil {
+		return err
+	}
+
+	if !product.IsBundle() {
+		stock, err := s.repo.AdjustStock(ctx, namespace, productID, variantID, delta)
+		if err != nil {
+			return err
+		}
+		s.publishStockUpdate(ctx, productID, variantID, delta, stock, userID)
+		return nil
+	}
+
+	if product.Bundle == nil {
+		return domain.ErrInvalidBundle
+	}
+
+	applied := make([]domain.BundleComponent, 0, len(product.Bundle.Components))
+	for _, c := range product.Bundle.Components {
+		stock, err := s.repo.AdjustStock(ctx, namespace, c.ProductID, c.VariantID, delta*c.Quantity)
+		if err != nil {
+			s.revertStock(ctx, namespace, applied, delta)
+			return err
+		}
+		applied = append(applied, c)
+		s.publishStockUpdate(ctx, c.ProductID, c.VariantID, delta*c.Quantity, stock, userID)
+	}
+
+	slog.InfoContext(ctx, "bundle stock updated",
+		"product_id", productID,
+		"delta", delta,
+		"updated_by", userID,
+	)
+
+	return nil
+}
+
+// revertStock undoes the stock movements already applied to the bundle components.
+func (s *ProductService) revertStock(ctx context.Context, namespace string, applied []domain.BundleComponent, delta int64) {
+	for _, c := range applied {
+		_, err := s.repo.AdjustStock(ctx, namespace, c.ProductID, c.VariantID, -delta*c.Quantity)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to revert bundle component stock",
+				"product_id", c.ProductID,
+				"variant_id", c.VariantID,
+				"delta", -delta*c.Quantity,
+				"error", err,
+			)
+		}
+	}
+}
+
+func (s *ProductService) publishStockUpdate(ctx context.Context, productID, variantID uuid.UUID, delta, stock int64, userID uuid.UUID) {
+	err := s.bus.Publish(ctx, "product:stock.updated", events.ProductStockUpdated{
+		ID:        productID,
+		VariantID: variantID,
+		Delta:     delta,
+		Stock:     stock,
+		UpdatedOn: time.Now(),
+		UpdatedBy: userID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish product:stock.updated event",
+			"product_id", productID,
+			"variant_id", variantID,
+			"error", err,
+		)
+	}
+}
@@ -36,6 +36,25 @@ func (s *ProductService) UpdateProduct(
 		return err
 	}
 
+	err = s.checkSlug(ctx, namespace, product)
+	if err != nil {
+		return err
+	}
+
+	// The stock never goes negative, and the bundles derive their price and stock from the components
+	if product.Stock < 0 {
+		product.Stock = 0
+	}
+	err = s.deriveBundle(ctx, namespace, product)
+	if err != nil {
+		return err
+	}
+
+	previous, err := s.repo.GetByID(ctx, namespace, product.ID)
+	if err != nil {
+		return err
+	}
+
 	product.UpdatedAt = time.Now()
 	err = s.repo.Update(ctx, namespace, product)
 	if err != nil {
@@ -47,6 +66,8 @@ func (s *ProductService) UpdateProduct(
 		return err
 	}
 
+	s.recordPriceChanges(ctx, namespace, priceChanges(namespace, previous, product, userID, product.UpdatedAt))
+
 	slog.InfoContext(ctx, "product updated",
 		"product_id", product.ID,
 		"updated_by", userID,
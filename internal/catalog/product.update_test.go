@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
 	"github.com/HBeserra/GoShop/internal/catalog"
+	"github.com/HBeserra/GoShop/pkg/currency"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -20,7 +22,9 @@ func TestUpdateProduct(t *testing.T) {
 	mockBus := NewMockEventBus(mockCtrl)
 	mockMedia := NewMockMediaCtrl(mockCtrl)
 
-	service, _ := catalog.NewProductService(mockRepo, mockBus, mockAuth, mockMedia)
+	mockHistory := NewMockPriceHistoryRepository(mockCtrl)
+
+	service, _ := catalog.NewProductService(mockRepo, mockBus, mockAuth, mockMedia, mockHistory)
 
 	validProduct := &domain.Product{
 		ID:     uuid.New(),
@@ -41,7 +45,15 @@ func TestUpdateProduct(t *testing.T) {
 			setupMocks: func() {
 				mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
 				mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any(), validProduct.ID).Return(&domain.Product{ID: validProduct.ID, Price: 400}, nil)
 				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any(), validProduct).Return(nil)
+				mockHistory.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, changes ...*domain.PriceChange) error {
+						assert.Len(t, changes, 1)
+						assert.Equal(t, currency.BRL(400), changes[0].PreviousPrice)
+						assert.Equal(t, currency.BRL(500), changes[0].Price)
+						return nil
+					})
 			},
 			product:       validProduct,
 			expectedError: nil,
@@ -68,11 +80,23 @@ func TestUpdateProduct(t *testing.T) {
 			setupMocks: func() {
 				mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
 				mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any(), validProduct.ID).Return(validProduct, nil)
 				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any(), validProduct).Return(domain.ErrProductNotFound)
 			},
 			product:       validProduct,
 			expectedError: domain.ErrProductNotFound,
 		},
+		{
+			name: "slug in use",
+			setupMocks: func() {
+				mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil)
+				mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				mockRepo.EXPECT().Find(gomock.Any(), gomock.Any(), dto.ProductFilter{Slugs: []string{"test-product"}}).
+					Return([]*domain.Product{{ID: uuid.New(), Slug: "test-product"}}, nil)
+			},
+			product:       &domain.Product{ID: validProduct.ID, Title: "Test Product", Slug: "test-product", Price: 500, Status: domain.ProductStatusDraft},
+			expectedError: domain.ErrSlugInUse,
+		},
 		{
 			name: "validation error",
 			setupMocks: func() {
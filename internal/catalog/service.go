@@ -2,9 +2,12 @@ package catalog
 
 import (
 	"context"
+	"fmt"
 	"github.com/HBeserra/GoShop/domain"
 	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/currency"
 	"github.com/google/uuid"
+	"time"
 )
 
 // ProductRepository defines an interface for managing product data, including retrieval, deletion, and restoration operations.
@@ -32,6 +35,39 @@ type ProductRepository interface {
 	Restore(ctx context.Context, namespace string, id uuid.UUID) error
 
 	GetProductLog(ctx context.Context, filter *dto.ProductLogFilter) ([]interface{}, error)
+
+	// AdjustStock atomically adds delta to the stock of the product, or of its variant when variantID is not uuid.Nil.
+	// It returns the resulting stock, or domain.ErrInsufficientStock if the stock would become negative.
+	AdjustStock(ctx context.Context, namespace string, productID, variantID uuid.UUID, delta int64) (int64, error)
+
+	// AddProductLog appends an entry to the product audit log.
+	AddProductLog(ctx context.Context, entry *domain.ProductLogEvent) error
+
+	// CreateTemplate stores a new product template.
+	CreateTemplate(ctx context.Context, namespace string, template *domain.ProductTemplate) error
+
+	// GetTemplateByID retrieves a product template by its unique identifier, returns domain.ErrTemplateNotFound if missing.
+	GetTemplateByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.ProductTemplate, error)
+
+	// FindTemplates lists all the product templates of the namespace.
+	FindTemplates(ctx context.Context, namespace string) ([]*domain.ProductTemplate, error)
+
+	// DeleteTemplate removes a product template by the provided UUID.
+	DeleteTemplate(ctx context.Context, namespace string, id uuid.UUID) error
+}
+
+// PriceHistoryRepository stores every price change of the products and variants.
+type PriceHistoryRepository interface {
+
+	// Record appends the price changes to the history.
+	Record(ctx context.Context, namespace string, changes ...*domain.PriceChange) error
+
+	// Timeline returns the price changes of the SKU within the filter period, ordered by ChangedAt.
+	Timeline(ctx context.Context, namespace string, filter dto.PriceHistoryFilter) ([]*domain.PriceChange, error)
+
+	// LastBefore returns the last price change of the SKU made at or before the given time,
+	// or domain.ErrPriceHistoryNotFound if there is none.
+	LastBefore(ctx context.Context, namespace string, sku string, t time.Time) (*domain.PriceChange, error)
 }
 
 // EventBus defines an interface for managing event publishing and subscription.
@@ -59,18 +95,55 @@ type MediaCtrl interface {
 	Save(ctx context.Context, namespace string, file []byte, filename string) (uuid.UUID, error)
 }
 
+// Exchange converts the prices to foreign currencies for display, see exchange.Service.
+type Exchange interface {
+	DisplayPrices(ctx context.Context, namespace string, amount currency.BRL, at time.Time, currencies ...currency.Currency) []domain.DisplayPrice
+}
+
 type ProductService struct {
-	repo  ProductRepository
-	bus   EventBus
-	auth  AuthService
-	media MediaCtrl
+	repo    ProductRepository
+	bus     EventBus
+	auth    AuthService
+	media   MediaCtrl
+	history PriceHistoryRepository
+
+	installments *currency.InstallmentPolicy
+	exchange     Exchange
 }
 
-func NewProductService(repo ProductRepository, bus EventBus, auth AuthService, media MediaCtrl) (*ProductService, error) {
+func NewProductService(repo ProductRepository, bus EventBus, auth AuthService, media MediaCtrl, history PriceHistoryRepository) (*ProductService, error) {
 	return &ProductService{
-		repo:  repo,
-		bus:   bus,
-		auth:  auth,
-		media: media,
+		repo:    repo,
+		bus:     bus,
+		auth:    auth,
+		media:   media,
+		history: history,
 	}, nil
 }
+
+// checkPermission verifies that the user of the context has the permission in the namespace.
+func (s *ProductService) checkPermission(ctx context.Context, namespace string, permission ...string) error {
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, permission...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return domain.ErrUnauthorized
+	}
+	return nil
+}
+
+// SetInstallmentPolicy enables the best installment plan on the product reads, nil disables it.
+func (s *ProductService) SetInstallmentPolicy(policy *currency.InstallmentPolicy) {
+	s.installments = policy
+}
+
+// SetExchange enables the display prices on the product reads requesting currencies, nil disables them.
+func (s *ProductService) SetExchange(exchange Exchange) {
+	s.exchange = exchange
+}
@@ -0,0 +1,56 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// slugify converts a title into a lowercase URL friendly slug, e.g. "Câmera Digital 4K" -> "camera-digital-4k".
+func slugify(title string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// drop the accents left by the decomposition
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			dash = false
+			sb.WriteRune(unicode.ToLower(r))
+		default:
+			dash = true
+		}
+	}
+	return sb.String()
+}
+
+// shortID returns the first block of a UUID, used to make the copied SKUs and the copied or generated slugs unique.
+func shortID(id uuid.UUID) string {
+	return id.String()[:8]
+}
+
+// checkSlug returns domain.ErrSlugInUse when another product of the namespace already has the slug of the product.
+// The unique index on the namespace and slug backs the check against concurrent writes.
+func (s *ProductService) checkSlug(ctx context.Context, namespace string, product *domain.Product) error {
+	if product.Slug == "" {
+		return nil
+	}
+	products, err := s.repo.Find(ctx, namespace, dto.ProductFilter{Slugs: []string{product.Slug}})
+	if err != nil {
+		return err
+	}
+	for _, other := range products {
+		if other.ID != product.ID {
+			return fmt.Errorf("%w: %q", domain.ErrSlugInUse, product.Slug)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package catalog
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"slices"
+)
+
+// CreateProductFromTemplate creates a product starting from the template defaults.
+// Fields already set in the product take precedence over the template ones.
+func (s *ProductService) CreateProductFromTemplate(
+	ctx context.Context,
+	namespace string,
+	templateID uuid.UUID,
+	product *domain.Product,
+) error {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.CreateProductFromTemplate")
+	defer span.End()
+
+	template, err := s.GetTemplate(ctx, namespace, templateID)
+	if err != nil {
+		return err
+	}
+
+	applyTemplate(template, product)
+
+	return s.CreateProduct(ctx, namespace, product)
+}
+
+// applyTemplate fills the zero-valued fields of the product with the template defaults.
+func applyTemplate(template *domain.ProductTemplate, product *domain.Product) {
+	if product.Title == "" {
+		product.Title = template.Title
+	}
+	if product.Price.IsZero() {
+		product.Price = template.Price
+	}
+	if product.Status == "" {
+		product.Status = template.Status
+	}
+	if product.ShortDesc == "" {
+		product.ShortDesc = template.ShortDesc
+	}
+	if product.HtmlDesc == "" {
+		product.HtmlDesc = template.HtmlDesc
+	}
+	if product.TextDesc == "" {
+		product.TextDesc = template.TextDesc
+	}
+	if len(product.Medias) == 0 {
+		product.Medias = slices.Clone(template.Medias)
+	}
+
+	for k, v := range template.Attributes {
+		if product.Attributes == nil {
+			product.Attributes = make(map[string]string, len(template.Attributes))
+		}
+		if _, ok := product.Attributes[k]; !ok {
+			product.Attributes[k] = v
+		}
+	}
+
+	if len(product.Variants) == 0 {
+		for _, v := range template.Variants {
+			product.Variants = append(product.Variants, domain.ProductVariant{
+				Title:     v.Title,
+				Price:     v.Price,
+				Medias:    slices.Clone(v.Medias),
+				ShortDesc: v.ShortDesc,
+				HtmlDesc:  v.HtmlDesc,
+				TextDesc:  v.TextDesc,
+			})
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package catalog_test
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/internal/catalog"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestCreateProductFromTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAuth := NewMockAuthService(ctrl)
+	mockRepo := NewMockProductRepository(ctrl)
+	mockBus := NewMockEventBus(ctrl)
+	mockHistory := NewMockPriceHistoryRepository(ctrl)
+	service, err := catalog.NewProductService(mockRepo, mockBus, mockAuth, NewMockMediaCtrl(ctrl), mockHistory)
+	require.NoError(t, err)
+
+	template := &domain.ProductTemplate{
+		ID:         uuid.New(),
+		Name:       "T-Shirt",
+		Title:      "Camiseta Básica Algodão",
+		Price:      currency.NewFromFloat(59.90),
+		Status:     domain.ProductStatusDraft,
+		Attributes: map[string]string{"material": "cotton", "brand": "Acme"},
+		ShortDesc:  "100% algodão",
+		Variants: []domain.ProductVariant{
+			{ID: uuid.New(), Title: "P", Price: currency.NewFromFloat(59.90)},
+			{ID: uuid.New(), Title: "M", Price: currency.NewFromFloat(59.90)},
+		},
+	}
+
+	mockAuth.EXPECT().GetUserID(gomock.Any()).Return(uuid.New(), nil).Times(2)
+	mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), "ns", "product_template:read").Return(true, nil)
+	mockAuth.EXPECT().CheckPermissions(gomock.Any(), gomock.Any(), "ns", "product:create").Return(true, nil)
+	mockRepo.EXPECT().GetTemplateByID(gomock.Any(), "ns", template.ID).Return(template, nil)
+	mockRepo.EXPECT().Find(gomock.Any(), "ns", dto.ProductFilter{Slugs: []string{"camiseta-estampada-algodao"}}).Return(nil, nil)
+	mockRepo.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).Return(nil)
+	mockHistory.EXPECT().Record(gomock.Any(), "ns", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mockBus.EXPECT().Publish(gomock.Any(), "product:created", gomock.Any()).Return(nil)
+
+	product := &domain.Product{
+		Title:      "Camiseta Estampada Algodão",
+		Attributes: map[string]string{"brand": "Other"},
+	}
+	err = service.CreateProductFromTemplate(context.Background(), "ns", template.ID, product)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Camiseta Estampada Algodão", product.Title)
+	assert.Equal(t, "camiseta-estampada-algodao", product.Slug)
+	assert.Equal(t, template.Price, product.Price)
+	assert.Equal(t, template.Status, product.Status)
+	assert.Equal(t, template.ShortDesc, product.ShortDesc)
+	assert.Equal(t, map[string]string{"material": "cotton", "brand": "Other"}, product.Attributes)
+
+	require.Len(t, product.Variants, 2)
+	for i, v := range product.Variants {
+		assert.NotEqual(t, template.Variants[i].ID, v.ID)
+		assert.Equal(t, product.ID, v.ProductID)
+		assert.Equal(t, template.Variants[i].Title, v.Title)
+	}
+}
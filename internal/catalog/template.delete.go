@@ -0,0 +1,41 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+)
+
+func (s *ProductService) DeleteTemplate(ctx context.Context, namespace string, id uuid.UUID) error {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.DeleteTemplate")
+	defer span.End()
+
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, "product_template:delete")
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return domain.ErrUnauthorized
+	}
+
+	err = s.repo.DeleteTemplate(ctx, namespace, id)
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "product template deleted",
+		"template_id", id,
+		"deleted_by", userID,
+	)
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package catalog
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+)
+
+// GetTemplate retrieves a product template by its ID.
+func (s *ProductService) GetTemplate(ctx context.Context, namespace string, id uuid.UUID) (*domain.ProductTemplate, error) {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.GetTemplate")
+	defer span.End()
+
+	if err := s.checkPermission(ctx, namespace, "product_template:read"); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTemplateByID(ctx, namespace, id)
+}
+
+// FindTemplates lists the product templates of the namespace.
+func (s *ProductService) FindTemplates(ctx context.Context, namespace string) ([]*domain.ProductTemplate, error) {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.FindTemplates")
+	defer span.End()
+
+	if err := s.checkPermission(ctx, namespace, "product_template:read"); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindTemplates(ctx, namespace)
+}
@@ -0,0 +1,55 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// CreateTemplate stores a reusable product template for the namespace.
+func (s *ProductService) CreateTemplate(ctx context.Context, namespace string, template *domain.ProductTemplate) error {
+
+	ctx, span := observability.StartSpan(ctx, "catalog.CreateTemplate")
+	defer span.End()
+
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, "product_template:create")
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return domain.ErrUnauthorized
+	}
+
+	if strings.TrimSpace(template.Name) == "" {
+		return domain.ErrInvalidTemplateName
+	}
+
+	template.ID = uuid.New()
+	template.Namespace = namespace
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+
+	err = s.repo.CreateTemplate(ctx, namespace, template)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "product template created",
+		"template_id", template.ID,
+		"name", template.Name,
+		"created_by", userID,
+	)
+
+	return nil
+}
@@ -2,70 +2,181 @@ package catalog
 
 import (
 	"context"
+	"fmt"
 	"github.com/HBeserra/GoShop/domain"
 	"github.com/HBeserra/GoShop/pkg/currency"
 	"github.com/HBeserra/GoShop/pkg/observability"
 	"slices"
 )
 
+const (
+	minTitleLength      = 10
+	maxTitleLength      = 100
+	maxVariantPriceRate = 5
+)
+
+var minProductPrice = currency.NewFromFloat(1.00)
+
+var validProductStatus = []domain.ProductStatus{
+	domain.ProductStatusDraft,
+	domain.ProductStatusAvailable,
+	domain.ProductStatusOutOfStock,
+}
+
+// Validate checks the product against the catalog rules, without changing it.
+// Every violation is collected and returned as a *domain.ValidationError, which still matches
+// the product sentinel errors (domain.ErrInvalidProductTitle, ...) through errors.Is.
 func (s *ProductService) Validate(ctx context.Context, namespace string, product *domain.Product) error {
 
 	ctx, span := observability.StartSpan(ctx, "catalog.Validate")
 	defer span.End()
 
-	if len(product.Title) < 10 || len(product.Title) > 100 {
-		return domain.ErrInvalidProductTitle
+	verr := new(domain.ValidationError)
+
+	if len(product.Title) < minTitleLength || len(product.Title) > maxTitleLength {
+		verr.Add("title", domain.ValidationCodeLength, domain.ErrInvalidProductTitle, map[string]any{
+			"min": minTitleLength,
+			"max": maxTitleLength,
+		})
+	}
+
+	price := product.Price
+	switch product.Type {
+	case "", domain.ProductTypeSimple:
+	case domain.ProductTypeBundle:
+		// The bundle price is derived from the components, so it is resolved first
+		price = s.validateBundle(ctx, namespace, product, verr)
+	default:
+		verr.Add("type", domain.ValidationCodeOneOf, domain.ErrInvalidBundle, map[string]any{
+			"allowed": []domain.ProductType{domain.ProductTypeSimple, domain.ProductTypeBundle},
+		})
 	}
 
-	if product.Price.LessOrEqual(currency.NewFromFloat(1.00)) {
-		return domain.ErrInvalidProductPrice
+	if price.LessOrEqual(minProductPrice) {
+		verr.Add("price", domain.ValidationCodeMin, domain.ErrInvalidProductPrice, map[string]any{
+			"min": minProductPrice,
+		})
 	}
 
-	var minPrice, maxPrice float64
-	for _, variant := range product.Variants {
-		if variant.Price.LessOrEqual(currency.NewFromFloat(1.00)) {
-			return domain.ErrInvalidProductPrice
+	// The variant prices may not drift too far apart from each other
+	var minPrice, maxPrice currency.BRL
+	for i, variant := range product.Variants {
+		if variant.Price.LessOrEqual(minProductPrice) {
+			verr.Add(fmt.Sprintf("variants[%d].price", i), domain.ValidationCodeMin, domain.ErrInvalidProductPrice, map[string]any{
+				"min": minProductPrice,
+			})
 		}
 
-		if variant.Price.Float64() < minPrice {
-			minPrice = variant.Price.Float64()
+		if i == 0 || variant.Price < minPrice {
+			minPrice = variant.Price
 		}
-		if variant.Price.Float64() > maxPrice {
-			maxPrice = variant.Price.Float64()
+		if i == 0 || variant.Price > maxPrice {
+			maxPrice = variant.Price
 		}
 	}
 
-	if maxPrice/minPrice > 5 {
-		return domain.ErrInvalidProductPrice
+	if len(product.Variants) > 0 && minPrice > 0 && maxPrice.Float64()/minPrice.Float64() > maxVariantPriceRate {
+		verr.Add("variants", domain.ValidationCodeRatio, domain.ErrInvalidProductPrice, map[string]any{
+			"max_ratio": maxVariantPriceRate,
+			"min_price": minPrice,
+			"max_price": maxPrice,
+		})
+	}
+
+	validateSales(verr, "", price, product.CompareAtPrice, product.Sales)
+	for i, variant := range product.Variants {
+		validateSales(verr, fmt.Sprintf("variants[%d].", i), variant.Price, variant.CompareAtPrice, variant.Sales)
+	}
+
+	if product.NCM != "" && !domain.ValidNCM(product.NCM) {
+		verr.Add("ncm", domain.ValidationCodeInvalid, domain.ErrInvalidNCM, nil)
+	}
+	if product.CFOP != "" && !domain.ValidCFOP(product.CFOP) {
+		verr.Add("cfop", domain.ValidationCodeInvalid, domain.ErrInvalidCFOP, nil)
+	}
+	if !product.Origin.Valid() {
+		verr.Add("origin", domain.ValidationCodeRange, domain.ErrInvalidTaxOrigin, map[string]any{
+			"min": domain.TaxOriginNational,
+			"max": domain.TaxOriginNationalImportAbove70,
+		})
 	}
 
-	if product.Stock < 0 {
-		product.Stock = 0
+	validateShipping(verr, "shipping.", product.Shipping)
+	for i, variant := range product.Variants {
+		validateShipping(verr, fmt.Sprintf("variants[%d].shipping.", i), variant.Shipping)
 	}
 
-	if !slices.Contains([]domain.ProductStatus{
-		domain.ProductStatusDraft,
-		domain.ProductStatusAvailable,
-		domain.ProductStatusOutOfStock,
-	}, product.Status) {
-		return domain.ErrInvalidProductStatus
+	if !slices.Contains(validProductStatus, product.Status) {
+		verr.Add("status", domain.ValidationCodeOneOf, domain.ErrInvalidProductStatus, map[string]any{
+			"allowed": validProductStatus,
+		})
 	}
 
 	// Validate the medias
-	for _, mediaID := range product.Medias {
+	for i, mediaID := range product.Medias {
 		_, err := s.media.GetByID(ctx, namespace, mediaID)
 		if err != nil {
-			return domain.ErrInvalidMedia
+			verr.Add(fmt.Sprintf("medias[%d]", i), domain.ValidationCodeNotFound, domain.ErrInvalidMedia, map[string]any{
+				"id": mediaID,
+			})
 		}
 	}
 
-	for _, variant := range product.Variants {
-		for _, mediaID := range variant.Medias {
+	for i, variant := range product.Variants {
+		for j, mediaID := range variant.Medias {
 			_, err := s.media.GetByID(ctx, namespace, mediaID)
 			if err != nil {
-				return domain.ErrInvalidMedia
+				verr.Add(fmt.Sprintf("variants[%d].medias[%d]", i, j), domain.ValidationCodeNotFound, domain.ErrInvalidMedia, map[string]any{
+					"id": mediaID,
+				})
 			}
 		}
 	}
+
+	if err := verr.Err(); err != nil {
+		span.RecordError(err)
+		return err
+	}
 	return nil
 }
+
+// validateShipping checks the weight and dimensions of a product or variant, prefix is the field path prefix.
+func validateShipping(verr *domain.ValidationError, prefix string, profile domain.ShippingProfile) {
+	if profile.Weight < 0 {
+		verr.Add(prefix+"weight", domain.ValidationCodeMin, domain.ErrInvalidWeight, map[string]any{
+			"min": 0,
+		})
+	}
+	if !profile.Dimensions.Valid() {
+		verr.Add(prefix+"dimensions", domain.ValidationCodeInvalid, domain.ErrInvalidDimensions, nil)
+	}
+}
+
+// validateSales checks the compare-at price and the sale windows of a product or variant, prefix is the field path prefix.
+func validateSales(verr *domain.ValidationError, prefix string, price, compareAt currency.BRL, sales []domain.SalePrice) {
+	if !compareAt.IsZero() && compareAt < price {
+		verr.Add(prefix+"compare_at_price", domain.ValidationCodeMin, domain.ErrInvalidProductPrice, map[string]any{
+			"min": price,
+		})
+	}
+
+	// a sale above the regular price is never applied, see domain.EffectivePriceAt
+	for i, sale := range sales {
+		field := fmt.Sprintf("%ssales[%d]", prefix, i)
+		if sale.Price <= 0 {
+			verr.Add(field+".price", domain.ValidationCodeMin, domain.ErrInvalidSalePrice, map[string]any{
+				"min": 0,
+			})
+		}
+		if sale.Price > price {
+			verr.Add(field+".price", domain.ValidationCodeMax, domain.ErrInvalidSalePrice, map[string]any{
+				"max": price,
+			})
+		}
+		if !sale.Start.IsZero() && !sale.End.IsZero() && !sale.End.After(sale.Start) {
+			verr.Add(field+".end", domain.ValidationCodeMin, domain.ErrInvalidSalePrice, map[string]any{
+				"min": sale.Start,
+			})
+		}
+	}
+}
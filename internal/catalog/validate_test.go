@@ -0,0 +1,171 @@
+package catalog_test
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/catalog"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		product        *domain.Product
+		setup          func(media *MockMediaCtrl)
+		expectedFields []string
+		expectedErrors []error
+	}{
+		{
+			name: "valid product",
+			product: &domain.Product{
+				Title:  "Valid Product Title",
+				Price:  currency.NewFromFloat(50),
+				Status: domain.ProductStatusAvailable,
+				Variants: []domain.ProductVariant{
+					{Title: "Variant 1", Price: currency.NewFromFloat(40)},
+					{Title: "Variant 2", Price: currency.NewFromFloat(60)},
+				},
+			},
+		},
+		{
+			name: "collects every violation",
+			product: &domain.Product{
+				Title:  "Short",
+				Price:  currency.NewFromFloat(0.5),
+				Status: "unknown",
+				Variants: []domain.ProductVariant{
+					{Title: "Variant 1", Price: currency.NewFromFloat(40)},
+					{Title: "Variant 2", Price: currency.NewFromFloat(10)},
+					{Title: "Variant 3", Price: currency.NewFromFloat(0.2)},
+				},
+			},
+			expectedFields: []string{"title", "price", "variants[2].price", "variants", "status"},
+			expectedErrors: []error{domain.ErrInvalidProductTitle, domain.ErrInvalidProductPrice, domain.ErrInvalidProductStatus},
+		},
+		{
+			name: "variant price spread",
+			product: &domain.Product{
+				Title:  "Valid Product Title",
+				Price:  currency.NewFromFloat(50),
+				Status: domain.ProductStatusDraft,
+				Variants: []domain.ProductVariant{
+					{Title: "Variant 1", Price: currency.NewFromFloat(100)},
+					{Title: "Variant 2", Price: currency.NewFromFloat(10)},
+				},
+			},
+			expectedFields: []string{"variants"},
+			expectedErrors: []error{domain.ErrInvalidProductPrice},
+		},
+		{
+			name: "variant price spread ignores the base price",
+			product: &domain.Product{
+				Title:  "Valid Product Title",
+				Price:  currency.NewFromFloat(500),
+				Status: domain.ProductStatusDraft,
+				Variants: []domain.ProductVariant{
+					{Title: "Variant 1", Price: currency.NewFromFloat(20)},
+					{Title: "Variant 2", Price: currency.NewFromFloat(60)},
+				},
+			},
+		},
+		{
+			name: "sale prices",
+			product: &domain.Product{
+				Title:          "Valid Product Title",
+				Price:          currency.NewFromFloat(50),
+				CompareAtPrice: currency.NewFromFloat(60),
+				Status:         domain.ProductStatusAvailable,
+				Sales: []domain.SalePrice{
+					{Price: currency.NewFromFloat(55)},
+					{Price: currency.NewFromFloat(65)},
+					{Price: currency.NewFromFloat(40), Start: time.Now(), End: time.Now().Add(-time.Hour)},
+				},
+				Variants: []domain.ProductVariant{
+					{Title: "Variant 1", Price: currency.NewFromFloat(50), CompareAtPrice: currency.NewFromFloat(45)},
+				},
+			},
+			expectedFields: []string{"sales[0].price", "sales[1].price", "sales[2].end", "variants[0].compare_at_price"},
+			expectedErrors: []error{domain.ErrInvalidSalePrice, domain.ErrInvalidProductPrice},
+		},
+		{
+			name: "tax classification",
+			product: &domain.Product{
+				Title:  "Valid Product Title",
+				Price:  currency.NewFromFloat(50),
+				Status: domain.ProductStatusDraft,
+				NCM:    "8471.30.12",
+				Origin: 9,
+				CFOP:   "6102",
+			},
+			expectedFields: []string{"ncm", "cfop", "origin"},
+			expectedErrors: []error{domain.ErrInvalidNCM, domain.ErrInvalidCFOP, domain.ErrInvalidTaxOrigin},
+		},
+		{
+			name: "shipping profile",
+			product: &domain.Product{
+				Title:    "Valid Product Title",
+				Price:    currency.NewFromFloat(50),
+				Status:   domain.ProductStatusDraft,
+				Shipping: domain.ShippingProfile{Weight: -1},
+				Variants: []domain.ProductVariant{
+					{Title: "Variant 1", Price: currency.NewFromFloat(50), Shipping: domain.ShippingProfile{Dimensions: domain.Dimensions{Length: 100}}},
+				},
+			},
+			expectedFields: []string{"shipping.weight", "variants[0].shipping.dimensions"},
+			expectedErrors: []error{domain.ErrInvalidWeight, domain.ErrInvalidDimensions},
+		},
+		{
+			name: "missing medias",
+			product: &domain.Product{
+				Title:  "Valid Product Title",
+				Price:  currency.NewFromFloat(50),
+				Status: domain.ProductStatusDraft,
+				Medias: []uuid.UUID{uuid.New()},
+				Variants: []domain.ProductVariant{
+					{Title: "Variant 1", Price: currency.NewFromFloat(50), Medias: []uuid.UUID{uuid.New(), uuid.New()}},
+				},
+			},
+			setup: func(media *MockMediaCtrl) {
+				media.EXPECT().GetByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("not found")).Times(3)
+			},
+			expectedFields: []string{"medias[0]", "variants[0].medias[0]", "variants[0].medias[1]"},
+			expectedErrors: []error{domain.ErrInvalidMedia},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMedia := NewMockMediaCtrl(ctrl)
+			service, err := catalog.NewProductService(NewMockProductRepository(ctrl), NewMockEventBus(ctrl), NewMockAuthService(ctrl), mockMedia, NewMockPriceHistoryRepository(ctrl))
+			require.NoError(t, err)
+
+			if tt.setup != nil {
+				tt.setup(mockMedia)
+			}
+
+			err = service.Validate(context.Background(), "", tt.product)
+			if len(tt.expectedFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *domain.ValidationError
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, tt.expectedFields, verr.Fields())
+			for _, expected := range tt.expectedErrors {
+				assert.ErrorIs(t, err, expected)
+			}
+		})
+	}
+}
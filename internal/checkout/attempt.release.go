@@ -0,0 +1,95 @@
+package checkout
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/promotion"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// ReleaseStale compensates the checkouts interrupted before completing, whose attempt has not progressed for
+// the AttemptTimeout, and removes their attempts. It is meant to run periodically.
+func (s *Service) ReleaseStale(ctx context.Context, namespace string) (int, error) {
+
+	ctx, span := observability.StartSpan(ctx, "checkout.ReleaseStale")
+	defer span.End()
+
+	now := time.Now()
+	attempts, err := s.attempts.FindStale(ctx, namespace, now.Add(-s.config.AttemptTimeout))
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	released := 0
+	for _, attempt := range attempts {
+		ok, err := s.release(ctx, namespace, attempt, now)
+		if err != nil {
+			span.RecordError(err)
+			return released, err
+		}
+		if ok {
+			released++
+		}
+	}
+
+	slog.InfoContext(ctx, "stale checkout attempts released",
+		"namespace", namespace,
+		"released", released,
+	)
+	return released, nil
+}
+
+// stale reports whether the attempt is in progress and has not progressed for the AttemptTimeout.
+func (s *Service) stale(attempt *domain.CheckoutAttempt, now time.Time) bool {
+	return attempt.Status == domain.CheckoutStatusInProgress && attempt.UpdatedAt.Before(now.Add(-s.config.AttemptTimeout))
+}
+
+// release removes the stale attempt and compensates the steps it recorded. It reports false when the attempt
+// progressed or was released by someone else meanwhile.
+func (s *Service) release(ctx context.Context, namespace string, attempt *domain.CheckoutAttempt, now time.Time) (bool, error) {
+	claimed, err := s.attempts.DeleteStale(ctx, namespace, attempt.Key, now.Add(-s.config.AttemptTimeout))
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	c := &checkout{
+		namespace:  namespace,
+		customerID: attempt.CustomerID,
+		attempt:    attempt,
+		cart:       &domain.Cart{ID: attempt.CartID, Items: attempt.Reserved},
+		order:      &domain.Order{ID: attempt.OrderID},
+		discounts:  &promotion.Result{},
+		payment:    &domain.Payment{ID: attempt.PaymentID},
+	}
+	for _, id := range attempt.Promotions {
+		c.discounts.Applied = append(c.discounts.Applied, promotion.AppliedPromotion{PromotionID: id})
+	}
+
+	// The steps are compensated in reverse order of the progress recorded
+	sg := &saga{}
+	if len(attempt.Reserved) > 0 {
+		sg.done = append(sg.done, step{name: "reserve stock", compensate: c.releaseStock(s)})
+	}
+	if attempt.OrderID != uuid.Nil {
+		sg.done = append(sg.done, step{name: "create order", compensate: c.cancelOrder(s)})
+	}
+	if len(attempt.Promotions) > 0 {
+		sg.done = append(sg.done, step{name: "redeem coupons", compensate: c.releaseCoupons(s)})
+	}
+	if attempt.PaymentID != uuid.Nil {
+		sg.done = append(sg.done, step{name: "authorize payment", compensate: c.voidPayment(s)})
+	}
+	sg.rollback(ctx)
+
+	slog.InfoContext(ctx, "stale checkout attempt released",
+		"idempotency_key", attempt.Key,
+		"cart_id", attempt.CartID,
+		"customer_id", attempt.CustomerID,
+		"order_id", attempt.OrderID,
+	)
+	return true, nil
+}
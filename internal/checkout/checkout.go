@@ -0,0 +1,377 @@
+package checkout
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/promotion"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Request is a checkout submitted by the customer.
+type Request struct {
+	CartID uuid.UUID `json:"cart_id"`
+	// IdempotencyKey is generated by the client once per checkout and sent again on retries.
+	IdempotencyKey string               `json:"idempotency_key"`
+	PaymentMethod  domain.PaymentMethod `json:"payment_method"`
+	// ExpectedTotal is the total shown to the customer, the checkout fails with domain.ErrPriceChanged
+	// if the order total differs. It is not checked when zero.
+	ExpectedTotal currency.BRL `json:"expected_total"`
+}
+
+// checkout holds the state shared by the saga steps. The steps with side effects record their progress
+// in the attempt.
+type checkout struct {
+	namespace  string
+	customerID uuid.UUID
+	request    Request
+	attempt    *domain.CheckoutAttempt
+	cart       *domain.Cart
+	discounts  *promotion.Result
+	order      *domain.Order
+	payment    *domain.Payment
+}
+
+// Checkout turns the cart of the current user into a pending order with an authorized payment.
+//
+// The steps run as a saga: stock reservation, coupons, order creation, price lock, coupon redemption
+// and payment authorization. When a step fails the previous ones are compensated in reverse order,
+// releasing the stock and coupons, cancelling the order and voiding the payment.
+//
+// A request submitted again with the same idempotency key returns the order placed by the first one. The progress
+// is recorded in the attempt after each step, so an attempt interrupted by a crash is compensated and started
+// over once it has not progressed for the AttemptTimeout, see ReleaseStale.
+func (s *Service) Checkout(ctx context.Context, namespace string, req Request) (*domain.Order, error) {
+
+	ctx, span := observability.StartSpan(ctx, "checkout.Checkout")
+	defer span.End()
+
+	if req.IdempotencyKey == "" {
+		return nil, domain.ErrMissingIdempotencyKey
+	}
+
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if userID == uuid.Nil {
+		return nil, domain.ErrUnauthorized
+	}
+
+	now := time.Now()
+	attempt, created, err := s.begin(ctx, namespace, &domain.CheckoutAttempt{
+		Key:        req.IdempotencyKey,
+		Namespace:  namespace,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		CartID:     req.CartID,
+		CustomerID: userID,
+		Status:     domain.CheckoutStatusInProgress,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if !created {
+		return s.replay(ctx, namespace, attempt, userID, req)
+	}
+
+	c := &checkout{namespace: namespace, customerID: userID, request: req, attempt: attempt}
+	sg := &saga{}
+	steps := []step{
+		{name: "load cart", action: c.loadCart(s)},
+		{name: "reserve stock", action: c.reserveStock(s), compensate: c.releaseStock(s)},
+		{name: "apply coupons", action: c.applyCoupons(s)},
+		{name: "create order", action: c.createOrder(s), compensate: c.cancelOrder(s)},
+		{name: "lock prices", action: c.lockPrices},
+		{name: "redeem coupons", action: c.redeemCoupons(s), compensate: c.releaseCoupons(s)},
+		{name: "authorize payment", action: c.authorizePayment(s), compensate: c.voidPayment(s)},
+	}
+	for _, st := range steps {
+		if err = sg.run(ctx, st); err != nil {
+			break
+		}
+		if st.compensate != nil {
+			if err = s.record(ctx, c); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+		sg.rollback(ctx)
+		if derr := s.attempts.Delete(context.WithoutCancel(ctx), namespace, req.IdempotencyKey); derr != nil {
+			slog.ErrorContext(ctx, "failed to delete checkout attempt",
+				"idempotency_key", req.IdempotencyKey,
+				"error", derr,
+			)
+		}
+		slog.InfoContext(ctx, "checkout failed",
+			"cart_id", req.CartID,
+			"customer_id", userID,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	attempt.Status = domain.CheckoutStatusCompleted
+	attempt.OrderID = c.order.ID
+	attempt.PaymentID = c.payment.ID
+	attempt.UpdatedAt = time.Now()
+	if err = s.attempts.Complete(ctx, namespace, attempt); err != nil {
+		// The order is placed, failing now would leave the customer without it
+		slog.ErrorContext(ctx, "failed to complete checkout attempt",
+			"idempotency_key", req.IdempotencyKey,
+			"order_id", c.order.ID,
+			"error", err,
+		)
+	}
+
+	if err = s.carts.Delete(ctx, namespace, req.CartID); err != nil {
+		slog.ErrorContext(ctx, "failed to delete checked out cart",
+			"cart_id", req.CartID,
+			"order_id", c.order.ID,
+			"error", err,
+		)
+	}
+
+	slog.InfoContext(ctx, "checkout completed",
+		"cart_id", req.CartID,
+		"order_id", c.order.ID,
+		"payment_id", c.payment.ID,
+		"customer_id", userID,
+	)
+	return c.order, nil
+}
+
+// begin stores the attempt unless one with the same key exists. An existing attempt of the same checkout
+// which is stale is compensated and replaced.
+func (s *Service) begin(ctx context.Context, namespace string, attempt *domain.CheckoutAttempt) (*domain.CheckoutAttempt, bool, error) {
+	stored, created, err := s.attempts.Begin(ctx, namespace, attempt)
+	if err != nil || created {
+		return stored, created, err
+	}
+	if stored.CustomerID != attempt.CustomerID || stored.CartID != attempt.CartID || !s.stale(stored, attempt.CreatedAt) {
+		return stored, false, nil
+	}
+
+	released, err := s.release(ctx, namespace, stored, attempt.CreatedAt)
+	if err != nil {
+		return nil, false, err
+	}
+	if !released {
+		// another request released it first and is checking out again
+		return stored, false, nil
+	}
+	return s.attempts.Begin(ctx, namespace, attempt)
+}
+
+// record stores the progress of the checkout in its attempt.
+func (s *Service) record(ctx context.Context, c *checkout) error {
+	c.attempt.UpdatedAt = time.Now()
+	if err := s.attempts.Update(ctx, c.namespace, c.attempt); err != nil {
+		return fmt.Errorf("record progress: %w", err)
+	}
+	return nil
+}
+
+// replay answers a request whose idempotency key was already used.
+func (s *Service) replay(ctx context.Context, namespace string, attempt *domain.CheckoutAttempt, userID uuid.UUID, req Request) (*domain.Order, error) {
+	if attempt.CustomerID != userID || attempt.CartID != req.CartID {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if attempt.Status != domain.CheckoutStatusCompleted {
+		return nil, domain.ErrCheckoutInProgress
+	}
+	return s.orders.GetOrder(ctx, namespace, attempt.OrderID)
+}
+
+func (c *checkout) loadCart(s *Service) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		cart, err := s.carts.Get(ctx, c.namespace, c.request.CartID)
+		if err != nil {
+			return err
+		}
+		if len(cart.Items) == 0 {
+			return domain.ErrEmptyCart
+		}
+		if cart.HasBlockingIssues() {
+			return domain.ErrCartHasIssues
+		}
+		if cart.ShippingRequired {
+			if cart.ShippingOption == "" {
+				return domain.ErrShippingNotSelected
+			}
+			// the option is quoted again with the cart, it may no longer be offered
+			if _, ok := cart.SelectedShipping(); !ok {
+				return fmt.Errorf("%w: %s", domain.ErrShippingOptionNotFound, cart.ShippingOption)
+			}
+		}
+		c.cart = cart
+		return nil
+	}
+}
+
+func (c *checkout) reserveStock(s *Service) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for i, item := range c.cart.Items {
+			if err := s.inventory.MoveStock(ctx, c.namespace, item.ProductID, item.VariantID, -item.Quantity); err != nil {
+				// The step is not compensated when it fails, so the items reserved so far are released here
+				c.releaseItems(ctx, s, c.cart.Items[:i])
+				return err
+			}
+		}
+		c.attempt.Reserved = c.cart.Items
+		return nil
+	}
+}
+
+func (c *checkout) releaseStock(s *Service) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		c.releaseItems(ctx, s, c.cart.Items)
+		return nil
+	}
+}
+
+func (c *checkout) releaseItems(ctx context.Context, s *Service, items []domain.CartItem) {
+	for _, item := range items {
+		if err := s.inventory.MoveStock(ctx, c.namespace, item.ProductID, item.VariantID, item.Quantity); err != nil {
+			slog.ErrorContext(ctx, "failed to release reserved stock",
+				"product_id", item.ProductID,
+				"variant_id", item.VariantID,
+				"quantity", item.Quantity,
+				"error", err,
+			)
+		}
+	}
+}
+
+func (c *checkout) applyCoupons(s *Service) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		groups, err := s.groups.GetGroups(ctx, c.namespace, c.customerID)
+		if err != nil {
+			return err
+		}
+
+		input := promotion.Input{
+			CustomerID:     c.customerID,
+			CustomerGroups: groups,
+			Coupons:        c.cart.Coupons,
+			Shipping:       c.cart.Shipping,
+			Items:          make([]promotion.LineItem, 0, len(c.cart.Items)),
+		}
+		for _, item := range c.cart.Items {
+			input.Items = append(input.Items, promotion.LineItem{
+				ProductID:  item.ProductID,
+				VariantID:  item.VariantID,
+				Categories: item.Categories,
+				Quantity:   item.Quantity,
+				UnitPrice:  item.UnitPrice,
+			})
+		}
+
+		result, err := s.promotions.Apply(ctx, c.namespace, input)
+		if err != nil {
+			return err
+		}
+		// The customer expects the discount of every coupon entered
+		if len(result.Rejected) > 0 {
+			r := result.Rejected[0]
+			return fmt.Errorf("coupon %s: %w", r.Code, r.Err)
+		}
+		c.discounts = result
+		return nil
+	}
+}
+
+func (c *checkout) createOrder(s *Service) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		order := &domain.Order{
+			Coupons:        c.cart.Coupons,
+			ShippingCEP:    c.cart.ShippingCEP,
+			ShippingOption: c.cart.ShippingOption,
+			Lines:          make([]domain.OrderLine, 0, len(c.cart.Items)),
+		}
+		for _, item := range c.cart.Items {
+			order.Lines = append(order.Lines, domain.OrderLine{
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Quantity:  item.Quantity,
+			})
+		}
+
+		if err := s.orders.PlaceOrder(ctx, c.namespace, c.customerID, order, c.discounts); err != nil {
+			return err
+		}
+		c.order = order
+		c.attempt.OrderID = order.ID
+		return nil
+	}
+}
+
+func (c *checkout) cancelOrder(s *Service) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := s.orders.Abandon(ctx, c.namespace, c.order.ID, "checkout failed")
+		return err
+	}
+}
+
+// lockPrices makes sure the order was placed at the prices the customer agreed to: the expected total when sent,
+// otherwise the prices the items were added to the cart at. The cart is re-validated when loaded, so those items
+// are flagged when their price changed since, and the order lines are snapshotted from the catalog afterward.
+func (c *checkout) lockPrices(_ context.Context) error {
+	if c.request.ExpectedTotal != 0 {
+		if c.request.ExpectedTotal != c.order.Total {
+			return fmt.Errorf("%w: expected total %s, got %s", domain.ErrPriceChanged, c.request.ExpectedTotal, c.order.Total)
+		}
+		return nil
+	}
+	for i, item := range c.cart.Items {
+		if slices.Contains(item.Issues, domain.CartIssuePriceChanged) || c.order.Lines[i].UnitPrice != item.UnitPrice {
+			return fmt.Errorf("%w: %s", domain.ErrPriceChanged, item.SKU)
+		}
+	}
+	return nil
+}
+
+func (c *checkout) redeemCoupons(s *Service) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if err := s.promotions.Redeem(ctx, c.namespace, c.customerID, c.order.ID, c.discounts); err != nil {
+			return err
+		}
+		for _, applied := range c.discounts.Applied {
+			c.attempt.Promotions = append(c.attempt.Promotions, applied.PromotionID)
+		}
+		return nil
+	}
+}
+
+func (c *checkout) releaseCoupons(s *Service) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		s.promotions.Release(ctx, c.namespace, c.order.ID, c.discounts)
+		return nil
+	}
+}
+
+func (c *checkout) authorizePayment(s *Service) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		payment, err := s.payments.Authorize(ctx, c.namespace, c.order, c.request.PaymentMethod)
+		if err != nil {
+			return err
+		}
+		c.payment = payment
+		c.attempt.PaymentID = payment.ID
+		return nil
+	}
+}
+
+func (c *checkout) voidPayment(s *Service) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return s.payments.Abandon(ctx, c.namespace, c.payment.ID)
+	}
+}
@@ -0,0 +1,499 @@
+package checkout_test
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/checkout"
+	"github.com/HBeserra/GoShop/internal/promotion"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type setupParams struct {
+	attemptsService   *MockAttemptRepository
+	cartsService      *MockCarts
+	inventoryService  *MockInventory
+	promotionsService *MockPromotions
+	groupsService     *MockCustomerGroups
+	ordersService     *MockOrders
+	paymentsService   *MockPayments
+	authService       *MockAuthService
+}
+
+var config = &checkout.Config{AttemptTimeout: 15 * time.Minute}
+
+type fixture struct {
+	userID    uuid.UUID
+	cart      *domain.Cart
+	request   checkout.Request
+	result    *promotion.Result
+	paymentID uuid.UUID
+	// repriced is the price the first line is snapshotted at when the catalog changes during the checkout
+	repriced currency.BRL
+}
+
+func newFixture() *fixture {
+	cart := &domain.Cart{
+		ID:      uuid.New(),
+		Coupons: []string{"SALE10"},
+		Items: []domain.CartItem{
+			{ProductID: uuid.New(), SKU: "A", Quantity: 2, UnitPrice: currency.NewFromFloat(50)},
+			{ProductID: uuid.New(), VariantID: uuid.New(), SKU: "B", Quantity: 1, UnitPrice: currency.NewFromFloat(100)},
+		},
+	}
+	return &fixture{
+		userID:  uuid.New(),
+		cart:    cart,
+		request: checkout.Request{CartID: cart.ID, IdempotencyKey: "key-1", PaymentMethod: domain.PaymentMethodPix},
+		result: &promotion.Result{
+			Applied:       []promotion.AppliedPromotion{{PromotionID: uuid.New(), Code: "SALE10", Discount: currency.NewFromFloat(20)}},
+			ItemDiscounts: []currency.BRL{currency.NewFromFloat(10), currency.NewFromFloat(10)},
+			Discount:      currency.NewFromFloat(20),
+		},
+		paymentID: uuid.New(),
+	}
+}
+
+// expectBegin sets the expectations of a new checkout loading its cart.
+func expectBegin(t setupParams, f *fixture) {
+	t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+	t.attemptsService.EXPECT().Begin(gomock.Any(), "ns", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, a *domain.CheckoutAttempt) (*domain.CheckoutAttempt, bool, error) {
+			return a, true, nil
+		})
+	t.cartsService.EXPECT().Get(gomock.Any(), "ns", f.cart.ID).Return(f.cart, nil)
+}
+
+// expectUntilOrder sets the expectations of a checkout reaching the price lock.
+func expectUntilOrder(t setupParams, f *fixture) {
+	expectBegin(t, f)
+	for _, item := range f.cart.Items {
+		t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", item.ProductID, item.VariantID, -item.Quantity).Return(nil)
+	}
+	t.groupsService.EXPECT().GetGroups(gomock.Any(), "ns", f.userID).Return(nil, nil)
+	t.promotionsService.EXPECT().Apply(gomock.Any(), "ns", gomock.Any()).Return(f.result, nil)
+	t.ordersService.EXPECT().PlaceOrder(gomock.Any(), "ns", f.userID, gomock.Any(), f.result).
+		DoAndReturn(func(_ context.Context, _ string, _ uuid.UUID, order *domain.Order, discounts *promotion.Result) error {
+			order.ID = uuid.New()
+			order.Status = domain.OrderStatusPending
+			for i := range order.Lines {
+				order.Lines[i].UnitPrice = f.cart.Items[i].UnitPrice
+				order.Lines[i].Discount = discounts.ItemDiscounts[i]
+			}
+			if f.repriced != 0 {
+				order.Lines[0].UnitPrice = f.repriced
+			}
+			order.Shipping, order.ShippingDiscount = discounts.Shipping, discounts.ShippingDiscount
+			order.ComputeTotals()
+			return nil
+		})
+	// progress after the stock reservation and the order creation
+	t.attemptsService.EXPECT().Update(gomock.Any(), "ns", gomock.Any()).Return(nil).Times(2)
+}
+
+// expectCompleted sets the expectations of a checkout completing after the price lock.
+func expectCompleted(t setupParams, f *fixture) {
+	t.promotionsService.EXPECT().Redeem(gomock.Any(), "ns", f.userID, gomock.Any(), f.result).Return(nil)
+	t.paymentsService.EXPECT().Authorize(gomock.Any(), "ns", gomock.Any(), domain.PaymentMethodPix).
+		DoAndReturn(func(_ context.Context, _ string, order *domain.Order, _ domain.PaymentMethod) (*domain.Payment, error) {
+			return &domain.Payment{ID: f.paymentID, OrderID: order.ID, Amount: order.Total}, nil
+		})
+	t.attemptsService.EXPECT().Update(gomock.Any(), "ns", gomock.Any()).Return(nil).Times(2)
+	t.attemptsService.EXPECT().Complete(gomock.Any(), "ns", gomock.Any()).Return(nil)
+	t.cartsService.EXPECT().Delete(gomock.Any(), "ns", f.cart.ID).Return(nil)
+}
+
+// expectReleased sets the expectations of the compensation of the stock, order and coupons, in reverse order.
+func expectReleased(t setupParams, f *fixture) {
+	gomock.InOrder(
+		t.promotionsService.EXPECT().Release(gomock.Any(), "ns", gomock.Any(), gomock.Any()),
+		t.ordersService.EXPECT().Abandon(gomock.Any(), "ns", gomock.Any(), "checkout failed").Return(nil, nil),
+		t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", f.cart.Items[0].ProductID, f.cart.Items[0].VariantID, int64(2)).Return(nil),
+		t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", f.cart.Items[1].ProductID, f.cart.Items[1].VariantID, int64(1)).Return(nil),
+	)
+}
+
+func TestCheckout(t *testing.T) {
+	declined := errors.New("payment declined")
+	unavailable := errors.New("database is down")
+	var progress []domain.CheckoutAttempt
+	options := []domain.ShippingOption{{Provider: "table", Service: "pac", Price: currency.NewFromFloat(15)}}
+
+	tests := []struct {
+		name     string
+		fixture  func(f *fixture)
+		setup    func(t setupParams, f *fixture)
+		err      error
+		expected func(t *testing.T, f *fixture, order *domain.Order)
+	}{
+		{
+			name: "places the order",
+			setup: func(t setupParams, f *fixture) {
+				expectUntilOrder(t, f)
+				expectCompleted(t, f)
+			},
+			expected: func(t *testing.T, f *fixture, order *domain.Order) {
+				assert.Equal(t, []string{"SALE10"}, order.Coupons)
+				assert.Equal(t, currency.NewFromFloat(10), order.Lines[0].Discount)
+				assert.Equal(t, currency.NewFromFloat(180), order.Total)
+			},
+		},
+		{
+			name: "records the progress of the attempt",
+			setup: func(t setupParams, f *fixture) {
+				expectBegin(t, f)
+				t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				t.groupsService.EXPECT().GetGroups(gomock.Any(), "ns", f.userID).Return(nil, nil)
+				t.promotionsService.EXPECT().Apply(gomock.Any(), "ns", gomock.Any()).Return(f.result, nil)
+				t.ordersService.EXPECT().PlaceOrder(gomock.Any(), "ns", f.userID, gomock.Any(), f.result).
+					DoAndReturn(func(_ context.Context, _ string, _ uuid.UUID, order *domain.Order, _ *promotion.Result) error {
+						order.ID = uuid.New()
+						for i := range order.Lines {
+							order.Lines[i].UnitPrice = f.cart.Items[i].UnitPrice
+						}
+						return nil
+					})
+				t.promotionsService.EXPECT().Redeem(gomock.Any(), "ns", f.userID, gomock.Any(), f.result).Return(nil)
+				t.paymentsService.EXPECT().Authorize(gomock.Any(), "ns", gomock.Any(), domain.PaymentMethodPix).Return(&domain.Payment{ID: f.paymentID}, nil)
+
+				t.attemptsService.EXPECT().Update(gomock.Any(), "ns", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, a *domain.CheckoutAttempt) error {
+						progress = append(progress, *a)
+						return nil
+					}).Times(4)
+				t.attemptsService.EXPECT().Complete(gomock.Any(), "ns", gomock.Any()).Return(nil)
+				t.cartsService.EXPECT().Delete(gomock.Any(), "ns", f.cart.ID).Return(nil)
+			},
+			expected: func(t *testing.T, f *fixture, _ *domain.Order) {
+				require.Len(t, progress, 4)
+				assert.Equal(t, f.cart.Items, progress[0].Reserved)
+				assert.Equal(t, uuid.Nil, progress[0].OrderID)
+				assert.NotEqual(t, uuid.Nil, progress[1].OrderID)
+				assert.Empty(t, progress[1].Promotions)
+				assert.Equal(t, []uuid.UUID{f.result.Applied[0].PromotionID}, progress[2].Promotions)
+				assert.Equal(t, f.paymentID, progress[3].PaymentID)
+			},
+		},
+		{
+			name: "payment failure rolls every step back",
+			setup: func(t setupParams, f *fixture) {
+				expectUntilOrder(t, f)
+				t.promotionsService.EXPECT().Redeem(gomock.Any(), "ns", f.userID, gomock.Any(), f.result).Return(nil)
+				t.attemptsService.EXPECT().Update(gomock.Any(), "ns", gomock.Any()).Return(nil)
+				t.paymentsService.EXPECT().Authorize(gomock.Any(), "ns", gomock.Any(), domain.PaymentMethodPix).Return(nil, declined)
+				expectReleased(t, f)
+				t.attemptsService.EXPECT().Delete(gomock.Any(), "ns", "key-1").Return(nil)
+			},
+			err: declined,
+		},
+		{
+			name:    "expected total differs",
+			fixture: func(f *fixture) { f.request.ExpectedTotal = currency.NewFromFloat(150) },
+			setup: func(t setupParams, f *fixture) {
+				expectUntilOrder(t, f)
+				t.ordersService.EXPECT().Abandon(gomock.Any(), "ns", gomock.Any(), "checkout failed").Return(nil, nil)
+				t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				t.attemptsService.EXPECT().Delete(gomock.Any(), "ns", "key-1").Return(nil)
+			},
+			err: domain.ErrPriceChanged,
+		},
+		{
+			name:    "price changed since the item was added",
+			fixture: func(f *fixture) { f.cart.Items[1].Issues = []domain.CartIssue{domain.CartIssuePriceChanged} },
+			setup: func(t setupParams, f *fixture) {
+				expectUntilOrder(t, f)
+				t.ordersService.EXPECT().Abandon(gomock.Any(), "ns", gomock.Any(), "checkout failed").Return(nil, nil)
+				t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				t.attemptsService.EXPECT().Delete(gomock.Any(), "ns", "key-1").Return(nil)
+			},
+			err: domain.ErrPriceChanged,
+		},
+		{
+			name:    "price changed while checking out",
+			fixture: func(f *fixture) { f.repriced = currency.NewFromFloat(55) },
+			setup: func(t setupParams, f *fixture) {
+				expectUntilOrder(t, f)
+				t.ordersService.EXPECT().Abandon(gomock.Any(), "ns", gomock.Any(), "checkout failed").Return(nil, nil)
+				t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				t.attemptsService.EXPECT().Delete(gomock.Any(), "ns", "key-1").Return(nil)
+			},
+			err: domain.ErrPriceChanged,
+		},
+		{
+			name: "insufficient stock releases the items already reserved",
+			setup: func(t setupParams, f *fixture) {
+				expectBegin(t, f)
+				first, second := f.cart.Items[0], f.cart.Items[1]
+				gomock.InOrder(
+					t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", first.ProductID, first.VariantID, int64(-2)).Return(nil),
+					t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", second.ProductID, second.VariantID, int64(-1)).Return(domain.ErrInsufficientStock),
+					t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", first.ProductID, first.VariantID, int64(2)).Return(nil),
+				)
+				t.attemptsService.EXPECT().Delete(gomock.Any(), "ns", "key-1").Return(nil)
+			},
+			err: domain.ErrInsufficientStock,
+		},
+		{
+			name: "progress not recorded",
+			setup: func(t setupParams, f *fixture) {
+				expectBegin(t, f)
+				t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				t.attemptsService.EXPECT().Update(gomock.Any(), "ns", gomock.Any()).Return(unavailable)
+				// the reserved stock is released
+				t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				t.attemptsService.EXPECT().Delete(gomock.Any(), "ns", "key-1").Return(nil)
+			},
+			err: unavailable,
+		},
+		{
+			name: "rejected coupon",
+			fixture: func(f *fixture) {
+				f.result.Rejected = []promotion.RejectedCoupon{{Code: "SALE10", Err: domain.ErrCouponUsageLimit}}
+			},
+			setup: func(t setupParams, f *fixture) {
+				expectBegin(t, f)
+				t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(4)
+				t.attemptsService.EXPECT().Update(gomock.Any(), "ns", gomock.Any()).Return(nil)
+				t.groupsService.EXPECT().GetGroups(gomock.Any(), "ns", f.userID).Return(nil, nil)
+				t.promotionsService.EXPECT().Apply(gomock.Any(), "ns", gomock.Any()).Return(f.result, nil)
+				t.attemptsService.EXPECT().Delete(gomock.Any(), "ns", "key-1").Return(nil)
+			},
+			err: domain.ErrCouponUsageLimit,
+		},
+		{
+			name: "chosen shipping option is charged",
+			fixture: func(f *fixture) {
+				f.cart.ShippingRequired = true
+				f.cart.ShippingCEP, f.cart.ShippingOption = "40060000", "table:pac"
+				f.cart.ShippingOptions, f.cart.Shipping = options, currency.NewFromFloat(15)
+				f.result.Shipping = currency.NewFromFloat(15)
+			},
+			setup: func(t setupParams, f *fixture) {
+				expectUntilOrder(t, f)
+				expectCompleted(t, f)
+			},
+			expected: func(t *testing.T, f *fixture, order *domain.Order) {
+				assert.Equal(t, domain.CEP("40060000"), order.ShippingCEP)
+				assert.Equal(t, "table:pac", order.ShippingOption)
+				assert.Equal(t, currency.NewFromFloat(15), order.Shipping)
+				assert.Equal(t, currency.NewFromFloat(195), order.Total)
+			},
+		},
+		{
+			name: "no shipping option chosen",
+			fixture: func(f *fixture) {
+				f.cart.ShippingRequired = true
+				f.cart.ShippingCEP, f.cart.ShippingOptions = "40060000", options
+			},
+			setup: func(t setupParams, f *fixture) {
+				expectBegin(t, f)
+				t.attemptsService.EXPECT().Delete(gomock.Any(), "ns", "key-1").Return(nil)
+			},
+			err: domain.ErrShippingNotSelected,
+		},
+		{
+			name: "shipping option no longer offered",
+			fixture: func(f *fixture) {
+				f.cart.ShippingRequired = true
+				f.cart.ShippingCEP, f.cart.ShippingOption, f.cart.ShippingOptions = "40060000", "table:sedex", options
+			},
+			setup: func(t setupParams, f *fixture) {
+				expectBegin(t, f)
+				t.attemptsService.EXPECT().Delete(gomock.Any(), "ns", "key-1").Return(nil)
+			},
+			err: domain.ErrShippingOptionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			params := setupParams{
+				attemptsService:   NewMockAttemptRepository(ctrl),
+				cartsService:      NewMockCarts(ctrl),
+				inventoryService:  NewMockInventory(ctrl),
+				promotionsService: NewMockPromotions(ctrl),
+				groupsService:     NewMockCustomerGroups(ctrl),
+				ordersService:     NewMockOrders(ctrl),
+				paymentsService:   NewMockPayments(ctrl),
+				authService:       NewMockAuthService(ctrl),
+			}
+			service := checkout.NewService(config, params.attemptsService, params.cartsService, params.inventoryService,
+				params.promotionsService, params.groupsService, params.ordersService, params.paymentsService, params.authService)
+
+			f := newFixture()
+			if tt.fixture != nil {
+				tt.fixture(f)
+			}
+			tt.setup(params, f)
+
+			order, err := service.Checkout(context.Background(), "ns", f.request)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expected != nil {
+				tt.expected(t, f, order)
+			}
+		})
+	}
+}
+
+func TestCheckout_Idempotency(t *testing.T) {
+	f := newFixture()
+	orderID := uuid.New()
+	stale := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		request checkout.Request
+		setup   func(t setupParams)
+		err     error
+	}{
+		{
+			name:    "completed checkout returns the same order",
+			request: f.request,
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+				t.attemptsService.EXPECT().Begin(gomock.Any(), "ns", gomock.Any()).
+					Return(&domain.CheckoutAttempt{Key: "key-1", CartID: f.cart.ID, CustomerID: f.userID, Status: domain.CheckoutStatusCompleted, OrderID: orderID}, false, nil)
+				t.ordersService.EXPECT().GetOrder(gomock.Any(), "ns", orderID).Return(&domain.Order{ID: orderID}, nil)
+			},
+		},
+		{
+			name:    "checkout still running",
+			request: f.request,
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+				t.attemptsService.EXPECT().Begin(gomock.Any(), "ns", gomock.Any()).
+					Return(&domain.CheckoutAttempt{Key: "key-1", CartID: f.cart.ID, CustomerID: f.userID, Status: domain.CheckoutStatusInProgress, UpdatedAt: time.Now()}, false, nil)
+			},
+			err: domain.ErrCheckoutInProgress,
+		},
+		{
+			name:    "interrupted checkout is compensated and started over",
+			request: f.request,
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+				gomock.InOrder(
+					t.attemptsService.EXPECT().Begin(gomock.Any(), "ns", gomock.Any()).
+						Return(&domain.CheckoutAttempt{
+							Key: "key-1", CartID: f.cart.ID, CustomerID: f.userID, Status: domain.CheckoutStatusInProgress, UpdatedAt: stale,
+							Reserved: f.cart.Items, OrderID: orderID, Promotions: []uuid.UUID{f.result.Applied[0].PromotionID},
+						}, false, nil),
+					t.attemptsService.EXPECT().DeleteStale(gomock.Any(), "ns", "key-1", gomock.Any()).Return(true, nil),
+					t.promotionsService.EXPECT().Release(gomock.Any(), "ns", orderID, &promotion.Result{
+						Applied: []promotion.AppliedPromotion{{PromotionID: f.result.Applied[0].PromotionID}},
+					}),
+					t.ordersService.EXPECT().Abandon(gomock.Any(), "ns", orderID, "checkout failed").Return(nil, nil),
+					t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", f.cart.Items[0].ProductID, f.cart.Items[0].VariantID, int64(2)).Return(nil),
+					t.inventoryService.EXPECT().MoveStock(gomock.Any(), "ns", f.cart.Items[1].ProductID, f.cart.Items[1].VariantID, int64(1)).Return(nil),
+					// the checkout starts over with the cart, which is gone
+					t.attemptsService.EXPECT().Begin(gomock.Any(), "ns", gomock.Any()).
+						DoAndReturn(func(_ context.Context, _ string, a *domain.CheckoutAttempt) (*domain.CheckoutAttempt, bool, error) {
+							return a, true, nil
+						}),
+					t.cartsService.EXPECT().Get(gomock.Any(), "ns", f.cart.ID).Return(nil, domain.ErrCartNotFound),
+					t.attemptsService.EXPECT().Delete(gomock.Any(), "ns", "key-1").Return(nil),
+				)
+			},
+			err: domain.ErrCartNotFound,
+		},
+		{
+			name:    "interrupted checkout released by another request",
+			request: f.request,
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+				t.attemptsService.EXPECT().Begin(gomock.Any(), "ns", gomock.Any()).
+					Return(&domain.CheckoutAttempt{Key: "key-1", CartID: f.cart.ID, CustomerID: f.userID, Status: domain.CheckoutStatusInProgress, UpdatedAt: stale}, false, nil)
+				t.attemptsService.EXPECT().DeleteStale(gomock.Any(), "ns", "key-1", gomock.Any()).Return(false, nil)
+			},
+			err: domain.ErrCheckoutInProgress,
+		},
+		{
+			name:    "key used for another cart",
+			request: f.request,
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+				t.attemptsService.EXPECT().Begin(gomock.Any(), "ns", gomock.Any()).
+					Return(&domain.CheckoutAttempt{Key: "key-1", CartID: uuid.New(), CustomerID: f.userID, Status: domain.CheckoutStatusInProgress, UpdatedAt: stale}, false, nil)
+			},
+			err: domain.ErrIdempotencyKeyReused,
+		},
+		{
+			name:    "missing key",
+			request: checkout.Request{CartID: f.cart.ID},
+			setup:   func(t setupParams) {},
+			err:     domain.ErrMissingIdempotencyKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			params := setupParams{
+				attemptsService:   NewMockAttemptRepository(ctrl),
+				cartsService:      NewMockCarts(ctrl),
+				inventoryService:  NewMockInventory(ctrl),
+				promotionsService: NewMockPromotions(ctrl),
+				groupsService:     NewMockCustomerGroups(ctrl),
+				ordersService:     NewMockOrders(ctrl),
+				paymentsService:   NewMockPayments(ctrl),
+				authService:       NewMockAuthService(ctrl),
+			}
+			service := checkout.NewService(config, params.attemptsService, params.cartsService, params.inventoryService,
+				params.promotionsService, params.groupsService, params.ordersService, params.paymentsService, params.authService)
+
+			tt.setup(params)
+
+			order, err := service.Checkout(context.Background(), "ns", tt.request)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, orderID, order.ID)
+		})
+	}
+}
+
+func TestReleaseStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAttempts := NewMockAttemptRepository(ctrl)
+	mockInventory := NewMockInventory(ctrl)
+	mockPayments := NewMockPayments(ctrl)
+	mockOrders := NewMockOrders(ctrl)
+	service := checkout.NewService(config, mockAttempts, NewMockCarts(ctrl), mockInventory, NewMockPromotions(ctrl),
+		NewMockCustomerGroups(ctrl), mockOrders, mockPayments, NewMockAuthService(ctrl))
+
+	item := domain.CartItem{ProductID: uuid.New(), Quantity: 3}
+	paid := &domain.CheckoutAttempt{Key: "key-1", Reserved: []domain.CartItem{item}, OrderID: uuid.New(), PaymentID: uuid.New()}
+	reserved := &domain.CheckoutAttempt{Key: "key-2", Reserved: []domain.CartItem{item}}
+	taken := &domain.CheckoutAttempt{Key: "key-3", Reserved: []domain.CartItem{item}}
+
+	mockAttempts.EXPECT().FindStale(gomock.Any(), "ns", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, before time.Time) ([]*domain.CheckoutAttempt, error) {
+			assert.WithinDuration(t, time.Now().Add(-15*time.Minute), before, time.Minute)
+			return []*domain.CheckoutAttempt{paid, reserved, taken}, nil
+		})
+	mockAttempts.EXPECT().DeleteStale(gomock.Any(), "ns", "key-1", gomock.Any()).Return(true, nil)
+	mockAttempts.EXPECT().DeleteStale(gomock.Any(), "ns", "key-2", gomock.Any()).Return(true, nil)
+	mockAttempts.EXPECT().DeleteStale(gomock.Any(), "ns", "key-3", gomock.Any()).Return(false, nil)
+	gomock.InOrder(
+		mockPayments.EXPECT().Abandon(gomock.Any(), "ns", paid.PaymentID).Return(nil),
+		mockOrders.EXPECT().Abandon(gomock.Any(), "ns", paid.OrderID, "checkout failed").Return(nil, nil),
+		mockInventory.EXPECT().MoveStock(gomock.Any(), "ns", item.ProductID, uuid.Nil, int64(3)).Return(nil),
+	)
+	mockInventory.EXPECT().MoveStock(gomock.Any(), "ns", item.ProductID, uuid.Nil, int64(3)).Return(nil)
+
+	released, err := service.ReleaseStale(context.Background(), "ns")
+	require.NoError(t, err)
+	assert.Equal(t, 2, released)
+}
@@ -0,0 +1,488 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination mock_test.go --package checkout_test
+//
+
+// Package checkout_test is a generated GoMock package.
+package checkout_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/HBeserra/GoShop/domain"
+	promotion "github.com/HBeserra/GoShop/internal/promotion"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAttemptRepository is a mock of AttemptRepository interface.
+type MockAttemptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttemptRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAttemptRepositoryMockRecorder is the mock recorder for MockAttemptRepository.
+type MockAttemptRepositoryMockRecorder struct {
+	mock *MockAttemptRepository
+}
+
+// NewMockAttemptRepository creates a new mock instance.
+func NewMockAttemptRepository(ctrl *gomock.Controller) *MockAttemptRepository {
+	mock := &MockAttemptRepository{ctrl: ctrl}
+	mock.recorder = &MockAttemptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttemptRepository) EXPECT() *MockAttemptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockAttemptRepository) Begin(ctx context.Context, namespace string, attempt *domain.CheckoutAttempt) (*domain.CheckoutAttempt, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, namespace, attempt)
+	ret0, _ := ret[0].(*domain.CheckoutAttempt)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockAttemptRepositoryMockRecorder) Begin(ctx, namespace, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockAttemptRepository)(nil).Begin), ctx, namespace, attempt)
+}
+
+// Complete mocks base method.
+func (m *MockAttemptRepository) Complete(ctx context.Context, namespace string, attempt *domain.CheckoutAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, namespace, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockAttemptRepositoryMockRecorder) Complete(ctx, namespace, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockAttemptRepository)(nil).Complete), ctx, namespace, attempt)
+}
+
+// Delete mocks base method.
+func (m *MockAttemptRepository) Delete(ctx context.Context, namespace, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, namespace, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAttemptRepositoryMockRecorder) Delete(ctx, namespace, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAttemptRepository)(nil).Delete), ctx, namespace, key)
+}
+
+// DeleteStale mocks base method.
+func (m *MockAttemptRepository) DeleteStale(ctx context.Context, namespace, key string, before time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStale", ctx, namespace, key, before)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStale indicates an expected call of DeleteStale.
+func (mr *MockAttemptRepositoryMockRecorder) DeleteStale(ctx, namespace, key, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStale", reflect.TypeOf((*MockAttemptRepository)(nil).DeleteStale), ctx, namespace, key, before)
+}
+
+// FindStale mocks base method.
+func (m *MockAttemptRepository) FindStale(ctx context.Context, namespace string, before time.Time) ([]*domain.CheckoutAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStale", ctx, namespace, before)
+	ret0, _ := ret[0].([]*domain.CheckoutAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStale indicates an expected call of FindStale.
+func (mr *MockAttemptRepositoryMockRecorder) FindStale(ctx, namespace, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStale", reflect.TypeOf((*MockAttemptRepository)(nil).FindStale), ctx, namespace, before)
+}
+
+// Update mocks base method.
+func (m *MockAttemptRepository) Update(ctx context.Context, namespace string, attempt *domain.CheckoutAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, namespace, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAttemptRepositoryMockRecorder) Update(ctx, namespace, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAttemptRepository)(nil).Update), ctx, namespace, attempt)
+}
+
+// MockCarts is a mock of Carts interface.
+type MockCarts struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartsMockRecorder
+	isgomock struct{}
+}
+
+// MockCartsMockRecorder is the mock recorder for MockCarts.
+type MockCartsMockRecorder struct {
+	mock *MockCarts
+}
+
+// NewMockCarts creates a new mock instance.
+func NewMockCarts(ctrl *gomock.Controller) *MockCarts {
+	mock := &MockCarts{ctrl: ctrl}
+	mock.recorder = &MockCartsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCarts) EXPECT() *MockCartsMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCarts) Delete(ctx context.Context, namespace string, cartID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, namespace, cartID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCartsMockRecorder) Delete(ctx, namespace, cartID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCarts)(nil).Delete), ctx, namespace, cartID)
+}
+
+// Get mocks base method.
+func (m *MockCarts) Get(ctx context.Context, namespace string, cartID uuid.UUID) (*domain.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, namespace, cartID)
+	ret0, _ := ret[0].(*domain.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCartsMockRecorder) Get(ctx, namespace, cartID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCarts)(nil).Get), ctx, namespace, cartID)
+}
+
+// MockInventory is a mock of Inventory interface.
+type MockInventory struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryMockRecorder
+	isgomock struct{}
+}
+
+// MockInventoryMockRecorder is the mock recorder for MockInventory.
+type MockInventoryMockRecorder struct {
+	mock *MockInventory
+}
+
+// NewMockInventory creates a new mock instance.
+func NewMockInventory(ctrl *gomock.Controller) *MockInventory {
+	mock := &MockInventory{ctrl: ctrl}
+	mock.recorder = &MockInventoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventory) EXPECT() *MockInventoryMockRecorder {
+	return m.recorder
+}
+
+// MoveStock mocks base method.
+func (m *MockInventory) MoveStock(ctx context.Context, namespace string, productID, variantID uuid.UUID, delta int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveStock", ctx, namespace, productID, variantID, delta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveStock indicates an expected call of MoveStock.
+func (mr *MockInventoryMockRecorder) MoveStock(ctx, namespace, productID, variantID, delta any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveStock", reflect.TypeOf((*MockInventory)(nil).MoveStock), ctx, namespace, productID, variantID, delta)
+}
+
+// MockPromotions is a mock of Promotions interface.
+type MockPromotions struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromotionsMockRecorder
+	isgomock struct{}
+}
+
+// MockPromotionsMockRecorder is the mock recorder for MockPromotions.
+type MockPromotionsMockRecorder struct {
+	mock *MockPromotions
+}
+
+// NewMockPromotions creates a new mock instance.
+func NewMockPromotions(ctrl *gomock.Controller) *MockPromotions {
+	mock := &MockPromotions{ctrl: ctrl}
+	mock.recorder = &MockPromotionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromotions) EXPECT() *MockPromotionsMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockPromotions) Apply(ctx context.Context, namespace string, input promotion.Input) (*promotion.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, namespace, input)
+	ret0, _ := ret[0].(*promotion.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockPromotionsMockRecorder) Apply(ctx, namespace, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockPromotions)(nil).Apply), ctx, namespace, input)
+}
+
+// Redeem mocks base method.
+func (m *MockPromotions) Redeem(ctx context.Context, namespace string, customerID, orderID uuid.UUID, result *promotion.Result) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, namespace, customerID, orderID, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockPromotionsMockRecorder) Redeem(ctx, namespace, customerID, orderID, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockPromotions)(nil).Redeem), ctx, namespace, customerID, orderID, result)
+}
+
+// Release mocks base method.
+func (m *MockPromotions) Release(ctx context.Context, namespace string, orderID uuid.UUID, result *promotion.Result) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Release", ctx, namespace, orderID, result)
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockPromotionsMockRecorder) Release(ctx, namespace, orderID, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockPromotions)(nil).Release), ctx, namespace, orderID, result)
+}
+
+// MockCustomerGroups is a mock of CustomerGroups interface.
+type MockCustomerGroups struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerGroupsMockRecorder
+	isgomock struct{}
+}
+
+// MockCustomerGroupsMockRecorder is the mock recorder for MockCustomerGroups.
+type MockCustomerGroupsMockRecorder struct {
+	mock *MockCustomerGroups
+}
+
+// NewMockCustomerGroups creates a new mock instance.
+func NewMockCustomerGroups(ctrl *gomock.Controller) *MockCustomerGroups {
+	mock := &MockCustomerGroups{ctrl: ctrl}
+	mock.recorder = &MockCustomerGroupsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomerGroups) EXPECT() *MockCustomerGroupsMockRecorder {
+	return m.recorder
+}
+
+// GetGroups mocks base method.
+func (m *MockCustomerGroups) GetGroups(ctx context.Context, namespace string, customerID uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroups", ctx, namespace, customerID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroups indicates an expected call of GetGroups.
+func (mr *MockCustomerGroupsMockRecorder) GetGroups(ctx, namespace, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroups", reflect.TypeOf((*MockCustomerGroups)(nil).GetGroups), ctx, namespace, customerID)
+}
+
+// MockOrders is a mock of Orders interface.
+type MockOrders struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrdersMockRecorder
+	isgomock struct{}
+}
+
+// MockOrdersMockRecorder is the mock recorder for MockOrders.
+type MockOrdersMockRecorder struct {
+	mock *MockOrders
+}
+
+// NewMockOrders creates a new mock instance.
+func NewMockOrders(ctrl *gomock.Controller) *MockOrders {
+	mock := &MockOrders{ctrl: ctrl}
+	mock.recorder = &MockOrdersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrders) EXPECT() *MockOrdersMockRecorder {
+	return m.recorder
+}
+
+// Abandon mocks base method.
+func (m *MockOrders) Abandon(ctx context.Context, namespace string, id uuid.UUID, reason string) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Abandon", ctx, namespace, id, reason)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Abandon indicates an expected call of Abandon.
+func (mr *MockOrdersMockRecorder) Abandon(ctx, namespace, id, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abandon", reflect.TypeOf((*MockOrders)(nil).Abandon), ctx, namespace, id, reason)
+}
+
+// GetOrder mocks base method.
+func (m *MockOrders) GetOrder(ctx context.Context, namespace string, id uuid.UUID) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, namespace, id)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrdersMockRecorder) GetOrder(ctx, namespace, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrders)(nil).GetOrder), ctx, namespace, id)
+}
+
+// PlaceOrder mocks base method.
+func (m *MockOrders) PlaceOrder(ctx context.Context, namespace string, customerID uuid.UUID, order *domain.Order, discounts *promotion.Result) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceOrder", ctx, namespace, customerID, order, discounts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PlaceOrder indicates an expected call of PlaceOrder.
+func (mr *MockOrdersMockRecorder) PlaceOrder(ctx, namespace, customerID, order, discounts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceOrder", reflect.TypeOf((*MockOrders)(nil).PlaceOrder), ctx, namespace, customerID, order, discounts)
+}
+
+// MockPayments is a mock of Payments interface.
+type MockPayments struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentsMockRecorder
+	isgomock struct{}
+}
+
+// MockPaymentsMockRecorder is the mock recorder for MockPayments.
+type MockPaymentsMockRecorder struct {
+	mock *MockPayments
+}
+
+// NewMockPayments creates a new mock instance.
+func NewMockPayments(ctrl *gomock.Controller) *MockPayments {
+	mock := &MockPayments{ctrl: ctrl}
+	mock.recorder = &MockPaymentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPayments) EXPECT() *MockPaymentsMockRecorder {
+	return m.recorder
+}
+
+// Abandon mocks base method.
+func (m *MockPayments) Abandon(ctx context.Context, namespace string, paymentID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Abandon", ctx, namespace, paymentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Abandon indicates an expected call of Abandon.
+func (mr *MockPaymentsMockRecorder) Abandon(ctx, namespace, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abandon", reflect.TypeOf((*MockPayments)(nil).Abandon), ctx, namespace, paymentID)
+}
+
+// Authorize mocks base method.
+func (m *MockPayments) Authorize(ctx context.Context, namespace string, order *domain.Order, method domain.PaymentMethod) (*domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, namespace, order, method)
+	ret0, _ := ret[0].(*domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockPaymentsMockRecorder) Authorize(ctx, namespace, order, method any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockPayments)(nil).Authorize), ctx, namespace, order, method)
+}
+
+// MockAuthService is a mock of AuthService interface.
+type MockAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthServiceMockRecorder is the mock recorder for MockAuthService.
+type MockAuthServiceMockRecorder struct {
+	mock *MockAuthService
+}
+
+// NewMockAuthService creates a new mock instance.
+func NewMockAuthService(ctrl *gomock.Controller) *MockAuthService {
+	mock := &MockAuthService{ctrl: ctrl}
+	mock.recorder = &MockAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// GetUserID mocks base method.
+func (m *MockAuthService) GetUserID(ctx context.Context) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserID", ctx)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserID indicates an expected call of GetUserID.
+func (mr *MockAuthServiceMockRecorder) GetUserID(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserID", reflect.TypeOf((*MockAuthService)(nil).GetUserID), ctx)
+}
@@ -0,0 +1,47 @@
+package checkout
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// step is a saga step. Its compensation undoes the action once a later step fails, it may be nil.
+type step struct {
+	name       string
+	action     func(ctx context.Context) error
+	compensate func(ctx context.Context) error
+}
+
+// saga runs steps in order and keeps the completed ones to compensate them on failure.
+type saga struct {
+	done []step
+}
+
+// run executes the step, a failed step is not compensated since it is expected to leave no side effect.
+func (s *saga) run(ctx context.Context, st step) error {
+	if err := st.action(ctx); err != nil {
+		return fmt.Errorf("%s: %w", st.name, err)
+	}
+	s.done = append(s.done, st)
+	return nil
+}
+
+// rollback compensates the completed steps in reverse order. The compensations run even if the context
+// was cancelled, their failures are logged so they can be fixed manually.
+func (s *saga) rollback(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+	for i := len(s.done) - 1; i >= 0; i-- {
+		st := s.done[i]
+		if st.compensate == nil {
+			continue
+		}
+		if err := st.compensate(ctx); err != nil {
+			slog.ErrorContext(ctx, "failed to compensate checkout step",
+				"step", st.name,
+				"error", err,
+			)
+		}
+	}
+	s.done = nil
+}
@@ -0,0 +1,126 @@
+package checkout
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/promotion"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Config struct {
+	// AttemptTimeout is how long an attempt may stay in progress without recording any progress, after it
+	// the checkout is considered interrupted and its steps are compensated.
+	AttemptTimeout time.Duration `env:"CHECKOUT_ATTEMPT_TIMEOUT" envDefault:"15m"`
+}
+
+// AttemptRepository stores the checkout attempts by idempotency key.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  checkout_test
+type AttemptRepository interface {
+
+	// Begin atomically stores the attempt unless one with the same key exists in the namespace.
+	// It returns the stored attempt and whether it was created by this call.
+	Begin(ctx context.Context, namespace string, attempt *domain.CheckoutAttempt) (*domain.CheckoutAttempt, bool, error)
+
+	// Update stores the progress of an attempt in progress.
+	Update(ctx context.Context, namespace string, attempt *domain.CheckoutAttempt) error
+
+	// Complete stores the order and payment of a successful attempt.
+	Complete(ctx context.Context, namespace string, attempt *domain.CheckoutAttempt) error
+
+	// Delete removes the attempt, so a failed checkout can be retried with the same key.
+	Delete(ctx context.Context, namespace string, key string) error
+
+	// FindStale returns the attempts still in progress last updated before the given time.
+	FindStale(ctx context.Context, namespace string, before time.Time) ([]*domain.CheckoutAttempt, error)
+
+	// DeleteStale atomically removes the attempt if it is still in progress and was last updated before the
+	// given time. It reports whether the attempt was removed, so a single caller compensates it.
+	DeleteStale(ctx context.Context, namespace string, key string, before time.Time) (bool, error)
+}
+
+// Carts gives access to the carts being checked out.
+type Carts interface {
+	// Get returns the cart re-validated against the catalog.
+	Get(ctx context.Context, namespace string, cartID uuid.UUID) (*domain.Cart, error)
+	Delete(ctx context.Context, namespace string, cartID uuid.UUID) error
+}
+
+// Inventory reserves the stock of the ordered products.
+type Inventory interface {
+	// MoveStock adds delta to the stock without checking the permissions of the user, returning
+	// domain.ErrInsufficientStock if it would become negative.
+	MoveStock(ctx context.Context, namespace string, productID, variantID uuid.UUID, delta int64) error
+}
+
+// Promotions applies the coupons and records their usage.
+type Promotions interface {
+	Apply(ctx context.Context, namespace string, input promotion.Input) (*promotion.Result, error)
+	Redeem(ctx context.Context, namespace string, customerID, orderID uuid.UUID, result *promotion.Result) error
+	Release(ctx context.Context, namespace string, orderID uuid.UUID, result *promotion.Result)
+}
+
+// CustomerGroups returns the groups the promotions may be restricted to.
+type CustomerGroups interface {
+	GetGroups(ctx context.Context, namespace string, customerID uuid.UUID) ([]string, error)
+}
+
+// Orders places the orders.
+type Orders interface {
+	// PlaceOrder places the order for the customer with the discounts and shipping of the promotion result,
+	// without checking the permissions of the user.
+	PlaceOrder(ctx context.Context, namespace string, customerID uuid.UUID, order *domain.Order, discounts *promotion.Result) error
+	GetOrder(ctx context.Context, namespace string, id uuid.UUID) (*domain.Order, error)
+	// Abandon cancels the pending order without checking the permissions of the user.
+	Abandon(ctx context.Context, namespace string, id uuid.UUID, reason string) (*domain.Order, error)
+}
+
+// Payments authorizes the payment of the orders.
+type Payments interface {
+	Authorize(ctx context.Context, namespace string, order *domain.Order, method domain.PaymentMethod) (*domain.Payment, error)
+	// Abandon voids the payment without checking the permissions of the user.
+	Abandon(ctx context.Context, namespace string, paymentID uuid.UUID) error
+}
+
+// AuthService returns information about the current command
+type AuthService interface {
+	// GetUserID retrieves the unique identifier (UUID) of the user from the provided context. Returns an error if retrieval fails.
+	GetUserID(ctx context.Context) (uuid.UUID, error)
+}
+
+type Service struct {
+	config     *Config
+	attempts   AttemptRepository
+	carts      Carts
+	inventory  Inventory
+	promotions Promotions
+	groups     CustomerGroups
+	orders     Orders
+	payments   Payments
+	auth       AuthService
+}
+
+func NewService(
+	config *Config,
+	attempts AttemptRepository,
+	carts Carts,
+	inventory Inventory,
+	promotions Promotions,
+	groups CustomerGroups,
+	orders Orders,
+	payments Payments,
+	auth AuthService,
+) *Service {
+	return &Service{
+		config:     config,
+		attempts:   attempts,
+		carts:      carts,
+		inventory:  inventory,
+		promotions: promotions,
+		groups:     groups,
+		orders:     orders,
+		payments:   payments,
+		auth:       auth,
+	}
+}
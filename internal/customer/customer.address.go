@@ -0,0 +1,68 @@
+package customer
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"slices"
+)
+
+// AddAddress saves a new address for the customer, completed from its CEP. An address marked as default
+// replaces the previous default of its kind, the first address of a kind is always the default.
+func (s *Service) AddAddress(ctx context.Context, namespace string, customerID uuid.UUID, address domain.CustomerAddress) (*domain.Customer, error) {
+
+	ctx, span := observability.StartSpan(ctx, "customer.AddAddress")
+	defer span.End()
+
+	address.ID = uuid.New()
+	return s.modify(ctx, namespace, customerID, func(customer *domain.Customer) error {
+		if address.Default {
+			clearDefault(customer, address.Kind)
+		}
+		customer.Addresses = append(customer.Addresses, address)
+		return nil
+	})
+}
+
+// RemoveAddress deletes an address of the customer. When it was the default, the next address of its kind becomes the default.
+func (s *Service) RemoveAddress(ctx context.Context, namespace string, customerID, addressID uuid.UUID) (*domain.Customer, error) {
+
+	ctx, span := observability.StartSpan(ctx, "customer.RemoveAddress")
+	defer span.End()
+
+	return s.modify(ctx, namespace, customerID, func(customer *domain.Customer) error {
+		i := slices.IndexFunc(customer.Addresses, func(a domain.CustomerAddress) bool { return a.ID == addressID })
+		if i < 0 {
+			return domain.ErrAddressNotFound
+		}
+		customer.Addresses = slices.Delete(customer.Addresses, i, i+1)
+		return nil
+	})
+}
+
+// SetDefaultAddress makes the address the default of its kind.
+func (s *Service) SetDefaultAddress(ctx context.Context, namespace string, customerID, addressID uuid.UUID) (*domain.Customer, error) {
+
+	ctx, span := observability.StartSpan(ctx, "customer.SetDefaultAddress")
+	defer span.End()
+
+	return s.modify(ctx, namespace, customerID, func(customer *domain.Customer) error {
+		i := slices.IndexFunc(customer.Addresses, func(a domain.CustomerAddress) bool { return a.ID == addressID })
+		if i < 0 {
+			return domain.ErrAddressNotFound
+		}
+		clearDefault(customer, customer.Addresses[i].Kind)
+		customer.Addresses[i].Default = true
+		return nil
+	})
+}
+
+// clearDefault unsets the default address of the kind.
+func clearDefault(customer *domain.Customer, kind domain.AddressKind) {
+	for i := range customer.Addresses {
+		if customer.Addresses[i].Kind == kind {
+			customer.Addresses[i].Default = false
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package customer
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+)
+
+// GetCustomer returns the customer profile. Customers can read their own profile, anyone else needs the "customer:read" permission.
+func (s *Service) GetCustomer(ctx context.Context, namespace string, id uuid.UUID) (*domain.Customer, error) {
+
+	ctx, span := observability.StartSpan(ctx, "customer.GetCustomer")
+	defer span.End()
+
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.repo.GetByID(ctx, namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.authorize(ctx, userID, customer.UserID, namespace, "customer:read"); err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// GetCustomerByUser returns the customer profile of the user, see GetCustomer.
+func (s *Service) GetCustomerByUser(ctx context.Context, namespace string, user uuid.UUID) (*domain.Customer, error) {
+
+	ctx, span := observability.StartSpan(ctx, "customer.GetCustomerByUser")
+	defer span.End()
+
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.authorize(ctx, userID, user, namespace, "customer:read"); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByUser(ctx, namespace, user)
+}
+
+// LookupByUser returns the customer profile of the user without checking the permissions of the current user,
+// for the services acting on the orders of the customer such as the invoicing.
+func (s *Service) LookupByUser(ctx context.Context, namespace string, user uuid.UUID) (*domain.Customer, error) {
+
+	ctx, span := observability.StartSpan(ctx, "customer.LookupByUser")
+	defer span.End()
+
+	return s.repo.GetByUser(ctx, namespace, user)
+}
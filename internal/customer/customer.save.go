@@ -0,0 +1,271 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// minBirthDate is the earliest accepted date of birth.
+var minBirthDate = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// CreateCustomer creates the customer profile of a user, the current user when its UserID is empty.
+// Creating the profile of another user requires the "customer:write" permission.
+func (s *Service) CreateCustomer(ctx context.Context, namespace string, customer *domain.Customer) error {
+
+	ctx, span := observability.StartSpan(ctx, "customer.CreateCustomer")
+	defer span.End()
+
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if customer.UserID == uuid.Nil {
+		customer.UserID = userID
+	}
+	if err = s.authorize(ctx, userID, customer.UserID, namespace, "customer:write"); err != nil {
+		return err
+	}
+
+	if _, err = s.users.GetByID(ctx, namespace, customer.UserID); err != nil {
+		return err
+	}
+	_, err = s.repo.GetByUser(ctx, namespace, customer.UserID)
+	switch {
+	case err == nil:
+		return domain.ErrCustomerExists
+	case !errors.Is(err, domain.ErrCustomerNotFound):
+		span.RecordError(err)
+		return err
+	}
+
+	now := time.Now()
+	customer.ID = uuid.New()
+	customer.Namespace = namespace
+	customer.CreatedAt = now
+	customer.UpdatedAt = now
+	customer.AnonymizedAt = nil
+	customer.Consents.UpdatedAt = now
+
+	if err = s.validate(ctx, namespace, customer); err != nil {
+		return err
+	}
+
+	err = s.repo.Create(ctx, namespace, customer)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = s.bus.Publish(ctx, "customer:created", events.CustomerCreated{
+		ID:        customer.ID,
+		UserID:    customer.UserID,
+		CreatedOn: now,
+		CreatedBy: userID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to publish customer:created event",
+			"customer_id", customer.ID,
+			"error", err)
+	}
+
+	slog.InfoContext(ctx, "customer created",
+		"customer_id", customer.ID,
+		"user_id", customer.UserID,
+		"created_by", userID,
+	)
+	return nil
+}
+
+// UpdateCustomer replaces the document, phone, birth date, addresses and consents of the customer profile.
+// Updating the profile of another user requires the "customer:write" permission.
+func (s *Service) UpdateCustomer(ctx context.Context, namespace string, customer *domain.Customer) (*domain.Customer, error) {
+
+	ctx, span := observability.StartSpan(ctx, "customer.UpdateCustomer")
+	defer span.End()
+
+	return s.modify(ctx, namespace, customer.ID, func(stored *domain.Customer) error {
+		stored.Document = customer.Document
+		stored.Phone = customer.Phone
+		stored.BirthDate = customer.BirthDate
+		stored.Addresses = customer.Addresses
+		stored.Consents.Email = customer.Consents.Email
+		stored.Consents.SMS = customer.Consents.SMS
+		stored.Consents.WhatsApp = customer.Consents.WhatsApp
+		return nil
+	})
+}
+
+// modify applies the change to the stored customer profile, validates and stores it. It publishes the
+// "customer:updated" event, and "customer:consents_changed" when the consents were changed.
+func (s *Service) modify(ctx context.Context, namespace string, id uuid.UUID, change func(customer *domain.Customer) error) (*domain.Customer, error) {
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.repo.GetByID(ctx, namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.authorize(ctx, userID, customer.UserID, namespace, "customer:write"); err != nil {
+		return nil, err
+	}
+	if customer.AnonymizedAt != nil {
+		return nil, domain.ErrCustomerAnonymized
+	}
+
+	consents := customer.Consents
+	if err = change(customer); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	customer.UpdatedAt = now
+	consentsChanged := customer.Consents.Email != consents.Email || customer.Consents.SMS != consents.SMS ||
+		customer.Consents.WhatsApp != consents.WhatsApp
+	if consentsChanged {
+		customer.Consents.UpdatedAt = now
+	}
+
+	if err = s.validate(ctx, namespace, customer); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Update(ctx, namespace, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.bus.Publish(ctx, "customer:updated", events.CustomerUpdated{
+		ID:        customer.ID,
+		UserID:    customer.UserID,
+		UpdatedOn: now,
+		UpdatedBy: userID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish customer:updated event",
+			"customer_id", customer.ID,
+			"error", err)
+	}
+
+	if consentsChanged {
+		err = s.bus.Publish(ctx, "customer:consents_changed", events.CustomerConsentsChanged{
+			ID:        customer.ID,
+			UserID:    customer.UserID,
+			Email:     customer.Consents.Email,
+			SMS:       customer.Consents.SMS,
+			WhatsApp:  customer.Consents.WhatsApp,
+			ChangedOn: now,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to publish customer:consents_changed event",
+				"customer_id", customer.ID,
+				"error", err)
+		}
+	}
+
+	return customer, nil
+}
+
+// validate normalizes the document, phone, birth date and addresses of the customer and checks them.
+// Every violation is returned as a *domain.ValidationError, a document used by another customer as domain.ErrDocumentInUse.
+func (s *Service) validate(ctx context.Context, namespace string, customer *domain.Customer) error {
+	verr := new(domain.ValidationError)
+
+	if customer.Document != "" {
+		document, err := domain.ParseDocument(string(customer.Document))
+		if err != nil {
+			verr.Add("document", domain.ValidationCodeInvalid, domain.ErrInvalidDocument, nil)
+		} else {
+			customer.Document = document
+		}
+	}
+
+	if customer.Phone != "" {
+		phone, err := domain.ParsePhone(string(customer.Phone))
+		if err != nil {
+			verr.Add("phone", domain.ValidationCodeInvalid, domain.ErrInvalidPhone, nil)
+		} else {
+			customer.Phone = phone
+		}
+	}
+
+	if customer.BirthDate != nil {
+		y, m, d := customer.BirthDate.Date()
+		date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		customer.BirthDate = &date
+		if date.Before(minBirthDate) || date.After(time.Now()) {
+			verr.Add("birth_date", domain.ValidationCodeRange, domain.ErrInvalidBirthDate,
+				map[string]any{"min": minBirthDate.Format(time.DateOnly), "max": time.Now().Format(time.DateOnly)})
+		}
+	}
+
+	for i := range customer.Addresses {
+		address := &customer.Addresses[i]
+		field := fmt.Sprintf("addresses[%d]", i)
+		if address.ID == uuid.Nil {
+			address.ID = uuid.New()
+		}
+		if address.Kind != domain.AddressKindShipping && address.Kind != domain.AddressKindBilling {
+			verr.Add(field+".kind", domain.ValidationCodeOneOf, domain.ErrInvalidAddress,
+				map[string]any{"allowed": []domain.AddressKind{domain.AddressKindShipping, domain.AddressKindBilling}})
+		}
+		if err := s.addresses.Validate(ctx, &address.Address); err != nil {
+			var addressErr *domain.ValidationError
+			if !errors.As(err, &addressErr) {
+				return err
+			}
+			verr.Merge(field, addressErr)
+		}
+	}
+	for _, kind := range []domain.AddressKind{domain.AddressKindShipping, domain.AddressKindBilling} {
+		setDefault(customer, kind)
+	}
+
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	// The unique index of the repository catches the concurrent saves of the same document
+	if customer.Document != "" {
+		other, err := s.repo.GetByDocument(ctx, namespace, customer.Document)
+		switch {
+		case err == nil && other.ID != customer.ID:
+			return domain.ErrDocumentInUse
+		case err != nil && !errors.Is(err, domain.ErrCustomerNotFound):
+			return err
+		}
+	}
+	return nil
+}
+
+// setDefault keeps a single default address of the kind: the first one marked as default, or the first
+// address of the kind when none is.
+func setDefault(customer *domain.Customer, kind domain.AddressKind) {
+	found := false
+	first := -1
+	for i := range customer.Addresses {
+		address := &customer.Addresses[i]
+		if address.Kind != kind {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		if address.Default && found {
+			address.Default = false
+		}
+		found = found || address.Default
+	}
+	if !found && first >= 0 {
+		customer.Addresses[first].Default = true
+	}
+}
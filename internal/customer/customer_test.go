@@ -0,0 +1,448 @@
+package customer_test
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/customer"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type setupParams struct {
+	repoService      *MockCustomerRepository
+	usersService     *MockUserRepository
+	addressesService *MockAddresses
+	busService       *MockEventBus
+	authService      *MockAuthService
+}
+
+// invalidCEP is the error of the address service for a CEP it does not know.
+func invalidCEP() error {
+	verr := new(domain.ValidationError)
+	verr.Add("cep", domain.ValidationCodeInvalid, domain.ErrAddressNotFound, nil)
+	return verr
+}
+
+func TestCreateCustomer(t *testing.T) {
+	userID := uuid.New()
+	birth := time.Date(1990, 5, 17, 15, 30, 0, 0, time.Local)
+	future := time.Now().AddDate(1, 0, 0)
+
+	// expectNew expects the checks of a new profile of the current user
+	expectNew := func(t setupParams) {
+		t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+		t.usersService.EXPECT().GetByID(gomock.Any(), "ns", userID).Return(&domain.User{ID: userID}, nil)
+		t.repoService.EXPECT().GetByUser(gomock.Any(), "ns", userID).Return(nil, domain.ErrCustomerNotFound)
+	}
+
+	tests := []struct {
+		name     string
+		customer *domain.Customer
+		setup    func(t setupParams)
+		err      error
+		fields   []string
+		expected func(t *testing.T, c *domain.Customer)
+	}{
+		{
+			name: "created",
+			customer: &domain.Customer{
+				Document:  "529.982.247-25",
+				Phone:     "(11) 98765-4321",
+				BirthDate: &birth,
+				Addresses: []domain.CustomerAddress{
+					{Kind: domain.AddressKindShipping, Address: domain.Address{CEP: "01310100", Number: "1000"}},
+					{Kind: domain.AddressKindShipping, Address: domain.Address{CEP: "40060000", Number: "10"}},
+					{Kind: domain.AddressKindBilling, Default: true, Address: domain.Address{CEP: "01310100", Number: "1000"}},
+				},
+			},
+			setup: func(t setupParams) {
+				expectNew(t)
+				t.addressesService.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+				t.repoService.EXPECT().GetByDocument(gomock.Any(), "ns", domain.Document("52998224725")).Return(nil, domain.ErrCustomerNotFound)
+				t.repoService.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "customer:created", gomock.Any()).Return(nil)
+			},
+			expected: func(t *testing.T, c *domain.Customer) {
+				assert.Equal(t, userID, c.UserID)
+				assert.Equal(t, domain.Document("52998224725"), c.Document)
+				assert.Equal(t, domain.Phone("+5511987654321"), c.Phone)
+				assert.Equal(t, time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC), *c.BirthDate)
+				assert.False(t, c.Consents.Email || c.Consents.SMS || c.Consents.WhatsApp)
+
+				shipping, ok := c.DefaultAddress(domain.AddressKindShipping)
+				require.True(t, ok)
+				assert.Equal(t, domain.CEP("01310100"), shipping.CEP)
+				assert.NotEqual(t, uuid.Nil, shipping.ID)
+				_, ok = c.DefaultAddress(domain.AddressKindBilling)
+				assert.True(t, ok)
+			},
+		},
+		{
+			name:     "profile exists",
+			customer: &domain.Customer{},
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.usersService.EXPECT().GetByID(gomock.Any(), "ns", userID).Return(&domain.User{ID: userID}, nil)
+				t.repoService.EXPECT().GetByUser(gomock.Any(), "ns", userID).Return(&domain.Customer{}, nil)
+			},
+			err: domain.ErrCustomerExists,
+		},
+		{
+			name:     "profile of another user",
+			customer: &domain.Customer{UserID: uuid.New()},
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "customer:write").Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name:     "unauthenticated",
+			customer: &domain.Customer{},
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, errors.New("no session"))
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name:     "document in use",
+			customer: &domain.Customer{Document: "11.222.333/0001-81"},
+			setup: func(t setupParams) {
+				expectNew(t)
+				t.repoService.EXPECT().GetByDocument(gomock.Any(), "ns", domain.Document("11222333000181")).Return(&domain.Customer{ID: uuid.New()}, nil)
+			},
+			err: domain.ErrDocumentInUse,
+		},
+		{
+			name: "invalid fields",
+			customer: &domain.Customer{
+				Document:  "529.982.247-24",
+				Phone:     "1234",
+				BirthDate: &future,
+				Addresses: []domain.CustomerAddress{{Kind: "office", Address: domain.Address{CEP: "01310100"}}},
+			},
+			setup: func(t setupParams) {
+				expectNew(t)
+				t.addressesService.EXPECT().Validate(gomock.Any(), &domain.Address{CEP: "01310100"}).Return(nil)
+			},
+			fields: []string{"document", "phone", "birth_date", "addresses[0].kind"},
+		},
+		{
+			name: "invalid address",
+			customer: &domain.Customer{
+				Addresses: []domain.CustomerAddress{
+					{Kind: domain.AddressKindShipping, Address: domain.Address{CEP: "01310100", Number: "1000"}},
+					{Kind: domain.AddressKindBilling, Address: domain.Address{CEP: "99999999", Number: "1"}},
+				},
+			},
+			setup: func(t setupParams) {
+				expectNew(t)
+				t.addressesService.EXPECT().Validate(gomock.Any(), &domain.Address{CEP: "01310100", Number: "1000"}).Return(nil)
+				t.addressesService.EXPECT().Validate(gomock.Any(), &domain.Address{CEP: "99999999", Number: "1"}).Return(invalidCEP())
+			},
+			err:    domain.ErrAddressNotFound,
+			fields: []string{"addresses[1].cep"},
+		},
+		{
+			name: "address service unavailable",
+			customer: &domain.Customer{
+				Addresses: []domain.CustomerAddress{{Kind: domain.AddressKindShipping, Address: domain.Address{CEP: "01310100"}}},
+			},
+			setup: func(t setupParams) {
+				expectNew(t)
+				t.addressesService.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(context.DeadlineExceeded)
+			},
+			err: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockCustomerRepository(ctrl)
+			mockUsers := NewMockUserRepository(ctrl)
+			mockAddresses := NewMockAddresses(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+
+			service := customer.NewService(mockRepo, mockUsers, mockAddresses, mockBus, mockAuth)
+			tt.setup(setupParams{
+				repoService:      mockRepo,
+				usersService:     mockUsers,
+				addressesService: mockAddresses,
+				busService:       mockBus,
+				authService:      mockAuth,
+			})
+
+			err := service.CreateCustomer(context.Background(), "ns", tt.customer)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			if tt.fields != nil {
+				var verr *domain.ValidationError
+				require.ErrorAs(t, err, &verr)
+				assert.Equal(t, tt.fields, verr.Fields())
+			}
+			if tt.err == nil && tt.fields == nil {
+				require.NoError(t, err)
+			}
+			if tt.expected != nil {
+				tt.expected(t, tt.customer)
+			}
+		})
+	}
+}
+
+func TestUpdateCustomer(t *testing.T) {
+	userID := uuid.New()
+	anonymized := time.Now()
+
+	tests := []struct {
+		name     string
+		stored   *domain.Customer
+		update   domain.Customer
+		setup    func(t setupParams, stored *domain.Customer)
+		err      error
+		expected func(t *testing.T, c *domain.Customer)
+	}{
+		{
+			name:   "consents changed",
+			stored: &domain.Customer{ID: uuid.New(), UserID: userID, Consents: domain.Consents{Email: true}},
+			update: domain.Customer{Consents: domain.Consents{WhatsApp: true}},
+			setup: func(t setupParams, stored *domain.Customer) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", stored.ID).Return(stored, nil)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", stored).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "customer:updated", gomock.Any()).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "customer:consents_changed", gomock.Any()).Return(nil)
+			},
+			expected: func(t *testing.T, c *domain.Customer) {
+				assert.False(t, c.Consents.Email)
+				assert.True(t, c.Consents.WhatsApp)
+				assert.WithinDuration(t, time.Now(), c.Consents.UpdatedAt, time.Second)
+			},
+		},
+		{
+			name:   "profile of another user with permission",
+			stored: &domain.Customer{ID: uuid.New(), UserID: uuid.New()},
+			update: domain.Customer{Phone: "(11) 98765-4321"},
+			setup: func(t setupParams, stored *domain.Customer) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", stored.ID).Return(stored, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "customer:write").Return(true, nil)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", stored).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "customer:updated", gomock.Any()).Return(nil)
+			},
+			expected: func(t *testing.T, c *domain.Customer) {
+				assert.Equal(t, domain.Phone("+5511987654321"), c.Phone)
+			},
+		},
+		{
+			name:   "profile of another user without permission",
+			stored: &domain.Customer{ID: uuid.New(), UserID: uuid.New()},
+			setup: func(t setupParams, stored *domain.Customer) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", stored.ID).Return(stored, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "customer:write").Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name:   "anonymized profile",
+			stored: &domain.Customer{ID: uuid.New(), UserID: userID, AnonymizedAt: &anonymized},
+			update: domain.Customer{Phone: "(11) 98765-4321"},
+			setup: func(t setupParams, stored *domain.Customer) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", stored.ID).Return(stored, nil)
+			},
+			err: domain.ErrCustomerAnonymized,
+		},
+		{
+			name:   "unauthenticated",
+			stored: &domain.Customer{ID: uuid.New(), UserID: userID},
+			setup: func(t setupParams, stored *domain.Customer) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockCustomerRepository(ctrl)
+			mockAddresses := NewMockAddresses(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+
+			service := customer.NewService(mockRepo, nil, mockAddresses, mockBus, mockAuth)
+			tt.setup(setupParams{
+				repoService:      mockRepo,
+				addressesService: mockAddresses,
+				busService:       mockBus,
+				authService:      mockAuth,
+			}, tt.stored)
+
+			update := tt.update
+			update.ID = tt.stored.ID
+			got, err := service.UpdateCustomer(context.Background(), "ns", &update)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expected != nil {
+				tt.expected(t, got)
+			}
+		})
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	userID := uuid.New()
+	home, office := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name     string
+		change   func(s *customer.Service, id uuid.UUID) (*domain.Customer, error)
+		setup    func(t setupParams, stored *domain.Customer)
+		err      error
+		fields   []string
+		expected func(t *testing.T, c *domain.Customer)
+	}{
+		{
+			name: "default address added",
+			change: func(s *customer.Service, id uuid.UUID) (*domain.Customer, error) {
+				return s.AddAddress(context.Background(), "ns", id, domain.CustomerAddress{
+					Kind: domain.AddressKindShipping, Default: true, Address: domain.Address{CEP: "40060000", Number: "10"},
+				})
+			},
+			setup: func(t setupParams, stored *domain.Customer) {
+				t.addressesService.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", stored).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "customer:updated", gomock.Any()).Return(nil)
+			},
+			expected: func(t *testing.T, c *domain.Customer) {
+				require.Len(t, c.Addresses, 3)
+				added, _ := c.DefaultAddress(domain.AddressKindShipping)
+				assert.Equal(t, c.Addresses[2].ID, added.ID)
+			},
+		},
+		{
+			name: "address with an unknown CEP",
+			change: func(s *customer.Service, id uuid.UUID) (*domain.Customer, error) {
+				return s.AddAddress(context.Background(), "ns", id, domain.CustomerAddress{
+					Kind: domain.AddressKindShipping, Address: domain.Address{CEP: "99999999", Number: "1"},
+				})
+			},
+			setup: func(t setupParams, stored *domain.Customer) {
+				t.addressesService.EXPECT().Validate(gomock.Any(), &domain.Address{}).Return(nil).Times(2)
+				t.addressesService.EXPECT().Validate(gomock.Any(), &domain.Address{CEP: "99999999", Number: "1"}).Return(invalidCEP())
+			},
+			err:    domain.ErrAddressNotFound,
+			fields: []string{"addresses[2].cep"},
+		},
+		{
+			name: "default address set",
+			change: func(s *customer.Service, id uuid.UUID) (*domain.Customer, error) {
+				return s.SetDefaultAddress(context.Background(), "ns", id, office)
+			},
+			setup: func(t setupParams, stored *domain.Customer) {
+				t.addressesService.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", stored).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "customer:updated", gomock.Any()).Return(nil)
+			},
+			expected: func(t *testing.T, c *domain.Customer) {
+				def, _ := c.DefaultAddress(domain.AddressKindShipping)
+				assert.Equal(t, office, def.ID)
+			},
+		},
+		{
+			name: "default address removed",
+			change: func(s *customer.Service, id uuid.UUID) (*domain.Customer, error) {
+				return s.RemoveAddress(context.Background(), "ns", id, home)
+			},
+			setup: func(t setupParams, stored *domain.Customer) {
+				t.addressesService.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", stored).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "customer:updated", gomock.Any()).Return(nil)
+			},
+			expected: func(t *testing.T, c *domain.Customer) {
+				require.Len(t, c.Addresses, 1)
+				def, _ := c.DefaultAddress(domain.AddressKindShipping)
+				assert.Equal(t, office, def.ID)
+			},
+		},
+		{
+			name: "missing address removed",
+			change: func(s *customer.Service, id uuid.UUID) (*domain.Customer, error) {
+				return s.RemoveAddress(context.Background(), "ns", id, uuid.New())
+			},
+			setup: func(t setupParams, stored *domain.Customer) {},
+			err:   domain.ErrAddressNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockCustomerRepository(ctrl)
+			mockAddresses := NewMockAddresses(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+
+			service := customer.NewService(mockRepo, nil, mockAddresses, mockBus, mockAuth)
+			stored := &domain.Customer{
+				ID:     uuid.New(),
+				UserID: userID,
+				Addresses: []domain.CustomerAddress{
+					{ID: home, Kind: domain.AddressKindShipping, Default: true},
+					{ID: office, Kind: domain.AddressKindShipping},
+				},
+			}
+			mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+			mockRepo.EXPECT().GetByID(gomock.Any(), "ns", stored.ID).Return(stored, nil)
+			tt.setup(setupParams{
+				repoService:      mockRepo,
+				addressesService: mockAddresses,
+				busService:       mockBus,
+				authService:      mockAuth,
+			}, stored)
+
+			got, err := tt.change(service, stored.ID)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+			if tt.fields != nil {
+				var verr *domain.ValidationError
+				require.ErrorAs(t, err, &verr)
+				assert.Equal(t, tt.fields, verr.Fields())
+			}
+			if tt.err != nil || tt.fields != nil {
+				return
+			}
+			require.NoError(t, err)
+			tt.expected(t, got)
+		})
+	}
+}
+
+func TestLookupByUser(t *testing.T) {
+	userID := uuid.New()
+	stored := &domain.Customer{ID: uuid.New(), UserID: userID}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockCustomerRepository(ctrl)
+	// no permission is checked, the auth service is not called
+	service := customer.NewService(mockRepo, nil, nil, nil, NewMockAuthService(ctrl))
+	mockRepo.EXPECT().GetByUser(gomock.Any(), "ns", userID).Return(stored, nil)
+
+	got, err := service.LookupByUser(context.Background(), "ns", userID)
+	require.NoError(t, err)
+	assert.Equal(t, stored, got)
+}
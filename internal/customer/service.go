@@ -0,0 +1,104 @@
+package customer
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/google/uuid"
+)
+
+// CustomerRepository defines an interface for managing the customer profiles.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  customer_test
+type CustomerRepository interface {
+
+	// Create stores a new customer profile, returns domain.ErrDocumentInUse if another profile of the namespace
+	// has its document.
+	Create(ctx context.Context, namespace string, customer *domain.Customer) error
+
+	// Update stores the changes of the customer profile, returns domain.ErrDocumentInUse as Create does.
+	Update(ctx context.Context, namespace string, customer *domain.Customer) error
+
+	// GetByID retrieves a customer by its unique identifier, returns domain.ErrCustomerNotFound if missing.
+	GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.Customer, error)
+
+	// GetByUser retrieves the customer profile of the user, returns domain.ErrCustomerNotFound if missing.
+	GetByUser(ctx context.Context, namespace string, userID uuid.UUID) (*domain.Customer, error)
+
+	// GetByDocument retrieves the customer with the CPF or CNPJ, returns domain.ErrCustomerNotFound if missing.
+	GetByDocument(ctx context.Context, namespace string, document domain.Document) (*domain.Customer, error)
+}
+
+// UserRepository gives access to the users the customer profiles belong to.
+type UserRepository interface {
+
+	// GetByID retrieves a user by its unique identifier, returns domain.ErrUserNotFound if missing.
+	GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.User, error)
+}
+
+// Addresses completes and validates the postal addresses, such as the address.Service.
+type Addresses interface {
+	Validate(ctx context.Context, address *domain.Address) error
+}
+
+// EventBus defines an interface for managing event publishing and subscription.
+type EventBus interface {
+	// Publish sends an event to all subscribers of the specified topic.
+	Publish(ctx context.Context, topic string, event interface{}) error
+}
+
+// AuthService returns information about the current command
+type AuthService interface {
+	// GetUserID retrieves the unique identifier (UUID) of the user from the provided context. Returns an error if retrieval fails.
+	GetUserID(ctx context.Context) (uuid.UUID, error)
+	// CheckPermissions verifies if a user has the required permissions for a specified namespace and action(s). It returns a boolean indicating access and an error if the operation fails.
+	CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error)
+}
+
+// Service manages the customer profiles. The customers manage their own profile, managing the profile of
+// someone else requires the "customer:*" permissions. The personal data of the profiles are exported and
+// anonymized by the privacy.Service, which answers the LGPD requests.
+type Service struct {
+	repo      CustomerRepository
+	users     UserRepository
+	addresses Addresses
+	bus       EventBus
+	auth      AuthService
+}
+
+func NewService(repo CustomerRepository, users UserRepository, addresses Addresses, bus EventBus, auth AuthService) *Service {
+	return &Service{
+		repo:      repo,
+		users:     users,
+		addresses: addresses,
+		bus:       bus,
+		auth:      auth,
+	}
+}
+
+// currentUser returns the authenticated user of the context.
+func (s *Service) currentUser(ctx context.Context) (uuid.UUID, error) {
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if userID == uuid.Nil {
+		return uuid.Nil, domain.ErrUnauthorized
+	}
+	return userID, nil
+}
+
+// authorize lets the user act on the profile of owner, its own profile or any profile with the permission.
+func (s *Service) authorize(ctx context.Context, userID, owner uuid.UUID, namespace, permission string) error {
+	if userID == owner {
+		return nil
+	}
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, permission)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return domain.ErrUnauthorized
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package exchange_test
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/internal/exchange"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var day = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+const ratesFile = `[
+	{"base": "USD", "quote": "BRL", "rate": "5.00", "effective_at": "2026-10-02T00:00:00Z", "source": "ptax"},
+	{"base": "USD", "quote": "BRL", "rate": "4.80", "effective_at": "2026-10-01T00:00:00Z", "source": "ptax"},
+	{"base": "BRL", "quote": "ARS", "rate": "250", "effective_at": "2026-10-01T00:00:00Z", "source": "manual"}
+]`
+
+func TestFileProvider(t *testing.T) {
+	fsys := fstest.MapFS{"rates.json": {Data: []byte(ratesFile)}}
+	provider, err := exchange.NewFileProvider(fsys, "rates.json")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected string
+		err      error
+	}{
+		{"before the first rate", day.Add(-time.Second), "", domain.ErrExchangeRateNotFound},
+		{"effective at is inclusive", day, "4.8", nil},
+		{"last effective rate", day.Add(36 * time.Hour), "5", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := provider.Rate(context.Background(), "ns", currency.CurrencyUSD, currency.CurrencyBRL, tt.at)
+			require.ErrorIs(t, err, tt.err)
+			if tt.err == nil {
+				assert.Equal(t, tt.expected, rate.Rate.String())
+			}
+		})
+	}
+
+	history, err := provider.History(context.Background(), "ns", dto.ExchangeRateFilter{Base: currency.CurrencyUSD})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.True(t, history[0].EffectiveAt.Before(history[1].EffectiveAt))
+
+	t.Run("an invalid file keeps the current rates", func(t *testing.T) {
+		fsys["rates.json"] = &fstest.MapFile{Data: []byte(`[{"base": "USD", "quote": "USD", "rate": "1"}]`)}
+		assert.ErrorIs(t, provider.Reload(), domain.ErrInvalidExchangeRate)
+
+		_, err := provider.Rate(context.Background(), "ns", currency.CurrencyUSD, currency.CurrencyBRL, day)
+		assert.NoError(t, err)
+	})
+}
+
+func TestConvert(t *testing.T) {
+	fsys := fstest.MapFS{"rates.json": {Data: []byte(ratesFile)}}
+	provider, err := exchange.NewFileProvider(fsys, "rates.json")
+	require.NoError(t, err)
+
+	service := exchange.NewService(&exchange.Config{
+		MaxAge: 72 * time.Hour,
+		Display: map[currency.Currency]exchange.DisplayRule{
+			currency.CurrencyUSD: {Mode: currency.RoundHalfUp, Ending: currency.PriceEnding{Ending: 90, Step: 100}},
+		},
+	}, provider, nil)
+
+	tests := []struct {
+		name     string
+		amount   currency.BRL
+		to       currency.Currency
+		at       time.Time
+		expected currency.Money
+		err      error
+	}{
+		// 100.00 BRL / 5.00 = 20.00 USD, raised to 20.90
+		{"inverse rate with price ending", 10000, currency.CurrencyUSD, day.Add(25 * time.Hour), currency.New(2090, currency.CurrencyUSD), nil},
+		// 99.99 BRL × 250 = 24997.50 ARS, rounded half-even
+		{"direct rate", 9999, "ARS", day.Add(time.Hour), currency.New(2499750, "ARS"), nil},
+		{"brl is not converted", 9999, currency.CurrencyBRL, day, currency.New(9999, currency.CurrencyBRL), nil},
+		{"expired rate", 10000, "ARS", day.Add(73 * time.Hour), currency.Money{}, domain.ErrExchangeRateExpired},
+		{"missing rate", 10000, currency.CurrencyEUR, day, currency.Money{}, domain.ErrExchangeRateNotFound},
+		{"unknown currency", 10000, "XXX", day, currency.Money{}, currency.ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := service.Convert(context.Background(), "ns", tt.amount, tt.to, tt.at)
+			require.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, price.Price)
+		})
+	}
+
+	t.Run("display prices leave out the missing rates", func(t *testing.T) {
+		prices := service.DisplayPrices(context.Background(), "ns", 10000, day, currency.CurrencyEUR, currency.CurrencyUSD)
+		require.Len(t, prices, 1)
+		assert.Equal(t, currency.New(2090, currency.CurrencyUSD), prices[0].Price)
+		// the inverse of 4.80
+		assert.Equal(t, "5/24", prices[0].Rate.String())
+		assert.Equal(t, day, prices[0].RateDate)
+	})
+}
+
+func TestRecordRates(t *testing.T) {
+	userID := uuid.New()
+
+	setup := func(t *testing.T) (*MockRateRepository, *MockAuthService, *exchange.Service) {
+		ctrl := gomock.NewController(t)
+		mockRepo := NewMockRateRepository(ctrl)
+		mockAuth := NewMockAuthService(ctrl)
+		mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+		mockAuth.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "exchange:write").Return(true, nil)
+		return mockRepo, mockAuth, exchange.NewService(&exchange.Config{}, mockRepo, mockAuth)
+	}
+
+	t.Run("records the rates", func(t *testing.T) {
+		mockRepo, _, service := setup(t)
+		rate := &domain.ExchangeRate{Base: currency.CurrencyUSD, Quote: currency.CurrencyBRL, Rate: currency.MustParseRate("5.43")}
+		mockRepo.EXPECT().Save(gomock.Any(), "ns", rate).Return(nil)
+
+		err := service.RecordRates(context.Background(), "ns", rate)
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, rate.ID)
+		assert.Equal(t, "ns", rate.Namespace)
+		assert.False(t, rate.EffectiveAt.IsZero())
+	})
+
+	t.Run("invalid rates", func(t *testing.T) {
+		_, _, service := setup(t)
+
+		err := service.RecordRates(context.Background(), "ns",
+			&domain.ExchangeRate{Base: currency.CurrencyUSD, Quote: currency.CurrencyUSD, Rate: currency.MustParseRate("1")},
+			&domain.ExchangeRate{Base: "XXX", Quote: currency.CurrencyBRL, Rate: currency.MustParseRate("-1")},
+		)
+
+		var verr *domain.ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.ErrorIs(t, err, domain.ErrInvalidExchangeRate)
+		assert.Equal(t, []string{"rates[0].quote", "rates[1].base", "rates[1].rate"}, verr.Fields())
+	})
+
+	t.Run("read-only provider", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAuth := NewMockAuthService(ctrl)
+		mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+		mockAuth.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "exchange:write").Return(true, nil)
+		service := exchange.NewService(&exchange.Config{}, NewMockRateProvider(ctrl), mockAuth)
+
+		err := service.RecordRates(context.Background(), "ns", &domain.ExchangeRate{})
+		assert.ErrorIs(t, err, domain.ErrExchangeRatesFixed)
+	})
+}
@@ -0,0 +1,208 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination mock_test.go --package exchange_test
+//
+
+// Package exchange_test is a generated GoMock package.
+package exchange_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/HBeserra/GoShop/domain"
+	dto "github.com/HBeserra/GoShop/domain/dto"
+	currency "github.com/HBeserra/GoShop/pkg/currency"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRateProvider is a mock of RateProvider interface.
+type MockRateProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockRateProviderMockRecorder is the mock recorder for MockRateProvider.
+type MockRateProviderMockRecorder struct {
+	mock *MockRateProvider
+}
+
+// NewMockRateProvider creates a new mock instance.
+func NewMockRateProvider(ctrl *gomock.Controller) *MockRateProvider {
+	mock := &MockRateProvider{ctrl: ctrl}
+	mock.recorder = &MockRateProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateProvider) EXPECT() *MockRateProviderMockRecorder {
+	return m.recorder
+}
+
+// History mocks base method.
+func (m *MockRateProvider) History(ctx context.Context, namespace string, filter dto.ExchangeRateFilter) ([]*domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, namespace, filter)
+	ret0, _ := ret[0].([]*domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockRateProviderMockRecorder) History(ctx, namespace, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockRateProvider)(nil).History), ctx, namespace, filter)
+}
+
+// Rate mocks base method.
+func (m *MockRateProvider) Rate(ctx context.Context, namespace string, base, quote currency.Currency, at time.Time) (*domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rate", ctx, namespace, base, quote, at)
+	ret0, _ := ret[0].(*domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rate indicates an expected call of Rate.
+func (mr *MockRateProviderMockRecorder) Rate(ctx, namespace, base, quote, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rate", reflect.TypeOf((*MockRateProvider)(nil).Rate), ctx, namespace, base, quote, at)
+}
+
+// MockRateRepository is a mock of RateRepository interface.
+type MockRateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRateRepositoryMockRecorder is the mock recorder for MockRateRepository.
+type MockRateRepositoryMockRecorder struct {
+	mock *MockRateRepository
+}
+
+// NewMockRateRepository creates a new mock instance.
+func NewMockRateRepository(ctrl *gomock.Controller) *MockRateRepository {
+	mock := &MockRateRepository{ctrl: ctrl}
+	mock.recorder = &MockRateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateRepository) EXPECT() *MockRateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// History mocks base method.
+func (m *MockRateRepository) History(ctx context.Context, namespace string, filter dto.ExchangeRateFilter) ([]*domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, namespace, filter)
+	ret0, _ := ret[0].([]*domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockRateRepositoryMockRecorder) History(ctx, namespace, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockRateRepository)(nil).History), ctx, namespace, filter)
+}
+
+// Rate mocks base method.
+func (m *MockRateRepository) Rate(ctx context.Context, namespace string, base, quote currency.Currency, at time.Time) (*domain.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rate", ctx, namespace, base, quote, at)
+	ret0, _ := ret[0].(*domain.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rate indicates an expected call of Rate.
+func (mr *MockRateRepositoryMockRecorder) Rate(ctx, namespace, base, quote, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rate", reflect.TypeOf((*MockRateRepository)(nil).Rate), ctx, namespace, base, quote, at)
+}
+
+// Save mocks base method.
+func (m *MockRateRepository) Save(ctx context.Context, namespace string, rates ...*domain.ExchangeRate) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, namespace}
+	for _, a := range rates {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Save", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockRateRepositoryMockRecorder) Save(ctx, namespace any, rates ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, namespace}, rates...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRateRepository)(nil).Save), varargs...)
+}
+
+// MockAuthService is a mock of AuthService interface.
+type MockAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthServiceMockRecorder is the mock recorder for MockAuthService.
+type MockAuthServiceMockRecorder struct {
+	mock *MockAuthService
+}
+
+// NewMockAuthService creates a new mock instance.
+func NewMockAuthService(ctrl *gomock.Controller) *MockAuthService {
+	mock := &MockAuthService{ctrl: ctrl}
+	mock.recorder = &MockAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckPermissions mocks base method.
+func (m *MockAuthService) CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, userID, namespace}
+	for _, a := range permission {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckPermissions", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPermissions indicates an expected call of CheckPermissions.
+func (mr *MockAuthServiceMockRecorder) CheckPermissions(ctx, userID, namespace any, permission ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, userID, namespace}, permission...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPermissions", reflect.TypeOf((*MockAuthService)(nil).CheckPermissions), varargs...)
+}
+
+// GetUserID mocks base method.
+func (m *MockAuthService) GetUserID(ctx context.Context) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserID", ctx)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserID indicates an expected call of GetUserID.
+func (mr *MockAuthServiceMockRecorder) GetUserID(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserID", reflect.TypeOf((*MockAuthService)(nil).GetUserID), ctx)
+}
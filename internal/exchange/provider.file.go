@@ -0,0 +1,113 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"io/fs"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FileProvider is a read-only RateProvider loaded from a JSON file holding an array of rates, such as
+//
+//	[{"base": "USD", "quote": "BRL", "rate": "5.4321", "effective_at": "2026-10-01T00:00:00Z", "source": "ptax"}]
+//
+// The same rates are served to every namespace. The new quotes, such as the daily PTAX, are appended
+// to the file and read with Reload.
+type FileProvider struct {
+	fsys fs.FS
+	name string
+
+	mu    sync.RWMutex
+	rates map[pair][]*domain.ExchangeRate // sorted by EffectiveAt
+}
+
+type pair struct {
+	base, quote currency.Currency
+}
+
+// NewFileProvider loads the rates of the named file.
+func NewFileProvider(fsys fs.FS, name string) (*FileProvider, error) {
+	p := &FileProvider{fsys: fsys, name: name}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload replaces the rates with the content of the file. When a rate of the file is invalid none is
+// replaced and the error names it.
+func (p *FileProvider) Reload() error {
+	data, err := fs.ReadFile(p.fsys, p.name)
+	if err != nil {
+		return err
+	}
+
+	var list []*domain.ExchangeRate
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("%w: %s: %w", domain.ErrInvalidExchangeRate, p.name, err)
+	}
+
+	rates := map[pair][]*domain.ExchangeRate{}
+	for i, r := range list {
+		if !r.Base.Valid() || !r.Quote.Valid() || r.Base == r.Quote || r.Rate.IsZero() || r.Rate.Rat().Sign() <= 0 {
+			return fmt.Errorf("%w: %s: rates[%d]", domain.ErrInvalidExchangeRate, p.name, i)
+		}
+		key := pair{r.Base, r.Quote}
+		rates[key] = append(rates[key], r)
+	}
+	for _, history := range rates {
+		slices.SortStableFunc(history, func(a, b *domain.ExchangeRate) int {
+			return a.EffectiveAt.Compare(b.EffectiveAt)
+		})
+	}
+
+	p.mu.Lock()
+	p.rates = rates
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *FileProvider) Rate(_ context.Context, _ string, base, quote currency.Currency, at time.Time) (*domain.ExchangeRate, error) {
+	p.mu.RLock()
+	history := p.rates[pair{base, quote}]
+	p.mu.RUnlock()
+
+	// the last rate effective at the given time
+	i, _ := slices.BinarySearchFunc(history, at, func(r *domain.ExchangeRate, t time.Time) int {
+		if r.EffectiveAt.After(t) {
+			return 1
+		}
+		return -1
+	})
+	if i == 0 {
+		return nil, domain.ErrExchangeRateNotFound
+	}
+	return history[i-1], nil
+}
+
+func (p *FileProvider) History(_ context.Context, _ string, filter dto.ExchangeRateFilter) ([]*domain.ExchangeRate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var result []*domain.ExchangeRate
+	for key, history := range p.rates {
+		if (filter.Base != "" && key.base != filter.Base) || (filter.Quote != "" && key.quote != filter.Quote) {
+			continue
+		}
+		for _, r := range history {
+			if (filter.Start.IsZero() || !r.EffectiveAt.Before(filter.Start)) && (filter.End.IsZero() || r.EffectiveAt.Before(filter.End)) {
+				result = append(result, r)
+			}
+		}
+	}
+	slices.SortStableFunc(result, func(a, b *domain.ExchangeRate) int {
+		return a.EffectiveAt.Compare(b.EffectiveAt)
+	})
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"log/slog"
+	"time"
+)
+
+// Convert converts the BRL amount to the currency with the rate effective at the given time, rounded with the
+// display rule of the currency. The result is meant for display, the orders are always charged in BRL.
+func (s *Service) Convert(ctx context.Context, namespace string, amount currency.BRL, to currency.Currency, at time.Time) (domain.DisplayPrice, error) {
+
+	ctx, span := observability.StartSpan(ctx, "exchange.Convert")
+	defer span.End()
+
+	return s.convert(ctx, namespace, amount, to, at)
+}
+
+// DisplayPrices converts the BRL amount to each currency, see Convert. The currencies that cannot be
+// converted, such as the ones without a current rate, are left out and logged, so a missing rate never
+// prevents the canonical price from being shown.
+func (s *Service) DisplayPrices(ctx context.Context, namespace string, amount currency.BRL, at time.Time, currencies ...currency.Currency) []domain.DisplayPrice {
+
+	ctx, span := observability.StartSpan(ctx, "exchange.DisplayPrices")
+	defer span.End()
+
+	prices := make([]domain.DisplayPrice, 0, len(currencies))
+	for _, c := range currencies {
+		price, err := s.convert(ctx, namespace, amount, c, at)
+		if err != nil {
+			span.RecordError(err)
+			slog.ErrorContext(ctx, "failed to convert display price",
+				"currency", c,
+				"amount", amount,
+				"error", err,
+			)
+			continue
+		}
+		prices = append(prices, price)
+	}
+	return prices
+}
+
+func (s *Service) convert(ctx context.Context, namespace string, amount currency.BRL, to currency.Currency, at time.Time) (domain.DisplayPrice, error) {
+	if !to.Valid() {
+		return domain.DisplayPrice{}, currency.ErrUnknownCurrency
+	}
+	if to == currency.CurrencyBRL {
+		return domain.DisplayPrice{Price: amount.Money(), Rate: currency.NewRate(1, 1), RateDate: at}, nil
+	}
+
+	rate, effectiveAt, err := s.rate(ctx, namespace, currency.CurrencyBRL, to, at)
+	if err != nil {
+		return domain.DisplayPrice{}, err
+	}
+
+	rule := s.config.Display[to]
+	price, err := amount.Money().Convert(to, rate, rule.Mode)
+	if err != nil {
+		return domain.DisplayPrice{}, err
+	}
+
+	return domain.DisplayPrice{
+		Price:    rule.Ending.Apply(price),
+		Rate:     rate,
+		RateDate: effectiveAt,
+	}, nil
+}
+
+// rate returns the base to quote rate effective at the given time and when it became effective.
+// When the pair is only quoted the other way around, such as USD/BRL for a BRL to USD conversion,
+// the inverse rate is used.
+func (s *Service) rate(ctx context.Context, namespace string, base, quote currency.Currency, at time.Time) (currency.Rate, time.Time, error) {
+	inverse := false
+	r, err := s.provider.Rate(ctx, namespace, base, quote, at)
+	if errors.Is(err, domain.ErrExchangeRateNotFound) {
+		inverse = true
+		r, err = s.provider.Rate(ctx, namespace, quote, base, at)
+	}
+	if err != nil {
+		return currency.Rate{}, time.Time{}, err
+	}
+
+	if s.config.MaxAge > 0 && at.Sub(r.EffectiveAt) > s.config.MaxAge {
+		return currency.Rate{}, time.Time{}, domain.ErrExchangeRateExpired
+	}
+
+	rate := r.Rate
+	if inverse {
+		if rate, err = rate.Inverse(); err != nil {
+			return currency.Rate{}, time.Time{}, domain.ErrInvalidExchangeRate
+		}
+	}
+	return rate, r.EffectiveAt, nil
+}
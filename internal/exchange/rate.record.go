@@ -0,0 +1,78 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// RecordRates appends the rates to the history, a rate without EffectiveAt is effective immediately.
+// It returns domain.ErrExchangeRatesFixed when the provider is read-only, such as a FileProvider.
+func (s *Service) RecordRates(ctx context.Context, namespace string, rates ...*domain.ExchangeRate) error {
+
+	ctx, span := observability.StartSpan(ctx, "exchange.RecordRates")
+	defer span.End()
+
+	if err := s.checkPermission(ctx, namespace, "exchange:write"); err != nil {
+		return err
+	}
+
+	repo, ok := s.provider.(RateRepository)
+	if !ok {
+		return domain.ErrExchangeRatesFixed
+	}
+
+	verr := &domain.ValidationError{}
+	now := time.Now()
+	for i, r := range rates {
+		field := fmt.Sprintf("rates[%d]", i)
+		if !r.Base.Valid() {
+			verr.Add(field+".base", domain.ValidationCodeInvalid, domain.ErrInvalidExchangeRate, nil)
+		}
+		if !r.Quote.Valid() || r.Quote == r.Base {
+			verr.Add(field+".quote", domain.ValidationCodeInvalid, domain.ErrInvalidExchangeRate, nil)
+		}
+		if r.Rate.IsZero() || r.Rate.Rat().Sign() <= 0 {
+			verr.Add(field+".rate", domain.ValidationCodeMin, domain.ErrInvalidExchangeRate, map[string]any{"min": 0})
+		}
+
+		r.ID = uuid.New()
+		r.Namespace = namespace
+		r.CreatedAt = now
+		if r.EffectiveAt.IsZero() {
+			r.EffectiveAt = now
+		}
+	}
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	if err := repo.Save(ctx, namespace, rates...); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "exchange rates recorded",
+		"namespace", namespace,
+		"rates", len(rates),
+	)
+	return nil
+}
+
+// History returns the rates matching the filter, ordered by EffectiveAt.
+func (s *Service) History(ctx context.Context, namespace string, filter dto.ExchangeRateFilter) ([]*domain.ExchangeRate, error) {
+
+	ctx, span := observability.StartSpan(ctx, "exchange.History")
+	defer span.End()
+
+	if err := s.checkPermission(ctx, namespace, "exchange:read"); err != nil {
+		return nil, err
+	}
+
+	return s.provider.History(ctx, namespace, filter)
+}
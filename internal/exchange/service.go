@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Config struct {
+	// MaxAge is how long a rate can be used after it became effective, zero means forever.
+	MaxAge time.Duration `env:"EXCHANGE_RATE_MAX_AGE" envDefault:"72h"`
+	// Display holds the rounding of the display prices per currency, the currencies without a rule
+	// use the half-even rounding without a price ending.
+	Display map[currency.Currency]DisplayRule
+	// RatesPath is the JSON file of a FileProvider, used when the rates are not recorded in the database.
+	RatesPath string `env:"EXCHANGE_RATES"`
+}
+
+// DisplayRule defines how a converted price is rounded for display.
+type DisplayRule struct {
+	Mode   currency.RoundingMode
+	Ending currency.PriceEnding
+}
+
+// RateProvider gives access to the dated exchange rates.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  exchange_test
+type RateProvider interface {
+
+	// Rate returns the last rate of the pair effective at the given time, or domain.ErrExchangeRateNotFound.
+	Rate(ctx context.Context, namespace string, base, quote currency.Currency, at time.Time) (*domain.ExchangeRate, error)
+
+	// History returns the rates matching the filter, ordered by EffectiveAt.
+	History(ctx context.Context, namespace string, filter dto.ExchangeRateFilter) ([]*domain.ExchangeRate, error)
+}
+
+// RateRepository is a RateProvider backed by the database, where new rates can be recorded.
+type RateRepository interface {
+	RateProvider
+
+	// Save appends the rates to the history.
+	Save(ctx context.Context, namespace string, rates ...*domain.ExchangeRate) error
+}
+
+// AuthService returns information about the current command
+type AuthService interface {
+
+	// GetUserID retrieves the unique identifier (UUID) of the user from the provided context. Returns an error if retrieval fails.
+	GetUserID(ctx context.Context) (uuid.UUID, error)
+	// CheckPermissions verifies if a user has the required permissions for a specified namespace and action(s). It returns a boolean indicating access and an error if the operation fails.
+	CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error)
+}
+
+// Service converts the BRL prices to foreign currencies for display.
+type Service struct {
+	config   *Config
+	provider RateProvider
+	auth     AuthService
+}
+
+func NewService(config *Config, provider RateProvider, auth AuthService) *Service {
+	return &Service{
+		config:   config,
+		provider: provider,
+		auth:     auth,
+	}
+}
+
+// checkPermission verifies that the user of the context has the permission in the namespace.
+func (s *Service) checkPermission(ctx context.Context, namespace string, permission ...string) error {
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, permission...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return domain.ErrUnauthorized
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package invoice
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/internal/tax"
+	"github.com/HBeserra/GoShop/pkg/nfe"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"math/big"
+	"slices"
+	"time"
+)
+
+// software identifies the application in the NF-e.
+const software = "GoShop"
+
+// IssueRequest describes the invoice of an order.
+type IssueRequest struct {
+	OrderID uuid.UUID
+	// Recipient is the buyer, with the delivery address of the order.
+	Recipient nfe.Recipient
+	// PaymentMethod is the method the order was paid with.
+	PaymentMethod domain.PaymentMethod
+}
+
+// Issue generates the NF-e of a paid order, signs it and submits it to SEFAZ. The recipient must be the customer
+// who placed the order, at the address the order is delivered to. The invoice is stored as pending, with its
+// number and access key, before being submitted and then with the answer of SEFAZ: a rejected invoice is returned
+// along with domain.ErrInvoiceRejected and may be issued again with the same number once the cause is fixed, an
+// authorized one publishes "invoice:authorized". A pending invoice, whose answer was lost, is submitted again unchanged.
+func (s *Service) Issue(ctx context.Context, namespace string, req IssueRequest) (*domain.Invoice, error) {
+
+	ctx, span := observability.StartSpan(ctx, "invoice.Issue")
+	defer span.End()
+
+	userID, err := s.checkPermission(ctx, namespace, "invoice:issue")
+	if err != nil {
+		return nil, err
+	}
+	if s.cert == nil {
+		return nil, fmt.Errorf("%w: no certificate", nfe.ErrInvalidCertificate)
+	}
+
+	order, err := s.orders.GetOrder(ctx, namespace, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains([]domain.OrderStatus{domain.OrderStatusPaid, domain.OrderStatusFulfilled, domain.OrderStatusDelivered}, order.Status) {
+		return nil, fmt.Errorf("%w: order is %s", domain.ErrOrderNotPaid, order.Status)
+	}
+
+	invoice, err := s.repo.GetByOrder(ctx, namespace, order.ID)
+	isNew := false
+	switch {
+	case errors.Is(err, domain.ErrInvoiceNotFound):
+		invoice, isNew = new(domain.Invoice), true
+	case err != nil:
+		return nil, err
+	case invoice.Status == domain.InvoiceStatusAuthorized:
+		return nil, domain.ErrInvoiceAlreadyIssued
+	}
+
+	customer, err := s.customers.LookupByUser(ctx, namespace, order.CustomerID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if err = checkRecipient(order, customer, req.Recipient); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if isNew || invoice.Status != domain.InvoiceStatusPending {
+		if err = s.prepare(ctx, namespace, order, req, invoice, isNew, now); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	receipt, err := s.sefaz.Authorize(ctx, s.config.Environment, []byte(invoice.XML))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	invoice.UpdatedAt = now
+	invoice.StatusCode = receipt.StatusCode
+	invoice.Reason = receipt.Reason
+	invoice.Status = domain.InvoiceStatusRejected
+	if receipt.Authorized() {
+		invoice.Status = domain.InvoiceStatusAuthorized
+		invoice.Protocol = receipt.Protocol
+		invoice.AuthorizedAt = receipt.ReceivedAt
+	}
+	if err = s.repo.Update(ctx, namespace, invoice); err != nil {
+		return nil, err
+	}
+
+	if invoice.Status == domain.InvoiceStatusRejected {
+		return invoice, fmt.Errorf("%w: %d %s", domain.ErrInvoiceRejected, receipt.StatusCode, receipt.Reason)
+	}
+
+	event := events.InvoiceAuthorized{
+		ID:           invoice.ID,
+		OrderID:      order.ID,
+		AccessKey:    invoice.AccessKey,
+		Protocol:     invoice.Protocol,
+		AuthorizedAt: invoice.AuthorizedAt,
+		IssuedBy:     userID,
+	}
+	if err := s.bus.Publish(ctx, "invoice:authorized", event); err != nil {
+		slog.Error("failed to publish invoice authorized event", "invoice_id", invoice.ID, "error", err)
+	}
+
+	return invoice, nil
+}
+
+// prepare generates and signs the NF-e of the order and stores the invoice as pending, created when isNew with
+// the next number of the series, which the invoice then keeps.
+func (s *Service) prepare(ctx context.Context, namespace string, order *domain.Order, req IssueRequest, invoice *domain.Invoice, isNew bool, now time.Time) error {
+	breakdown, err := s.taxes.Calculate(ctx, namespace, tax.Request{
+		DestinationState: req.Recipient.Address.State,
+		Contributor:      req.Recipient.Contributor,
+		Lines:            order.Lines,
+		Freight:          order.Shipping - order.ShippingDiscount,
+		At:               order.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	products, err := s.products(ctx, namespace, order.Lines)
+	if err != nil {
+		return err
+	}
+
+	if isNew {
+		number, err := s.repo.NextNumber(ctx, namespace, s.config.Series)
+		if err != nil {
+			return err
+		}
+		*invoice = domain.Invoice{
+			ID:        uuid.New(),
+			Namespace: namespace,
+			CreatedAt: now,
+			OrderID:   order.ID,
+			Series:    s.config.Series,
+			Number:    number,
+		}
+	}
+
+	doc, err := s.document(order, breakdown, products, req, invoice, now)
+	if err != nil {
+		return err
+	}
+	unsigned, err := doc.XML()
+	if err != nil {
+		return err
+	}
+	signed, err := nfe.Sign(unsigned, s.cert, now)
+	if err != nil {
+		return err
+	}
+
+	invoice.AccessKey, _ = doc.AccessKey()
+	invoice.XML = string(signed)
+	invoice.UpdatedAt = now
+	invoice.Status = domain.InvoiceStatusPending
+	invoice.StatusCode = 0
+	invoice.Reason = ""
+
+	if isNew {
+		return s.repo.Create(ctx, namespace, invoice)
+	}
+	return s.repo.Update(ctx, namespace, invoice)
+}
+
+// checkRecipient verifies that the recipient is the customer who placed the order, identified by its CPF or CNPJ,
+// at one of its addresses with the CEP the order is delivered to.
+func checkRecipient(order *domain.Order, customer *domain.Customer, recipient nfe.Recipient) error {
+	document := string(customer.Document)
+	switch {
+	case customer.Document.IsCNPJ():
+		if recipient.CNPJ != document || recipient.CPF != "" {
+			return fmt.Errorf("%w: CNPJ", domain.ErrRecipientMismatch)
+		}
+	case customer.Document.IsCPF():
+		if recipient.CPF != document || recipient.CNPJ != "" {
+			return fmt.Errorf("%w: CPF", domain.ErrRecipientMismatch)
+		}
+	default:
+		return fmt.Errorf("%w: the customer has no CPF or CNPJ", domain.ErrRecipientMismatch)
+	}
+
+	address := recipient.Address
+	if order.ShippingCEP != "" && address.ZipCode != string(order.ShippingCEP) {
+		return fmt.Errorf("%w: the address is not the delivery address", domain.ErrRecipientMismatch)
+	}
+	for _, a := range customer.Addresses {
+		if string(a.CEP) == address.ZipCode && a.Number == address.Number && a.State == address.State && a.CityCode == address.CityCode {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: the address is not an address of the customer", domain.ErrRecipientMismatch)
+}
+
+// products returns the catalog products of the lines by ID.
+func (s *Service) products(ctx context.Context, namespace string, lines []domain.OrderLine) (map[uuid.UUID]*domain.Product, error) {
+	ids := make([]uuid.UUID, 0, len(lines))
+	for _, l := range lines {
+		ids = append(ids, l.ProductID)
+	}
+	found, err := s.catalog.Lookup(ctx, namespace, dto.ProductFilter{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	products := make(map[uuid.UUID]*domain.Product, len(found))
+	for _, p := range found {
+		products[p.ID] = p
+	}
+	return products, nil
+}
+
+// paymentMethods maps the payment methods to the tPag of the NF-e.
+var paymentMethods = map[domain.PaymentMethod]string{
+	domain.PaymentMethodCreditCard: nfe.PaymentCreditCard,
+	domain.PaymentMethodPix:        nfe.PaymentPix,
+	domain.PaymentMethodBoleto:     nfe.PaymentBoleto,
+}
+
+// document builds the NF-e of the order, the lines of the breakdown being those of the order.
+func (s *Service) document(order *domain.Order, breakdown *domain.TaxBreakdown, products map[uuid.UUID]*domain.Product, req IssueRequest, invoice *domain.Invoice, now time.Time) (*nfe.Document, error) {
+	if len(breakdown.Lines) != len(order.Lines) {
+		return nil, fmt.Errorf("%w: tax breakdown of %d lines for %d order lines", domain.ErrInvalidOrder, len(breakdown.Lines), len(order.Lines))
+	}
+
+	code, err := randomCode(invoice.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &nfe.Document{
+		Environment: s.config.Environment,
+		Issuer:      s.config.Issuer,
+		Recipient:   req.Recipient,
+		Series:      invoice.Series,
+		Number:      invoice.Number,
+		Code:        code,
+		Issued:      now,
+		Operation:   s.config.Operation,
+		Items:       make([]nfe.Item, 0, len(order.Lines)),
+		Software:    software,
+	}
+	if order.Shipping == 0 {
+		doc.FreightMode = nfe.FreightNone
+	}
+
+	interstate := doc.Interstate()
+	for i, l := range order.Lines {
+		product, ok := products[l.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, l.ProductID)
+		}
+		lt := breakdown.Lines[i]
+
+		item := nfe.Item{
+			Code:        l.SKU,
+			Description: l.Title,
+			NCM:         lt.NCM,
+			CFOP:        domain.SaleCFOP(product.CFOP, interstate, req.Recipient.Contributor),
+			Quantity:    l.Quantity,
+			UnitPrice:   l.UnitPrice,
+			Discount:    l.Discount,
+			Freight:     lt.Value - l.Total(),
+			Origin:      int(lt.Origin),
+			ICMS:        nfe.Tax(lt.ICMS),
+			IPI:         nfe.Tax(lt.IPI),
+			PIS:         nfe.Tax(lt.PIS),
+			COFINS:      nfe.Tax(lt.COFINS),
+			DIFAL:       nfe.Tax(lt.DIFAL),
+		}
+		if item.Code == "" {
+			item.Code = l.ProductID.String()
+		}
+		if interstate {
+			item.InterstateRate = lt.ICMS.Rate
+		}
+		if lt.DIFAL.Amount > 0 {
+			// the breakdown holds the gap between the rates, the NF-e the internal rate of the destination
+			item.DIFAL.Rate = lt.DIFAL.Rate.Add(lt.ICMS.Rate)
+		}
+		doc.Items = append(doc.Items, item)
+	}
+
+	tPag, ok := paymentMethods[req.PaymentMethod]
+	if !ok {
+		tPag = nfe.PaymentOther
+	}
+	doc.Payments = []nfe.Payment{{Method: tPag, Amount: doc.Totals().Invoice}}
+
+	return doc, nil
+}
+
+// randomCode returns the cNF of the invoice, a random number of 8 digits different from its number.
+func randomCode(number int64) (int, error) {
+	for {
+		n, err := rand.Int(rand.Reader, big.NewInt(100_000_000))
+		if err != nil {
+			return 0, err
+		}
+		if code := int(n.Int64()); int64(code) != number {
+			return code, nil
+		}
+	}
+}
@@ -0,0 +1,438 @@
+package invoice_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/internal/invoice"
+	"github.com/HBeserra/GoShop/internal/tax"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/HBeserra/GoShop/pkg/nfe"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var issuer = nfe.Issuer{
+	CNPJ: "00000000000191",
+	Name: "GoShop Comercio Ltda",
+	IE:   "123456789012",
+	CRT:  nfe.CRTRegimeNormal,
+	Address: nfe.Address{
+		Street: "Avenida Paulista", Number: "1000", District: "Bela Vista",
+		CityCode: "3550308", City: "Sao Paulo", State: "SP", ZipCode: "01310100",
+	},
+}
+
+var recipient = nfe.Recipient{
+	CPF:  "12345678909",
+	Name: "Maria Silva",
+	Address: nfe.Address{
+		Street: "Avenida Sete de Setembro", Number: "10", District: "Centro",
+		CityCode: "2927408", City: "Salvador", State: "BA", ZipCode: "40060000",
+	},
+}
+
+type setupParams struct {
+	repoService      *MockInvoiceRepository
+	ordersService    *MockOrders
+	customersService *MockCustomers
+	taxesService     *MockTaxes
+	catalogService   *MockProductCatalog
+	sefazService     *MockSefaz
+	busService       *MockEventBus
+	authService      *MockAuthService
+}
+
+// fixture is a paid order of one product, with its tax breakdown for an interstate sale to BA, and the customer
+// who placed it.
+type fixture struct {
+	userID    uuid.UUID
+	order     *domain.Order
+	product   *domain.Product
+	breakdown *domain.TaxBreakdown
+	customer  *domain.Customer
+}
+
+func newFixture() *fixture {
+	product := &domain.Product{ID: uuid.New(), NCM: "84713012"}
+	customer := &domain.Customer{
+		ID:       uuid.New(),
+		UserID:   uuid.New(),
+		Document: "12345678909",
+		Addresses: []domain.CustomerAddress{{
+			ID:   uuid.New(),
+			Kind: domain.AddressKindShipping,
+			Address: domain.Address{
+				CEP: "40060000", Street: "Avenida Sete de Setembro", Number: "10", District: "Centro",
+				City: "Salvador", State: "BA", CityCode: "2927408",
+			},
+		}},
+	}
+	order := &domain.Order{
+		ID:          uuid.New(),
+		CustomerID:  customer.UserID,
+		Status:      domain.OrderStatusPaid,
+		CreatedAt:   time.Now(),
+		ShippingCEP: "40060000",
+		Lines: []domain.OrderLine{
+			{ProductID: product.ID, SKU: "NB-01", Title: "Notebook", UnitPrice: 100000, Quantity: 1, Discount: 5000},
+		},
+		Shipping: 2000,
+	}
+	order.ComputeTotals()
+
+	breakdown := &domain.TaxBreakdown{
+		OriginState:      "SP",
+		DestinationState: "BA",
+		Lines: []domain.LineTax{{
+			ProductID: product.ID,
+			NCM:       product.NCM,
+			Value:     97000,
+			ICMS:      domain.TaxAmount{Base: 97000, Rate: currency.MustParseRate("0.07"), Amount: 6790},
+			DIFAL:     domain.TaxAmount{Base: 97000, Rate: currency.MustParseRate("0.135"), Amount: 13095},
+			PIS:       domain.TaxAmount{Base: 90210, Rate: currency.MustParseRate("0.0165"), Amount: 1488},
+			COFINS:    domain.TaxAmount{Base: 90210, Rate: currency.MustParseRate("0.076"), Amount: 6856},
+		}},
+	}
+	return &fixture{userID: uuid.New(), order: order, product: product, breakdown: breakdown, customer: customer}
+}
+
+// expectAllowed expects the permission check of the issuing user.
+func expectAllowed(t setupParams, f *fixture) {
+	t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+	t.authService.EXPECT().CheckPermissions(gomock.Any(), f.userID, "ns", "invoice:issue").Return(true, nil)
+}
+
+// expectOrder expects the reads of the order, its invoice and its customer.
+func expectOrder(t setupParams, f *fixture, existing *domain.Invoice) {
+	expectAllowed(t, f)
+	t.ordersService.EXPECT().GetOrder(gomock.Any(), "ns", f.order.ID).Return(f.order, nil)
+	if existing == nil {
+		t.repoService.EXPECT().GetByOrder(gomock.Any(), "ns", f.order.ID).Return(nil, domain.ErrInvoiceNotFound)
+	} else {
+		t.repoService.EXPECT().GetByOrder(gomock.Any(), "ns", f.order.ID).Return(existing, nil)
+	}
+	t.customersService.EXPECT().LookupByUser(gomock.Any(), "ns", f.customer.UserID).Return(f.customer, nil)
+}
+
+// expectDocument expects the reads generating the NF-e of the order.
+func expectDocument(t setupParams, f *fixture) {
+	t.taxesService.EXPECT().Calculate(gomock.Any(), "ns", tax.Request{
+		DestinationState: "BA",
+		Lines:            f.order.Lines,
+		Freight:          2000,
+		At:               f.order.CreatedAt,
+	}).Return(f.breakdown, nil)
+	t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", dto.ProductFilter{IDs: []uuid.UUID{f.product.ID}}).Return([]*domain.Product{f.product}, nil)
+}
+
+// expectPending expects the invoice to be stored as pending with its number and access key before being submitted.
+func expectPending(number int64) func(_ context.Context, _ string, inv *domain.Invoice) error {
+	return func(_ context.Context, _ string, inv *domain.Invoice) error {
+		if inv.Status != domain.InvoiceStatusPending || inv.Number != number || !nfe.ValidAccessKey(inv.AccessKey) || inv.XML == "" {
+			return fmt.Errorf("unexpected invoice %s %d %q", inv.Status, inv.Number, inv.AccessKey)
+		}
+		return nil
+	}
+}
+
+func TestIssue(t *testing.T) {
+	rejected := &domain.Invoice{ID: uuid.New(), Namespace: "ns", Series: 1, Number: 7, Status: domain.InvoiceStatusRejected, StatusCode: 778}
+	pending := &domain.Invoice{ID: uuid.New(), Namespace: "ns", Series: 1, Number: 8, Status: domain.InvoiceStatusPending, XML: "<NFe/>"}
+
+	tests := []struct {
+		name      string
+		recipient func(r *nfe.Recipient)
+		method    domain.PaymentMethod
+		setup     func(t setupParams, f *fixture)
+		// useMockSefaz submits the invoices to the sefazService instead of a FakeSefaz
+		useMockSefaz   bool
+		rejectNext     bool
+		err            error
+		expectedStatus domain.InvoiceStatus
+		expected       func(t *testing.T, f *fixture, inv *domain.Invoice)
+	}{
+		{
+			name:   "authorized",
+			method: domain.PaymentMethodPix,
+			setup: func(t setupParams, f *fixture) {
+				expectOrder(t, f, nil)
+				expectDocument(t, f)
+				t.repoService.EXPECT().NextNumber(gomock.Any(), "ns", 1).Return(int64(42), nil)
+				t.repoService.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).DoAndReturn(expectPending(42))
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", gomock.Any()).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "invoice:authorized", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, event interface{}) error {
+						if e := event.(events.InvoiceAuthorized); e.OrderID != f.order.ID || e.IssuedBy != f.userID {
+							return fmt.Errorf("unexpected event %+v", e)
+						}
+						return nil
+					})
+			},
+			expectedStatus: domain.InvoiceStatusAuthorized,
+			expected: func(t *testing.T, f *fixture, inv *domain.Invoice) {
+				assert.Equal(t, int64(42), inv.Number)
+				assert.Equal(t, invoice.StatusAuthorized, inv.StatusCode)
+				assert.Len(t, inv.Protocol, 15)
+				assert.True(t, nfe.ValidAccessKey(inv.AccessKey))
+
+				_, err := nfe.Verify([]byte(inv.XML))
+				require.NoError(t, err)
+				for _, expected := range []string{
+					"<CFOP>6108</CFOP>",
+					"<vFrete>20.00</vFrete>",
+					"<vDesc>50.00</vDesc>",
+					"<pICMSUFDest>20.5000</pICMSUFDest>",
+					"<tPag>17</tPag><vPag>970.00</vPag>",
+				} {
+					assert.Contains(t, inv.XML, expected)
+				}
+			},
+		},
+		{
+			name: "rejected",
+			setup: func(t setupParams, f *fixture) {
+				expectOrder(t, f, nil)
+				expectDocument(t, f)
+				t.repoService.EXPECT().NextNumber(gomock.Any(), "ns", 1).Return(int64(7), nil)
+				t.repoService.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).DoAndReturn(expectPending(7))
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", gomock.Any()).Return(nil)
+			},
+			rejectNext:     true,
+			err:            domain.ErrInvoiceRejected,
+			expectedStatus: domain.InvoiceStatusRejected,
+			expected: func(t *testing.T, f *fixture, inv *domain.Invoice) {
+				assert.Equal(t, 778, inv.StatusCode)
+				assert.Empty(t, inv.Protocol)
+			},
+		},
+		{
+			name: "rejected invoice issued again keeps its number",
+			setup: func(t setupParams, f *fixture) {
+				expectOrder(t, f, rejected)
+				expectDocument(t, f)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", rejected).DoAndReturn(expectPending(7))
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", rejected).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "invoice:authorized", gomock.Any()).Return(nil)
+			},
+			expectedStatus: domain.InvoiceStatusAuthorized,
+			expected: func(t *testing.T, f *fixture, inv *domain.Invoice) {
+				assert.Equal(t, rejected.ID, inv.ID)
+				assert.Equal(t, int64(7), inv.Number)
+				assert.Contains(t, inv.XML, "<tPag>99</tPag>")
+			},
+		},
+		{
+			name: "pending invoice submitted again unchanged",
+			setup: func(t setupParams, f *fixture) {
+				expectOrder(t, f, pending)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", pending).Return(nil)
+			},
+			// the FakeSefaz rejects the unsigned XML
+			err:            domain.ErrInvoiceRejected,
+			expectedStatus: domain.InvoiceStatusRejected,
+			expected: func(t *testing.T, f *fixture, inv *domain.Invoice) {
+				assert.Equal(t, "<NFe/>", inv.XML)
+				assert.Equal(t, int64(8), inv.Number)
+			},
+		},
+		{
+			name: "sefaz unreachable leaves the invoice pending",
+			setup: func(t setupParams, f *fixture) {
+				expectOrder(t, f, nil)
+				expectDocument(t, f)
+				t.repoService.EXPECT().NextNumber(gomock.Any(), "ns", 1).Return(int64(9), nil)
+				t.repoService.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).DoAndReturn(expectPending(9))
+				t.sefazService.EXPECT().Authorize(gomock.Any(), nfe.EnvironmentHomologation, gomock.Any()).Return(nil, context.DeadlineExceeded)
+			},
+			useMockSefaz: true,
+			err:          context.DeadlineExceeded,
+		},
+		{
+			name: "recipient with another CPF",
+			recipient: func(r *nfe.Recipient) {
+				r.CPF = "98765432100"
+			},
+			setup: func(t setupParams, f *fixture) {
+				expectOrder(t, f, nil)
+			},
+			err: domain.ErrRecipientMismatch,
+		},
+		{
+			name: "recipient with a CNPJ",
+			recipient: func(r *nfe.Recipient) {
+				r.CPF, r.CNPJ = "", "11222333000181"
+			},
+			setup: func(t setupParams, f *fixture) {
+				expectOrder(t, f, nil)
+			},
+			err: domain.ErrRecipientMismatch,
+		},
+		{
+			name: "recipient at another address of the same CEP",
+			recipient: func(r *nfe.Recipient) {
+				r.Address.Number = "20"
+			},
+			setup: func(t setupParams, f *fixture) {
+				expectOrder(t, f, nil)
+			},
+			err: domain.ErrRecipientMismatch,
+		},
+		{
+			name: "recipient in another state than the delivery",
+			recipient: func(r *nfe.Recipient) {
+				r.Address = nfe.Address{
+					Street: "Rua das Flores", Number: "10", District: "Centro",
+					CityCode: "3304557", City: "Rio de Janeiro", State: "RJ", ZipCode: "20010000",
+				}
+			},
+			setup: func(t setupParams, f *fixture) {
+				f.customer.Addresses = append(f.customer.Addresses, domain.CustomerAddress{Address: domain.Address{
+					CEP: "20010000", Number: "10", State: "RJ", CityCode: "3304557",
+				}})
+				expectOrder(t, f, nil)
+			},
+			err: domain.ErrRecipientMismatch,
+		},
+		{
+			name: "customer without document",
+			setup: func(t setupParams, f *fixture) {
+				f.customer.Document = ""
+				expectOrder(t, f, nil)
+			},
+			err: domain.ErrRecipientMismatch,
+		},
+		{
+			name: "customer not found",
+			setup: func(t setupParams, f *fixture) {
+				expectAllowed(t, f)
+				t.ordersService.EXPECT().GetOrder(gomock.Any(), "ns", f.order.ID).Return(f.order, nil)
+				t.repoService.EXPECT().GetByOrder(gomock.Any(), "ns", f.order.ID).Return(nil, domain.ErrInvoiceNotFound)
+				t.customersService.EXPECT().LookupByUser(gomock.Any(), "ns", f.customer.UserID).Return(nil, domain.ErrCustomerNotFound)
+			},
+			err: domain.ErrCustomerNotFound,
+		},
+		{
+			name: "order not paid",
+			setup: func(t setupParams, f *fixture) {
+				f.order.Status = domain.OrderStatusPending
+				expectAllowed(t, f)
+				t.ordersService.EXPECT().GetOrder(gomock.Any(), "ns", f.order.ID).Return(f.order, nil)
+			},
+			err: domain.ErrOrderNotPaid,
+		},
+		{
+			name: "already issued",
+			setup: func(t setupParams, f *fixture) {
+				expectAllowed(t, f)
+				t.ordersService.EXPECT().GetOrder(gomock.Any(), "ns", f.order.ID).Return(f.order, nil)
+				t.repoService.EXPECT().GetByOrder(gomock.Any(), "ns", f.order.ID).Return(&domain.Invoice{Status: domain.InvoiceStatusAuthorized}, nil)
+			},
+			err: domain.ErrInvoiceAlreadyIssued,
+		},
+		{
+			name: "unauthorized",
+			setup: func(t setupParams, f *fixture) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), f.userID, "ns", "invoice:issue").Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockInvoiceRepository(ctrl)
+			mockOrders := NewMockOrders(ctrl)
+			mockCustomers := NewMockCustomers(ctrl)
+			mockTaxes := NewMockTaxes(ctrl)
+			mockCatalog := NewMockProductCatalog(ctrl)
+			mockSefaz := NewMockSefaz(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+
+			fake := invoice.NewFakeSefaz()
+			var sefaz invoice.Sefaz = fake
+			if tt.useMockSefaz {
+				sefaz = mockSefaz
+			}
+			if tt.rejectNext {
+				fake.RejectNext(778, "Rejeicao: Informado NCM inexistente")
+			}
+			config := &invoice.Config{Environment: nfe.EnvironmentHomologation, Series: 1, Operation: "Venda de mercadoria", Issuer: issuer}
+			service := invoice.NewService(config, mockRepo, mockOrders, mockCustomers, mockTaxes, mockCatalog, sefaz, testCertificate(t), mockBus, mockAuth)
+
+			f := newFixture()
+			tt.setup(setupParams{
+				repoService:      mockRepo,
+				ordersService:    mockOrders,
+				customersService: mockCustomers,
+				taxesService:     mockTaxes,
+				catalogService:   mockCatalog,
+				sefazService:     mockSefaz,
+				busService:       mockBus,
+				authService:      mockAuth,
+			}, f)
+
+			r := recipient
+			if tt.recipient != nil {
+				tt.recipient(&r)
+			}
+			got, err := service.Issue(context.Background(), "ns", invoice.IssueRequest{OrderID: f.order.ID, Recipient: r, PaymentMethod: tt.method})
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.expectedStatus != "" {
+				require.NotNil(t, got)
+				assert.Equal(t, tt.expectedStatus, got.Status)
+			}
+			if tt.expected != nil {
+				tt.expected(t, f, got)
+			}
+		})
+	}
+}
+
+func TestFakeSefaz(t *testing.T) {
+	sefaz := invoice.NewFakeSefaz()
+
+	receipt, err := sefaz.Authorize(context.Background(), nfe.EnvironmentHomologation, []byte("<NFe/>"))
+	require.NoError(t, err)
+	assert.False(t, receipt.Authorized())
+	assert.Equal(t, 297, receipt.StatusCode)
+
+	_, err = sefaz.Authorize(context.Background(), nfe.EnvironmentProduction, []byte("<NFe/>"))
+	assert.Error(t, err)
+}
+
+// testCertificate returns a self-signed certificate standing for the A1 certificate of the issuer.
+func testCertificate(t *testing.T) *nfe.Certificate {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "GOSHOP COMERCIO LTDA:00000000000191"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &nfe.Certificate{Leaf: leaf, Key: key}
+}
@@ -0,0 +1,397 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination mock_test.go --package invoice_test
+//
+
+// Package invoice_test is a generated GoMock package.
+package invoice_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/HBeserra/GoShop/domain"
+	dto "github.com/HBeserra/GoShop/domain/dto"
+	invoice "github.com/HBeserra/GoShop/internal/invoice"
+	tax "github.com/HBeserra/GoShop/internal/tax"
+	nfe "github.com/HBeserra/GoShop/pkg/nfe"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockInvoiceRepository is a mock of InvoiceRepository interface.
+type MockInvoiceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvoiceRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockInvoiceRepositoryMockRecorder is the mock recorder for MockInvoiceRepository.
+type MockInvoiceRepositoryMockRecorder struct {
+	mock *MockInvoiceRepository
+}
+
+// NewMockInvoiceRepository creates a new mock instance.
+func NewMockInvoiceRepository(ctrl *gomock.Controller) *MockInvoiceRepository {
+	mock := &MockInvoiceRepository{ctrl: ctrl}
+	mock.recorder = &MockInvoiceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvoiceRepository) EXPECT() *MockInvoiceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockInvoiceRepository) Create(ctx context.Context, namespace string, arg2 *domain.Invoice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, namespace, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInvoiceRepositoryMockRecorder) Create(ctx, namespace, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvoiceRepository)(nil).Create), ctx, namespace, arg2)
+}
+
+// GetByOrder mocks base method.
+func (m *MockInvoiceRepository) GetByOrder(ctx context.Context, namespace string, orderID uuid.UUID) (*domain.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrder", ctx, namespace, orderID)
+	ret0, _ := ret[0].(*domain.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrder indicates an expected call of GetByOrder.
+func (mr *MockInvoiceRepositoryMockRecorder) GetByOrder(ctx, namespace, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrder", reflect.TypeOf((*MockInvoiceRepository)(nil).GetByOrder), ctx, namespace, orderID)
+}
+
+// NextNumber mocks base method.
+func (m *MockInvoiceRepository) NextNumber(ctx context.Context, namespace string, series int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextNumber", ctx, namespace, series)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextNumber indicates an expected call of NextNumber.
+func (mr *MockInvoiceRepositoryMockRecorder) NextNumber(ctx, namespace, series any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextNumber", reflect.TypeOf((*MockInvoiceRepository)(nil).NextNumber), ctx, namespace, series)
+}
+
+// Update mocks base method.
+func (m *MockInvoiceRepository) Update(ctx context.Context, namespace string, arg2 *domain.Invoice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, namespace, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockInvoiceRepositoryMockRecorder) Update(ctx, namespace, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInvoiceRepository)(nil).Update), ctx, namespace, arg2)
+}
+
+// MockOrders is a mock of Orders interface.
+type MockOrders struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrdersMockRecorder
+	isgomock struct{}
+}
+
+// MockOrdersMockRecorder is the mock recorder for MockOrders.
+type MockOrdersMockRecorder struct {
+	mock *MockOrders
+}
+
+// NewMockOrders creates a new mock instance.
+func NewMockOrders(ctrl *gomock.Controller) *MockOrders {
+	mock := &MockOrders{ctrl: ctrl}
+	mock.recorder = &MockOrdersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrders) EXPECT() *MockOrdersMockRecorder {
+	return m.recorder
+}
+
+// GetOrder mocks base method.
+func (m *MockOrders) GetOrder(ctx context.Context, namespace string, id uuid.UUID) (*domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, namespace, id)
+	ret0, _ := ret[0].(*domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrdersMockRecorder) GetOrder(ctx, namespace, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrders)(nil).GetOrder), ctx, namespace, id)
+}
+
+// MockCustomers is a mock of Customers interface.
+type MockCustomers struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomersMockRecorder
+	isgomock struct{}
+}
+
+// MockCustomersMockRecorder is the mock recorder for MockCustomers.
+type MockCustomersMockRecorder struct {
+	mock *MockCustomers
+}
+
+// NewMockCustomers creates a new mock instance.
+func NewMockCustomers(ctrl *gomock.Controller) *MockCustomers {
+	mock := &MockCustomers{ctrl: ctrl}
+	mock.recorder = &MockCustomersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomers) EXPECT() *MockCustomersMockRecorder {
+	return m.recorder
+}
+
+// LookupByUser mocks base method.
+func (m *MockCustomers) LookupByUser(ctx context.Context, namespace string, user uuid.UUID) (*domain.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupByUser", ctx, namespace, user)
+	ret0, _ := ret[0].(*domain.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupByUser indicates an expected call of LookupByUser.
+func (mr *MockCustomersMockRecorder) LookupByUser(ctx, namespace, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupByUser", reflect.TypeOf((*MockCustomers)(nil).LookupByUser), ctx, namespace, user)
+}
+
+// MockTaxes is a mock of Taxes interface.
+type MockTaxes struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxesMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxesMockRecorder is the mock recorder for MockTaxes.
+type MockTaxesMockRecorder struct {
+	mock *MockTaxes
+}
+
+// NewMockTaxes creates a new mock instance.
+func NewMockTaxes(ctrl *gomock.Controller) *MockTaxes {
+	mock := &MockTaxes{ctrl: ctrl}
+	mock.recorder = &MockTaxesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxes) EXPECT() *MockTaxesMockRecorder {
+	return m.recorder
+}
+
+// Calculate mocks base method.
+func (m *MockTaxes) Calculate(ctx context.Context, namespace string, req tax.Request) (*domain.TaxBreakdown, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Calculate", ctx, namespace, req)
+	ret0, _ := ret[0].(*domain.TaxBreakdown)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Calculate indicates an expected call of Calculate.
+func (mr *MockTaxesMockRecorder) Calculate(ctx, namespace, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Calculate", reflect.TypeOf((*MockTaxes)(nil).Calculate), ctx, namespace, req)
+}
+
+// MockProductCatalog is a mock of ProductCatalog interface.
+type MockProductCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductCatalogMockRecorder
+	isgomock struct{}
+}
+
+// MockProductCatalogMockRecorder is the mock recorder for MockProductCatalog.
+type MockProductCatalogMockRecorder struct {
+	mock *MockProductCatalog
+}
+
+// NewMockProductCatalog creates a new mock instance.
+func NewMockProductCatalog(ctrl *gomock.Controller) *MockProductCatalog {
+	mock := &MockProductCatalog{ctrl: ctrl}
+	mock.recorder = &MockProductCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductCatalog) EXPECT() *MockProductCatalogMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockProductCatalog) Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", ctx, namespace, filter)
+	ret0, _ := ret[0].([]*domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockProductCatalogMockRecorder) Lookup(ctx, namespace, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockProductCatalog)(nil).Lookup), ctx, namespace, filter)
+}
+
+// MockSefaz is a mock of Sefaz interface.
+type MockSefaz struct {
+	ctrl     *gomock.Controller
+	recorder *MockSefazMockRecorder
+	isgomock struct{}
+}
+
+// MockSefazMockRecorder is the mock recorder for MockSefaz.
+type MockSefazMockRecorder struct {
+	mock *MockSefaz
+}
+
+// NewMockSefaz creates a new mock instance.
+func NewMockSefaz(ctrl *gomock.Controller) *MockSefaz {
+	mock := &MockSefaz{ctrl: ctrl}
+	mock.recorder = &MockSefazMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSefaz) EXPECT() *MockSefazMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockSefaz) Authorize(ctx context.Context, environment nfe.Environment, signed []byte) (*invoice.Receipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, environment, signed)
+	ret0, _ := ret[0].(*invoice.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockSefazMockRecorder) Authorize(ctx, environment, signed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockSefaz)(nil).Authorize), ctx, environment, signed)
+}
+
+// MockEventBus is a mock of EventBus interface.
+type MockEventBus struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventBusMockRecorder
+	isgomock struct{}
+}
+
+// MockEventBusMockRecorder is the mock recorder for MockEventBus.
+type MockEventBusMockRecorder struct {
+	mock *MockEventBus
+}
+
+// NewMockEventBus creates a new mock instance.
+func NewMockEventBus(ctrl *gomock.Controller) *MockEventBus {
+	mock := &MockEventBus{ctrl: ctrl}
+	mock.recorder = &MockEventBusMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventBus) EXPECT() *MockEventBusMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventBus) Publish(ctx context.Context, topic string, event any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, topic, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventBusMockRecorder) Publish(ctx, topic, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventBus)(nil).Publish), ctx, topic, event)
+}
+
+// MockAuthService is a mock of AuthService interface.
+type MockAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthServiceMockRecorder is the mock recorder for MockAuthService.
+type MockAuthServiceMockRecorder struct {
+	mock *MockAuthService
+}
+
+// NewMockAuthService creates a new mock instance.
+func NewMockAuthService(ctrl *gomock.Controller) *MockAuthService {
+	mock := &MockAuthService{ctrl: ctrl}
+	mock.recorder = &MockAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckPermissions mocks base method.
+func (m *MockAuthService) CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, userID, namespace}
+	for _, a := range permission {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckPermissions", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPermissions indicates an expected call of CheckPermissions.
+func (mr *MockAuthServiceMockRecorder) CheckPermissions(ctx, userID, namespace any, permission ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, userID, namespace}, permission...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPermissions", reflect.TypeOf((*MockAuthService)(nil).CheckPermissions), varargs...)
+}
+
+// GetUserID mocks base method.
+func (m *MockAuthService) GetUserID(ctx context.Context) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserID", ctx)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserID indicates an expected call of GetUserID.
+func (mr *MockAuthServiceMockRecorder) GetUserID(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserID", reflect.TypeOf((*MockAuthService)(nil).GetUserID), ctx)
+}
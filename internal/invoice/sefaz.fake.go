@@ -0,0 +1,69 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/pkg/nfe"
+	"sync"
+	"time"
+)
+
+// FakeSefaz is a local Sefaz for tests and development. It checks the signature and the access key of the
+// NF-e, rejects the duplicated ones and authorizes the others with sequential protocols, in homologation only.
+type FakeSefaz struct {
+	mu         sync.Mutex
+	seq        int64
+	authorized map[string]bool
+	reject     *Receipt
+}
+
+func NewFakeSefaz() *FakeSefaz {
+	return &FakeSefaz{authorized: make(map[string]bool)}
+}
+
+// RejectNext makes the next submission rejected with the status code and reason.
+func (f *FakeSefaz) RejectNext(code int, reason string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reject = &Receipt{StatusCode: code, Reason: reason}
+}
+
+func (f *FakeSefaz) Authorize(_ context.Context, environment nfe.Environment, signed []byte) (*Receipt, error) {
+	// an invoice of production has fiscal value, it must never be authorized locally
+	if environment == nfe.EnvironmentProduction {
+		return nil, errors.New("the fake SEFAZ authorizes only the homologation invoices")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if f.reject != nil {
+		receipt := f.reject
+		f.reject = nil
+		receipt.ReceivedAt = now
+		return receipt, nil
+	}
+
+	if _, err := nfe.Verify(signed); err != nil {
+		return &Receipt{StatusCode: 297, Reason: "Rejeicao: Assinatura difere do calculado", ReceivedAt: now}, nil
+	}
+	key, err := nfe.KeyOf(signed)
+	if err != nil {
+		return &Receipt{StatusCode: 236, Reason: "Rejeicao: Chave de Acesso com digito verificador invalido", ReceivedAt: now}, nil
+	}
+	if f.authorized[key] {
+		return &Receipt{StatusCode: 204, Reason: "Rejeicao: Duplicidade de NF-e", ReceivedAt: now}, nil
+	}
+
+	f.authorized[key] = true
+	f.seq++
+	return &Receipt{
+		StatusCode: StatusAuthorized,
+		Reason:     "Autorizado o uso da NF-e",
+		// the nProt holds the authorizer type, the state code, the year and a sequence
+		Protocol:   fmt.Sprintf("1%s%s%010d", key[:2], now.Format("06"), f.seq),
+		ReceivedAt: now,
+	}, nil
+}
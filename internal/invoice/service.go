@@ -0,0 +1,155 @@
+package invoice
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/internal/tax"
+	"github.com/HBeserra/GoShop/pkg/nfe"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Config struct {
+	// Environment is the SEFAZ environment the invoices are issued in, 1 for production and 2 for homologation.
+	Environment nfe.Environment `env:"INVOICE_ENVIRONMENT" envDefault:"2"`
+	Series      int             `env:"INVOICE_SERIES" envDefault:"1"`
+	Operation   string          `env:"INVOICE_OPERATION" envDefault:"Venda de mercadoria"`
+	// CertificatePath is the PKCS#12 (.pfx) file of the A1 certificate of the issuer, see nfe.LoadCertificate.
+	CertificatePath     string `env:"INVOICE_CERTIFICATE"`
+	CertificatePassword string `env:"INVOICE_CERTIFICATE_PASSWORD"`
+	// Issuer is the company issuing the invoices, its address state must be the one the goods are shipped from.
+	Issuer nfe.Issuer
+}
+
+// InvoiceRepository defines an interface for managing the invoices.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  invoice_test
+type InvoiceRepository interface {
+
+	// Create stores a new invoice.
+	Create(ctx context.Context, namespace string, invoice *domain.Invoice) error
+
+	// Update stores the new state of the invoice.
+	Update(ctx context.Context, namespace string, invoice *domain.Invoice) error
+
+	// GetByOrder retrieves the invoice of the order, returns domain.ErrInvoiceNotFound if missing.
+	GetByOrder(ctx context.Context, namespace string, orderID uuid.UUID) (*domain.Invoice, error)
+
+	// NextNumber atomically reserves the next invoice number of the series, starting at 1.
+	NextNumber(ctx context.Context, namespace string, series int) (int64, error)
+}
+
+// Orders gives access to the orders being invoiced.
+type Orders interface {
+	GetOrder(ctx context.Context, namespace string, id uuid.UUID) (*domain.Order, error)
+}
+
+// Customers gives access to the customers of the invoiced orders, to check the recipient of the invoices.
+type Customers interface {
+	// LookupByUser returns the customer profile of the user without checking the permissions of the current
+	// user, returns domain.ErrCustomerNotFound if missing.
+	LookupByUser(ctx context.Context, namespace string, user uuid.UUID) (*domain.Customer, error)
+}
+
+// Taxes computes the tax breakdown of the invoiced orders.
+type Taxes interface {
+	Calculate(ctx context.Context, namespace string, req tax.Request) (*domain.TaxBreakdown, error)
+}
+
+// ProductCatalog gives access to the catalog products, to read their CFOP.
+type ProductCatalog interface {
+	// Lookup returns the products matching the filter without checking the permissions of the user, issuing
+	// an invoice requires only the "invoice:issue" permission.
+	Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error)
+}
+
+// Sefaz submits the signed NF-e to the tax authority of the issuer state for authorization.
+type Sefaz interface {
+	// Authorize sends the signed NF-e and returns the answer of SEFAZ. An error is returned only when no
+	// answer was received, a rejection is a Receipt with its status code.
+	Authorize(ctx context.Context, environment nfe.Environment, signed []byte) (*Receipt, error)
+}
+
+// EventBus defines an interface for managing event publishing and subscription.
+type EventBus interface {
+	// Publish sends an event to all subscribers of the specified topic.
+	Publish(ctx context.Context, topic string, event interface{}) error
+}
+
+// AuthService returns information about the current command
+type AuthService interface {
+	// GetUserID retrieves the unique identifier (UUID) of the user from the provided context. Returns an error if retrieval fails.
+	GetUserID(ctx context.Context) (uuid.UUID, error)
+	// CheckPermissions verifies if a user has the required permissions for a specified namespace and action(s). It returns a boolean indicating access and an error if the operation fails.
+	CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error)
+}
+
+// Receipt is the answer of SEFAZ to the submission of an NF-e.
+type Receipt struct {
+	// StatusCode is the cStat, 100 when the NF-e is authorized.
+	StatusCode int
+	// Reason is the xMotivo describing the status.
+	Reason string
+	// Protocol is the nProt of an authorized NF-e.
+	Protocol   string
+	ReceivedAt time.Time
+}
+
+// Status codes of SEFAZ.
+const (
+	StatusAuthorized     = 100
+	StatusAuthorizedLate = 150
+)
+
+// Authorized reports whether SEFAZ authorized the NF-e.
+func (r *Receipt) Authorized() bool {
+	return r.StatusCode == StatusAuthorized || r.StatusCode == StatusAuthorizedLate
+}
+
+// Service issues the NF-e of the paid orders.
+type Service struct {
+	config    *Config
+	repo      InvoiceRepository
+	orders    Orders
+	customers Customers
+	taxes     Taxes
+	catalog   ProductCatalog
+	sefaz     Sefaz
+	cert      *nfe.Certificate
+	bus       EventBus
+	auth      AuthService
+}
+
+// NewService creates the invoice service, cert is the A1 certificate signing the invoices.
+func NewService(config *Config, repo InvoiceRepository, orders Orders, customers Customers, taxes Taxes, catalog ProductCatalog, sefaz Sefaz, cert *nfe.Certificate, bus EventBus, auth AuthService) *Service {
+	return &Service{
+		config:    config,
+		repo:      repo,
+		orders:    orders,
+		customers: customers,
+		taxes:     taxes,
+		catalog:   catalog,
+		sefaz:     sefaz,
+		cert:      cert,
+		bus:       bus,
+		auth:      auth,
+	}
+}
+
+// checkPermission verifies that the current user has the permission and returns its ID.
+func (s *Service) checkPermission(ctx context.Context, namespace string, permission string) (uuid.UUID, error) {
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, permission)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return uuid.Nil, domain.ErrUnauthorized
+	}
+	return userID, nil
+}
@@ -0,0 +1,27 @@
+package media
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+)
+
+// GetURL returns a link downloading the media. Unlike the Url stored with the media, the link of the S3Storage
+// is presigned, so it reads the private buckets too but expires after the URLExpiry: it is requested for each
+// page showing the media rather than stored.
+func (s *Service) GetURL(ctx context.Context, namespace string, id uuid.UUID) (string, error) {
+
+	ctx, span := observability.StartSpan(ctx, "media.GetURL")
+	defer span.End()
+
+	media, err := s.repo.GetByID(ctx, namespace, id)
+	if err != nil {
+		return "", err
+	}
+	url, err := s.storage.GetURL(media.Filename)
+	if err != nil {
+		span.RecordError(err)
+		return "", err
+	}
+	return url, nil
+}
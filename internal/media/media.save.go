@@ -1,8 +1,11 @@
 package media
 
 import (
-	"bytes"
+	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/HBeserra/GoShop/domain"
 	"github.com/HBeserra/GoShop/pkg/observability"
@@ -13,6 +16,10 @@ import (
 	"time"
 )
 
+// Save streams the upload to the storage: the upload is limited to the MaxUploadSize, its content type
+// sniffed, compressed when a compressor is set for its type, hashed and limited to the MaxSize as it is
+// written, so only small buffers are held in memory whatever the size of the file. The uploads exceeding
+// a limit fail with domain.ErrFileTooLarge as soon as the limit is crossed, and nothing is stored.
 func (s *Service) Save(
 	ctx context.Context,
 	namespace string,
@@ -44,43 +51,78 @@ func (s *Service) Save(
 		return uuid.Nil, domain.ErrInvalidMediaType
 	}
 
-	var fileData []byte
-	if compressor, ok := s.compressor[contentType]; ok {
-		fileData, err = compressor.Compress(file, s.config.CompressLevel)
-		if err != nil {
-			return uuid.Nil, err
-		}
+	// the readers knowing their size, such as the files, are rejected before reading anything
+	if sized, ok := file.(interface{ Size() int64 }); ok && s.config.MaxUploadSize > 0 && sized.Size() > int64(s.config.MaxUploadSize) {
+		return uuid.Nil, domain.ErrFileTooLarge
 	}
 
-	if len(fileData) > int(s.config.MaxSize) {
-		return uuid.Nil, domain.ErrFileTooLarge
+	upload := newLimitedReader(file, s.config.MaxUploadSize)
+	input := bufio.NewReaderSize(upload, sniffLen)
+	head, err := input.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return uuid.Nil, err
+	}
+	if !sniffMatches(head, contentType) {
+		return uuid.Nil, domain.ErrInvalidMediaType
 	}
 
-	reader := bytes.NewReader(fileData)
+	var content io.Reader = input
+	var compressed chan error
+	if compressor, ok := s.compressor[contentType]; ok && s.config.Compress {
+		pr, pw := io.Pipe()
+		compressed = make(chan error, 1)
+		go func() {
+			err := compressor.Compress(pw, input, s.config.CompressLevel)
+			pw.CloseWithError(err)
+			compressed <- err
+		}()
+		defer func() {
+			// a storage failing early stops reading, closing the pipe unblocks the compressor
+			pr.Close()
+			<-compressed
+		}()
+		content = pr
+	}
+
+	hash := sha256.New()
+	output := newLimitedReader(io.TeeReader(content, hash), s.maxSize(contentType))
 
-	url, err := s.storage.Save(reader, filename)
+	url, err := s.storage.Save(output, filename)
 	if err != nil {
+		// the limits are checked by their flags, the storage may wrap the errors of the readers
+		if upload.exceeded.Load() || output.exceeded.Load() {
+			err = domain.ErrFileTooLarge
+		}
+		span.RecordError(err)
 		slog.ErrorContext(ctx, "error saving file",
 			"error", err,
 			"filename", filename,
-			"contentType", contentType,
-			"size", len(fileData),
+			"content_type", contentType,
+			"size", output.n.Load(),
 			"namespace", namespace,
 		)
 		return uuid.Nil, err
 	}
 
-	s.CreateThumb(ctx, fileData, filename, contentType)
-
+	now := time.Now()
 	return s.repo.Save(ctx, namespace, &domain.Media{
-		ID:        uuid.New(),
-		Namespace: namespace,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		Filename:  filename,
-		Url:       url,
-		Type:      contentType,
-		Size:      len(fileData),
+		ID:         uuid.New(),
+		Namespace:  namespace,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Filename:   filename,
+		Url:        url,
+		Type:       contentType,
+		Size:       int(output.n.Load()),
+		Checksum:   hex.EncodeToString(hash.Sum(nil)),
+		UploadedBy: userID,
 	})
+}
 
+// maxSize returns the maximum stored size of the content type.
+func (s *Service) maxSize(contentType string) domain.ByteSize {
+	if size, ok := s.config.MaxSizeByType[contentType]; ok {
+		return size
+	}
+	return s.config.MaxSize
 }
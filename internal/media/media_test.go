@@ -0,0 +1,326 @@
+package media_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/media"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"io"
+	"strings"
+	"testing"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+// gzipCompressor is a streaming FileCompressor.
+type gzipCompressor struct{}
+
+func (gzipCompressor) Compress(w io.Writer, file io.Reader, level int) error {
+	zw, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(zw, file); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// upload is a PNG of the given size generated as it is read, it is never held in memory.
+type upload struct {
+	size, read int64
+}
+
+func (u *upload) Read(p []byte) (int, error) {
+	if u.read >= u.size {
+		return 0, io.EOF
+	}
+	n := int(min(int64(len(p)), u.size-u.read))
+	for i := range n {
+		if pos := u.read + int64(i); pos < int64(len(pngHeader)) {
+			p[i] = pngHeader[pos]
+		} else {
+			p[i] = byte(pos % 251)
+		}
+	}
+	u.read += int64(n)
+	return n, nil
+}
+
+func (u *upload) Close() error { return nil }
+
+// file is an upload knowing its size, as the multipart.File of the HTTP handlers.
+type file struct{ *strings.Reader }
+
+func (file) Close() error { return nil }
+
+type setupParams struct {
+	repoService    *MockMediaRepository
+	authService    *MockAuthService
+	storageService *MockFileStorage
+}
+
+// expectAllowed expects the permission check of the uploading user.
+func expectAllowed(t setupParams, userID uuid.UUID) {
+	t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+	t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "media:create").Return(true, nil)
+}
+
+func TestSave(t *testing.T) {
+	config := &media.Config{MediaTypes: []string{"image/png"}, MaxUploadSize: 1 << 20, MaxSize: 1 << 20, Compress: true, CompressLevel: 9}
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		setup    func(t setupParams, saved **domain.Media)
+		err      error
+		expected func(t *testing.T, storage *media.LocalStorage, saved *domain.Media)
+	}{
+		{
+			name: "compressed and stored",
+			setup: func(t setupParams, saved **domain.Media) {
+				expectAllowed(t, userID)
+				t.repoService.EXPECT().Save(gomock.Any(), "ns", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, m *domain.Media) (uuid.UUID, error) {
+					*saved = m
+					return m.ID, nil
+				})
+			},
+			expected: func(t *testing.T, storage *media.LocalStorage, saved *domain.Media) {
+				stored, err := storage.Get("products/mug.png")
+				require.NoError(t, err)
+				sum := sha256.Sum256(stored)
+				assert.Equal(t, hex.EncodeToString(sum[:]), saved.Checksum)
+				assert.Equal(t, len(stored), saved.Size)
+				assert.Equal(t, "/media/products/mug.png", saved.Url)
+				assert.Equal(t, userID, saved.UploadedBy)
+
+				zr, err := gzip.NewReader(bytes.NewReader(stored))
+				require.NoError(t, err)
+				original, err := io.ReadAll(zr)
+				require.NoError(t, err)
+				assert.Len(t, original, 100_000)
+				assert.Equal(t, pngHeader, original[:len(pngHeader)])
+			},
+		},
+		{
+			name: "unauthenticated",
+			setup: func(t setupParams, saved **domain.Media) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, errors.New("no session"))
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name: "without permission",
+			setup: func(t setupParams, saved **domain.Media) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "media:create").Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockMediaRepository(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			storage, err := media.NewLocalStorage(t.TempDir(), "/media")
+			require.NoError(t, err)
+
+			service := media.NewService(config, storage, mockRepo, mockAuth, map[string]media.FileCompressor{"image/png": gzipCompressor{}})
+			var saved *domain.Media
+			tt.setup(setupParams{repoService: mockRepo, authService: mockAuth}, &saved)
+
+			_, err = service.Save(context.Background(), "ns", &upload{size: 100_000}, "products/mug.png", "image/png")
+			if tt.err != nil {
+				assert.ErrorContains(t, err, tt.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			tt.expected(t, storage, saved)
+		})
+	}
+}
+
+func TestSave_Limits(t *testing.T) {
+	config := &media.Config{
+		MediaTypes:    []string{"image/png", "image/jpeg"},
+		MaxUploadSize: 64 << 10,
+		MaxSize:       32 << 10,
+		MaxSizeByType: map[string]domain.ByteSize{"image/jpeg": 128},
+	}
+	userID := uuid.New()
+
+	tests := []struct {
+		name        string
+		file        io.ReadCloser
+		contentType string
+		err         error
+		maxRead     int64
+	}{
+		{
+			name:        "upload size known beforehand",
+			file:        file{strings.NewReader(string(pngHeader) + strings.Repeat("x", 100<<10))},
+			contentType: "image/png",
+			err:         domain.ErrFileTooLarge,
+		},
+		{
+			name:        "upload larger than the MaxUploadSize",
+			file:        &upload{size: 100 << 20},
+			contentType: "image/png",
+			err:         domain.ErrFileTooLarge,
+			// the upload is not read further than the limit and a read buffer
+			maxRead: 64<<10 + 32<<10,
+		},
+		{
+			name:        "stored file larger than the MaxSize",
+			file:        &upload{size: 48 << 10},
+			contentType: "image/png",
+			err:         domain.ErrFileTooLarge,
+		},
+		{
+			name:        "stored file larger than the MaxSize of its type",
+			file:        io.NopCloser(bytes.NewReader(append([]byte("\xff\xd8\xff"), make([]byte, 200)...))),
+			contentType: "image/jpeg",
+			err:         domain.ErrFileTooLarge,
+		},
+		{
+			name:        "content not matching its type",
+			file:        io.NopCloser(strings.NewReader("<html><script>alert(1)</script></html>")),
+			contentType: "image/png",
+			err:         domain.ErrInvalidMediaType,
+		},
+		{
+			name:        "binary content declared as an image",
+			file:        io.NopCloser(bytes.NewReader(append([]byte("\x7fELF\x02\x01\x01"), make([]byte, 100)...))),
+			contentType: "image/png",
+			err:         domain.ErrInvalidMediaType,
+		},
+		{
+			name:        "type not allowed",
+			file:        io.NopCloser(strings.NewReader("GIF89a")),
+			contentType: "image/gif",
+			err:         domain.ErrInvalidMediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockMediaRepository(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			storage, err := media.NewLocalStorage(t.TempDir(), "/media")
+			require.NoError(t, err)
+
+			service := media.NewService(config, storage, mockRepo, mockAuth, nil)
+			// the repository is not called, nothing is saved
+			expectAllowed(setupParams{repoService: mockRepo, authService: mockAuth}, userID)
+
+			_, err = service.Save(context.Background(), "ns", tt.file, "upload.png", tt.contentType)
+			assert.ErrorIs(t, err, tt.err)
+
+			// nothing is stored
+			_, err = storage.Get("upload.png")
+			assert.ErrorIs(t, err, domain.ErrFileNotFound)
+			if u, ok := tt.file.(*upload); ok && tt.maxRead > 0 {
+				assert.LessOrEqual(t, u.read, tt.maxRead)
+			}
+		})
+	}
+}
+
+func TestGetURL(t *testing.T) {
+	stored := &domain.Media{ID: uuid.New(), Filename: "products/mug.png", Url: "https://shop.s3.amazonaws.com/media/products/mug.png"}
+
+	tests := []struct {
+		name     string
+		setup    func(t setupParams)
+		err      error
+		expected string
+	}{
+		{
+			name: "link of the storage",
+			setup: func(t setupParams) {
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", stored.ID).Return(stored, nil)
+				t.storageService.EXPECT().GetURL("products/mug.png").Return("https://shop.s3.amazonaws.com/media/products/mug.png?X-Amz-Signature=abc", nil)
+			},
+			expected: "https://shop.s3.amazonaws.com/media/products/mug.png?X-Amz-Signature=abc",
+		},
+		{
+			name: "invalid file name",
+			setup: func(t setupParams) {
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", stored.ID).Return(stored, nil)
+				t.storageService.EXPECT().GetURL("products/mug.png").Return("", domain.ErrInvalidFilename)
+			},
+			err: domain.ErrInvalidFilename,
+		},
+		{
+			name: "media not found",
+			setup: func(t setupParams) {
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", stored.ID).Return(nil, domain.ErrInvalidMedia)
+			},
+			err: domain.ErrInvalidMedia,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockMediaRepository(ctrl)
+			mockStorage := NewMockFileStorage(ctrl)
+
+			service := media.NewService(&media.Config{}, mockStorage, mockRepo, NewMockAuthService(ctrl), nil)
+			tt.setup(setupParams{repoService: mockRepo, storageService: mockStorage})
+
+			url, err := service.GetURL(context.Background(), "ns", stored.ID)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, url)
+		})
+	}
+}
+
+// BenchmarkSave saves concurrent uploads of growing sizes: the memory allocated per upload (B/op) stays flat
+// as the whole file is never held in memory.
+func BenchmarkSave(b *testing.B) {
+	for _, size := range []int64{1 << 20, 16 << 20, 64 << 20} {
+		b.Run(domain.ByteSize(size).String(), func(b *testing.B) {
+			ctrl := gomock.NewController(b)
+			mockRepo := NewMockMediaRepository(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			storage, err := media.NewLocalStorage(b.TempDir(), "/media")
+			require.NoError(b, err)
+
+			config := &media.Config{MediaTypes: []string{"image/png"}, Compress: true, CompressLevel: 1}
+			service := media.NewService(config, storage, mockRepo, mockAuth, map[string]media.FileCompressor{"image/png": gzipCompressor{}})
+
+			// the number of uploads depends on b.N
+			userID := uuid.New()
+			mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil).AnyTimes()
+			mockAuth.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "media:create").Return(true, nil).AnyTimes()
+			mockRepo.EXPECT().Save(gomock.Any(), "ns", gomock.Any()).Return(uuid.New(), nil).AnyTimes()
+
+			b.ReportAllocs()
+			b.SetBytes(size)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				name := uuid.NewString() + ".png"
+				for pb.Next() {
+					if _, err := service.Save(context.Background(), "ns", &upload{size: size}, name, "image/png"); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+		})
+	}
+}
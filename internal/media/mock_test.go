@@ -195,18 +195,17 @@ func (m *MockFileCompressor) EXPECT() *MockFileCompressorMockRecorder {
 }
 
 // Compress mocks base method.
-func (m *MockFileCompressor) Compress(file io.Reader, level int) ([]byte, error) {
+func (m *MockFileCompressor) Compress(w io.Writer, file io.Reader, level int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Compress", file, level)
-	ret0, _ := ret[0].([]byte)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Compress", w, file, level)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Compress indicates an expected call of Compress.
-func (mr *MockFileCompressorMockRecorder) Compress(file, level any) *gomock.Call {
+func (mr *MockFileCompressorMockRecorder) Compress(w, file, level any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compress", reflect.TypeOf((*MockFileCompressor)(nil).Compress), file, level)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compress", reflect.TypeOf((*MockFileCompressor)(nil).Compress), w, file, level)
 }
 
 // MockAuthService is a mock of AuthService interface.
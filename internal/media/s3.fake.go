@@ -0,0 +1,202 @@
+package media
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeS3 is an in-memory stand-in of the S3 API, for the tests and the local development: its URL is the
+// Endpoint of an S3Storage with PathStyle. It supports the object uploads, including the multipart ones,
+// downloads, deletions and presigned URLs, but checks neither the signatures nor their expiry.
+type FakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]*FakeObject
+	uploads map[string]*fakeUpload
+}
+
+// FakeObject is an object stored by the FakeS3.
+type FakeObject struct {
+	Data        []byte
+	ContentType string
+	ETag        string
+	// Parts is the number of parts of the multipart uploads, 0 for the objects uploaded at once.
+	Parts    int
+	Modified time.Time
+}
+
+type fakeUpload struct {
+	contentType string
+	parts       map[int][]byte
+}
+
+// NewFakeS3 creates the stand-in with the empty buckets.
+func NewFakeS3(buckets ...string) *FakeS3 {
+	f := &FakeS3{buckets: make(map[string]map[string]*FakeObject), uploads: make(map[string]*fakeUpload)}
+	for _, bucket := range buckets {
+		f.buckets[bucket] = make(map[string]*FakeObject)
+	}
+	return f
+}
+
+// Object returns the object stored under the key of the bucket.
+func (f *FakeS3) Object(bucket, key string) (*FakeObject, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	object, ok := f.buckets[bucket][key]
+	return object, ok
+}
+
+func (f *FakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	query := r.URL.Query()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	objects, ok := f.buckets[bucket]
+	if !ok {
+		fakeError(w, r, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	if key == "" {
+		fakeError(w, r, http.StatusNotImplemented, "NotImplemented")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		id := uuid.NewString()
+		f.uploads[id] = &fakeUpload{contentType: r.Header.Get("Content-Type"), parts: make(map[int][]byte)}
+		fakeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadId string
+		}{Bucket: bucket, Key: key, UploadId: id})
+
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		upload, ok := f.uploads[query.Get("uploadId")]
+		number, err := strconv.Atoi(query.Get("partNumber"))
+		if !ok || err != nil {
+			fakeError(w, r, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			fakeError(w, r, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		upload.parts[number] = data
+		w.Header().Set("ETag", etag(data))
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		upload, ok := f.uploads[query.Get("uploadId")]
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}
+		if !ok || xml.NewDecoder(r.Body).Decode(&complete) != nil || len(complete.Parts) == 0 {
+			fakeError(w, r, http.StatusBadRequest, "InvalidPart")
+			return
+		}
+		var data, sums []byte
+		for i, part := range complete.Parts {
+			content, ok := upload.parts[part.PartNumber]
+			if !ok || part.ETag != etag(content) || (i > 0 && part.PartNumber <= complete.Parts[i-1].PartNumber) {
+				fakeError(w, r, http.StatusBadRequest, "InvalidPart")
+				return
+			}
+			sum := md5.Sum(content)
+			data, sums = append(data, content...), append(sums, sum[:]...)
+		}
+		sum := md5.Sum(sums)
+		object := &FakeObject{
+			Data:        data,
+			ContentType: upload.contentType,
+			ETag:        fmt.Sprintf("%q", hex.EncodeToString(sum[:])+"-"+strconv.Itoa(len(complete.Parts))),
+			Parts:       len(complete.Parts),
+			Modified:    time.Now(),
+		}
+		objects[key] = object
+		delete(f.uploads, query.Get("uploadId"))
+		fakeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Bucket  string
+			Key     string
+			ETag    string
+		}{Bucket: bucket, Key: key, ETag: object.ETag})
+
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(f.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			fakeError(w, r, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		objects[key] = &FakeObject{Data: data, ContentType: r.Header.Get("Content-Type"), ETag: etag(data), Modified: time.Now()}
+		w.Header().Set("ETag", objects[key].ETag)
+
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		object, ok := objects[key]
+		if !ok {
+			fakeError(w, r, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Type", object.ContentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.Data)))
+		w.Header().Set("ETag", object.ETag)
+		w.Header().Set("Last-Modified", object.Modified.UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(object.Data)
+		}
+
+	case r.Method == http.MethodDelete:
+		delete(objects, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		fakeError(w, r, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+// etag returns the ETag of an object uploaded at once, the MD5 of its content.
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return strconv.Quote(hex.EncodeToString(sum[:]))
+}
+
+func fakeXML(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+// fakeError writes an S3 error, without body for the HEAD requests as S3 does.
+func fakeError(w http.ResponseWriter, r *http.Request, status int, code string) {
+	if r.Method == http.MethodHead {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+		Message string
+	}{Code: code, Message: http.StatusText(status)})
+}
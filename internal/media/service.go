@@ -15,7 +15,13 @@ type Config struct {
 	Compress      bool                            `env:"COMPRESS" envDefault:"true"`
 	CompressLevel int                             `env:"COMPRESS_LEVEL" envDefault:"9"`
 	MaxUploadSize domain.ByteSize                 `env:"MAX_UPLOAD_SIZE" envDefault:"5mb"`
-	MaxSizeByType map[ContentType]domain.ByteSize `env:"MAX_SIZE_BY_TYPE" envDefault:"{}"`
+	MaxSizeByType map[ContentType]domain.ByteSize `env:"MAX_SIZE_BY_TYPE"`
+	// Storage selects the FileStorage: "local" for the LocalStorage or "s3" for the S3Storage.
+	Storage string `env:"MEDIA_STORAGE" envDefault:"local"`
+	// StoragePath is the root directory of the LocalStorage, and StorageURL the base URL it is served at.
+	StoragePath string `env:"MEDIA_STORAGE_PATH" envDefault:"data/media"`
+	StorageURL  string `env:"MEDIA_STORAGE_URL" envDefault:"/media"`
+	S3          S3Config
 }
 
 type Service struct {
@@ -28,8 +34,10 @@ type Service struct {
 
 //go:generate mockgen -source=service.go -destination mock_test.go --package  media_test
 type FileStorage interface {
+	// Save stores the file and returns its permanent URL, stored with the media.
 	Save(file io.Reader, filename string) (string, error)
 	Get(filename string) ([]byte, error)
+	// GetURL returns a link downloading the file, which may expire such as the presigned URLs of S3.
 	GetURL(filename string) (string, error)
 	Delete(filename string) error
 }
@@ -40,8 +48,10 @@ type MediaRepository interface {
 	Delete(ctx context.Context, namespace string, id uuid.UUID) error
 }
 
+// FileCompressor compresses the files of a content type as they are uploaded.
 type FileCompressor interface {
-	Compress(file io.Reader, level int) ([]byte, error)
+	// Compress writes the compressed content of the file to w while reading it, without buffering the whole file.
+	Compress(w io.Writer, file io.Reader, level int) error
 }
 
 type AuthService interface {
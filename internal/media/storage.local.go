@@ -0,0 +1,281 @@
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LocalStorage is a FileStorage on the local disk which stores the files by the SHA-256 of their content,
+// so identical files are stored once. The root directory holds:
+//
+//	blobs/ab/cd/abcd…       the contents, sharded by the first bytes of their hash
+//	blobs/ab/cd/abcd….refs  the number of file names referencing the content
+//	refs/<filename>         the hash of the content of the file name
+//	tmp/                    the files being written
+//
+// Every file is written to tmp/ and renamed into place, so a crash never leaves a partial file.
+// LocalStorage is also the http.Handler serving the files at the base URL returned by GetURL.
+type LocalStorage struct {
+	root    string
+	baseURL string
+	// mu serializes the changes of the references, the contents are written outside of it
+	mu sync.Mutex
+}
+
+// NewLocalStorage creates the storage directories under root. The URLs of the files are baseURL
+// followed by their name, such as "https://cdn.example.com/media/products/mug.png".
+func NewLocalStorage(root, baseURL string) (*LocalStorage, error) {
+	for _, dir := range []string{"blobs", "refs", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &LocalStorage{root: root, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+// Save stores the content of the file under its name, replacing the previous content of the name.
+func (s *LocalStorage) Save(file io.Reader, filename string) (string, error) {
+	name, err := cleanName(filename)
+	if err != nil {
+		return "", err
+	}
+
+	// the content is hashed while written, outside of the lock
+	tmp, err := os.CreateTemp(filepath.Join(s.root, "tmp"), "blob-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hash), file)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, err := s.ref(name)
+	if err != nil && !errors.Is(err, domain.ErrFileNotFound) {
+		return "", err
+	}
+	if previous == sum {
+		return s.GetURL(name)
+	}
+
+	blob := s.blobPath(sum)
+	if _, err = os.Stat(blob); errors.Is(err, fs.ErrNotExist) {
+		if err = os.MkdirAll(filepath.Dir(blob), 0o755); err != nil {
+			return "", err
+		}
+		if err = os.Rename(tmp.Name(), blob); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	if err = s.addRefs(sum, 1); err != nil {
+		return "", err
+	}
+	if err = s.writeFile(s.refPath(name), []byte(sum)); err != nil {
+		return "", err
+	}
+	if previous != "" {
+		if err = s.addRefs(previous, -1); err != nil {
+			return "", err
+		}
+	}
+	return s.GetURL(name)
+}
+
+// Get returns the content of the file, domain.ErrFileNotFound if missing.
+func (s *LocalStorage) Get(filename string) ([]byte, error) {
+	f, err := s.open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// GetURL returns the URL of the file under the base URL, whether the file exists or not.
+func (s *LocalStorage) GetURL(filename string) (string, error) {
+	name, err := cleanName(filename)
+	if err != nil {
+		return "", err
+	}
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return s.baseURL + "/" + strings.Join(segments, "/"), nil
+}
+
+// Delete removes the file name. Its content is removed once no other name references it.
+func (s *LocalStorage) Delete(filename string) error {
+	name, err := cleanName(filename)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sum, err := s.ref(name)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(s.refPath(name)); err != nil {
+		return err
+	}
+	return s.addRefs(sum, -1)
+}
+
+// ServeHTTP serves the files by their name, the path of the request relative to the base URL, e.g. mounted
+// with http.StripPrefix("/media", storage). The content type is detected from the extension of the name, or the content.
+func (s *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	f, err := s.open(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	// the content of a hash never changes, the hash is a strong validator
+	w.Header().Set("ETag", strconv.Quote(filepath.Base(f.Name())))
+	http.ServeContent(w, r, path.Base(name), info.ModTime(), f)
+}
+
+// open opens the content of the file name.
+func (s *LocalStorage) open(filename string) (*os.File, error) {
+	name, err := cleanName(filename)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	sum, err := s.ref(name)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	// the content is opened under the lock, a concurrent Delete cannot remove it in between
+	f, err := os.Open(s.blobPath(sum))
+	s.mu.Unlock()
+	return f, err
+}
+
+// ref returns the hash of the content of the file name, domain.ErrFileNotFound if missing.
+func (s *LocalStorage) ref(name string) (string, error) {
+	data, err := os.ReadFile(s.refPath(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s", domain.ErrFileNotFound, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, decodeErr := hex.DecodeString(string(data)); decodeErr != nil || len(data) != 2*sha256.Size {
+		return "", fmt.Errorf("corrupted reference %s", name)
+	}
+	return string(data), nil
+}
+
+// addRefs changes the reference count of the content, removing the content when it drops to zero.
+func (s *LocalStorage) addRefs(sum string, delta int) error {
+	refs := s.blobPath(sum) + ".refs"
+	count := 0
+	data, err := os.ReadFile(refs)
+	switch {
+	case err == nil:
+		if count, err = strconv.Atoi(string(data)); err != nil {
+			return fmt.Errorf("corrupted reference count %s: %w", refs, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	count += delta
+	if count > 0 {
+		return s.writeFile(refs, []byte(strconv.Itoa(count)))
+	}
+	if err = os.Remove(s.blobPath(sum)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err = os.Remove(refs); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// writeFile replaces the file atomically, by renaming a temporary file over it.
+func (s *LocalStorage) writeFile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Join(s.root, "tmp"), "ref-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (s *LocalStorage) blobPath(sum string) string {
+	return filepath.Join(s.root, "blobs", sum[:2], sum[2:4], sum)
+}
+
+func (s *LocalStorage) refPath(name string) string {
+	return filepath.Join(s.root, "refs", filepath.FromSlash(name))
+}
+
+// cleanName normalizes the file name to a relative slash-separated path, and rejects the names escaping
+// the storage, such as "../etc/passwd", the absolute names and the names with a backslash or a NUL byte.
+func cleanName(filename string) (string, error) {
+	name := path.Clean("/" + filename)[1:]
+	if name == "" || strings.HasPrefix(filename, "/") || strings.ContainsAny(filename, "\\\x00") ||
+		strings.Contains("/"+filename+"/", "/../") || !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("%w: %q", domain.ErrInvalidFilename, filename)
+	}
+	return name, nil
+}
@@ -0,0 +1,204 @@
+package media
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+type S3Config struct {
+	Bucket string `env:"MEDIA_S3_BUCKET"`
+	// Prefix is prepended to the file names, such as "media/".
+	Prefix string `env:"MEDIA_S3_PREFIX"`
+	// Endpoint replaces the AWS endpoint, for the S3-compatible services such as MinIO.
+	Endpoint string `env:"MEDIA_S3_ENDPOINT"`
+	Region   string `env:"MEDIA_S3_REGION" envDefault:"us-east-1"`
+	// PathStyle addresses the bucket in the path of the URLs instead of the host name, as MinIO requires.
+	PathStyle bool `env:"MEDIA_S3_PATH_STYLE" envDefault:"false"`
+	// AccessKey and SecretKey are the static credentials, the default AWS credential chain is used when empty.
+	AccessKey string `env:"MEDIA_S3_ACCESS_KEY"`
+	SecretKey string `env:"MEDIA_S3_SECRET_KEY"`
+	// PartSize is the size of the parts of the multipart uploads, the smaller files are uploaded at once.
+	// The parts are buffered in memory, at least 5mb as required by S3.
+	PartSize domain.ByteSize `env:"MEDIA_S3_PART_SIZE" envDefault:"5mb"`
+	// URLExpiry is the validity of the presigned URLs returned by GetURL.
+	URLExpiry time.Duration `env:"MEDIA_S3_URL_EXPIRY" envDefault:"1h"`
+	// PublicURL is the base URL the objects are served at, such as a CDN in front of the bucket. The URLs
+	// returned by Save are the URLs of the objects on the endpoint when empty.
+	PublicURL string `env:"MEDIA_S3_PUBLIC_URL"`
+}
+
+// S3Storage is a FileStorage on a bucket of Amazon S3 or an S3-compatible service. The files are stored under
+// the prefix with their content type. Save returns the permanent URL of the object, and GetURL a presigned URL
+// expiring after the URLExpiry, so the bucket can stay private.
+type S3Storage struct {
+	config   *S3Config
+	client   *s3.S3
+	uploader *s3manager.Uploader
+}
+
+// NewS3Storage creates the S3 client of the configuration, no request is sent to the service.
+func NewS3Storage(config *S3Config) (*S3Storage, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("the S3 bucket is required")
+	}
+	partSize := int64(config.PartSize)
+	if partSize < s3manager.MinUploadPartSize {
+		partSize = s3manager.MinUploadPartSize
+	}
+
+	awsConfig := aws.NewConfig().WithRegion(config.Region).WithS3ForcePathStyle(config.PathStyle)
+	if config.Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(config.Endpoint)
+	}
+	if config.AccessKey != "" {
+		awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(config.AccessKey, config.SecretKey, ""))
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s3.New(sess)
+	return &S3Storage{
+		config: config,
+		client: client,
+		uploader: s3manager.NewUploaderWithClient(client, func(u *s3manager.Uploader) {
+			u.PartSize = partSize
+		}),
+	}, nil
+}
+
+// Save uploads the file, in parts when it is larger than the PartSize, and returns its unsigned URL under the
+// PublicURL or the endpoint. The content type is detected from the extension of the name, or the content.
+func (s *S3Storage) Save(file io.Reader, filename string) (string, error) {
+	key, err := s.key(filename)
+	if err != nil {
+		return "", err
+	}
+
+	body := bufio.NewReaderSize(file, 512)
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		head, err := body.Peek(512)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		contentType = http.DetectContentType(head)
+	}
+
+	_, err = s.uploader.Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(s.config.Bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.objectURL(key)
+}
+
+// Get downloads the file, domain.ErrFileNotFound if missing.
+func (s *S3Storage) Get(filename string) ([]byte, error) {
+	key, err := s.key(filename)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, s.notFound(err, filename)
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+// GetURL returns a presigned URL downloading the file, valid for the URLExpiry.
+func (s *S3Storage) GetURL(filename string) (string, error) {
+	key, err := s.key(filename)
+	if err != nil {
+		return "", err
+	}
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(key),
+	})
+	return req.Presign(s.config.URLExpiry)
+}
+
+// objectURL returns the URL of the object, which never expires but needs the bucket, or the PublicURL,
+// to be readable.
+func (s *S3Storage) objectURL(key string) (string, error) {
+	if s.config.PublicURL != "" {
+		segments := strings.Split(key, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		return strings.TrimSuffix(s.config.PublicURL, "/") + "/" + strings.Join(segments, "/"), nil
+	}
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(key),
+	})
+	if err := req.Build(); err != nil {
+		return "", err
+	}
+	return req.HTTPRequest.URL.String(), nil
+}
+
+// Delete removes the file, domain.ErrFileNotFound if missing.
+func (s *S3Storage) Delete(filename string) error {
+	key, err := s.key(filename)
+	if err != nil {
+		return err
+	}
+	// S3 deletes the missing objects silently, they are looked up first to report them
+	_, err = s.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return s.notFound(err, filename)
+	}
+	_, err = s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// key returns the object key of the file name, see cleanName.
+func (s *S3Storage) key(filename string) (string, error) {
+	name, err := cleanName(filename)
+	if err != nil {
+		return "", err
+	}
+	return s.config.Prefix + name, nil
+}
+
+// notFound translates the missing object errors of S3 to domain.ErrFileNotFound.
+func (s *S3Storage) notFound(err error, filename string) error {
+	// GetObject reports NoSuchKey, HeadObject has no body and only reports NotFound
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && (awsErr.Code() == s3.ErrCodeNoSuchKey || awsErr.Code() == "NotFound") {
+		return fmt.Errorf("%w: %s", domain.ErrFileNotFound, filename)
+	}
+	return err
+}
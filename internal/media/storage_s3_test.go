@@ -0,0 +1,126 @@
+package media_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newS3Storage(t *testing.T, publicURL string) (*media.S3Storage, *media.FakeS3) {
+	fake := media.NewFakeS3("shop")
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	storage, err := media.NewS3Storage(&media.S3Config{
+		Bucket:    "shop",
+		Prefix:    "media/",
+		Endpoint:  server.URL,
+		Region:    "us-east-1",
+		PathStyle: true,
+		AccessKey: "key",
+		SecretKey: "secret",
+		URLExpiry: 15 * time.Minute,
+		PublicURL: publicURL,
+	})
+	require.NoError(t, err)
+	return storage, fake
+}
+
+func TestS3Storage(t *testing.T) {
+	storage, fake := newS3Storage(t, "")
+
+	link, err := storage.Save(strings.NewReader("<svg></svg>"), "products/logo.svg")
+	require.NoError(t, err)
+
+	object, ok := fake.Object("shop", "media/products/logo.svg")
+	require.True(t, ok)
+	assert.Equal(t, "image/svg+xml", object.ContentType)
+	assert.Zero(t, object.Parts)
+
+	// the stored URL is the permanent URL of the object
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "/shop/media/products/logo.svg", u.Path)
+	assert.Empty(t, u.RawQuery)
+
+	// the URL of GetURL is presigned with its expiry and downloads the file
+	link, err = storage.GetURL("products/logo.svg")
+	require.NoError(t, err)
+	u, err = url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "/shop/media/products/logo.svg", u.Path)
+	assert.Equal(t, "900", u.Query().Get("X-Amz-Expires"))
+	assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+	res, err := http.Get(link)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	assert.Equal(t, "<svg></svg>", string(body))
+
+	data, err := storage.Get("products/logo.svg")
+	require.NoError(t, err)
+	assert.Equal(t, "<svg></svg>", string(data))
+
+	require.NoError(t, storage.Delete("products/logo.svg"))
+	_, err = storage.Get("products/logo.svg")
+	assert.ErrorIs(t, err, domain.ErrFileNotFound)
+	assert.ErrorIs(t, storage.Delete("products/logo.svg"), domain.ErrFileNotFound)
+
+	_, err = storage.Save(strings.NewReader("data"), "../secret")
+	assert.ErrorIs(t, err, domain.ErrInvalidFilename)
+}
+
+func TestS3Storage_PublicURL(t *testing.T) {
+	storage, _ := newS3Storage(t, "https://cdn.example.com/")
+
+	link, err := storage.Save(strings.NewReader("<svg></svg>"), "products/logo 2.svg")
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/media/products/logo%202.svg", link)
+
+	// GetURL still presigns the URLs on the endpoint
+	link, err = storage.GetURL("products/logo 2.svg")
+	require.NoError(t, err)
+	assert.NotContains(t, link, "cdn.example.com")
+	assert.Contains(t, link, "X-Amz-Signature=")
+}
+
+func TestS3Storage_ContentSniffing(t *testing.T) {
+	storage, fake := newS3Storage(t, "")
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 100)...)
+	_, err := storage.Save(bytes.NewReader(png), "uploads/photo")
+	require.NoError(t, err)
+
+	object, ok := fake.Object("shop", "media/uploads/photo")
+	require.True(t, ok)
+	assert.Equal(t, "image/png", object.ContentType)
+	assert.Equal(t, png, object.Data)
+}
+
+func TestS3Storage_Multipart(t *testing.T) {
+	storage, fake := newS3Storage(t, "")
+
+	data := make([]byte, 12<<20)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	// a reader without Seek nor Len is uploaded in parts as it is read
+	_, err = storage.Save(io.MultiReader(bytes.NewReader(data)), "videos/tour.mp4")
+	require.NoError(t, err)
+
+	object, ok := fake.Object("shop", "media/videos/tour.mp4")
+	require.True(t, ok)
+	assert.Equal(t, 3, object.Parts)
+	assert.Equal(t, "video/mp4", object.ContentType)
+	assert.True(t, bytes.Equal(data, object.Data))
+}
@@ -0,0 +1,155 @@
+package media_test
+
+import (
+	"bytes"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// blobs returns the number of contents stored under the root.
+func blobs(t *testing.T, root string) int {
+	count := 0
+	err := filepath.WalkDir(filepath.Join(root, "blobs"), func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && !strings.HasSuffix(path, ".refs") {
+			count++
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return count
+}
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	storage, err := media.NewLocalStorage(root, "https://cdn.example.com/media/")
+	require.NoError(t, err)
+
+	url, err := storage.Save(strings.NewReader("mug"), "products/mug 1.png")
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/media/products/mug%201.png", url)
+
+	// the identical content is stored once
+	_, err = storage.Save(strings.NewReader("mug"), "products/mug-copy.png")
+	require.NoError(t, err)
+	_, err = storage.Save(strings.NewReader("cup"), "products/cup.png")
+	require.NoError(t, err)
+	assert.Equal(t, 2, blobs(t, root))
+
+	data, err := storage.Get("products/mug-copy.png")
+	require.NoError(t, err)
+	assert.Equal(t, "mug", string(data))
+
+	// the shared content is kept until its last name is deleted
+	require.NoError(t, storage.Delete("products/mug 1.png"))
+	data, err = storage.Get("products/mug-copy.png")
+	require.NoError(t, err)
+	assert.Equal(t, "mug", string(data))
+	assert.Equal(t, 2, blobs(t, root))
+
+	// replacing the content of a name releases the previous content
+	_, err = storage.Save(strings.NewReader("cup"), "products/mug-copy.png")
+	require.NoError(t, err)
+	assert.Equal(t, 1, blobs(t, root))
+
+	require.NoError(t, storage.Delete("products/mug-copy.png"))
+	require.NoError(t, storage.Delete("products/cup.png"))
+	assert.Equal(t, 0, blobs(t, root))
+
+	_, err = storage.Get("products/cup.png")
+	assert.ErrorIs(t, err, domain.ErrFileNotFound)
+	assert.ErrorIs(t, storage.Delete("products/cup.png"), domain.ErrFileNotFound)
+
+	// nothing is left in the temporary directory
+	entries, err := os.ReadDir(filepath.Join(root, "tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLocalStorage_PathTraversal(t *testing.T) {
+	storage, err := media.NewLocalStorage(t.TempDir(), "/media")
+	require.NoError(t, err)
+
+	for _, name := range []string{"", ".", "../secret", "products/../../secret", "/etc/passwd", "products\\..\\secret", "mug\x00.png"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := storage.Save(strings.NewReader("data"), name)
+			assert.ErrorIs(t, err, domain.ErrInvalidFilename)
+			_, err = storage.Get(name)
+			assert.ErrorIs(t, err, domain.ErrInvalidFilename)
+			assert.ErrorIs(t, storage.Delete(name), domain.ErrInvalidFilename)
+		})
+	}
+
+	// the names are normalized
+	_, err = storage.Save(strings.NewReader("data"), "products/./mug.png")
+	require.NoError(t, err)
+	_, err = storage.Get("products//mug.png")
+	assert.NoError(t, err)
+}
+
+func TestLocalStorage_Concurrent(t *testing.T) {
+	root := t.TempDir()
+	storage, err := media.NewLocalStorage(root, "/media")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := filepath.Join("copies", string(rune('a'+i))+".png")
+			_, err := storage.Save(bytes.NewReader(bytes.Repeat([]byte("x"), 1<<16)), name)
+			assert.NoError(t, err)
+			if i%2 == 0 {
+				assert.NoError(t, storage.Delete(name))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, blobs(t, root))
+
+	for i := 1; i < 20; i += 2 {
+		require.NoError(t, storage.Delete(filepath.Join("copies", string(rune('a'+i))+".png")))
+	}
+	assert.Equal(t, 0, blobs(t, root))
+}
+
+func TestLocalStorage_ServeHTTP(t *testing.T) {
+	storage, err := media.NewLocalStorage(t.TempDir(), "/media")
+	require.NoError(t, err)
+	_, err = storage.Save(strings.NewReader("<svg></svg>"), "logo.svg")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.StripPrefix("/media", storage))
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/media/logo.svg")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "image/svg+xml", res.Header.Get("Content-Type"))
+	assert.Equal(t, "<svg></svg>", string(body))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/media/logo.svg", nil)
+	req.Header.Set("If-None-Match", res.Header.Get("ETag"))
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotModified, res.StatusCode)
+
+	res, err = http.Get(server.URL + "/media/missing.svg")
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
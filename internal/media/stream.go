@@ -0,0 +1,57 @@
+package media
+
+import (
+	"github.com/HBeserra/GoShop/domain"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// limitedReader reads up to max bytes and fails with domain.ErrFileTooLarge as soon as the reader holds more,
+// without reading the rest. A zero max is unlimited.
+type limitedReader struct {
+	r        io.Reader
+	max      int64
+	n        atomic.Int64
+	exceeded atomic.Bool
+}
+
+func newLimitedReader(r io.Reader, max domain.ByteSize) *limitedReader {
+	return &limitedReader{r: r, max: int64(max)}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	total := l.n.Add(int64(n))
+	if l.max > 0 && total > l.max {
+		l.exceeded.Store(true)
+		return max(0, n-int(total-l.max)), domain.ErrFileTooLarge
+	}
+	return n, err
+}
+
+// sniffMatches reports whether the content type detected from the first bytes of a file is consistent with the
+// declared content type. The content the sniffer does not recognize is accepted unless an image is declared,
+// as are the XML based types detected as plain XML, such as "image/svg+xml".
+func sniffMatches(head []byte, contentType string) bool {
+	declared, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	switch detected {
+	case declared:
+		return true
+	case "application/octet-stream":
+		// a binary declared as an image would be served with the image type
+		return !strings.HasPrefix(declared, "image/")
+	case "text/xml":
+		return len(declared) > 4 && declared[len(declared)-4:] == "+xml"
+	}
+	return false
+}
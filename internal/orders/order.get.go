@@ -0,0 +1,53 @@
+package orders
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+)
+
+// GetOrder returns the order. Customers can read their own orders, anyone else needs the "order:read" permission.
+func (s *Service) GetOrder(ctx context.Context, namespace string, id uuid.UUID) (*domain.Order, error) {
+
+	ctx, span := observability.StartSpan(ctx, "orders.GetOrder")
+	defer span.End()
+
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.repo.GetByID(ctx, namespace, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.CustomerID != userID {
+		if err = s.checkPermission(ctx, userID, namespace, "order:read"); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// FindOrders lists the orders matching the filter. Customers can list their own orders,
+// listing the orders of other customers requires the "order:read" permission.
+func (s *Service) FindOrders(ctx context.Context, namespace string, filter dto.OrderFilter) ([]*domain.Order, error) {
+
+	ctx, span := observability.StartSpan(ctx, "orders.FindOrders")
+	defer span.End()
+
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.CustomerID != userID {
+		if err = s.checkPermission(ctx, userID, namespace, "order:read"); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Find(ctx, namespace, filter)
+}
@@ -0,0 +1,201 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/internal/promotion"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// CreateOrder places a pending order for the current user. Only the product, variant and quantity of the
+// lines are read, the title, SKU and unit price are copied from the catalog. The discounts and the shipping
+// are computed by the checkout, an order carrying them is rejected, see PlaceOrder.
+func (s *Service) CreateOrder(ctx context.Context, namespace string, order *domain.Order) error {
+
+	ctx, span := observability.StartSpan(ctx, "orders.CreateOrder")
+	defer span.End()
+
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if err = s.checkPermission(ctx, userID, namespace, "order:create"); err != nil {
+		return err
+	}
+
+	if err = rejectAmounts(order); err != nil {
+		return err
+	}
+
+	err = s.place(ctx, namespace, userID, order, nil)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return err
+}
+
+// PlaceOrder places a pending order for the customer with the discounts and shipping of the promotion result,
+// whose item discounts follow the order lines.
+//
+// It is called by the checkout once the cart was re-validated and the promotions applied, so no permission
+// is checked.
+func (s *Service) PlaceOrder(ctx context.Context, namespace string, customerID uuid.UUID, order *domain.Order, discounts *promotion.Result) error {
+
+	ctx, span := observability.StartSpan(ctx, "orders.PlaceOrder")
+	defer span.End()
+
+	if len(discounts.ItemDiscounts) != len(order.Lines) {
+		return fmt.Errorf("%w: %d discounts for %d lines", domain.ErrInvalidOrder, len(discounts.ItemDiscounts), len(order.Lines))
+	}
+
+	err := s.place(ctx, namespace, customerID, order, discounts)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return err
+}
+
+// place snapshots the catalog into the order, applies the discounts when not nil and stores the order.
+func (s *Service) place(ctx context.Context, namespace string, customerID uuid.UUID, order *domain.Order, discounts *promotion.Result) error {
+	now := time.Now()
+	order.ID = uuid.New()
+	order.Namespace = namespace
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	order.CustomerID = customerID
+	order.Status = domain.OrderStatusPending
+	order.History = []domain.OrderTransition{{To: domain.OrderStatusPending, At: now, UserID: customerID}}
+
+	if discounts != nil {
+		for i := range order.Lines {
+			order.Lines[i].Discount = discounts.ItemDiscounts[i]
+		}
+		order.Shipping, order.ShippingDiscount = discounts.Shipping, discounts.ShippingDiscount
+	}
+
+	err := s.snapshot(ctx, namespace, order, now)
+	if err != nil {
+		return err
+	}
+	order.ComputeTotals()
+
+	err = s.repo.Create(ctx, namespace, order)
+	if err != nil {
+		return err
+	}
+
+	err = s.bus.Publish(ctx, "order:created", events.OrderCreated{
+		ID:         order.ID,
+		CustomerID: order.CustomerID,
+		Total:      order.Total,
+		CreatedOn:  order.CreatedAt,
+		CreatedBy:  customerID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish order:created event",
+			"order_id", order.ID,
+			"error", err)
+	}
+
+	slog.InfoContext(ctx, "order created",
+		"order_id", order.ID,
+		"customer_id", order.CustomerID,
+		"total", order.Total,
+	)
+	return nil
+}
+
+// rejectAmounts fails when the caller set a discount or the shipping of the order.
+func rejectAmounts(order *domain.Order) error {
+	verr := &domain.ValidationError{}
+	for i, line := range order.Lines {
+		if line.Discount != 0 {
+			verr.Add(fmt.Sprintf("lines[%d].discount", i), domain.ValidationCodeInvalid, domain.ErrInvalidOrder, nil)
+		}
+	}
+	if order.Shipping != 0 {
+		verr.Add("shipping", domain.ValidationCodeInvalid, domain.ErrInvalidOrder, nil)
+	}
+	if order.ShippingDiscount != 0 {
+		verr.Add("shipping_discount", domain.ValidationCodeInvalid, domain.ErrInvalidOrder, nil)
+	}
+	return verr.Err()
+}
+
+// snapshot copies the title, SKU and current effective price of the ordered products into the order lines.
+func (s *Service) snapshot(ctx context.Context, namespace string, order *domain.Order, now time.Time) error {
+	verr := &domain.ValidationError{}
+	if len(order.Lines) == 0 {
+		verr.Add("lines", domain.ValidationCodeRequired, domain.ErrInvalidOrder, nil)
+		return verr
+	}
+
+	ids := make([]uuid.UUID, 0, len(order.Lines))
+	for _, l := range order.Lines {
+		if !slices.Contains(ids, l.ProductID) {
+			ids = append(ids, l.ProductID)
+		}
+	}
+	found, err := s.catalog.Lookup(ctx, namespace, dto.ProductFilter{IDs: ids})
+	if err != nil {
+		return err
+	}
+	products := make(map[uuid.UUID]*domain.Product, len(found))
+	for _, p := range found {
+		products[p.ID] = p
+	}
+
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		field := fmt.Sprintf("lines[%d]", i)
+
+		if line.Quantity < 1 {
+			verr.Add(field+".quantity", domain.ValidationCodeMin, domain.ErrInvalidQuantity, map[string]any{"min": 1})
+			continue
+		}
+
+		product, ok := products[line.ProductID]
+		if !ok {
+			verr.Add(field+".product_id", domain.ValidationCodeNotFound, domain.ErrProductNotFound, nil)
+			continue
+		}
+		if product.Status != domain.ProductStatusAvailable {
+			verr.Add(field+".product_id", domain.ValidationCodeInvalid, domain.ErrProductUnavailable, nil)
+			continue
+		}
+
+		line.SKU, line.Title, line.UnitPrice = product.SKU, product.Title, product.EffectivePriceAt(now)
+		if line.VariantID != uuid.Nil {
+			idx := slices.IndexFunc(product.Variants, func(v domain.ProductVariant) bool { return v.ID == line.VariantID })
+			if idx < 0 {
+				verr.Add(field+".variant_id", domain.ValidationCodeNotFound, domain.ErrVariantNotFound, nil)
+				continue
+			}
+			variant := product.Variants[idx]
+			line.SKU, line.Title, line.UnitPrice = variant.SKU, product.Title+" - "+variant.Title, variant.EffectivePriceAt(now)
+		}
+
+		if line.Discount < 0 || line.Discount > line.Subtotal() {
+			verr.Add(field+".discount", domain.ValidationCodeRange, domain.ErrInvalidOrder, map[string]any{
+				"min": 0,
+				"max": line.Subtotal(),
+			})
+		}
+	}
+
+	if order.Shipping < 0 || order.ShippingDiscount < 0 || order.ShippingDiscount > order.Shipping {
+		verr.Add("shipping_discount", domain.ValidationCodeRange, domain.ErrInvalidOrder, map[string]any{
+			"min": 0,
+			"max": order.Shipping,
+		})
+	}
+
+	return verr.Err()
+}
@@ -0,0 +1,48 @@
+package orders
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"slices"
+)
+
+// paymentTransitions maps the payment statuses to the order status they lead to, from the order statuses
+// they apply to.
+var paymentTransitions = map[domain.PaymentStatus]struct {
+	from []domain.OrderStatus
+	to   domain.OrderStatus
+}{
+	domain.PaymentStatusCaptured: {from: []domain.OrderStatus{domain.OrderStatusPending}, to: domain.OrderStatusPaid},
+	domain.PaymentStatusRefunded: {
+		from: []domain.OrderStatus{domain.OrderStatusPaid, domain.OrderStatusFulfilled, domain.OrderStatusDelivered},
+		to:   domain.OrderStatusRefunded,
+	},
+	domain.PaymentStatusVoided: {from: []domain.OrderStatus{domain.OrderStatusPending}, to: domain.OrderStatusCancelled},
+	domain.PaymentStatusFailed: {from: []domain.OrderStatus{domain.OrderStatusPending}, to: domain.OrderStatusCancelled},
+}
+
+// SyncPayment moves the order according to the status of its payment: a captured payment pays the order,
+// a refunded one refunds it, and a voided or failed one cancels the pending order.
+//
+// It is called by the payment service once the provider reported the status, so no permission is checked
+// and the transition is recorded without a user. Other payment statuses, or orders already moved past
+// the status, leave the order unchanged.
+func (s *Service) SyncPayment(ctx context.Context, namespace string, orderID uuid.UUID, status domain.PaymentStatus) (*domain.Order, error) {
+
+	ctx, span := observability.StartSpan(ctx, "orders.SyncPayment")
+	defer span.End()
+
+	order, err := s.repo.GetByID(ctx, namespace, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, ok := paymentTransitions[status]
+	if !ok || !slices.Contains(transition.from, order.Status) {
+		return order, nil
+	}
+
+	return s.changeStatus(ctx, namespace, order, transition.to, uuid.Nil, "payment "+string(status))
+}
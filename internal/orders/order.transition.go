@@ -0,0 +1,121 @@
+package orders
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// transitionPermissions maps each target status to the permission required to reach it.
+var transitionPermissions = map[domain.OrderStatus]string{
+	domain.OrderStatusPaid:      "order:pay",
+	domain.OrderStatusFulfilled: "order:fulfill",
+	domain.OrderStatusDelivered: "order:deliver",
+	domain.OrderStatusCancelled: "order:cancel",
+	domain.OrderStatusRefunded:  "order:refund",
+}
+
+// MarkPaid moves a pending order to paid.
+func (s *Service) MarkPaid(ctx context.Context, namespace string, id uuid.UUID) (*domain.Order, error) {
+	return s.transition(ctx, namespace, id, domain.OrderStatusPaid, "")
+}
+
+// Fulfill moves a paid order to fulfilled, once it was shipped.
+func (s *Service) Fulfill(ctx context.Context, namespace string, id uuid.UUID) (*domain.Order, error) {
+	return s.transition(ctx, namespace, id, domain.OrderStatusFulfilled, "")
+}
+
+// MarkDelivered moves a fulfilled order to delivered.
+func (s *Service) MarkDelivered(ctx context.Context, namespace string, id uuid.UUID) (*domain.Order, error) {
+	return s.transition(ctx, namespace, id, domain.OrderStatusDelivered, "")
+}
+
+// Cancel cancels a pending order.
+func (s *Service) Cancel(ctx context.Context, namespace string, id uuid.UUID, reason string) (*domain.Order, error) {
+	return s.transition(ctx, namespace, id, domain.OrderStatusCancelled, reason)
+}
+
+// Abandon cancels a pending order the checkout could not complete. It is called by the checkout to compensate
+// the order creation, so no permission is checked and the transition is recorded without a user. An order
+// already cancelled, for instance by its voided payment, is returned unchanged.
+func (s *Service) Abandon(ctx context.Context, namespace string, id uuid.UUID, reason string) (*domain.Order, error) {
+
+	ctx, span := observability.StartSpan(ctx, "orders.Abandon")
+	defer span.End()
+
+	order, err := s.repo.GetByID(ctx, namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == domain.OrderStatusCancelled {
+		return order, nil
+	}
+	return s.changeStatus(ctx, namespace, order, domain.OrderStatusCancelled, uuid.Nil, reason)
+}
+
+// Refund refunds a paid, fulfilled or delivered order.
+func (s *Service) Refund(ctx context.Context, namespace string, id uuid.UUID, reason string) (*domain.Order, error) {
+	return s.transition(ctx, namespace, id, domain.OrderStatusRefunded, reason)
+}
+
+// transition checks the permission of the current user and changes the order status.
+func (s *Service) transition(ctx context.Context, namespace string, id uuid.UUID, to domain.OrderStatus, reason string) (*domain.Order, error) {
+
+	ctx, span := observability.StartSpan(ctx, "orders.Transition")
+	defer span.End()
+
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkPermission(ctx, userID, namespace, transitionPermissions[to]); err != nil {
+		return nil, err
+	}
+
+	order, err := s.repo.GetByID(ctx, namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.changeStatus(ctx, namespace, order, to, userID, reason)
+}
+
+// changeStatus applies the transition, stores it and publishes the event on the topic of the new status.
+func (s *Service) changeStatus(ctx context.Context, namespace string, order *domain.Order, to domain.OrderStatus, userID uuid.UUID, reason string) (*domain.Order, error) {
+	from := order.Status
+	err := order.Transition(to, userID, reason, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.UpdateStatus(ctx, namespace, order, from)
+	if err != nil {
+		return nil, err
+	}
+
+	topic := "order:" + string(to)
+	err = s.bus.Publish(ctx, topic, events.OrderStatusChanged{
+		ID:        order.ID,
+		From:      string(from),
+		To:        string(to),
+		Reason:    reason,
+		ChangedOn: order.UpdatedAt,
+		ChangedBy: userID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish "+topic+" event",
+			"order_id", order.ID,
+			"error", err)
+	}
+
+	slog.InfoContext(ctx, "order status changed",
+		"order_id", order.ID,
+		"from", from,
+		"to", to,
+		"changed_by", userID,
+	)
+	return order, nil
+}
@@ -0,0 +1,465 @@
+package orders_test
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/internal/orders"
+	"github.com/HBeserra/GoShop/internal/promotion"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type setupParams struct {
+	repoService    *MockOrderRepository
+	catalogService *MockProductCatalog
+	busService     *MockEventBus
+	authService    *MockAuthService
+}
+
+func TestCreateOrder(t *testing.T) {
+	userID := uuid.New()
+	variantID := uuid.New()
+	product := &domain.Product{
+		ID:     uuid.New(),
+		SKU:    "TSHIRT",
+		Title:  "Cotton t-shirt",
+		Price:  currency.NewFromFloat(50),
+		Status: domain.ProductStatusAvailable,
+		Sales:  []domain.SalePrice{{Price: currency.NewFromFloat(40), Start: time.Now().Add(-time.Hour)}},
+		Variants: []domain.ProductVariant{
+			{ID: variantID, SKU: "TSHIRT-G", Title: "G", Price: currency.NewFromFloat(55)},
+		},
+	}
+	draft := &domain.Product{ID: uuid.New(), Price: currency.NewFromFloat(10), Status: domain.ProductStatusDraft}
+
+	tests := []struct {
+		name     string
+		order    *domain.Order
+		setup    func(t setupParams)
+		err      error
+		fields   []string
+		expected func(t *testing.T, order *domain.Order)
+	}{
+		{
+			name: "snapshots the catalog",
+			order: &domain.Order{
+				Lines: []domain.OrderLine{
+					// the caller cannot choose the price or the title
+					{ProductID: product.ID, Quantity: 2, UnitPrice: 1, Title: "fake"},
+					{ProductID: product.ID, VariantID: variantID, Quantity: 1},
+				},
+			},
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "order:create").Return(true, nil)
+				t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+				t.repoService.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "order:created", gomock.AssignableToTypeOf(events.OrderCreated{})).Return(nil)
+			},
+			expected: func(t *testing.T, order *domain.Order) {
+				assert.Equal(t, domain.OrderStatusPending, order.Status)
+				assert.Equal(t, userID, order.CustomerID)
+				assert.Equal(t, "ns", order.Namespace)
+				assert.Equal(t, domain.OrderLine{
+					ProductID: product.ID, SKU: "TSHIRT", Title: "Cotton t-shirt",
+					UnitPrice: currency.NewFromFloat(40), Quantity: 2,
+				}, order.Lines[0])
+				assert.Equal(t, "TSHIRT-G", order.Lines[1].SKU)
+				assert.Equal(t, "Cotton t-shirt - G", order.Lines[1].Title)
+				assert.Equal(t, currency.NewFromFloat(135), order.Subtotal)
+				assert.Equal(t, currency.NewFromFloat(135), order.Total)
+				require.Len(t, order.History, 1)
+				assert.Equal(t, domain.OrderStatusPending, order.History[0].To)
+			},
+		},
+		{
+			name: "invalid lines",
+			order: &domain.Order{
+				Lines: []domain.OrderLine{
+					{ProductID: product.ID, Quantity: 0},
+					{ProductID: draft.ID, Quantity: 1},
+					{ProductID: uuid.New(), Quantity: 1},
+					{ProductID: product.ID, VariantID: uuid.New(), Quantity: 1},
+				},
+			},
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "order:create").Return(true, nil)
+				t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product, draft}, nil)
+			},
+			err:    domain.ErrProductUnavailable,
+			fields: []string{"lines[0].quantity", "lines[1].product_id", "lines[2].product_id", "lines[3].variant_id"},
+		},
+		{
+			name: "caller supplied discounts and shipping",
+			order: &domain.Order{
+				Lines: []domain.OrderLine{
+					{ProductID: product.ID, Quantity: 1},
+					{ProductID: product.ID, Quantity: 1, Discount: currency.NewFromFloat(40)},
+				},
+				Shipping:         currency.NewFromFloat(15),
+				ShippingDiscount: currency.NewFromFloat(15),
+			},
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "order:create").Return(true, nil)
+			},
+			err:    domain.ErrInvalidOrder,
+			fields: []string{"lines[1].discount", "shipping", "shipping_discount"},
+		},
+		{
+			name:  "without permission",
+			order: &domain.Order{},
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "order:create").Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name:  "unauthenticated",
+			order: &domain.Order{},
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockOrderRepository(ctrl)
+			mockCatalog := NewMockProductCatalog(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			service := orders.NewService(mockRepo, mockCatalog, mockBus, mockAuth)
+
+			tt.setup(setupParams{repoService: mockRepo, catalogService: mockCatalog, busService: mockBus, authService: mockAuth})
+
+			err := service.CreateOrder(context.Background(), "ns", tt.order)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				if tt.fields != nil {
+					var verr *domain.ValidationError
+					require.True(t, errors.As(err, &verr))
+					assert.Equal(t, tt.fields, verr.Fields())
+				}
+				return
+			}
+			require.NoError(t, err)
+			tt.expected(t, tt.order)
+		})
+	}
+}
+
+func TestPlaceOrder(t *testing.T) {
+	customerID := uuid.New()
+	product := &domain.Product{ID: uuid.New(), SKU: "MUG", Price: currency.NewFromFloat(50), Status: domain.ProductStatusAvailable}
+
+	tests := []struct {
+		name      string
+		lines     []domain.OrderLine
+		discounts *promotion.Result
+		setup     func(t setupParams)
+		err       error
+		expected  func(t *testing.T, order *domain.Order)
+	}{
+		{
+			name:  "applies the discounts and shipping without permission",
+			lines: []domain.OrderLine{{ProductID: product.ID, Quantity: 2}},
+			discounts: &promotion.Result{
+				ItemDiscounts:    []currency.BRL{currency.NewFromFloat(10)},
+				Shipping:         currency.NewFromFloat(15),
+				ShippingDiscount: currency.NewFromFloat(5),
+			},
+			setup: func(t setupParams) {
+				t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+				t.repoService.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "order:created", gomock.Any()).Return(nil)
+			},
+			expected: func(t *testing.T, order *domain.Order) {
+				assert.Equal(t, customerID, order.CustomerID)
+				assert.Equal(t, currency.NewFromFloat(10), order.Lines[0].Discount)
+				assert.Equal(t, currency.NewFromFloat(15), order.Shipping)
+				assert.Equal(t, currency.NewFromFloat(5), order.ShippingDiscount)
+				assert.Equal(t, currency.NewFromFloat(100), order.Total)
+			},
+		},
+		{
+			name:      "discounts not matching the lines",
+			lines:     []domain.OrderLine{{ProductID: product.ID, Quantity: 2}},
+			discounts: &promotion.Result{},
+			setup:     func(t setupParams) {},
+			err:       domain.ErrInvalidOrder,
+		},
+		{
+			name:      "discount above the line subtotal",
+			lines:     []domain.OrderLine{{ProductID: product.ID, Quantity: 1}},
+			discounts: &promotion.Result{ItemDiscounts: []currency.BRL{currency.NewFromFloat(60)}},
+			setup: func(t setupParams) {
+				t.catalogService.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+			},
+			err: domain.ErrInvalidOrder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockOrderRepository(ctrl)
+			mockCatalog := NewMockProductCatalog(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			service := orders.NewService(mockRepo, mockCatalog, mockBus, NewMockAuthService(ctrl))
+
+			tt.setup(setupParams{repoService: mockRepo, catalogService: mockCatalog, busService: mockBus})
+
+			order := &domain.Order{Lines: tt.lines}
+			err := service.PlaceOrder(context.Background(), "ns", customerID, order, tt.discounts)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			tt.expected(t, order)
+		})
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		from       domain.OrderStatus
+		call       func(svc *orders.Service, id uuid.UUID) (*domain.Order, error)
+		permission string
+		to         domain.OrderStatus
+		err        error
+	}{
+		{
+			name: "pay",
+			from: domain.OrderStatusPending,
+			call: func(svc *orders.Service, id uuid.UUID) (*domain.Order, error) {
+				return svc.MarkPaid(context.Background(), "ns", id)
+			},
+			permission: "order:pay",
+			to:         domain.OrderStatusPaid,
+		},
+		{
+			name: "fulfill",
+			from: domain.OrderStatusPaid,
+			call: func(svc *orders.Service, id uuid.UUID) (*domain.Order, error) {
+				return svc.Fulfill(context.Background(), "ns", id)
+			},
+			permission: "order:fulfill",
+			to:         domain.OrderStatusFulfilled,
+		},
+		{
+			name: "deliver",
+			from: domain.OrderStatusFulfilled,
+			call: func(svc *orders.Service, id uuid.UUID) (*domain.Order, error) {
+				return svc.MarkDelivered(context.Background(), "ns", id)
+			},
+			permission: "order:deliver",
+			to:         domain.OrderStatusDelivered,
+		},
+		{
+			name: "cancel",
+			from: domain.OrderStatusPending,
+			call: func(svc *orders.Service, id uuid.UUID) (*domain.Order, error) {
+				return svc.Cancel(context.Background(), "ns", id, "customer request")
+			},
+			permission: "order:cancel",
+			to:         domain.OrderStatusCancelled,
+		},
+		{
+			name: "refund",
+			from: domain.OrderStatusDelivered,
+			call: func(svc *orders.Service, id uuid.UUID) (*domain.Order, error) {
+				return svc.Refund(context.Background(), "ns", id, "damaged")
+			},
+			permission: "order:refund",
+			to:         domain.OrderStatusRefunded,
+		},
+		{
+			name: "cancel a paid order",
+			from: domain.OrderStatusPaid,
+			call: func(svc *orders.Service, id uuid.UUID) (*domain.Order, error) {
+				return svc.Cancel(context.Background(), "ns", id, "")
+			},
+			permission: "order:cancel",
+			err:        domain.ErrInvalidOrderTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &domain.Order{ID: uuid.New(), Status: tt.from}
+
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockOrderRepository(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			service := orders.NewService(mockRepo, NewMockProductCatalog(ctrl), mockBus, mockAuth)
+
+			mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+			mockAuth.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", tt.permission).Return(true, nil)
+			mockRepo.EXPECT().GetByID(gomock.Any(), "ns", order.ID).Return(order, nil)
+			if tt.err == nil {
+				mockRepo.EXPECT().UpdateStatus(gomock.Any(), "ns", order, tt.from).Return(nil)
+				mockBus.EXPECT().Publish(gomock.Any(), "order:"+string(tt.to), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, event interface{}) error {
+						e := event.(events.OrderStatusChanged)
+						assert.Equal(t, order.ID, e.ID)
+						assert.Equal(t, string(tt.from), e.From)
+						assert.Equal(t, string(tt.to), e.To)
+						assert.Equal(t, userID, e.ChangedBy)
+						return nil
+					})
+			}
+
+			got, err := tt.call(service, order.ID)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.to, got.Status)
+		})
+	}
+}
+
+func TestAbandon(t *testing.T) {
+	tests := []struct {
+		name string
+		from domain.OrderStatus
+		err  error
+	}{
+		{name: "cancels a pending order without permission", from: domain.OrderStatusPending},
+		{name: "already cancelled", from: domain.OrderStatusCancelled},
+		{name: "paid order", from: domain.OrderStatusPaid, err: domain.ErrInvalidOrderTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &domain.Order{ID: uuid.New(), Status: tt.from}
+
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockOrderRepository(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			service := orders.NewService(mockRepo, NewMockProductCatalog(ctrl), mockBus, NewMockAuthService(ctrl))
+
+			mockRepo.EXPECT().GetByID(gomock.Any(), "ns", order.ID).Return(order, nil)
+			if tt.err == nil && tt.from != domain.OrderStatusCancelled {
+				mockRepo.EXPECT().UpdateStatus(gomock.Any(), "ns", order, tt.from).Return(nil)
+				mockBus.EXPECT().Publish(gomock.Any(), "order:cancelled", gomock.Any()).Return(nil)
+			}
+
+			got, err := service.Abandon(context.Background(), "ns", order.ID, "checkout failed")
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.OrderStatusCancelled, got.Status)
+			if tt.from != domain.OrderStatusCancelled {
+				assert.Equal(t, uuid.Nil, got.History[len(got.History)-1].UserID)
+			}
+		})
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	customerID := uuid.New()
+	other := uuid.New()
+	order := &domain.Order{ID: uuid.New(), CustomerID: customerID}
+
+	tests := []struct {
+		name  string
+		setup func(t setupParams)
+		err   error
+	}{
+		{
+			name: "customer reads its own order",
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(customerID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", order.ID).Return(order, nil)
+			},
+		},
+		{
+			name: "other customers need the permission",
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(other, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", order.ID).Return(order, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), other, "ns", "order:read").Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockOrderRepository(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			service := orders.NewService(mockRepo, NewMockProductCatalog(ctrl), NewMockEventBus(ctrl), mockAuth)
+
+			tt.setup(setupParams{repoService: mockRepo, authService: mockAuth})
+
+			got, err := service.GetOrder(context.Background(), "ns", order.ID)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, order, got)
+		})
+	}
+}
+
+func TestSyncPayment(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     domain.OrderStatus
+		payment  domain.PaymentStatus
+		expected domain.OrderStatus
+	}{
+		{name: "captured pays the order", from: domain.OrderStatusPending, payment: domain.PaymentStatusCaptured, expected: domain.OrderStatusPaid},
+		{name: "failed cancels the order", from: domain.OrderStatusPending, payment: domain.PaymentStatusFailed, expected: domain.OrderStatusCancelled},
+		{name: "refunded refunds a delivered order", from: domain.OrderStatusDelivered, payment: domain.PaymentStatusRefunded, expected: domain.OrderStatusRefunded},
+		{name: "authorized leaves the order pending", from: domain.OrderStatusPending, payment: domain.PaymentStatusAuthorized, expected: domain.OrderStatusPending},
+		{name: "repeated capture is ignored", from: domain.OrderStatusPaid, payment: domain.PaymentStatusCaptured, expected: domain.OrderStatusPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &domain.Order{ID: uuid.New(), Status: tt.from}
+
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockOrderRepository(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			service := orders.NewService(mockRepo, NewMockProductCatalog(ctrl), mockBus, NewMockAuthService(ctrl))
+
+			mockRepo.EXPECT().GetByID(gomock.Any(), "ns", order.ID).Return(order, nil)
+			if tt.expected != tt.from {
+				mockRepo.EXPECT().UpdateStatus(gomock.Any(), "ns", order, tt.from).Return(nil)
+				mockBus.EXPECT().Publish(gomock.Any(), "order:"+string(tt.expected), gomock.Any()).Return(nil)
+			}
+
+			got, err := service.SyncPayment(context.Background(), "ns", order.ID, tt.payment)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got.Status)
+			if tt.expected != tt.from {
+				assert.Equal(t, uuid.Nil, got.History[len(got.History)-1].UserID)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/google/uuid"
+)
+
+// OrderRepository defines an interface for managing the orders.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  orders_test
+type OrderRepository interface {
+
+	// Create stores a new order.
+	Create(ctx context.Context, namespace string, order *domain.Order) error
+
+	// GetByID retrieves an order by its unique identifier, returns domain.ErrOrderNotFound if missing.
+	GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.Order, error)
+
+	// Find retrieves the orders matching the filter, newest first.
+	Find(ctx context.Context, namespace string, filter dto.OrderFilter) ([]*domain.Order, error)
+
+	// UpdateStatus stores the new status and history of the order only if its stored status is still from,
+	// otherwise it returns domain.ErrInvalidOrderTransition. It prevents concurrent transitions from overwriting each other.
+	UpdateStatus(ctx context.Context, namespace string, order *domain.Order, from domain.OrderStatus) error
+}
+
+// ProductCatalog gives access to the catalog products the order lines are copied from.
+type ProductCatalog interface {
+	// Lookup returns the products matching the filter without checking the permissions of the user,
+	// the orders are checked by their own permissions.
+	Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error)
+}
+
+// EventBus defines an interface for managing event publishing and subscription.
+type EventBus interface {
+	// Publish sends an event to all subscribers of the specified topic.
+	Publish(ctx context.Context, topic string, event interface{}) error
+}
+
+// AuthService returns information about the current command
+type AuthService interface {
+	// GetUserID retrieves the unique identifier (UUID) of the user from the provided context. Returns an error if retrieval fails.
+	GetUserID(ctx context.Context) (uuid.UUID, error)
+	// CheckPermissions verifies if a user has the required permissions for a specified namespace and action(s). It returns a boolean indicating access and an error if the operation fails.
+	CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error)
+}
+
+type Service struct {
+	repo    OrderRepository
+	catalog ProductCatalog
+	bus     EventBus
+	auth    AuthService
+}
+
+func NewService(repo OrderRepository, catalog ProductCatalog, bus EventBus, auth AuthService) *Service {
+	return &Service{
+		repo:    repo,
+		catalog: catalog,
+		bus:     bus,
+		auth:    auth,
+	}
+}
+
+// currentUser returns the authenticated user of the context.
+func (s *Service) currentUser(ctx context.Context) (uuid.UUID, error) {
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if userID == uuid.Nil {
+		return uuid.Nil, domain.ErrUnauthorized
+	}
+	return userID, nil
+}
+
+// checkPermission verifies that the user has the permission in the namespace.
+func (s *Service) checkPermission(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) error {
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, permission...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return domain.ErrUnauthorized
+	}
+	return nil
+}
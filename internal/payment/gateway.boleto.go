@@ -0,0 +1,94 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/boleto"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"time"
+)
+
+type BoletoConfig struct {
+	// Bank is the 3 digit code of the issuing bank.
+	Bank string `env:"BOLETO_BANK" envDefault:"001"`
+	// Agreement is the 7 digit "convênio" of the store with the bank.
+	Agreement string `env:"BOLETO_AGREEMENT"`
+	// Wallet is the 2 digit "carteira" of the boletos.
+	Wallet string `env:"BOLETO_WALLET" envDefault:"17"`
+	// DueDays is the number of days the customer has to pay the boleto.
+	DueDays int `env:"BOLETO_DUE_DAYS" envDefault:"3"`
+}
+
+// BoletoGateway issues boletos with the free field layout of the 7 digit agreements of Banco do Brasil:
+// six zeros, the agreement, a 10 digit sequential number and the wallet. The boletos and their sequence
+// are stored in the repository, their payment is reported by the bank and recorded with Settle.
+type BoletoGateway struct {
+	config *BoletoConfig
+	ledger *ledger
+}
+
+func NewBoletoGateway(config *BoletoConfig, repo TransactionRepository) *BoletoGateway {
+	return &BoletoGateway{config: config, ledger: newLedger("boleto", repo)}
+}
+
+func (g *BoletoGateway) Authorize(ctx context.Context, req AuthorizeRequest) (*Transaction, error) {
+	if req.Method != domain.PaymentMethodBoleto {
+		return nil, domain.ErrUnsupportedPaymentMethod
+	}
+	if req.Amount <= 0 {
+		return nil, domain.ErrInvalidPaymentAmount
+	}
+
+	seq, err := g.ledger.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	due := time.Now().AddDate(0, 0, g.config.DueDays)
+	b := boleto.Boleto{
+		Bank:      g.config.Bank,
+		DueDate:   due,
+		Amount:    req.Amount,
+		FreeField: fmt.Sprintf("000000%07s%010d%02s", g.config.Agreement, seq, g.config.Wallet),
+	}
+	barcode, err := b.Barcode()
+	if err != nil {
+		return nil, err
+	}
+	line, err := boleto.LinhaDigitavel(barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.ledger.add(ctx, &Transaction{
+		// The "nosso número" identifies the boleto in the bank return files
+		ID:            fmt.Sprintf("%07s%010d", g.config.Agreement, seq),
+		Status:        domain.PaymentStatusPending,
+		Amount:        req.Amount,
+		BoletoBarcode: barcode,
+		BoletoLine:    line,
+		ExpiresAt:     due,
+	})
+}
+
+// Capture is not supported, a boleto is settled when the customer pays it.
+func (g *BoletoGateway) Capture(_ context.Context, _ string, _ currency.BRL) (*Transaction, error) {
+	return nil, domain.ErrInvalidPaymentOperation
+}
+
+func (g *BoletoGateway) Refund(ctx context.Context, transactionID string, amount currency.BRL) (*Transaction, error) {
+	return g.ledger.refund(ctx, transactionID, amount)
+}
+
+func (g *BoletoGateway) Void(ctx context.Context, transactionID string) (*Transaction, error) {
+	return g.ledger.void(ctx, transactionID)
+}
+
+func (g *BoletoGateway) Status(ctx context.Context, transactionID string) (*Transaction, error) {
+	return g.ledger.status(ctx, transactionID)
+}
+
+// Settle records the payment of the boleto reported by the bank.
+func (g *BoletoGateway) Settle(ctx context.Context, transactionID string) (*Transaction, error) {
+	return g.ledger.settle(ctx, transactionID)
+}
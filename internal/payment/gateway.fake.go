@@ -0,0 +1,119 @@
+package payment
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"sync"
+)
+
+// declinedCents is the cents part of the card amounts declined by the fake gateway.
+const declinedCents = 51
+
+// FakeGateway is a deterministic PaymentGateway for tests and development. Its transaction IDs are
+// sequential, card payments are authorized unless the amount ends with 51 cents, and Pix or boleto
+// charges stay pending until Settle is called. The transactions are kept in memory.
+type FakeGateway struct {
+	ledger *ledger
+}
+
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{ledger: newLedger("fake", &memoryTransactions{txs: map[string]*Transaction{}})}
+}
+
+func (g *FakeGateway) Authorize(ctx context.Context, req AuthorizeRequest) (*Transaction, error) {
+	if req.Amount <= 0 {
+		return nil, domain.ErrInvalidPaymentAmount
+	}
+
+	seq, err := g.ledger.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx := &Transaction{ID: g.ledger.id(seq), Amount: req.Amount}
+	switch req.Method {
+	case domain.PaymentMethodCreditCard:
+		tx.Status = domain.PaymentStatusAuthorized
+		if req.Amount.Cents()%100 == declinedCents {
+			tx.Status = domain.PaymentStatusFailed
+			if _, err = g.ledger.add(ctx, tx); err != nil {
+				return nil, err
+			}
+			return nil, domain.ErrPaymentDeclined
+		}
+	case domain.PaymentMethodPix:
+		tx.Status = domain.PaymentStatusPending
+		tx.PixPayload = "fake-pix-" + tx.ID
+	case domain.PaymentMethodBoleto:
+		tx.Status = domain.PaymentStatusPending
+		tx.BoletoLine = "fake-boleto-" + tx.ID
+	default:
+		return nil, domain.ErrUnsupportedPaymentMethod
+	}
+	return g.ledger.add(ctx, tx)
+}
+
+func (g *FakeGateway) Capture(ctx context.Context, transactionID string, amount currency.BRL) (*Transaction, error) {
+	return g.ledger.capture(ctx, transactionID, amount)
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, transactionID string, amount currency.BRL) (*Transaction, error) {
+	return g.ledger.refund(ctx, transactionID, amount)
+}
+
+func (g *FakeGateway) Void(ctx context.Context, transactionID string) (*Transaction, error) {
+	return g.ledger.void(ctx, transactionID)
+}
+
+func (g *FakeGateway) Status(ctx context.Context, transactionID string) (*Transaction, error) {
+	return g.ledger.status(ctx, transactionID)
+}
+
+// Settle simulates the customer paying a pending Pix or boleto charge.
+func (g *FakeGateway) Settle(ctx context.Context, transactionID string) (*Transaction, error) {
+	return g.ledger.settle(ctx, transactionID)
+}
+
+// memoryTransactions is the TransactionRepository of the FakeGateway.
+type memoryTransactions struct {
+	mu  sync.Mutex
+	seq int64
+	txs map[string]*Transaction
+}
+
+func (m *memoryTransactions) Create(_ context.Context, _ string, tx *Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := *tx
+	m.txs[tx.ID] = &c
+	return nil
+}
+
+func (m *memoryTransactions) Get(_ context.Context, _ string, id string) (*Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tx, ok := m.txs[id]
+	if !ok {
+		return nil, domain.ErrPaymentNotFound
+	}
+	c := *tx
+	return &c, nil
+}
+
+func (m *memoryTransactions) Update(_ context.Context, _ string, tx *Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.txs[tx.ID]; !ok {
+		return domain.ErrPaymentNotFound
+	}
+	c := *tx
+	m.txs[tx.ID] = &c
+	return nil
+}
+
+func (m *memoryTransactions) NextSequence(_ context.Context, _ string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	return m.seq, nil
+}
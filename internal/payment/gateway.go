@@ -0,0 +1,55 @@
+package payment
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"time"
+)
+
+// AuthorizeRequest asks the gateway to authorize, or to issue the charge of, a payment.
+type AuthorizeRequest struct {
+	PaymentID uuid.UUID
+	OrderID   uuid.UUID
+	Method    domain.PaymentMethod
+	Amount    currency.BRL
+}
+
+// Transaction is the state of a payment in the gateway.
+type Transaction struct {
+	ID       string
+	Status   domain.PaymentStatus
+	Amount   currency.BRL
+	Captured currency.BRL
+	Refunded currency.BRL
+
+	// PixPayload, BoletoBarcode and BoletoLine are the instructions given to the customer, depending on the method.
+	PixPayload    string
+	BoletoBarcode string
+	BoletoLine    string
+	ExpiresAt     time.Time
+}
+
+// PaymentGateway is a payment provider.
+//
+// Card payments are authorized then captured. Pix and boleto charges stay pending until the customer
+// pays them, the provider then reports them as captured through a webhook.
+type PaymentGateway interface {
+
+	// Authorize reserves the amount, or issues the charge of the asynchronous methods.
+	// It returns domain.ErrPaymentDeclined when the provider refuses the payment.
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Transaction, error)
+
+	// Capture settles the authorized amount, it cannot exceed the authorized one.
+	Capture(ctx context.Context, transactionID string, amount currency.BRL) (*Transaction, error)
+
+	// Refund returns a part or all of the captured amount.
+	Refund(ctx context.Context, transactionID string, amount currency.BRL) (*Transaction, error)
+
+	// Void cancels an authorization or a charge not paid yet.
+	Void(ctx context.Context, transactionID string) (*Transaction, error)
+
+	// Status returns the current state of the transaction.
+	Status(ctx context.Context, transactionID string) (*Transaction, error)
+}
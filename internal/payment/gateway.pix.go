@@ -0,0 +1,82 @@
+package payment
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/HBeserra/GoShop/pkg/pix"
+	"strings"
+	"time"
+)
+
+type PixConfig struct {
+	// Key is the Pix key of the store receiving the payments.
+	Key          string        `env:"PIX_KEY"`
+	MerchantName string        `env:"PIX_MERCHANT_NAME"`
+	MerchantCity string        `env:"PIX_MERCHANT_CITY"`
+	Expiration   time.Duration `env:"PIX_EXPIRATION" envDefault:"30m"`
+}
+
+// PixGateway issues Pix charges as BR Codes paid to the store key. The charges are stored in the
+// repository, their payment is reported by the bank webhook and recorded with Settle.
+type PixGateway struct {
+	config *PixConfig
+	ledger *ledger
+}
+
+func NewPixGateway(config *PixConfig, repo TransactionRepository) *PixGateway {
+	return &PixGateway{config: config, ledger: newLedger("pix", repo)}
+}
+
+func (g *PixGateway) Authorize(ctx context.Context, req AuthorizeRequest) (*Transaction, error) {
+	if req.Method != domain.PaymentMethodPix {
+		return nil, domain.ErrUnsupportedPaymentMethod
+	}
+	if req.Amount <= 0 {
+		return nil, domain.ErrInvalidPaymentAmount
+	}
+
+	// The txid is made of letters and digits only and is limited to 25 characters
+	txID := strings.ReplaceAll(req.PaymentID.String(), "-", "")[:25]
+	payload, err := pix.BRCode{
+		Key:          g.config.Key,
+		MerchantName: g.config.MerchantName,
+		MerchantCity: g.config.MerchantCity,
+		Amount:       req.Amount,
+		TxID:         txID,
+		Unique:       true,
+	}.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.ledger.add(ctx, &Transaction{
+		ID:         txID,
+		Status:     domain.PaymentStatusPending,
+		Amount:     req.Amount,
+		PixPayload: payload,
+		ExpiresAt:  time.Now().Add(g.config.Expiration),
+	})
+}
+
+// Capture is not supported, a Pix is settled when the customer pays it.
+func (g *PixGateway) Capture(_ context.Context, _ string, _ currency.BRL) (*Transaction, error) {
+	return nil, domain.ErrInvalidPaymentOperation
+}
+
+func (g *PixGateway) Refund(ctx context.Context, transactionID string, amount currency.BRL) (*Transaction, error) {
+	return g.ledger.refund(ctx, transactionID, amount)
+}
+
+func (g *PixGateway) Void(ctx context.Context, transactionID string) (*Transaction, error) {
+	return g.ledger.void(ctx, transactionID)
+}
+
+func (g *PixGateway) Status(ctx context.Context, transactionID string) (*Transaction, error) {
+	return g.ledger.status(ctx, transactionID)
+}
+
+// Settle records the payment of the charge reported by the bank.
+func (g *PixGateway) Settle(ctx context.Context, transactionID string) (*Transaction, error) {
+	return g.ledger.settle(ctx, transactionID)
+}
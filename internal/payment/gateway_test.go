@@ -0,0 +1,179 @@
+package payment_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/payment"
+	"github.com/HBeserra/GoShop/pkg/boleto"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/HBeserra/GoShop/pkg/pix"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+)
+
+func TestFakeGateway(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("card lifecycle", func(t *testing.T) {
+		g := payment.NewFakeGateway()
+		tx, err := g.Authorize(ctx, payment.AuthorizeRequest{Method: domain.PaymentMethodCreditCard, Amount: currency.NewFromFloat(100)})
+		require.NoError(t, err)
+		assert.Equal(t, "fake-000001", tx.ID)
+		assert.Equal(t, domain.PaymentStatusAuthorized, tx.Status)
+
+		_, err = g.Refund(ctx, tx.ID, currency.NewFromFloat(10))
+		assert.ErrorIs(t, err, domain.ErrInvalidPaymentOperation, "an authorization cannot be refunded")
+
+		_, err = g.Capture(ctx, tx.ID, currency.NewFromFloat(101))
+		assert.ErrorIs(t, err, domain.ErrInvalidPaymentAmount)
+
+		tx, err = g.Capture(ctx, tx.ID, currency.NewFromFloat(100))
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentStatusCaptured, tx.Status)
+
+		tx, err = g.Refund(ctx, tx.ID, currency.NewFromFloat(40))
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentStatusCaptured, tx.Status, "partially refunded")
+
+		tx, err = g.Refund(ctx, tx.ID, currency.NewFromFloat(60))
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentStatusRefunded, tx.Status)
+		assert.Equal(t, currency.NewFromFloat(100), tx.Refunded)
+
+		_, err = g.Void(ctx, tx.ID)
+		assert.ErrorIs(t, err, domain.ErrInvalidPaymentOperation)
+	})
+
+	t.Run("declined card", func(t *testing.T) {
+		g := payment.NewFakeGateway()
+		_, err := g.Authorize(ctx, payment.AuthorizeRequest{Method: domain.PaymentMethodCreditCard, Amount: currency.NewFromFloat(10.51)})
+		assert.ErrorIs(t, err, domain.ErrPaymentDeclined)
+
+		tx, err := g.Status(ctx, "fake-000001")
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentStatusFailed, tx.Status)
+	})
+
+	t.Run("pix is settled by the customer", func(t *testing.T) {
+		g := payment.NewFakeGateway()
+		tx, err := g.Authorize(ctx, payment.AuthorizeRequest{Method: domain.PaymentMethodPix, Amount: currency.NewFromFloat(50)})
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentStatusPending, tx.Status)
+
+		_, err = g.Capture(ctx, tx.ID, tx.Amount)
+		assert.ErrorIs(t, err, domain.ErrInvalidPaymentOperation)
+
+		tx, err = g.Settle(ctx, tx.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentStatusCaptured, tx.Status)
+		assert.Equal(t, currency.NewFromFloat(50), tx.Captured)
+	})
+}
+
+func TestPixGateway(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockTransactionRepository(ctrl)
+	g := payment.NewPixGateway(&payment.PixConfig{Key: "pix@goshop.com.br", MerchantName: "GoShop", MerchantCity: "Curitiba"}, mockRepo)
+	paymentID := uuid.New()
+
+	mockRepo.EXPECT().Create(gomock.Any(), "pix", gomock.Any()).Return(nil)
+
+	tx, err := g.Authorize(context.Background(), payment.AuthorizeRequest{
+		PaymentID: paymentID,
+		Method:    domain.PaymentMethodPix,
+		Amount:    currency.NewFromFloat(89.9),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusPending, tx.Status)
+	assert.Len(t, tx.ID, 25)
+	assert.Contains(t, tx.PixPayload, "540589.90")
+	assert.Contains(t, tx.PixPayload, "0525"+tx.ID)
+
+	body, crc := tx.PixPayload[:len(tx.PixPayload)-4], tx.PixPayload[len(tx.PixPayload)-4:]
+	assert.True(t, strings.HasSuffix(body, "6304"))
+	assert.Equal(t, fmt.Sprintf("%04X", pix.CRC16(body)), crc)
+
+	_, err = g.Authorize(context.Background(), payment.AuthorizeRequest{Method: domain.PaymentMethodBoleto, Amount: 1})
+	assert.ErrorIs(t, err, domain.ErrUnsupportedPaymentMethod)
+}
+
+func TestPixGateway_Settle(t *testing.T) {
+	tx := &payment.Transaction{ID: "abc", Status: domain.PaymentStatusPending, Amount: currency.NewFromFloat(10)}
+
+	tests := []struct {
+		name   string
+		id     string
+		setup  func(t setupParams)
+		err    error
+		status domain.PaymentStatus
+	}{
+		{
+			// a charge issued before a restart, or by another replica, is read from the repository
+			name: "pending charge",
+			id:   "abc",
+			setup: func(t setupParams) {
+				t.transactionService.EXPECT().Get(gomock.Any(), "pix", "abc").Return(tx, nil)
+				t.transactionService.EXPECT().Update(gomock.Any(), "pix", gomock.Any()).Return(nil)
+			},
+			status: domain.PaymentStatusCaptured,
+		},
+		{
+			name: "unknown charge",
+			id:   "xyz",
+			setup: func(t setupParams) {
+				t.transactionService.EXPECT().Get(gomock.Any(), "pix", "xyz").Return(nil, domain.ErrPaymentNotFound)
+			},
+			err: domain.ErrPaymentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockTransactionRepository(ctrl)
+			g := payment.NewPixGateway(&payment.PixConfig{}, mockRepo)
+
+			tt.setup(setupParams{transactionService: mockRepo})
+
+			got, err := g.Settle(context.Background(), tt.id)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, got.Status)
+			assert.Equal(t, tx.Amount, got.Captured)
+		})
+	}
+}
+
+func TestBoletoGateway(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockTransactionRepository(ctrl)
+	g := payment.NewBoletoGateway(&payment.BoletoConfig{Bank: "001", Agreement: "1234567", Wallet: "17", DueDays: 3}, mockRepo)
+
+	// the sequence continues from the one stored
+	mockRepo.EXPECT().NextSequence(gomock.Any(), "boleto").Return(int64(42), nil)
+	mockRepo.EXPECT().Create(gomock.Any(), "boleto", gomock.Any()).Return(nil)
+
+	tx, err := g.Authorize(context.Background(), payment.AuthorizeRequest{
+		PaymentID: uuid.New(),
+		Method:    domain.PaymentMethodBoleto,
+		Amount:    currency.NewFromFloat(250),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "12345670000000042", tx.ID)
+	require.Len(t, tx.BoletoBarcode, 44)
+	assert.Equal(t, "0019", tx.BoletoBarcode[:4])
+	assert.Equal(t, "0000025000", tx.BoletoBarcode[9:19])
+	assert.Equal(t, "0000001234567000000004217", tx.BoletoBarcode[19:])
+
+	line, err := boleto.LinhaDigitavel(tx.BoletoBarcode)
+	require.NoError(t, err)
+	assert.Equal(t, line, tx.BoletoLine)
+}
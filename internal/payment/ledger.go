@@ -0,0 +1,109 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
+)
+
+// ledger keeps the transactions of the gateways implemented in process and enforces their state changes.
+type ledger struct {
+	prefix string
+	repo   TransactionRepository
+}
+
+func newLedger(prefix string, repo TransactionRepository) *ledger {
+	return &ledger{prefix: prefix, repo: repo}
+}
+
+// next returns the next sequence number, transaction IDs are deterministic.
+func (l *ledger) next(ctx context.Context) (int64, error) {
+	return l.repo.NextSequence(ctx, l.prefix)
+}
+
+func (l *ledger) id(seq int64) string {
+	return fmt.Sprintf("%s-%06d", l.prefix, seq)
+}
+
+func (l *ledger) add(ctx context.Context, tx *Transaction) (*Transaction, error) {
+	if err := l.repo.Create(ctx, l.prefix, tx); err != nil {
+		return nil, err
+	}
+	c := *tx
+	return &c, nil
+}
+
+// update applies fn to the stored transaction and returns a copy of the result.
+func (l *ledger) update(ctx context.Context, id string, fn func(tx *Transaction) error) (*Transaction, error) {
+	tx, err := l.repo.Get(ctx, l.prefix, id)
+	if err != nil {
+		return nil, err
+	}
+	if fn != nil {
+		if err = fn(tx); err != nil {
+			return nil, err
+		}
+		if err = l.repo.Update(ctx, l.prefix, tx); err != nil {
+			return nil, err
+		}
+	}
+	c := *tx
+	return &c, nil
+}
+
+func (l *ledger) status(ctx context.Context, id string) (*Transaction, error) {
+	return l.update(ctx, id, nil)
+}
+
+func (l *ledger) capture(ctx context.Context, id string, amount currency.BRL) (*Transaction, error) {
+	return l.update(ctx, id, func(tx *Transaction) error {
+		if tx.Status != domain.PaymentStatusAuthorized {
+			return fmt.Errorf("%w: capture a %s payment", domain.ErrInvalidPaymentOperation, tx.Status)
+		}
+		if amount <= 0 || amount > tx.Amount {
+			return domain.ErrInvalidPaymentAmount
+		}
+		tx.Captured = amount
+		tx.Status = domain.PaymentStatusCaptured
+		return nil
+	})
+}
+
+// settle records the payment of a pending charge by the customer.
+func (l *ledger) settle(ctx context.Context, id string) (*Transaction, error) {
+	return l.update(ctx, id, func(tx *Transaction) error {
+		if tx.Status != domain.PaymentStatusPending {
+			return fmt.Errorf("%w: settle a %s payment", domain.ErrInvalidPaymentOperation, tx.Status)
+		}
+		tx.Captured = tx.Amount
+		tx.Status = domain.PaymentStatusCaptured
+		return nil
+	})
+}
+
+func (l *ledger) refund(ctx context.Context, id string, amount currency.BRL) (*Transaction, error) {
+	return l.update(ctx, id, func(tx *Transaction) error {
+		if tx.Status != domain.PaymentStatusCaptured {
+			return fmt.Errorf("%w: refund a %s payment", domain.ErrInvalidPaymentOperation, tx.Status)
+		}
+		if amount <= 0 || tx.Refunded.Add(amount) > tx.Captured {
+			return domain.ErrInvalidPaymentAmount
+		}
+		tx.Refunded = tx.Refunded.Add(amount)
+		if tx.Refunded == tx.Captured {
+			tx.Status = domain.PaymentStatusRefunded
+		}
+		return nil
+	})
+}
+
+func (l *ledger) void(ctx context.Context, id string) (*Transaction, error) {
+	return l.update(ctx, id, func(tx *Transaction) error {
+		if tx.Status != domain.PaymentStatusAuthorized && tx.Status != domain.PaymentStatusPending {
+			return fmt.Errorf("%w: void a %s payment", domain.ErrInvalidPaymentOperation, tx.Status)
+		}
+		tx.Status = domain.PaymentStatusVoided
+		return nil
+	})
+}
@@ -0,0 +1,106 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// Authorize starts the payment of the order total. Card payments are authorized and captured later,
+// Pix and boleto payments stay pending with the instructions given to the customer until they are paid.
+// A declined payment is stored as failed and domain.ErrPaymentDeclined is returned.
+func (s *Service) Authorize(ctx context.Context, namespace string, order *domain.Order, method domain.PaymentMethod) (*domain.Payment, error) {
+
+	ctx, span := observability.StartSpan(ctx, "payment.Authorize")
+	defer span.End()
+
+	if err := s.checkAccess(ctx, namespace, order.CustomerID, "payment:create"); err != nil {
+		return nil, err
+	}
+
+	gateway, err := s.gateway(method)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payment := &domain.Payment{
+		ID:         uuid.New(),
+		Namespace:  namespace,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		OrderID:    order.ID,
+		CustomerID: order.CustomerID,
+		Method:     method,
+		Amount:     order.Total,
+	}
+
+	tx, err := gateway.Authorize(ctx, AuthorizeRequest{
+		PaymentID: payment.ID,
+		OrderID:   order.ID,
+		Method:    method,
+		Amount:    order.Total,
+	})
+	if errors.Is(err, domain.ErrPaymentDeclined) {
+		payment.Status = domain.PaymentStatusFailed
+		if cerr := s.repo.Create(ctx, namespace, payment); cerr != nil {
+			span.RecordError(cerr)
+		}
+		slog.InfoContext(ctx, "payment declined",
+			"payment_id", payment.ID,
+			"order_id", order.ID,
+			"method", method,
+		)
+		return nil, err
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	apply(payment, tx)
+	err = s.repo.Create(ctx, namespace, payment)
+	if err != nil {
+		span.RecordError(err)
+		// The gateway holds the authorization, release it so the customer is not charged
+		if _, verr := gateway.Void(context.WithoutCancel(ctx), tx.ID); verr != nil {
+			slog.ErrorContext(ctx, "failed to void the authorization of an unsaved payment",
+				"transaction_id", tx.ID,
+				"error", verr,
+			)
+		}
+		return nil, err
+	}
+
+	s.publish(ctx, payment, "")
+
+	slog.InfoContext(ctx, "payment authorized",
+		"payment_id", payment.ID,
+		"order_id", order.ID,
+		"method", method,
+		"status", payment.Status,
+	)
+	return payment, nil
+}
+
+// apply copies the gateway state of the transaction into the payment.
+func apply(payment *domain.Payment, tx *Transaction) {
+	payment.TransactionID = tx.ID
+	payment.Status = tx.Status
+	payment.Captured = tx.Captured
+	payment.Refunded = tx.Refunded
+	if tx.PixPayload != "" {
+		payment.PixPayload = tx.PixPayload
+	}
+	if tx.BoletoBarcode != "" || tx.BoletoLine != "" {
+		payment.BoletoBarcode = tx.BoletoBarcode
+		payment.BoletoLine = tx.BoletoLine
+	}
+	if !tx.ExpiresAt.IsZero() {
+		payment.ExpiresAt = tx.ExpiresAt
+	}
+}
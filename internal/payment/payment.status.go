@@ -0,0 +1,164 @@
+package payment
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/HBeserra/GoShop/pkg/pix"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// Capture settles the full authorized amount of a card payment and marks the order as paid.
+func (s *Service) Capture(ctx context.Context, namespace string, paymentID uuid.UUID) (*domain.Payment, error) {
+
+	ctx, span := observability.StartSpan(ctx, "payment.Capture")
+	defer span.End()
+
+	if err := s.checkPermission(ctx, namespace, "payment:capture"); err != nil {
+		return nil, err
+	}
+
+	return s.operate(ctx, namespace, paymentID, func(g PaymentGateway, p *domain.Payment) (*Transaction, error) {
+		return g.Capture(ctx, p.TransactionID, p.Amount)
+	})
+}
+
+// Refund returns the amount to the customer, the order is refunded once the whole payment is.
+func (s *Service) Refund(ctx context.Context, namespace string, paymentID uuid.UUID, amount currency.BRL) (*domain.Payment, error) {
+
+	ctx, span := observability.StartSpan(ctx, "payment.Refund")
+	defer span.End()
+
+	if err := s.checkPermission(ctx, namespace, "payment:refund"); err != nil {
+		return nil, err
+	}
+
+	return s.operate(ctx, namespace, paymentID, func(g PaymentGateway, p *domain.Payment) (*Transaction, error) {
+		return g.Refund(ctx, p.TransactionID, amount)
+	})
+}
+
+// Void cancels a payment not captured yet, for instance when the checkout is rolled back.
+func (s *Service) Void(ctx context.Context, namespace string, paymentID uuid.UUID) error {
+
+	ctx, span := observability.StartSpan(ctx, "payment.Void")
+	defer span.End()
+
+	payment, err := s.repo.GetByID(ctx, namespace, paymentID)
+	if err != nil {
+		return err
+	}
+	if err = s.checkAccess(ctx, namespace, payment.CustomerID, "payment:void"); err != nil {
+		return err
+	}
+
+	_, err = s.operate(ctx, namespace, paymentID, func(g PaymentGateway, p *domain.Payment) (*Transaction, error) {
+		return g.Void(ctx, p.TransactionID)
+	})
+	return err
+}
+
+// Abandon voids the payment of a checkout that could not complete. It is called by the checkout to compensate
+// the payment authorization, so no permission is checked.
+func (s *Service) Abandon(ctx context.Context, namespace string, paymentID uuid.UUID) error {
+
+	ctx, span := observability.StartSpan(ctx, "payment.Abandon")
+	defer span.End()
+
+	_, err := s.operate(ctx, namespace, paymentID, func(g PaymentGateway, p *domain.Payment) (*Transaction, error) {
+		return g.Void(ctx, p.TransactionID)
+	})
+	return err
+}
+
+// PixQRCode renders the QR code of a pending Pix payment as a PNG image.
+func (s *Service) PixQRCode(ctx context.Context, namespace string, paymentID uuid.UUID, size int) ([]byte, error) {
+
+	ctx, span := observability.StartSpan(ctx, "payment.PixQRCode")
+	defer span.End()
+
+	payment, err := s.repo.GetByID(ctx, namespace, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkAccess(ctx, namespace, payment.CustomerID, "payment:read"); err != nil {
+		return nil, err
+	}
+	if payment.Method != domain.PaymentMethodPix || payment.PixPayload == "" {
+		return nil, domain.ErrInvalidPaymentOperation
+	}
+
+	return pix.QRCode(payment.PixPayload, size)
+}
+
+// operate runs a gateway operation on the payment and stores its outcome.
+func (s *Service) operate(ctx context.Context, namespace string, paymentID uuid.UUID, op func(g PaymentGateway, p *domain.Payment) (*Transaction, error)) (*domain.Payment, error) {
+	payment, err := s.repo.GetByID(ctx, namespace, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	gateway, err := s.gateway(payment.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := op(gateway, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, s.sync(ctx, namespace, payment, tx)
+}
+
+// sync stores the transaction state in the payment and, when the status changed, publishes it
+// and moves the order accordingly.
+func (s *Service) sync(ctx context.Context, namespace string, payment *domain.Payment, tx *Transaction) error {
+	from := payment.Status
+	apply(payment, tx)
+	payment.UpdatedAt = time.Now()
+
+	err := s.repo.Update(ctx, namespace, payment)
+	if err != nil {
+		return err
+	}
+	if payment.Status == from {
+		return nil
+	}
+
+	s.publish(ctx, payment, from)
+
+	_, err = s.orders.SyncPayment(ctx, namespace, payment.OrderID, payment.Status)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to sync order with payment",
+			"payment_id", payment.ID,
+			"order_id", payment.OrderID,
+			"status", payment.Status,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
+
+// publish sends the payment status on the topic of the status, e.g. "payment:captured".
+func (s *Service) publish(ctx context.Context, payment *domain.Payment, from domain.PaymentStatus) {
+	topic := "payment:" + string(payment.Status)
+	err := s.bus.Publish(ctx, topic, events.PaymentStatusChanged{
+		ID:        payment.ID,
+		OrderID:   payment.OrderID,
+		From:      string(from),
+		To:        string(payment.Status),
+		Amount:    payment.Amount,
+		ChangedOn: payment.UpdatedAt,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish "+topic+" event",
+			"payment_id", payment.ID,
+			"error", err)
+	}
+}
@@ -0,0 +1,286 @@
+package payment_test
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/payment"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type setupParams struct {
+	repoService        *MockPaymentRepository
+	ordersService      *MockOrders
+	busService         *MockEventBus
+	authService        *MockAuthService
+	transactionService *MockTransactionRepository
+}
+
+func TestAuthorize(t *testing.T) {
+	customerID := uuid.New()
+
+	tests := []struct {
+		name           string
+		method         domain.PaymentMethod
+		total          currency.BRL
+		setup          func(t setupParams)
+		expectedStatus domain.PaymentStatus
+		err            error
+	}{
+		{
+			name:   "card",
+			method: domain.PaymentMethodCreditCard,
+			total:  currency.NewFromFloat(100),
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(customerID, nil)
+				t.repoService.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "payment:authorized", gomock.Any()).Return(nil)
+			},
+			expectedStatus: domain.PaymentStatusAuthorized,
+		},
+		{
+			name:   "pix",
+			method: domain.PaymentMethodPix,
+			total:  currency.NewFromFloat(100),
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(customerID, nil)
+				t.repoService.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "payment:pending", gomock.Any()).Return(nil)
+			},
+			expectedStatus: domain.PaymentStatusPending,
+		},
+		{
+			name:   "declined card",
+			method: domain.PaymentMethodCreditCard,
+			total:  currency.NewFromFloat(100.51),
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(customerID, nil)
+				// the failed attempt is recorded
+				t.repoService.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, p *domain.Payment) error {
+						if p.Status != domain.PaymentStatusFailed {
+							return errors.New("unexpected status " + string(p.Status))
+						}
+						return nil
+					})
+			},
+			err: domain.ErrPaymentDeclined,
+		},
+		{
+			name:   "method without gateway",
+			method: domain.PaymentMethodBoleto,
+			total:  currency.NewFromFloat(100),
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(customerID, nil)
+			},
+			err: domain.ErrUnsupportedPaymentMethod,
+		},
+		{
+			name:   "someone else's order",
+			method: domain.PaymentMethodPix,
+			total:  currency.NewFromFloat(100),
+			setup: func(t setupParams) {
+				userID := uuid.New()
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", gomock.Any()).Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockPaymentRepository(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+			gateway := payment.NewFakeGateway()
+			service := payment.NewService(&payment.Config{WebhookSecret: "secret"}, mockRepo, map[domain.PaymentMethod]payment.PaymentGateway{
+				domain.PaymentMethodCreditCard: gateway,
+				domain.PaymentMethodPix:        gateway,
+			}, NewMockOrders(ctrl), mockBus, mockAuth)
+
+			tt.setup(setupParams{repoService: mockRepo, busService: mockBus, authService: mockAuth})
+
+			order := &domain.Order{ID: uuid.New(), CustomerID: customerID, Total: tt.total}
+			got, err := service.Authorize(context.Background(), "ns", order, tt.method)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, got.TransactionID)
+			assert.Equal(t, tt.expectedStatus, got.Status)
+			assert.Equal(t, order.ID, got.OrderID)
+			assert.Equal(t, tt.total, got.Amount)
+		})
+	}
+}
+
+func TestHandleWebhook(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		signature func(service *payment.Service, body []byte) string
+		setup     func(t setupParams, p *domain.Payment)
+		err       error
+		expected  func(t *testing.T, p *domain.Payment)
+	}{
+		{
+			name:   "paid pix pays the order",
+			status: "captured",
+			setup: func(t setupParams, p *domain.Payment) {
+				t.repoService.EXPECT().GetByTransaction(gomock.Any(), "ns", domain.PaymentMethodPix, p.TransactionID).Return(p, nil)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", p).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "payment:captured", gomock.Any()).Return(nil)
+				t.ordersService.EXPECT().SyncPayment(gomock.Any(), "ns", p.OrderID, domain.PaymentStatusCaptured).Return(&domain.Order{}, nil)
+			},
+			expected: func(t *testing.T, p *domain.Payment) {
+				assert.Equal(t, domain.PaymentStatusCaptured, p.Status)
+				assert.Equal(t, currency.NewFromFloat(80), p.Captured)
+			},
+		},
+		{
+			name:   "the gateway status prevails",
+			status: "refunded",
+			setup: func(t setupParams, p *domain.Payment) {
+				t.repoService.EXPECT().GetByTransaction(gomock.Any(), "ns", domain.PaymentMethodPix, p.TransactionID).Return(p, nil)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", p).Return(nil)
+			},
+			expected: func(t *testing.T, p *domain.Payment) {
+				assert.Equal(t, domain.PaymentStatusPending, p.Status)
+			},
+		},
+		{
+			name:      "invalid signature",
+			status:    "captured",
+			signature: func(_ *payment.Service, _ []byte) string { return "00ff" },
+			setup:     func(t setupParams, p *domain.Payment) {},
+			err:       domain.ErrInvalidWebhookSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockPaymentRepository(ctrl)
+			mockOrders := NewMockOrders(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			gateway := payment.NewFakeGateway()
+			service := payment.NewService(&payment.Config{WebhookSecret: "secret"}, mockRepo, map[domain.PaymentMethod]payment.PaymentGateway{
+				domain.PaymentMethodPix: gateway,
+			}, mockOrders, mockBus, NewMockAuthService(ctrl))
+
+			tx, err := gateway.Authorize(ctx, payment.AuthorizeRequest{Method: domain.PaymentMethodPix, Amount: currency.NewFromFloat(80)})
+			require.NoError(t, err)
+			p := &domain.Payment{
+				ID:            uuid.New(),
+				OrderID:       uuid.New(),
+				Method:        domain.PaymentMethodPix,
+				Status:        domain.PaymentStatusPending,
+				Amount:        tx.Amount,
+				TransactionID: tx.ID,
+			}
+			tt.setup(setupParams{repoService: mockRepo, ordersService: mockOrders, busService: mockBus}, p)
+
+			body := []byte(`{"transaction_id":"` + tx.ID + `","status":"` + tt.status + `"}`)
+			signature := service.Sign(body)
+			if tt.signature != nil {
+				signature = tt.signature(service, body)
+			}
+
+			err = service.HandleWebhook(ctx, "ns", domain.PaymentMethodPix, body, signature)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			tt.expected(t, p)
+		})
+	}
+
+	t.Run("notification delivered again", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		mockRepo := NewMockPaymentRepository(ctrl)
+		gateway := payment.NewFakeGateway()
+		service := payment.NewService(&payment.Config{WebhookSecret: "secret"}, mockRepo, map[domain.PaymentMethod]payment.PaymentGateway{
+			domain.PaymentMethodPix: gateway,
+		}, NewMockOrders(ctrl), NewMockEventBus(ctrl), NewMockAuthService(ctrl))
+
+		tx, err := gateway.Authorize(ctx, payment.AuthorizeRequest{Method: domain.PaymentMethodPix, Amount: currency.NewFromFloat(80)})
+		require.NoError(t, err)
+		tx, err = gateway.Settle(ctx, tx.ID)
+		require.NoError(t, err)
+		p := &domain.Payment{ID: uuid.New(), OrderID: uuid.New(), Method: domain.PaymentMethodPix, Status: tx.Status, Amount: tx.Amount, Captured: tx.Captured, TransactionID: tx.ID}
+
+		// nothing is published nor synced with the order
+		mockRepo.EXPECT().GetByTransaction(gomock.Any(), "ns", domain.PaymentMethodPix, p.TransactionID).Return(p, nil)
+		mockRepo.EXPECT().Update(gomock.Any(), "ns", p).Return(nil)
+
+		body := []byte(`{"transaction_id":"` + tx.ID + `","status":"captured"}`)
+		err = service.HandleWebhook(ctx, "ns", domain.PaymentMethodPix, body, service.Sign(body))
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentStatusCaptured, p.Status)
+	})
+}
+
+func TestCapture(t *testing.T) {
+	userID := uuid.New()
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockPaymentRepository(ctrl)
+	mockOrders := NewMockOrders(ctrl)
+	mockBus := NewMockEventBus(ctrl)
+	mockAuth := NewMockAuthService(ctrl)
+	gateway := payment.NewFakeGateway()
+	service := payment.NewService(&payment.Config{}, mockRepo, map[domain.PaymentMethod]payment.PaymentGateway{
+		domain.PaymentMethodCreditCard: gateway,
+	}, mockOrders, mockBus, mockAuth)
+
+	tx, err := gateway.Authorize(context.Background(), payment.AuthorizeRequest{Method: domain.PaymentMethodCreditCard, Amount: currency.NewFromFloat(30)})
+	require.NoError(t, err)
+	p := &domain.Payment{ID: uuid.New(), OrderID: uuid.New(), Method: domain.PaymentMethodCreditCard, Status: tx.Status, Amount: tx.Amount, TransactionID: tx.ID}
+
+	mockAuth.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+	mockAuth.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "payment:capture").Return(true, nil)
+	mockRepo.EXPECT().GetByID(gomock.Any(), "ns", p.ID).Return(p, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), "ns", p).Return(nil)
+	mockBus.EXPECT().Publish(gomock.Any(), "payment:captured", gomock.Any()).Return(nil)
+	mockOrders.EXPECT().SyncPayment(gomock.Any(), "ns", p.OrderID, domain.PaymentStatusCaptured).Return(&domain.Order{}, nil)
+
+	got, err := service.Capture(context.Background(), "ns", p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusCaptured, got.Status)
+	assert.Equal(t, currency.NewFromFloat(30), got.Captured)
+}
+
+func TestAbandon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockPaymentRepository(ctrl)
+	mockOrders := NewMockOrders(ctrl)
+	mockBus := NewMockEventBus(ctrl)
+	gateway := payment.NewFakeGateway()
+	service := payment.NewService(&payment.Config{}, mockRepo, map[domain.PaymentMethod]payment.PaymentGateway{
+		domain.PaymentMethodCreditCard: gateway,
+	}, mockOrders, mockBus, NewMockAuthService(ctrl))
+
+	tx, err := gateway.Authorize(context.Background(), payment.AuthorizeRequest{Method: domain.PaymentMethodCreditCard, Amount: currency.NewFromFloat(30)})
+	require.NoError(t, err)
+	p := &domain.Payment{ID: uuid.New(), OrderID: uuid.New(), Method: domain.PaymentMethodCreditCard, Status: tx.Status, Amount: tx.Amount, TransactionID: tx.ID}
+
+	// no user nor permission is required
+	mockRepo.EXPECT().GetByID(gomock.Any(), "ns", p.ID).Return(p, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), "ns", p).Return(nil)
+	mockBus.EXPECT().Publish(gomock.Any(), "payment:voided", gomock.Any()).Return(nil)
+	mockOrders.EXPECT().SyncPayment(gomock.Any(), "ns", p.OrderID, domain.PaymentStatusVoided).Return(&domain.Order{}, nil)
+
+	err = service.Abandon(context.Background(), "ns", p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentStatusVoided, p.Status)
+}
@@ -0,0 +1,126 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/google/uuid"
+)
+
+type Config struct {
+	// WebhookSecret signs the status updates sent by the payment providers.
+	WebhookSecret string `env:"PAYMENT_WEBHOOK_SECRET"`
+}
+
+// PaymentRepository defines an interface for managing the payments.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  payment_test
+type PaymentRepository interface {
+
+	// Create stores a new payment.
+	Create(ctx context.Context, namespace string, payment *domain.Payment) error
+
+	// GetByID retrieves a payment by its unique identifier, returns domain.ErrPaymentNotFound if missing.
+	GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.Payment, error)
+
+	// GetByTransaction retrieves a payment by its gateway transaction ID, returns domain.ErrPaymentNotFound if missing.
+	GetByTransaction(ctx context.Context, namespace string, method domain.PaymentMethod, transactionID string) (*domain.Payment, error)
+
+	// Update stores the new state of the payment.
+	Update(ctx context.Context, namespace string, payment *domain.Payment) error
+}
+
+// TransactionRepository stores the transactions of the gateways implemented in process, so they outlive
+// the process and are shared by its replicas. The transactions are kept apart by gateway.
+type TransactionRepository interface {
+
+	// Create stores a new transaction.
+	Create(ctx context.Context, gateway string, tx *Transaction) error
+
+	// Get retrieves a transaction by its ID, returns domain.ErrPaymentNotFound if missing.
+	Get(ctx context.Context, gateway string, id string) (*Transaction, error)
+
+	// Update stores the new state of the transaction.
+	Update(ctx context.Context, gateway string, tx *Transaction) error
+
+	// NextSequence atomically increments and returns the sequence of the gateway, starting at 1.
+	NextSequence(ctx context.Context, gateway string) (int64, error)
+}
+
+// Orders moves the orders along with their payments.
+type Orders interface {
+	// SyncPayment applies the payment status to the order state machine.
+	SyncPayment(ctx context.Context, namespace string, orderID uuid.UUID, status domain.PaymentStatus) (*domain.Order, error)
+}
+
+// EventBus defines an interface for managing event publishing and subscription.
+type EventBus interface {
+	// Publish sends an event to all subscribers of the specified topic.
+	Publish(ctx context.Context, topic string, event interface{}) error
+}
+
+// AuthService returns information about the current command
+type AuthService interface {
+	// GetUserID retrieves the unique identifier (UUID) of the user from the provided context. Returns an error if retrieval fails.
+	GetUserID(ctx context.Context) (uuid.UUID, error)
+	// CheckPermissions verifies if a user has the required permissions for a specified namespace and action(s). It returns a boolean indicating access and an error if the operation fails.
+	CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error)
+}
+
+type Service struct {
+	config   *Config
+	repo     PaymentRepository
+	gateways map[domain.PaymentMethod]PaymentGateway
+	orders   Orders
+	bus      EventBus
+	auth     AuthService
+}
+
+// NewService creates the payment service, gateways maps each accepted payment method to its provider.
+func NewService(config *Config, repo PaymentRepository, gateways map[domain.PaymentMethod]PaymentGateway, orders Orders, bus EventBus, auth AuthService) *Service {
+	return &Service{
+		config:   config,
+		repo:     repo,
+		gateways: gateways,
+		orders:   orders,
+		bus:      bus,
+		auth:     auth,
+	}
+}
+
+// gateway returns the provider of the payment method.
+func (s *Service) gateway(method domain.PaymentMethod) (PaymentGateway, error) {
+	g, ok := s.gateways[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedPaymentMethod, method)
+	}
+	return g, nil
+}
+
+// checkAccess verifies that the current user is the customer who owns the payment or has the permission.
+func (s *Service) checkAccess(ctx context.Context, namespace string, customerID uuid.UUID, permission string) error {
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if userID == uuid.Nil {
+		return domain.ErrUnauthorized
+	}
+	if userID == customerID {
+		return nil
+	}
+
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, permission)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return domain.ErrUnauthorized
+	}
+	return nil
+}
+
+// checkPermission verifies that the current user has the permission, owning the payment is not enough.
+func (s *Service) checkPermission(ctx context.Context, namespace string, permission string) error {
+	return s.checkAccess(ctx, namespace, uuid.Nil, permission)
+}
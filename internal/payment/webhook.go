@@ -0,0 +1,95 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"log/slog"
+)
+
+// WebhookEvent is a payment status update sent by a provider.
+type WebhookEvent struct {
+	TransactionID string               `json:"transaction_id"`
+	Status        domain.PaymentStatus `json:"status"`
+}
+
+// Settler is implemented by the gateways whose asynchronous charges are settled from the bank notification.
+type Settler interface {
+	Settle(ctx context.Context, transactionID string) (*Transaction, error)
+}
+
+// HandleWebhook applies a status update of the provider of the payment method. The body must be signed
+// with the HMAC-SHA256 of the webhook secret, hex encoded in signature.
+//
+// The gateway remains the source of truth: the transaction status is read back from it, except for the
+// pending charges reported as paid to a Settler. Updates repeating the current status are ignored, so
+// providers may deliver them more than once.
+func (s *Service) HandleWebhook(ctx context.Context, namespace string, method domain.PaymentMethod, body []byte, signature string) error {
+
+	ctx, span := observability.StartSpan(ctx, "payment.HandleWebhook")
+	defer span.End()
+
+	if !s.validSignature(body, signature) {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	gateway, err := s.gateway(method)
+	if err != nil {
+		return err
+	}
+
+	payment, err := s.repo.GetByTransaction(ctx, namespace, method, event.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	var tx *Transaction
+	settler, ok := gateway.(Settler)
+	if ok && event.Status == domain.PaymentStatusCaptured && payment.Status == domain.PaymentStatusPending {
+		tx, err = settler.Settle(ctx, event.TransactionID)
+	} else {
+		tx, err = gateway.Status(ctx, event.TransactionID)
+	}
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "payment webhook received",
+		"payment_id", payment.ID,
+		"transaction_id", event.TransactionID,
+		"reported_status", event.Status,
+		"status", tx.Status,
+	)
+	return s.sync(ctx, namespace, payment, tx)
+}
+
+// Sign returns the signature expected for the webhook body.
+func (s *Service) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(s.config.WebhookSecret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Service) validSignature(body []byte, signature string) bool {
+	if s.config.WebhookSecret == "" {
+		return false
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(s.config.WebhookSecret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
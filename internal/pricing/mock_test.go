@@ -0,0 +1,253 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination mock_test.go --package pricing_test
+//
+
+// Package pricing_test is a generated GoMock package.
+package pricing_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/HBeserra/GoShop/domain"
+	dto "github.com/HBeserra/GoShop/domain/dto"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPriceListRepository is a mock of PriceListRepository interface.
+type MockPriceListRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceListRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPriceListRepositoryMockRecorder is the mock recorder for MockPriceListRepository.
+type MockPriceListRepositoryMockRecorder struct {
+	mock *MockPriceListRepository
+}
+
+// NewMockPriceListRepository creates a new mock instance.
+func NewMockPriceListRepository(ctrl *gomock.Controller) *MockPriceListRepository {
+	mock := &MockPriceListRepository{ctrl: ctrl}
+	mock.recorder = &MockPriceListRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceListRepository) EXPECT() *MockPriceListRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPriceListRepository) Create(ctx context.Context, namespace string, list *domain.PriceList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, namespace, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPriceListRepositoryMockRecorder) Create(ctx, namespace, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPriceListRepository)(nil).Create), ctx, namespace, list)
+}
+
+// Delete mocks base method.
+func (m *MockPriceListRepository) Delete(ctx context.Context, namespace string, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, namespace, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPriceListRepositoryMockRecorder) Delete(ctx, namespace, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPriceListRepository)(nil).Delete), ctx, namespace, id)
+}
+
+// Find mocks base method.
+func (m *MockPriceListRepository) Find(ctx context.Context, namespace string, filter dto.PriceListFilter) ([]*domain.PriceList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, namespace, filter)
+	ret0, _ := ret[0].([]*domain.PriceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockPriceListRepositoryMockRecorder) Find(ctx, namespace, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockPriceListRepository)(nil).Find), ctx, namespace, filter)
+}
+
+// GetByID mocks base method.
+func (m *MockPriceListRepository) GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.PriceList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, namespace, id)
+	ret0, _ := ret[0].(*domain.PriceList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPriceListRepositoryMockRecorder) GetByID(ctx, namespace, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPriceListRepository)(nil).GetByID), ctx, namespace, id)
+}
+
+// Update mocks base method.
+func (m *MockPriceListRepository) Update(ctx context.Context, namespace string, list *domain.PriceList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, namespace, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPriceListRepositoryMockRecorder) Update(ctx, namespace, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPriceListRepository)(nil).Update), ctx, namespace, list)
+}
+
+// MockProductCatalog is a mock of ProductCatalog interface.
+type MockProductCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductCatalogMockRecorder
+	isgomock struct{}
+}
+
+// MockProductCatalogMockRecorder is the mock recorder for MockProductCatalog.
+type MockProductCatalogMockRecorder struct {
+	mock *MockProductCatalog
+}
+
+// NewMockProductCatalog creates a new mock instance.
+func NewMockProductCatalog(ctrl *gomock.Controller) *MockProductCatalog {
+	mock := &MockProductCatalog{ctrl: ctrl}
+	mock.recorder = &MockProductCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductCatalog) EXPECT() *MockProductCatalogMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockProductCatalog) Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", ctx, namespace, filter)
+	ret0, _ := ret[0].([]*domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockProductCatalogMockRecorder) Lookup(ctx, namespace, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockProductCatalog)(nil).Lookup), ctx, namespace, filter)
+}
+
+// MockCustomerGroups is a mock of CustomerGroups interface.
+type MockCustomerGroups struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerGroupsMockRecorder
+	isgomock struct{}
+}
+
+// MockCustomerGroupsMockRecorder is the mock recorder for MockCustomerGroups.
+type MockCustomerGroupsMockRecorder struct {
+	mock *MockCustomerGroups
+}
+
+// NewMockCustomerGroups creates a new mock instance.
+func NewMockCustomerGroups(ctrl *gomock.Controller) *MockCustomerGroups {
+	mock := &MockCustomerGroups{ctrl: ctrl}
+	mock.recorder = &MockCustomerGroupsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomerGroups) EXPECT() *MockCustomerGroupsMockRecorder {
+	return m.recorder
+}
+
+// GetGroups mocks base method.
+func (m *MockCustomerGroups) GetGroups(ctx context.Context, namespace string, customerID uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroups", ctx, namespace, customerID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroups indicates an expected call of GetGroups.
+func (mr *MockCustomerGroupsMockRecorder) GetGroups(ctx, namespace, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroups", reflect.TypeOf((*MockCustomerGroups)(nil).GetGroups), ctx, namespace, customerID)
+}
+
+// MockAuthService is a mock of AuthService interface.
+type MockAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthServiceMockRecorder is the mock recorder for MockAuthService.
+type MockAuthServiceMockRecorder struct {
+	mock *MockAuthService
+}
+
+// NewMockAuthService creates a new mock instance.
+func NewMockAuthService(ctrl *gomock.Controller) *MockAuthService {
+	mock := &MockAuthService{ctrl: ctrl}
+	mock.recorder = &MockAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckPermissions mocks base method.
+func (m *MockAuthService) CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, userID, namespace}
+	for _, a := range permission {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckPermissions", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPermissions indicates an expected call of CheckPermissions.
+func (mr *MockAuthServiceMockRecorder) CheckPermissions(ctx, userID, namespace any, permission ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, userID, namespace}, permission...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPermissions", reflect.TypeOf((*MockAuthService)(nil).CheckPermissions), varargs...)
+}
+
+// GetUserID mocks base method.
+func (m *MockAuthService) GetUserID(ctx context.Context) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserID", ctx)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserID indicates an expected call of GetUserID.
+func (mr *MockAuthServiceMockRecorder) GetUserID(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserID", reflect.TypeOf((*MockAuthService)(nil).GetUserID), ctx)
+}
@@ -0,0 +1,209 @@
+package pricing
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+)
+
+type PriceSource string
+
+const (
+	// PriceSourceCatalog is the regular catalog price.
+	PriceSourceCatalog PriceSource = "catalog"
+	// PriceSourceSale is a scheduled sale price of the catalog.
+	PriceSourceSale PriceSource = "sale"
+	// PriceSourcePriceList is a tier of a customer-group price list.
+	PriceSourcePriceList PriceSource = "price_list"
+)
+
+// Quote is the resolved price of a SKU for a customer and quantity.
+type Quote struct {
+	SKU       string       `json:"sku"`
+	Quantity  int64        `json:"quantity"`
+	ProductID uuid.UUID    `json:"product_id"`
+	VariantID uuid.UUID    `json:"variant_id"`
+	ListPrice currency.BRL `json:"list_price"`
+	UnitPrice currency.BRL `json:"unit_price"`
+	Total     currency.BRL `json:"total"`
+	Rule      MatchedRule  `json:"rule"`
+}
+
+// MatchedRule explains which rule produced the quoted unit price.
+type MatchedRule struct {
+	Source        PriceSource `json:"source"`
+	PriceListID   uuid.UUID   `json:"price_list_id,omitempty"`
+	PriceListName string      `json:"price_list_name,omitempty"`
+	// SKU is the price list entry SKU, it is the product SKU when a variant falls back to its product entry.
+	SKU         string `json:"sku,omitempty"`
+	MinQuantity int64  `json:"min_quantity,omitempty"`
+	Explanation string `json:"explanation"`
+}
+
+// Resolve returns the best applicable unit price of the SKU for the customer and quantity.
+//
+// The precedence order is:
+//  1. The price lists assigned to one of the customer groups and active now. Within a list the entry of
+//     the variant SKU beats the entry of its product SKU, and the tier with the highest minimum quantity
+//     not above the requested quantity applies.
+//  2. Among the matching price lists the highest priority wins, ties are broken by the lowest price and
+//     then by the price list ID, so the result never depends on the storage order.
+//  3. The catalog effective price, either a scheduled sale or the regular price, applies when no price list
+//     matches or when it is lower than the winning price list price.
+//
+// An anonymous customer is identified by uuid.Nil and only gets catalog prices.
+func (s *Service) Resolve(ctx context.Context, namespace string, customerID uuid.UUID, sku string, quantity int64) (*Quote, error) {
+
+	ctx, span := observability.StartSpan(ctx, "pricing.Resolve")
+	defer span.End()
+
+	if quantity < 1 {
+		return nil, domain.ErrInvalidQuantity
+	}
+
+	now := time.Now()
+
+	item, err := s.findSKU(ctx, namespace, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &Quote{
+		SKU:       sku,
+		Quantity:  quantity,
+		ProductID: item.productID,
+		VariantID: item.variantID,
+		ListPrice: item.price,
+	}
+
+	effective := domain.EffectivePriceAt(item.price, item.sales, now)
+	quote.UnitPrice = effective
+	quote.Rule = MatchedRule{Source: PriceSourceCatalog, Explanation: "regular catalog price"}
+	if effective < item.price {
+		quote.Rule = MatchedRule{Source: PriceSourceSale, Explanation: "scheduled sale price"}
+	}
+
+	var groups []string
+	if customerID != uuid.Nil {
+		groups, err = s.groups.GetGroups(ctx, namespace, customerID)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	if len(groups) > 0 {
+		lists, err := s.repo.Find(ctx, namespace, dto.PriceListFilter{Groups: groups, At: now})
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		if rule, price, ok := bestPriceListRule(lists, groups, item, quantity, now); ok {
+			if price <= effective {
+				quote.UnitPrice = price
+				quote.Rule = rule
+			} else {
+				quote.Rule.Explanation += fmt.Sprintf(", lower than the price list %q", rule.PriceListName)
+			}
+		}
+	}
+
+	quote.Total = quote.UnitPrice.MulQuantity(quantity)
+	return quote, nil
+}
+
+// skuItem is the catalog product or variant identified by a SKU.
+type skuItem struct {
+	productID  uuid.UUID
+	variantID  uuid.UUID
+	sku        string
+	productSKU string
+	price      currency.BRL
+	sales      []domain.SalePrice
+}
+
+func (s *Service) findSKU(ctx context.Context, namespace string, sku string) (*skuItem, error) {
+	products, err := s.catalog.Lookup(ctx, namespace, dto.ProductFilter{SKUs: []string{sku}})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range products {
+		if p.SKU == sku {
+			return &skuItem{productID: p.ID, sku: sku, productSKU: p.SKU, price: p.Price, sales: p.Sales}, nil
+		}
+		for _, v := range p.Variants {
+			if v.SKU == sku {
+				return &skuItem{productID: p.ID, variantID: v.ID, sku: sku, productSKU: p.SKU, price: v.Price, sales: v.Sales}, nil
+			}
+		}
+	}
+	return nil, domain.ErrSKUNotFound
+}
+
+// bestPriceListRule applies the price list precedence order, see Resolve.
+func bestPriceListRule(lists []*domain.PriceList, groups []string, item *skuItem, quantity int64, now time.Time) (MatchedRule, currency.BRL, bool) {
+	type candidate struct {
+		list *domain.PriceList
+		sku  string
+		tier domain.PriceTier
+	}
+
+	var candidates []candidate
+	for _, list := range lists {
+		if !list.ActiveAt(now) || !hasAnyGroup(list, groups) {
+			continue
+		}
+		if tier, ok := list.TierFor(item.sku, quantity); ok {
+			candidates = append(candidates, candidate{list: list, sku: item.sku, tier: tier})
+			continue
+		}
+		if item.variantID != uuid.Nil && item.productSKU != "" {
+			if tier, ok := list.TierFor(item.productSKU, quantity); ok {
+				candidates = append(candidates, candidate{list: list, sku: item.productSKU, tier: tier})
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return MatchedRule{}, 0, false
+	}
+
+	best := slices.MinFunc(candidates, func(a, b candidate) int {
+		if a.list.Priority != b.list.Priority {
+			return cmp.Compare(b.list.Priority, a.list.Priority)
+		}
+		if a.tier.Price != b.tier.Price {
+			return cmp.Compare(a.tier.Price, b.tier.Price)
+		}
+		return strings.Compare(a.list.ID.String(), b.list.ID.String())
+	})
+
+	return MatchedRule{
+		Source:        PriceSourcePriceList,
+		PriceListID:   best.list.ID,
+		PriceListName: best.list.Name,
+		SKU:           best.sku,
+		MinQuantity:   best.tier.MinQuantity,
+		Explanation: fmt.Sprintf("price list %q (priority %d), tier from %d units",
+			best.list.Name, best.list.Priority, best.tier.MinQuantity),
+	}, best.tier.Price, true
+}
+
+func hasAnyGroup(list *domain.PriceList, groups []string) bool {
+	for _, g := range groups {
+		if slices.Contains(list.CustomerGroups, g) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package pricing_test
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/pricing"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestResolve(t *testing.T) {
+
+	customerID := uuid.New()
+	product := &domain.Product{
+		ID:    uuid.New(),
+		SKU:   "SHIRT",
+		Price: currency.NewFromFloat(50),
+		Variants: []domain.ProductVariant{
+			{ID: uuid.New(), SKU: "SHIRT-P", Price: currency.NewFromFloat(50)},
+			{ID: uuid.New(), SKU: "SHIRT-G", Price: currency.NewFromFloat(55), Sales: []domain.SalePrice{
+				{Price: currency.NewFromFloat(30), Start: time.Now().Add(-time.Hour)},
+			}},
+		},
+	}
+
+	wholesale := &domain.PriceList{
+		ID:             uuid.New(),
+		Name:           "Wholesale",
+		CustomerGroups: []string{"b2b"},
+		Priority:       1,
+		Entries: []domain.PriceListEntry{
+			{SKU: "SHIRT", Tiers: []domain.PriceTier{
+				{MinQuantity: 1, Price: currency.NewFromFloat(45)},
+				{MinQuantity: 10, Price: currency.NewFromFloat(40)},
+				{MinQuantity: 50, Price: currency.NewFromFloat(35)},
+			}},
+			{SKU: "SHIRT-G", Tiers: []domain.PriceTier{
+				{MinQuantity: 1, Price: currency.NewFromFloat(48)},
+			}},
+		},
+	}
+	partners := &domain.PriceList{
+		ID:             uuid.New(),
+		Name:           "Partners",
+		CustomerGroups: []string{"partners"},
+		Priority:       5,
+		Entries: []domain.PriceListEntry{
+			{SKU: "SHIRT-P", Tiers: []domain.PriceTier{{MinQuantity: 20, Price: currency.NewFromFloat(42)}}},
+		},
+	}
+	expired := &domain.PriceList{
+		ID:             uuid.New(),
+		Name:           "Black Friday",
+		CustomerGroups: []string{"b2b"},
+		Priority:       10,
+		End:            time.Now().Add(-time.Hour),
+		Entries: []domain.PriceListEntry{
+			{SKU: "SHIRT", Tiers: []domain.PriceTier{{MinQuantity: 1, Price: currency.NewFromFloat(10)}}},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		customerID        uuid.UUID
+		groups            []string
+		sku               string
+		quantity          int64
+		expectedPrice     currency.BRL
+		expectedSource    pricing.PriceSource
+		expectedPriceList uuid.UUID
+		expectedMinQty    int64
+	}{
+		{
+			name:           "anonymous customer gets the catalog price",
+			customerID:     uuid.Nil,
+			sku:            "SHIRT",
+			quantity:       100,
+			expectedPrice:  currency.NewFromFloat(50),
+			expectedSource: pricing.PriceSourceCatalog,
+		},
+		{
+			name:              "quantity break",
+			customerID:        customerID,
+			groups:            []string{"b2b"},
+			sku:               "SHIRT",
+			quantity:          12,
+			expectedPrice:     currency.NewFromFloat(40),
+			expectedSource:    pricing.PriceSourcePriceList,
+			expectedPriceList: wholesale.ID,
+			expectedMinQty:    10,
+		},
+		{
+			name:              "variant falls back to the product entry",
+			customerID:        customerID,
+			groups:            []string{"b2b"},
+			sku:               "SHIRT-P",
+			quantity:          50,
+			expectedPrice:     currency.NewFromFloat(35),
+			expectedSource:    pricing.PriceSourcePriceList,
+			expectedPriceList: wholesale.ID,
+			expectedMinQty:    50,
+		},
+		{
+			name:              "highest priority wins",
+			customerID:        customerID,
+			groups:            []string{"b2b", "partners"},
+			sku:               "SHIRT-P",
+			quantity:          50,
+			expectedPrice:     currency.NewFromFloat(42),
+			expectedSource:    pricing.PriceSourcePriceList,
+			expectedPriceList: partners.ID,
+			expectedMinQty:    20,
+		},
+		{
+			name:           "lower catalog sale beats the price list",
+			customerID:     customerID,
+			groups:         []string{"b2b"},
+			sku:            "SHIRT-G",
+			quantity:       1,
+			expectedPrice:  currency.NewFromFloat(30),
+			expectedSource: pricing.PriceSourceSale,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockPriceListRepository(ctrl)
+			mockCatalog := NewMockProductCatalog(ctrl)
+			mockGroups := NewMockCustomerGroups(ctrl)
+			service := pricing.NewService(mockRepo, mockCatalog, mockGroups, NewMockAuthService(ctrl))
+
+			mockCatalog.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+			if tt.customerID != uuid.Nil {
+				mockGroups.EXPECT().GetGroups(gomock.Any(), "ns", tt.customerID).Return(tt.groups, nil)
+				mockRepo.EXPECT().Find(gomock.Any(), "ns", gomock.Any()).Return([]*domain.PriceList{expired, wholesale, partners}, nil)
+			}
+
+			quote, err := service.Resolve(context.Background(), "ns", tt.customerID, tt.sku, tt.quantity)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedPrice, quote.UnitPrice)
+			assert.Equal(t, tt.expectedPrice*currency.BRL(tt.quantity), quote.Total)
+			assert.Equal(t, tt.expectedSource, quote.Rule.Source)
+			assert.Equal(t, tt.expectedPriceList, quote.Rule.PriceListID)
+			assert.Equal(t, tt.expectedMinQty, quote.Rule.MinQuantity)
+			assert.NotEmpty(t, quote.Rule.Explanation)
+		})
+	}
+}
+
+func TestResolve_InvalidInput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockCatalog := NewMockProductCatalog(ctrl)
+	service := pricing.NewService(NewMockPriceListRepository(ctrl), mockCatalog, NewMockCustomerGroups(ctrl), NewMockAuthService(ctrl))
+
+	_, err := service.Resolve(context.Background(), "ns", uuid.Nil, "SKU", 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidQuantity)
+
+	mockCatalog.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return(nil, nil)
+	_, err = service.Resolve(context.Background(), "ns", uuid.Nil, "SKU", 1)
+	assert.ErrorIs(t, err, domain.ErrSKUNotFound)
+}
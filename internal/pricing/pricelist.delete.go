@@ -0,0 +1,30 @@
+package pricing
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+)
+
+func (s *Service) DeletePriceList(ctx context.Context, namespace string, id uuid.UUID) error {
+
+	ctx, span := observability.StartSpan(ctx, "pricing.DeletePriceList")
+	defer span.End()
+
+	userID, err := s.checkPermission(ctx, namespace, "price_list:delete")
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.Delete(ctx, namespace, id)
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "price list deleted",
+		"price_list_id", id,
+		"deleted_by", userID,
+	)
+	return nil
+}
@@ -0,0 +1,73 @@
+package pricing
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// CreatePriceList validates and stores a new price list.
+func (s *Service) CreatePriceList(ctx context.Context, namespace string, list *domain.PriceList) error {
+
+	ctx, span := observability.StartSpan(ctx, "pricing.CreatePriceList")
+	defer span.End()
+
+	userID, err := s.checkPermission(ctx, namespace, "price_list:create")
+	if err != nil {
+		return err
+	}
+
+	if err := Validate(list); err != nil {
+		return err
+	}
+
+	list.ID = uuid.New()
+	list.Namespace = namespace
+	list.CreatedAt = time.Now()
+	list.UpdatedAt = time.Now()
+
+	err = s.repo.Create(ctx, namespace, list)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "price list created",
+		"price_list_id", list.ID,
+		"name", list.Name,
+		"created_by", userID,
+	)
+	return nil
+}
+
+// UpdatePriceList validates and replaces an existing price list.
+func (s *Service) UpdatePriceList(ctx context.Context, namespace string, list *domain.PriceList) error {
+
+	ctx, span := observability.StartSpan(ctx, "pricing.UpdatePriceList")
+	defer span.End()
+
+	userID, err := s.checkPermission(ctx, namespace, "price_list:update")
+	if err != nil {
+		return err
+	}
+
+	if err := Validate(list); err != nil {
+		return err
+	}
+
+	list.UpdatedAt = time.Now()
+	err = s.repo.Update(ctx, namespace, list)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "price list updated",
+		"price_list_id", list.ID,
+		"updated_by", userID,
+	)
+	return nil
+}
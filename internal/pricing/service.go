@@ -0,0 +1,84 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/google/uuid"
+)
+
+// PriceListRepository defines an interface for managing the price lists.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  pricing_test
+type PriceListRepository interface {
+
+	// Find retrieves the price lists matching the filter.
+	Find(ctx context.Context, namespace string, filter dto.PriceListFilter) ([]*domain.PriceList, error)
+
+	// GetByID retrieves a price list by its unique identifier, returns domain.ErrPriceListNotFound if missing.
+	GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.PriceList, error)
+
+	// Create adds a new price list to the repository.
+	Create(ctx context.Context, namespace string, list *domain.PriceList) error
+
+	// Update replaces an existing price list.
+	Update(ctx context.Context, namespace string, list *domain.PriceList) error
+
+	// Delete removes a price list by the provided UUID.
+	Delete(ctx context.Context, namespace string, id uuid.UUID) error
+}
+
+// ProductCatalog gives access to the catalog products, it is implemented by the catalog service.
+type ProductCatalog interface {
+	// Lookup returns the products matching the filter, with the bundle prices derived from their components,
+	// without checking the permissions of the user.
+	Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error)
+}
+
+// CustomerGroups resolves the groups a customer belongs to.
+type CustomerGroups interface {
+	// GetGroups returns the groups of the customer, an anonymous customer (uuid.Nil) has no group.
+	GetGroups(ctx context.Context, namespace string, customerID uuid.UUID) ([]string, error)
+}
+
+// AuthService returns information about the current command
+type AuthService interface {
+	// GetUserID retrieves the unique identifier (UUID) of the user from the provided context. Returns an error if retrieval fails.
+	GetUserID(ctx context.Context) (uuid.UUID, error)
+	// CheckPermissions verifies if a user has the required permissions for a specified namespace and action(s). It returns a boolean indicating access and an error if the operation fails.
+	CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error)
+}
+
+type Service struct {
+	repo    PriceListRepository
+	catalog ProductCatalog
+	groups  CustomerGroups
+	auth    AuthService
+}
+
+func NewService(repo PriceListRepository, catalog ProductCatalog, groups CustomerGroups, auth AuthService) *Service {
+	return &Service{
+		repo:    repo,
+		catalog: catalog,
+		groups:  groups,
+		auth:    auth,
+	}
+}
+
+// checkPermission verifies that the user of the context has the permission in the namespace.
+func (s *Service) checkPermission(ctx context.Context, namespace string, permission ...string) (uuid.UUID, error) {
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, permission...)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return uuid.Nil, domain.ErrUnauthorized
+	}
+	return userID, nil
+}
@@ -0,0 +1,56 @@
+package pricing
+
+import (
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"strings"
+)
+
+// Validate checks the price list, every violation is collected into a *domain.ValidationError.
+func Validate(list *domain.PriceList) error {
+	verr := new(domain.ValidationError)
+
+	if strings.TrimSpace(list.Name) == "" {
+		verr.Add("name", domain.ValidationCodeRequired, domain.ErrInvalidPriceList, nil)
+	}
+
+	if len(list.CustomerGroups) == 0 {
+		verr.Add("customer_groups", domain.ValidationCodeRequired, domain.ErrInvalidPriceList, nil)
+	}
+
+	if !list.Start.IsZero() && !list.End.IsZero() && !list.End.After(list.Start) {
+		verr.Add("end", domain.ValidationCodeMin, domain.ErrInvalidPriceList, map[string]any{"min": list.Start})
+	}
+
+	skus := map[string]bool{}
+	for i, entry := range list.Entries {
+		field := fmt.Sprintf("entries[%d]", i)
+		if entry.SKU == "" {
+			verr.Add(field+".sku", domain.ValidationCodeRequired, domain.ErrInvalidPriceList, nil)
+		} else if skus[entry.SKU] {
+			verr.Add(field+".sku", domain.ValidationCodeInvalid, domain.ErrInvalidPriceList, map[string]any{"duplicate": entry.SKU})
+		}
+		skus[entry.SKU] = true
+
+		if len(entry.Tiers) == 0 {
+			verr.Add(field+".tiers", domain.ValidationCodeRequired, domain.ErrInvalidPriceList, nil)
+		}
+
+		quantities := map[int64]bool{}
+		for j, tier := range entry.Tiers {
+			tierField := fmt.Sprintf("%s.tiers[%d]", field, j)
+			if tier.MinQuantity < 1 {
+				verr.Add(tierField+".min_quantity", domain.ValidationCodeMin, domain.ErrInvalidPriceList, map[string]any{"min": 1})
+			} else if quantities[tier.MinQuantity] {
+				verr.Add(tierField+".min_quantity", domain.ValidationCodeInvalid, domain.ErrInvalidPriceList, map[string]any{"duplicate": tier.MinQuantity})
+			}
+			quantities[tier.MinQuantity] = true
+
+			if tier.Price <= 0 {
+				verr.Add(tierField+".price", domain.ValidationCodeMin, domain.ErrInvalidPriceList, map[string]any{"min": 0})
+			}
+		}
+	}
+
+	return verr.Err()
+}
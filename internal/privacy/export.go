@@ -0,0 +1,100 @@
+package privacy
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"path"
+	"time"
+)
+
+// Export is the personal data held about a user, in a portable format (LGPD art. 18, V).
+type Export struct {
+	RequestID  uuid.UUID                 `json:"request_id"`
+	ExportedAt time.Time                 `json:"exported_at"`
+	User       *domain.User              `json:"user"`
+	Customer   *domain.Customer          `json:"customer"`
+	Orders     []*domain.Order           `json:"orders"`
+	Carts      []*domain.Cart            `json:"carts"`
+	Logs       []*domain.ProductLogEvent `json:"product_logs"`
+	Media      []*domain.Media           `json:"media"`
+}
+
+// collect gathers the personal data of the user of the request.
+func (s *Service) collect(ctx context.Context, namespace string, request *domain.DataSubjectRequest, now time.Time) (*Export, error) {
+	export := &Export{RequestID: request.ID, ExportedAt: now}
+
+	var err error
+	export.User, err = s.users.GetByID(ctx, namespace, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	export.Customer, err = s.customers.GetByUser(ctx, namespace, request.UserID)
+	if err != nil && !errors.Is(err, domain.ErrCustomerNotFound) {
+		return nil, err
+	}
+	export.Orders, err = s.orders.Find(ctx, namespace, dto.OrderFilter{CustomerID: request.UserID})
+	if err != nil {
+		return nil, err
+	}
+	export.Carts, err = s.carts.FindByUser(ctx, namespace, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	export.Logs, err = s.logs.FindByUser(ctx, namespace, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	export.Media, err = s.media.FindByUploader(ctx, namespace, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// writeArchive writes the export as a ZIP file: the data in "data.json" and the uploaded files under "media/<media id>/".
+// The files missing from the storage are skipped, the data still lists them, while any other storage error fails the export.
+func (s *Service) writeArchive(ctx context.Context, w io.Writer, export *Export) error {
+	archive := zip.NewWriter(w)
+
+	f, err := archive.CreateHeader(&zip.FileHeader{Name: "data.json", Method: zip.Deflate, Modified: export.ExportedAt})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(export); err != nil {
+		return err
+	}
+
+	for _, media := range export.Media {
+		data, err := s.storage.Get(media.Filename)
+		if errors.Is(err, domain.ErrFileNotFound) {
+			slog.WarnContext(ctx, "media file missing from the export",
+				"request_id", export.RequestID,
+				"media_id", media.ID,
+				"error", err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		// the base name keeps the stored file name from escaping its directory in the archive
+		name := path.Join("media", media.ID.String(), path.Base("/"+media.Filename))
+		f, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: media.CreatedAt})
+		if err != nil {
+			return err
+		}
+		if _, err = f.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
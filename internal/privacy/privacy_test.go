@@ -0,0 +1,500 @@
+package privacy_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/internal/privacy"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"io"
+	"testing"
+	"time"
+)
+
+type setupParams struct {
+	repoService      *MockRequestRepository
+	usersService     *MockUserRepository
+	customersService *MockCustomerRepository
+	ordersService    *MockOrderRepository
+	cartsService     *MockCartRepository
+	logsService      *MockProductLogRepository
+	mediaService     *MockMediaRepository
+	storageService   *MockFileStorage
+	exportsService   *MockFileStorage
+	busService       *MockEventBus
+	authService      *MockAuthService
+}
+
+// fixture is an open request of a user, processed by an admin.
+type fixture struct {
+	adminID uuid.UUID
+	userID  uuid.UUID
+	request *domain.DataSubjectRequest
+	// archive is the export saved to the exports storage
+	archive []byte
+}
+
+func newFixture(kind domain.DataSubjectRequestType) *fixture {
+	userID := uuid.New()
+	return &fixture{
+		adminID: uuid.New(),
+		userID:  userID,
+		request: &domain.DataSubjectRequest{ID: uuid.New(), UserID: userID, Type: kind, Status: domain.DataSubjectRequestPending},
+	}
+}
+
+// expectAdmin authenticates the call as a user with the "privacy:manage" permission.
+func expectAdmin(t setupParams, f *fixture) {
+	t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.adminID, nil)
+	t.authService.EXPECT().CheckPermissions(gomock.Any(), f.adminID, "ns", "privacy:manage").Return(true, nil)
+}
+
+// expectCollect expects the reads of the personal data of the user, with two uploaded files, one missing from the storage.
+func expectCollect(t setupParams, f *fixture) {
+	t.usersService.EXPECT().GetByID(gomock.Any(), "ns", f.userID).Return(&domain.User{ID: f.userID, Email: "maria@example.com"}, nil)
+	t.customersService.EXPECT().GetByUser(gomock.Any(), "ns", f.userID).Return(&domain.Customer{UserID: f.userID, Document: "52998224725"}, nil)
+	t.ordersService.EXPECT().Find(gomock.Any(), "ns", dto.OrderFilter{CustomerID: f.userID}).Return([]*domain.Order{{ID: uuid.New(), CustomerID: f.userID}}, nil)
+	t.cartsService.EXPECT().FindByUser(gomock.Any(), "ns", f.userID).Return([]*domain.Cart{{ID: uuid.New(), UserID: f.userID}}, nil)
+	t.logsService.EXPECT().FindByUser(gomock.Any(), "ns", f.userID).Return([]*domain.ProductLogEvent{{UserID: f.userID}}, nil)
+	t.mediaService.EXPECT().FindByUploader(gomock.Any(), "ns", f.userID).Return([]*domain.Media{
+		{ID: uuid.New(), Filename: "../avatar.png", UploadedBy: f.userID},
+		{ID: uuid.New(), Filename: "missing.png", UploadedBy: f.userID},
+	}, nil)
+	t.storageService.EXPECT().Get("../avatar.png").Return([]byte("png"), nil)
+	t.storageService.EXPECT().Get("missing.png").Return(nil, domain.ErrFileNotFound)
+}
+
+func TestOpenRequest(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		kind   domain.DataSubjectRequestType
+		setup  func(t setupParams)
+		err    error
+	}{
+		{
+			name:   "own request",
+			userID: userID,
+			kind:   domain.DataSubjectRequestAccess,
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.usersService.EXPECT().GetByID(gomock.Any(), "ns", userID).Return(&domain.User{ID: userID}, nil)
+				t.repoService.EXPECT().Create(gomock.Any(), "ns", gomock.Any()).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "privacy:request_opened", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:   "request of someone else without permission",
+			userID: uuid.New(),
+			kind:   domain.DataSubjectRequestErasure,
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), userID, "ns", "privacy:manage").Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name:   "unknown type",
+			userID: userID,
+			kind:   "rectification",
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(userID, nil)
+			},
+			err: domain.ErrInvalidDataRequest,
+		},
+		{
+			name:   "unauthenticated",
+			userID: userID,
+			kind:   domain.DataSubjectRequestAccess,
+			setup: func(t setupParams) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(uuid.Nil, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockRequestRepository(ctrl)
+			mockUsers := NewMockUserRepository(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+
+			service := privacy.NewService(&privacy.Config{Deadline: 15 * 24 * time.Hour}, mockRepo, mockUsers, nil, nil, nil, nil, nil, nil, nil, mockBus, mockAuth)
+			tt.setup(setupParams{
+				repoService:  mockRepo,
+				usersService: mockUsers,
+				busService:   mockBus,
+				authService:  mockAuth,
+			})
+
+			request, err := service.OpenRequest(context.Background(), "ns", tt.userID, tt.kind)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.DataSubjectRequestPending, request.Status)
+			assert.WithinDuration(t, time.Now().Add(15*24*time.Hour), request.Deadline, time.Second)
+			assert.False(t, request.Overdue(time.Now()))
+			assert.True(t, request.Overdue(request.Deadline.Add(time.Second)))
+		})
+	}
+}
+
+func TestOpenRequests(t *testing.T) {
+	now := time.Now()
+	later := &domain.DataSubjectRequest{ID: uuid.New(), Deadline: now.Add(48 * time.Hour)}
+	overdue := &domain.DataSubjectRequest{ID: uuid.New(), Deadline: now.Add(-time.Hour)}
+
+	tests := []struct {
+		name     string
+		setup    func(t setupParams, f *fixture)
+		err      error
+		expected []*domain.DataSubjectRequest
+	}{
+		{
+			name: "closest deadline first",
+			setup: func(t setupParams, f *fixture) {
+				expectAdmin(t, f)
+				t.repoService.EXPECT().FindOpen(gomock.Any(), "ns").Return([]*domain.DataSubjectRequest{later, overdue}, nil)
+			},
+			expected: []*domain.DataSubjectRequest{overdue, later},
+		},
+		{
+			name: "unauthorized",
+			setup: func(t setupParams, f *fixture) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), f.userID, "ns", "privacy:manage").Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockRequestRepository(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+
+			service := privacy.NewService(&privacy.Config{}, mockRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockAuth)
+			tt.setup(setupParams{repoService: mockRepo, authService: mockAuth}, newFixture(domain.DataSubjectRequestAccess))
+
+			requests, err := service.OpenRequests(context.Background(), "ns")
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, requests)
+		})
+	}
+}
+
+func TestProcessRequest(t *testing.T) {
+	failure := errors.New("database unavailable")
+
+	tests := []struct {
+		name     string
+		kind     domain.DataSubjectRequestType
+		setup    func(t setupParams, f *fixture)
+		err      error
+		expected func(t *testing.T, f *fixture)
+	}{
+		{
+			name: "access request exported to the private storage",
+			kind: domain.DataSubjectRequestAccess,
+			setup: func(t setupParams, f *fixture) {
+				expectAdmin(t, f)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", f.request.ID).Return(f.request, nil)
+				expectCollect(t, f)
+				t.exportsService.EXPECT().Save(gomock.Any(), "ns/"+f.request.ID.String()+".zip").DoAndReturn(func(r io.Reader, filename string) (string, error) {
+					var err error
+					f.archive, err = io.ReadAll(r)
+					return filename, err
+				})
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", f.request).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "privacy:request_completed", gomock.Any()).Return(nil)
+			},
+			expected: func(t *testing.T, f *fixture) {
+				assert.Equal(t, domain.DataSubjectRequestCompleted, f.request.Status)
+				assert.NotNil(t, f.request.CompletedAt)
+				assert.Equal(t, "ns/"+f.request.ID.String()+".zip", f.request.ExportFile)
+
+				reader, err := zip.NewReader(bytes.NewReader(f.archive), int64(len(f.archive)))
+				require.NoError(t, err)
+				require.Len(t, reader.File, 2)
+				assert.Regexp(t, `^media/[0-9a-f-]{36}/avatar\.png$`, reader.File[1].Name)
+
+				file, err := reader.File[0].Open()
+				require.NoError(t, err)
+				var export privacy.Export
+				require.NoError(t, json.NewDecoder(file).Decode(&export))
+				assert.Equal(t, "maria@example.com", export.User.Email)
+				assert.Equal(t, domain.Document("52998224725"), export.Customer.Document)
+				assert.Len(t, export.Orders, 1)
+				assert.Len(t, export.Carts, 1)
+				assert.Len(t, export.Logs, 1)
+				assert.Len(t, export.Media, 2)
+			},
+		},
+		{
+			name: "export storage failing before reading the archive",
+			kind: domain.DataSubjectRequestAccess,
+			setup: func(t setupParams, f *fixture) {
+				expectAdmin(t, f)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", f.request.ID).Return(f.request, nil)
+				t.usersService.EXPECT().GetByID(gomock.Any(), "ns", f.userID).Return(&domain.User{ID: f.userID}, nil)
+				t.customersService.EXPECT().GetByUser(gomock.Any(), "ns", f.userID).Return(nil, domain.ErrCustomerNotFound)
+				t.ordersService.EXPECT().Find(gomock.Any(), "ns", dto.OrderFilter{CustomerID: f.userID}).Return(nil, nil)
+				t.cartsService.EXPECT().FindByUser(gomock.Any(), "ns", f.userID).Return(nil, nil)
+				t.logsService.EXPECT().FindByUser(gomock.Any(), "ns", f.userID).Return(nil, nil)
+				t.mediaService.EXPECT().FindByUploader(gomock.Any(), "ns", f.userID).Return(nil, nil)
+				t.exportsService.EXPECT().Save(gomock.Any(), gomock.Any()).Return("", failure)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", f.request).Return(nil)
+			},
+			err: failure,
+			expected: func(t *testing.T, f *fixture) {
+				assert.Equal(t, domain.DataSubjectRequestFailed, f.request.Status)
+				assert.Empty(t, f.request.ExportFile)
+			},
+		},
+		{
+			name: "media storage failing",
+			kind: domain.DataSubjectRequestAccess,
+			setup: func(t setupParams, f *fixture) {
+				expectAdmin(t, f)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", f.request.ID).Return(f.request, nil)
+				t.usersService.EXPECT().GetByID(gomock.Any(), "ns", f.userID).Return(&domain.User{ID: f.userID}, nil)
+				t.customersService.EXPECT().GetByUser(gomock.Any(), "ns", f.userID).Return(nil, domain.ErrCustomerNotFound)
+				t.ordersService.EXPECT().Find(gomock.Any(), "ns", dto.OrderFilter{CustomerID: f.userID}).Return(nil, nil)
+				t.cartsService.EXPECT().FindByUser(gomock.Any(), "ns", f.userID).Return(nil, nil)
+				t.logsService.EXPECT().FindByUser(gomock.Any(), "ns", f.userID).Return(nil, nil)
+				t.mediaService.EXPECT().FindByUploader(gomock.Any(), "ns", f.userID).Return([]*domain.Media{
+					{ID: uuid.New(), Filename: "avatar.png", UploadedBy: f.userID},
+				}, nil)
+				t.storageService.EXPECT().Get("avatar.png").Return(nil, failure)
+				t.exportsService.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(r io.Reader, filename string) (string, error) {
+					_, err := io.ReadAll(r)
+					return "", err
+				})
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", f.request).Return(nil)
+			},
+			err: failure,
+			expected: func(t *testing.T, f *fixture) {
+				assert.Equal(t, domain.DataSubjectRequestFailed, f.request.Status)
+				assert.Empty(t, f.request.ExportFile)
+			},
+		},
+		{
+			name: "erasure request",
+			kind: domain.DataSubjectRequestErasure,
+			setup: func(t setupParams, f *fixture) {
+				previous := &domain.DataSubjectRequest{ID: uuid.New(), UserID: f.userID, Type: domain.DataSubjectRequestAccess, ExportFile: "ns/previous.zip"}
+				user := &domain.User{ID: f.userID, Name: "Maria Silva", Email: "maria@example.com"}
+				customer := &domain.Customer{UserID: f.userID, Document: "52998224725", Phone: "+5511987654321"}
+				cartID := uuid.New()
+
+				var pseudonym uuid.UUID
+				expectAdmin(t, f)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", f.request.ID).Return(f.request, nil)
+				t.logsService.EXPECT().ReplaceUser(gomock.Any(), "ns", f.userID, gomock.Not(f.userID)).DoAndReturn(func(_ context.Context, _ string, _, p uuid.UUID) error {
+					pseudonym = p
+					return nil
+				})
+				t.mediaService.EXPECT().ReplaceUploader(gomock.Any(), "ns", f.userID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, _, p uuid.UUID) error {
+					if p != pseudonym {
+						return errors.New("another pseudonym")
+					}
+					return nil
+				})
+				t.cartsService.EXPECT().FindByUser(gomock.Any(), "ns", f.userID).Return([]*domain.Cart{{ID: cartID}}, nil)
+				t.cartsService.EXPECT().Delete(gomock.Any(), "ns", cartID).Return(nil)
+				t.repoService.EXPECT().FindByUser(gomock.Any(), "ns", f.userID).Return([]*domain.DataSubjectRequest{previous, f.request}, nil)
+				t.exportsService.EXPECT().Delete("ns/previous.zip").Return(nil)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", previous).DoAndReturn(func(_ context.Context, _ string, r *domain.DataSubjectRequest) error {
+					if r.ExportFile != "" {
+						return errors.New("export file kept")
+					}
+					return nil
+				})
+				t.customersService.EXPECT().GetByUser(gomock.Any(), "ns", f.userID).Return(customer, nil)
+				t.customersService.EXPECT().Update(gomock.Any(), "ns", customer).DoAndReturn(func(_ context.Context, _ string, c *domain.Customer) error {
+					if c.Document != "" || c.AnonymizedAt == nil {
+						return errors.New("customer not anonymized")
+					}
+					return nil
+				})
+				t.usersService.EXPECT().GetByID(gomock.Any(), "ns", f.userID).Return(user, nil)
+				t.usersService.EXPECT().Update(gomock.Any(), "ns", user).DoAndReturn(func(_ context.Context, _ string, u *domain.User) error {
+					if u.Name != "" || u.Email == "maria@example.com" {
+						return errors.New("user not anonymized")
+					}
+					return nil
+				})
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", f.request).Return(nil)
+				t.busService.EXPECT().Publish(gomock.Any(), "privacy:request_completed", gomock.Any()).Return(nil)
+			},
+			expected: func(t *testing.T, f *fixture) {
+				assert.Equal(t, domain.DataSubjectRequestCompleted, f.request.Status)
+			},
+		},
+		{
+			name: "failed erasure can be processed again",
+			kind: domain.DataSubjectRequestErasure,
+			setup: func(t setupParams, f *fixture) {
+				expectAdmin(t, f)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", f.request.ID).Return(f.request, nil)
+				t.logsService.EXPECT().ReplaceUser(gomock.Any(), "ns", f.userID, gomock.Any()).Return(failure)
+				t.repoService.EXPECT().Update(gomock.Any(), "ns", f.request).Return(nil)
+			},
+			err: failure,
+			expected: func(t *testing.T, f *fixture) {
+				assert.Equal(t, domain.DataSubjectRequestFailed, f.request.Status)
+				assert.Equal(t, failure.Error(), f.request.Error)
+				assert.True(t, f.request.Open())
+			},
+		},
+		{
+			name: "completed request",
+			kind: domain.DataSubjectRequestErasure,
+			setup: func(t setupParams, f *fixture) {
+				f.request.Status = domain.DataSubjectRequestCompleted
+				expectAdmin(t, f)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", f.request.ID).Return(f.request, nil)
+			},
+			err: domain.ErrDataRequestCompleted,
+		},
+		{
+			name: "unauthorized",
+			kind: domain.DataSubjectRequestAccess,
+			setup: func(t setupParams, f *fixture) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), f.userID, "ns", "privacy:manage").Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockRequestRepository(ctrl)
+			mockUsers := NewMockUserRepository(ctrl)
+			mockCustomers := NewMockCustomerRepository(ctrl)
+			mockOrders := NewMockOrderRepository(ctrl)
+			mockCarts := NewMockCartRepository(ctrl)
+			mockLogs := NewMockProductLogRepository(ctrl)
+			mockMedia := NewMockMediaRepository(ctrl)
+			mockStorage := NewMockFileStorage(ctrl)
+			mockExports := NewMockFileStorage(ctrl)
+			mockBus := NewMockEventBus(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+
+			service := privacy.NewService(&privacy.Config{Deadline: 15 * 24 * time.Hour}, mockRepo, mockUsers, mockCustomers, mockOrders,
+				mockCarts, mockLogs, mockMedia, mockStorage, mockExports, mockBus, mockAuth)
+
+			f := newFixture(tt.kind)
+			tt.setup(setupParams{
+				repoService:      mockRepo,
+				usersService:     mockUsers,
+				customersService: mockCustomers,
+				ordersService:    mockOrders,
+				cartsService:     mockCarts,
+				logsService:      mockLogs,
+				mediaService:     mockMedia,
+				storageService:   mockStorage,
+				exportsService:   mockExports,
+				busService:       mockBus,
+				authService:      mockAuth,
+			}, f)
+
+			_, err := service.ProcessRequest(context.Background(), "ns", f.request.ID)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.expected != nil {
+				tt.expected(t, f)
+			}
+		})
+	}
+}
+
+func TestWriteExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t setupParams, f *fixture)
+		err      error
+		expected string
+	}{
+		{
+			name: "own export",
+			setup: func(t setupParams, f *fixture) {
+				f.request.ExportFile = "ns/export.zip"
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", f.request.ID).Return(f.request, nil)
+				t.exportsService.EXPECT().Get("ns/export.zip").Return([]byte("zip"), nil)
+			},
+			expected: "zip",
+		},
+		{
+			name: "export of someone else without permission",
+			setup: func(t setupParams, f *fixture) {
+				f.request.ExportFile = "ns/export.zip"
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.adminID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", f.request.ID).Return(f.request, nil)
+				t.authService.EXPECT().CheckPermissions(gomock.Any(), f.adminID, "ns", "privacy:manage").Return(false, nil)
+			},
+			err: domain.ErrUnauthorized,
+		},
+		{
+			name: "request without export",
+			setup: func(t setupParams, f *fixture) {
+				t.authService.EXPECT().GetUserID(gomock.Any()).Return(f.userID, nil)
+				t.repoService.EXPECT().GetByID(gomock.Any(), "ns", f.request.ID).Return(f.request, nil)
+			},
+			err: domain.ErrExportNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockRequestRepository(ctrl)
+			mockStorage := NewMockFileStorage(ctrl)
+			mockExports := NewMockFileStorage(ctrl)
+			mockAuth := NewMockAuthService(ctrl)
+
+			service := privacy.NewService(&privacy.Config{}, mockRepo, nil, nil, nil, nil, nil, nil, mockStorage, mockExports, nil, mockAuth)
+
+			f := newFixture(domain.DataSubjectRequestAccess)
+			tt.setup(setupParams{
+				repoService:    mockRepo,
+				storageService: mockStorage,
+				exportsService: mockExports,
+				authService:    mockAuth,
+			}, f)
+
+			var buf bytes.Buffer
+			err := service.WriteExport(context.Background(), "ns", f.request.ID, &buf)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package privacy
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// OpenRequest registers a request of the user with the deadline of the Config. Users open their own requests,
+// opening a request on behalf of another user requires the "privacy:manage" permission.
+func (s *Service) OpenRequest(ctx context.Context, namespace string, userID uuid.UUID, kind domain.DataSubjectRequestType) (*domain.DataSubjectRequest, error) {
+
+	ctx, span := observability.StartSpan(ctx, "privacy.OpenRequest")
+	defer span.End()
+
+	currentID, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userID != currentID {
+		if err = s.checkPermission(ctx, currentID, namespace, "privacy:manage"); err != nil {
+			return nil, err
+		}
+	}
+	if kind != domain.DataSubjectRequestAccess && kind != domain.DataSubjectRequestErasure {
+		verr := new(domain.ValidationError)
+		verr.Add("type", domain.ValidationCodeOneOf, domain.ErrInvalidDataRequest,
+			map[string]any{"allowed": []domain.DataSubjectRequestType{domain.DataSubjectRequestAccess, domain.DataSubjectRequestErasure}})
+		return nil, verr
+	}
+	if _, err = s.users.GetByID(ctx, namespace, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	request := &domain.DataSubjectRequest{
+		ID:          uuid.New(),
+		Namespace:   namespace,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		UserID:      userID,
+		Type:        kind,
+		Status:      domain.DataSubjectRequestPending,
+		RequestedBy: currentID,
+		Deadline:    now.Add(s.config.Deadline),
+	}
+	err = s.repo.Create(ctx, namespace, request)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	err = s.bus.Publish(ctx, "privacy:request_opened", events.DataSubjectRequestOpened{
+		ID:          request.ID,
+		UserID:      request.UserID,
+		Type:        string(request.Type),
+		Deadline:    request.Deadline,
+		RequestedBy: currentID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to publish privacy:request_opened event",
+			"request_id", request.ID,
+			"error", err)
+	}
+
+	slog.InfoContext(ctx, "data subject request opened",
+		"request_id", request.ID,
+		"type", request.Type,
+		"deadline", request.Deadline,
+	)
+	return request, nil
+}
+
+// GetRequest returns the request. Users can follow their own requests, anyone else needs the "privacy:manage" permission.
+func (s *Service) GetRequest(ctx context.Context, namespace string, id uuid.UUID) (*domain.DataSubjectRequest, error) {
+
+	ctx, span := observability.StartSpan(ctx, "privacy.GetRequest")
+	defer span.End()
+
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := s.repo.GetByID(ctx, namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.UserID != userID {
+		if err = s.checkPermission(ctx, userID, namespace, "privacy:manage"); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
+
+// OpenRequests lists the requests still to be answered, the closest deadline first, to track the overdue ones.
+// It requires the "privacy:manage" permission.
+func (s *Service) OpenRequests(ctx context.Context, namespace string) ([]*domain.DataSubjectRequest, error) {
+
+	ctx, span := observability.StartSpan(ctx, "privacy.OpenRequests")
+	defer span.End()
+
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkPermission(ctx, userID, namespace, "privacy:manage"); err != nil {
+		return nil, err
+	}
+
+	requests, err := s.repo.FindOpen(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(requests, func(a, b *domain.DataSubjectRequest) int {
+		return a.Deadline.Compare(b.Deadline)
+	})
+	return requests, nil
+}
@@ -0,0 +1,204 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/events"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// ProcessRequest answers the request: an access request exports the personal data of the user to a ZIP file,
+// see WriteExport, an erasure request erases them. A request which fails is marked as failed and can be processed again.
+// It requires the "privacy:manage" permission.
+func (s *Service) ProcessRequest(ctx context.Context, namespace string, id uuid.UUID) (*domain.DataSubjectRequest, error) {
+
+	ctx, span := observability.StartSpan(ctx, "privacy.ProcessRequest")
+	defer span.End()
+
+	userID, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkPermission(ctx, userID, namespace, "privacy:manage"); err != nil {
+		return nil, err
+	}
+
+	request, err := s.repo.GetByID(ctx, namespace, id)
+	if err != nil {
+		return nil, err
+	}
+	if !request.Open() {
+		return nil, domain.ErrDataRequestCompleted
+	}
+
+	now := time.Now()
+	switch request.Type {
+	case domain.DataSubjectRequestAccess:
+		err = s.export(ctx, namespace, request, now)
+	case domain.DataSubjectRequestErasure:
+		err = s.erase(ctx, namespace, request, now)
+	default:
+		err = fmt.Errorf("%w: unknown type %q", domain.ErrInvalidDataRequest, request.Type)
+	}
+
+	request.UpdatedAt = now
+	if err != nil {
+		span.RecordError(err)
+		request.Status = domain.DataSubjectRequestFailed
+		request.Error = err.Error()
+		if updateErr := s.repo.Update(ctx, namespace, request); updateErr != nil {
+			slog.ErrorContext(ctx, "failed to update data subject request",
+				"request_id", request.ID,
+				"error", updateErr)
+		}
+		return nil, err
+	}
+
+	request.Status = domain.DataSubjectRequestCompleted
+	request.CompletedAt = &now
+	request.Error = ""
+	err = s.repo.Update(ctx, namespace, request)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	err = s.bus.Publish(ctx, "privacy:request_completed", events.DataSubjectRequestCompleted{
+		ID:          request.ID,
+		UserID:      request.UserID,
+		Type:        string(request.Type),
+		CompletedOn: now,
+		CompletedBy: userID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to publish privacy:request_completed event",
+			"request_id", request.ID,
+			"error", err)
+	}
+
+	slog.InfoContext(ctx, "data subject request completed",
+		"request_id", request.ID,
+		"type", request.Type,
+		"overdue", now.After(request.Deadline),
+		"completed_by", userID,
+	)
+	return request, nil
+}
+
+// export stores the ZIP file of the personal data of the user. The archive is streamed to the storage
+// while it is written, the media files it holds are never all in memory.
+func (s *Service) export(ctx context.Context, namespace string, request *domain.DataSubjectRequest, now time.Time) error {
+	data, err := s.collect(ctx, namespace, request, now)
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(s.writeArchive(ctx, pw, data))
+	}()
+
+	filename := fmt.Sprintf("%s/%s.zip", namespace, request.ID)
+	_, err = s.exports.Save(pr, filename)
+	// unblocks the writer when the storage stops reading early
+	pr.CloseWithError(err)
+	if err != nil {
+		return err
+	}
+	request.ExportFile = filename
+	return nil
+}
+
+// erase erases the personal data of the user. The audit logs and the media keep their records under a random
+// pseudonym of the user, the carts and previous exports are deleted, and the customer profile and user are anonymized.
+// The orders, and the payments and invoices of them, are financial records the store must retain (LGPD art. 16, I):
+// they keep the ID of the anonymized user.
+func (s *Service) erase(ctx context.Context, namespace string, request *domain.DataSubjectRequest, now time.Time) error {
+	// the pseudonym is not stored, the records cannot be linked back to the user
+	pseudonym := uuid.New()
+	if err := s.logs.ReplaceUser(ctx, namespace, request.UserID, pseudonym); err != nil {
+		return err
+	}
+	if err := s.media.ReplaceUploader(ctx, namespace, request.UserID, pseudonym); err != nil {
+		return err
+	}
+
+	carts, err := s.carts.FindByUser(ctx, namespace, request.UserID)
+	if err != nil {
+		return err
+	}
+	for _, cart := range carts {
+		if err = s.carts.Delete(ctx, namespace, cart.ID); err != nil {
+			return err
+		}
+	}
+
+	requests, err := s.repo.FindByUser(ctx, namespace, request.UserID)
+	if err != nil {
+		return err
+	}
+	for _, previous := range requests {
+		if previous.ExportFile == "" {
+			continue
+		}
+		if err = s.exports.Delete(previous.ExportFile); err != nil {
+			return err
+		}
+		previous.ExportFile = ""
+		previous.UpdatedAt = now
+		if err = s.repo.Update(ctx, namespace, previous); err != nil {
+			return err
+		}
+	}
+
+	customer, err := s.customers.GetByUser(ctx, namespace, request.UserID)
+	switch {
+	case errors.Is(err, domain.ErrCustomerNotFound):
+	case err != nil:
+		return err
+	case customer.AnonymizedAt == nil:
+		customer.Anonymize(now)
+		if err = s.customers.Update(ctx, namespace, customer); err != nil {
+			return err
+		}
+	}
+
+	// the user is anonymized last, so a failed erasure can be processed again
+	user, err := s.users.GetByID(ctx, namespace, request.UserID)
+	if err != nil {
+		return err
+	}
+	user.Anonymize(now)
+	return s.users.Update(ctx, namespace, user)
+}
+
+// WriteExport writes the ZIP file of a completed access request to w. Users can download their own exports,
+// anyone else needs the "privacy:manage" permission.
+func (s *Service) WriteExport(ctx context.Context, namespace string, id uuid.UUID, w io.Writer) error {
+
+	ctx, span := observability.StartSpan(ctx, "privacy.WriteExport")
+	defer span.End()
+
+	request, err := s.GetRequest(ctx, namespace, id)
+	if err != nil {
+		return err
+	}
+	if request.ExportFile == "" {
+		return domain.ErrExportNotFound
+	}
+
+	archive, err := s.exports.Get(request.ExportFile)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	_, err = w.Write(archive)
+	return err
+}
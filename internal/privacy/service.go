@@ -0,0 +1,188 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/google/uuid"
+	"io"
+	"time"
+)
+
+type Config struct {
+	// Deadline is the time given to answer a request, 15 days by the LGPD (art. 19, II).
+	Deadline time.Duration `env:"PRIVACY_DEADLINE" envDefault:"360h"`
+	// ExportPath is the directory of the private storage of the exports, it must never be served.
+	ExportPath string `env:"PRIVACY_EXPORT_PATH" envDefault:"data/privacy"`
+}
+
+// RequestRepository defines an interface for managing the data subject requests.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  privacy_test
+type RequestRepository interface {
+
+	// Create stores a new request.
+	Create(ctx context.Context, namespace string, request *domain.DataSubjectRequest) error
+
+	// Update stores the changes of the request.
+	Update(ctx context.Context, namespace string, request *domain.DataSubjectRequest) error
+
+	// GetByID retrieves a request by its unique identifier, returns domain.ErrDataRequestNotFound if missing.
+	GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.DataSubjectRequest, error)
+
+	// FindOpen retrieves the requests not completed yet.
+	FindOpen(ctx context.Context, namespace string) ([]*domain.DataSubjectRequest, error)
+
+	// FindByUser retrieves every request of the user.
+	FindByUser(ctx context.Context, namespace string, userID uuid.UUID) ([]*domain.DataSubjectRequest, error)
+}
+
+// UserRepository gives access to the users, the data subjects.
+type UserRepository interface {
+
+	// GetByID retrieves a user by its unique identifier, returns domain.ErrUserNotFound if missing.
+	GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.User, error)
+
+	// Update stores the changes of the user.
+	Update(ctx context.Context, namespace string, user *domain.User) error
+}
+
+// CustomerRepository gives access to the customer profiles of the users.
+type CustomerRepository interface {
+
+	// GetByUser retrieves the customer profile of the user, returns domain.ErrCustomerNotFound if missing.
+	GetByUser(ctx context.Context, namespace string, userID uuid.UUID) (*domain.Customer, error)
+
+	// Update stores the changes of the customer profile.
+	Update(ctx context.Context, namespace string, customer *domain.Customer) error
+}
+
+// OrderRepository gives access to the orders, which are financial records and are never erased.
+type OrderRepository interface {
+	Find(ctx context.Context, namespace string, filter dto.OrderFilter) ([]*domain.Order, error)
+}
+
+// CartRepository gives access to the carts of the users.
+type CartRepository interface {
+
+	// FindByUser retrieves the carts owned by the user.
+	FindByUser(ctx context.Context, namespace string, userID uuid.UUID) ([]*domain.Cart, error)
+
+	// Delete removes the cart.
+	Delete(ctx context.Context, namespace string, id uuid.UUID) error
+}
+
+// ProductLogRepository gives access to the product audit log, see domain.ProductLogEvent.
+type ProductLogRepository interface {
+
+	// FindByUser retrieves the log events recorded for the actions of the user.
+	FindByUser(ctx context.Context, namespace string, userID uuid.UUID) ([]*domain.ProductLogEvent, error)
+
+	// ReplaceUser replaces the user of the log events, keeping the log while unlinking it from the user.
+	ReplaceUser(ctx context.Context, namespace string, userID, pseudonym uuid.UUID) error
+}
+
+// MediaRepository gives access to the media uploaded by the users.
+type MediaRepository interface {
+
+	// FindByUploader retrieves the media uploaded by the user.
+	FindByUploader(ctx context.Context, namespace string, userID uuid.UUID) ([]*domain.Media, error)
+
+	// ReplaceUploader replaces the uploader of the media, the media themselves are kept as the catalog uses them.
+	ReplaceUploader(ctx context.Context, namespace string, userID, pseudonym uuid.UUID) error
+}
+
+// FileStorage holds files: the media files uploaded by the users, and the exports in a private storage.
+type FileStorage interface {
+	Save(file io.Reader, filename string) (string, error)
+	Get(filename string) ([]byte, error)
+	GetURL(filename string) (string, error)
+	Delete(filename string) error
+}
+
+// EventBus defines an interface for managing event publishing and subscription.
+type EventBus interface {
+	// Publish sends an event to all subscribers of the specified topic.
+	Publish(ctx context.Context, topic string, event interface{}) error
+}
+
+// AuthService returns information about the current command
+type AuthService interface {
+	// GetUserID retrieves the unique identifier (UUID) of the user from the provided context. Returns an error if retrieval fails.
+	GetUserID(ctx context.Context) (uuid.UUID, error)
+	// CheckPermissions verifies if a user has the required permissions for a specified namespace and action(s). It returns a boolean indicating access and an error if the operation fails.
+	CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error)
+}
+
+// Service answers the data subject requests of the LGPD: it exports the personal data of a user and erases them.
+// The users open and follow their own requests, processing them requires the "privacy:manage" permission.
+// The exports are kept in a storage of their own which is never served, they are downloaded with WriteExport.
+type Service struct {
+	config    *Config
+	repo      RequestRepository
+	users     UserRepository
+	customers CustomerRepository
+	orders    OrderRepository
+	carts     CartRepository
+	logs      ProductLogRepository
+	media     MediaRepository
+	storage   FileStorage
+	exports   FileStorage
+	bus       EventBus
+	auth      AuthService
+}
+
+func NewService(
+	config *Config,
+	repo RequestRepository,
+	users UserRepository,
+	customers CustomerRepository,
+	orders OrderRepository,
+	carts CartRepository,
+	logs ProductLogRepository,
+	media MediaRepository,
+	storage FileStorage,
+	exports FileStorage,
+	bus EventBus,
+	auth AuthService,
+) *Service {
+	return &Service{
+		config:    config,
+		repo:      repo,
+		users:     users,
+		customers: customers,
+		orders:    orders,
+		carts:     carts,
+		logs:      logs,
+		media:     media,
+		storage:   storage,
+		exports:   exports,
+		bus:       bus,
+		auth:      auth,
+	}
+}
+
+// currentUser returns the authenticated user of the context.
+func (s *Service) currentUser(ctx context.Context) (uuid.UUID, error) {
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if userID == uuid.Nil {
+		return uuid.Nil, domain.ErrUnauthorized
+	}
+	return userID, nil
+}
+
+// checkPermission verifies that the user has the permission in the namespace.
+func (s *Service) checkPermission(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) error {
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, permission...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return domain.ErrUnauthorized
+	}
+	return nil
+}
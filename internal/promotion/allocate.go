@@ -0,0 +1,22 @@
+package promotion
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+)
+
+// allocate distributes the amount across the weights proportionally with currency.BRL.Allocate: each part
+// gets the floor of its share and the remaining cents go to the largest remainders first.
+// The parts always sum to the amount, and a zero or negative weight always gets a zero part.
+func allocate(amount currency.BRL, weights []currency.BRL) []currency.BRL {
+	ratios := make([]int64, len(weights))
+	for i, w := range weights {
+		ratios[i] = max(w.Cents(), 0)
+	}
+
+	parts, err := amount.Allocate(ratios...)
+	if err != nil {
+		// every weight is zero
+		return make([]currency.BRL, len(weights))
+	}
+	return parts
+}
@@ -0,0 +1,39 @@
+package promotion
+
+import (
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"testing"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   currency.BRL
+		weights  []currency.BRL
+		expected []currency.BRL
+	}{
+		{"proportional", 1000, []currency.BRL{3000, 1000}, []currency.BRL{750, 250}},
+		{"remainder to the largest fraction", 100, []currency.BRL{1, 1, 1}, []currency.BRL{34, 33, 33}},
+		{"remainder ordering", 10, []currency.BRL{333, 333, 334}, []currency.BRL{3, 3, 4}},
+		{"zero weight gets nothing", 500, []currency.BRL{0, 2000, 0}, []currency.BRL{0, 500, 0}},
+		{"no weight", 500, []currency.BRL{0, 0}, []currency.BRL{0, 0}},
+		{"zero amount", 0, []currency.BRL{100, 200}, []currency.BRL{0, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := allocate(test.amount, test.weights)
+
+			var sum currency.BRL
+			for i, v := range result {
+				sum += v
+				if v != test.expected[i] {
+					t.Errorf("at index %d: expected: %v, got: %v", i, test.expected[i], v)
+				}
+			}
+			if test.name != "no weight" && sum != test.amount {
+				t.Errorf("expected the parts to sum %v, got %v", test.amount, sum)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package promotion
+
+import (
+	"cmp"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+)
+
+const fullPercentBps = 10_000
+
+// LineItem is an item of the cart or order being discounted.
+type LineItem struct {
+	ProductID  uuid.UUID    `json:"product_id"`
+	VariantID  uuid.UUID    `json:"variant_id"`
+	Categories []string     `json:"categories"`
+	Quantity   int64        `json:"quantity"`
+	UnitPrice  currency.BRL `json:"unit_price"`
+}
+
+// Total returns the line total before discounts.
+func (i LineItem) Total() currency.BRL {
+	return i.UnitPrice.MulQuantity(i.Quantity)
+}
+
+// Input is the cart or order the promotions are evaluated against.
+type Input struct {
+	CustomerID     uuid.UUID    `json:"customer_id"`
+	CustomerGroups []string     `json:"customer_groups"`
+	Items          []LineItem   `json:"items"`
+	Shipping       currency.BRL `json:"shipping"`
+	// Coupons are the codes entered by the customer.
+	Coupons []string  `json:"coupons"`
+	At      time.Time `json:"at"`
+}
+
+// AppliedPromotion is a promotion granted to the input, with its discount allocated across the line items.
+type AppliedPromotion struct {
+	PromotionID uuid.UUID `json:"promotion_id"`
+	Name        string    `json:"name"`
+	Code        string    `json:"code,omitempty"`
+	// ItemDiscounts holds the discount of each line item, in the order of Input.Items.
+	ItemDiscounts    []currency.BRL `json:"item_discounts"`
+	Discount         currency.BRL   `json:"discount"`
+	ShippingDiscount currency.BRL   `json:"shipping_discount"`
+}
+
+// RejectedCoupon is a coupon code entered by the customer which could not be applied.
+type RejectedCoupon struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+	Err    error  `json:"-"`
+}
+
+// Result is the outcome of the promotions evaluation.
+type Result struct {
+	Subtotal currency.BRL       `json:"subtotal"`
+	Applied  []AppliedPromotion `json:"applied"`
+	Rejected []RejectedCoupon   `json:"rejected"`
+	// ItemDiscounts holds the total discount of each line item, in the order of Input.Items.
+	ItemDiscounts    []currency.BRL `json:"item_discounts"`
+	Discount         currency.BRL   `json:"discount"`
+	Shipping         currency.BRL   `json:"shipping"`
+	ShippingDiscount currency.BRL   `json:"shipping_discount"`
+	// Total is the subtotal plus shipping, minus every discount.
+	Total currency.BRL `json:"total"`
+}
+
+// Evaluate applies the promotions to the input.
+//
+// The promotions are evaluated by descending priority, then by ID. Coupon promotions only apply when their
+// code was entered. An exclusive promotion is only applied if no other promotion was applied before it,
+// and once applied no further promotion is. Each discount is computed on the amounts left by the previous
+// ones, so the items never go below zero, and is allocated across the line items with exact cents.
+func Evaluate(promotions []*domain.Promotion, input Input) *Result {
+	result := &Result{
+		ItemDiscounts: make([]currency.BRL, len(input.Items)),
+		Shipping:      input.Shipping,
+	}
+
+	remaining := make([]currency.BRL, len(input.Items))
+	for i, item := range input.Items {
+		remaining[i] = item.Total()
+		result.Subtotal += item.Total()
+	}
+	remainingShipping := input.Shipping
+
+	coupons := map[string]bool{}
+	for _, code := range input.Coupons {
+		coupons[normalizeCode(code)] = true
+	}
+
+	sorted := slices.Clone(promotions)
+	slices.SortStableFunc(sorted, func(a, b *domain.Promotion) int {
+		if a.Priority != b.Priority {
+			return cmp.Compare(b.Priority, a.Priority)
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+
+	reject := func(p *domain.Promotion, err error) {
+		if p.IsCoupon() {
+			result.Rejected = append(result.Rejected, RejectedCoupon{Code: p.Code, Reason: err.Error(), Err: err})
+		}
+	}
+
+	exclusive := false
+	for _, p := range sorted {
+		if p.IsCoupon() && !coupons[normalizeCode(p.Code)] {
+			continue
+		}
+		if !p.ActiveAt(input.At) || !matches(p, input, result.Subtotal) {
+			reject(p, domain.ErrCouponNotApplicable)
+			continue
+		}
+		if exclusive || (p.Exclusive && len(result.Applied) > 0) {
+			reject(p, domain.ErrPromotionNotCombined)
+			continue
+		}
+
+		applied := apply(p, input, remaining, remainingShipping)
+		if applied.Discount == 0 && applied.ShippingDiscount == 0 {
+			reject(p, domain.ErrCouponNotApplicable)
+			continue
+		}
+
+		for i, d := range applied.ItemDiscounts {
+			remaining[i] -= d
+			result.ItemDiscounts[i] += d
+		}
+		remainingShipping -= applied.ShippingDiscount
+		result.Discount += applied.Discount
+		result.ShippingDiscount += applied.ShippingDiscount
+		result.Applied = append(result.Applied, applied)
+
+		if p.Exclusive {
+			exclusive = true
+		}
+	}
+
+	result.Total = result.Subtotal - result.Discount + result.Shipping - result.ShippingDiscount
+	return result
+}
+
+// matches checks the promotion conditions against the input.
+func matches(p *domain.Promotion, input Input, subtotal currency.BRL) bool {
+	c := p.Conditions
+	if subtotal < c.MinSubtotal {
+		return false
+	}
+	if len(c.CustomerGroups) > 0 && !slices.ContainsFunc(input.CustomerGroups, func(g string) bool {
+		return slices.Contains(c.CustomerGroups, g)
+	}) {
+		return false
+	}
+	return slices.ContainsFunc(input.Items, func(item LineItem) bool {
+		return eligible(p, item)
+	})
+}
+
+// eligible reports whether the item can receive the promotion discount.
+func eligible(p *domain.Promotion, item LineItem) bool {
+	c := p.Conditions
+	if len(c.ProductIDs) == 0 && len(c.Categories) == 0 {
+		return true
+	}
+	if slices.Contains(c.ProductIDs, item.ProductID) {
+		return true
+	}
+	return slices.ContainsFunc(item.Categories, func(category string) bool {
+		return slices.Contains(c.Categories, category)
+	})
+}
+
+// apply computes the discount of the promotion on the remaining amounts.
+func apply(p *domain.Promotion, input Input, remaining []currency.BRL, remainingShipping currency.BRL) AppliedPromotion {
+	applied := AppliedPromotion{
+		PromotionID:   p.ID,
+		Name:          p.Name,
+		Code:          p.Code,
+		ItemDiscounts: make([]currency.BRL, len(input.Items)),
+	}
+
+	weights := make([]currency.BRL, len(input.Items))
+	var eligibleTotal currency.BRL
+	for i, item := range input.Items {
+		if eligible(p, item) {
+			weights[i] = remaining[i]
+			eligibleTotal += remaining[i]
+		}
+	}
+
+	switch p.Action.Type {
+	case domain.PromotionPercentage:
+		// Round the discount half-up to the nearest cent before allocating it
+		discount := currency.BRL((eligibleTotal.Cents()*p.Action.PercentBps + fullPercentBps/2) / fullPercentBps)
+		applied.ItemDiscounts = allocate(min(discount, eligibleTotal), weights)
+
+	case domain.PromotionFixedAmount:
+		applied.ItemDiscounts = allocate(min(p.Action.Amount, eligibleTotal), weights)
+
+	case domain.PromotionBuyXGetY:
+		applied.ItemDiscounts = buyXGetY(p, input, remaining)
+
+	case domain.PromotionFreeShipping:
+		applied.ShippingDiscount = remainingShipping
+	}
+
+	for _, d := range applied.ItemDiscounts {
+		applied.Discount += d
+	}
+	return applied
+}
+
+// buyXGetY gives the cheapest eligible units for free, GetQuantity units for every BuyQuantity+GetQuantity units.
+func buyXGetY(p *domain.Promotion, input Input, remaining []currency.BRL) []currency.BRL {
+	discounts := make([]currency.BRL, len(input.Items))
+	group := p.Action.BuyQuantity + p.Action.GetQuantity
+	if p.Action.BuyQuantity < 1 || p.Action.GetQuantity < 1 {
+		return discounts
+	}
+
+	var lines []int
+	var units int64
+	for i, item := range input.Items {
+		if eligible(p, item) && item.Quantity > 0 {
+			lines = append(lines, i)
+			units += item.Quantity
+		}
+	}
+
+	free := units / group * p.Action.GetQuantity
+	slices.SortStableFunc(lines, func(a, b int) int {
+		return cmp.Compare(input.Items[a].UnitPrice, input.Items[b].UnitPrice)
+	})
+
+	for _, i := range lines {
+		if free == 0 {
+			break
+		}
+		n := min(free, input.Items[i].Quantity)
+		discounts[i] = min(input.Items[i].UnitPrice.MulQuantity(n), remaining[i])
+		free -= n
+	}
+	return discounts
+}
+
+// normalizeCode makes the coupon codes case and space insensitive.
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,113 @@
+package promotion_test
+
+import (
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/promotion"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+
+	shirtID, mugID := uuid.New(), uuid.New()
+	items := []promotion.LineItem{
+		{ProductID: shirtID, Categories: []string{"apparel"}, Quantity: 3, UnitPrice: currency.NewFromFloat(33.33)},
+		{ProductID: mugID, Categories: []string{"kitchen"}, Quantity: 1, UnitPrice: currency.NewFromFloat(20)},
+	}
+
+	newPromotion := func(priority int, action domain.PromotionAction) *domain.Promotion {
+		return &domain.Promotion{ID: uuid.New(), Name: string(action.Type), Active: true, Priority: priority, Action: action}
+	}
+
+	t.Run("percentage allocated with exact cents", func(t *testing.T) {
+		p := newPromotion(0, domain.PromotionAction{Type: domain.PromotionPercentage, PercentBps: 1000})
+
+		result := promotion.Evaluate([]*domain.Promotion{p}, promotion.Input{Items: items, At: time.Now()})
+
+		// 10% of 119.99 rounded half-up
+		assert.Equal(t, currency.BRL(1200), result.Discount)
+		assert.Equal(t, []currency.BRL{1000, 200}, result.ItemDiscounts)
+		assert.Equal(t, result.Subtotal-result.Discount, result.Total)
+	})
+
+	t.Run("category condition restricts the eligible items", func(t *testing.T) {
+		p := newPromotion(0, domain.PromotionAction{Type: domain.PromotionFixedAmount, Amount: currency.NewFromFloat(50)})
+		p.Conditions.Categories = []string{"kitchen"}
+
+		result := promotion.Evaluate([]*domain.Promotion{p}, promotion.Input{Items: items, At: time.Now()})
+
+		// capped at the mug price
+		assert.Equal(t, []currency.BRL{0, 2000}, result.ItemDiscounts)
+	})
+
+	t.Run("buy 2 get 1", func(t *testing.T) {
+		p := newPromotion(0, domain.PromotionAction{Type: domain.PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1})
+
+		result := promotion.Evaluate([]*domain.Promotion{p}, promotion.Input{Items: items, At: time.Now()})
+
+		// 4 units give 1 free unit, the cheapest is the mug
+		assert.Equal(t, []currency.BRL{0, 2000}, result.ItemDiscounts)
+	})
+
+	t.Run("free shipping with min subtotal", func(t *testing.T) {
+		p := newPromotion(0, domain.PromotionAction{Type: domain.PromotionFreeShipping})
+		p.Conditions.MinSubtotal = currency.NewFromFloat(100)
+
+		result := promotion.Evaluate([]*domain.Promotion{p}, promotion.Input{Items: items, Shipping: 1590, At: time.Now()})
+		assert.Equal(t, currency.BRL(1590), result.ShippingDiscount)
+		assert.Equal(t, result.Subtotal, result.Total)
+
+		result = promotion.Evaluate([]*domain.Promotion{p}, promotion.Input{Items: items[1:], Shipping: 1590, At: time.Now()})
+		assert.Empty(t, result.Applied)
+	})
+
+	t.Run("coupons, stacking and exclusivity", func(t *testing.T) {
+		auto := newPromotion(10, domain.PromotionAction{Type: domain.PromotionFixedAmount, Amount: 1000})
+		coupon := newPromotion(5, domain.PromotionAction{Type: domain.PromotionPercentage, PercentBps: 5000})
+		coupon.Code = "HALF"
+		exclusive := newPromotion(1, domain.PromotionAction{Type: domain.PromotionPercentage, PercentBps: 9000})
+		exclusive.Code = "VIP"
+		exclusive.Exclusive = true
+		b2b := newPromotion(0, domain.PromotionAction{Type: domain.PromotionFreeShipping})
+		b2b.Code = "B2B"
+		b2b.Conditions.CustomerGroups = []string{"b2b"}
+		notEntered := newPromotion(100, domain.PromotionAction{Type: domain.PromotionFixedAmount, Amount: 1})
+		notEntered.Code = "OTHER"
+
+		result := promotion.Evaluate(
+			[]*domain.Promotion{exclusive, b2b, coupon, auto, notEntered},
+			promotion.Input{Items: items, Shipping: 1000, Coupons: []string{"half", " vip", "B2B"}, At: time.Now()},
+		)
+
+		require.Len(t, result.Applied, 2)
+		assert.Equal(t, auto.ID, result.Applied[0].PromotionID)
+		assert.Equal(t, coupon.ID, result.Applied[1].PromotionID)
+		// 10.00 off, then 50% of the remaining 109.99
+		assert.Equal(t, currency.BRL(1000+5500), result.Discount)
+
+		require.Len(t, result.Rejected, 2)
+		assert.Equal(t, "VIP", result.Rejected[0].Code)
+		assert.ErrorIs(t, result.Rejected[0].Err, domain.ErrPromotionNotCombined)
+		assert.Equal(t, "B2B", result.Rejected[1].Code)
+		assert.ErrorIs(t, result.Rejected[1].Err, domain.ErrCouponNotApplicable)
+
+		var sum currency.BRL
+		for _, d := range result.ItemDiscounts {
+			sum += d
+		}
+		assert.Equal(t, result.Discount, sum)
+	})
+
+	t.Run("inactive promotion", func(t *testing.T) {
+		p := newPromotion(0, domain.PromotionAction{Type: domain.PromotionPercentage, PercentBps: 1000})
+		p.End = time.Now().Add(-time.Hour)
+
+		result := promotion.Evaluate([]*domain.Promotion{p}, promotion.Input{Items: items, At: time.Now()})
+		assert.Empty(t, result.Applied)
+		assert.Zero(t, result.Discount)
+	})
+}
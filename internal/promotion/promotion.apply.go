@@ -0,0 +1,104 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Apply evaluates the automatic promotions and the entered coupons against the input.
+// Coupons which are unknown, over their usage limits or not applicable are reported in Result.Rejected.
+func (s *Service) Apply(ctx context.Context, namespace string, input Input) (*Result, error) {
+
+	ctx, span := observability.StartSpan(ctx, "promotion.Apply")
+	defer span.End()
+
+	if input.At.IsZero() {
+		input.At = time.Now()
+	}
+
+	promotions, err := s.repo.FindActive(ctx, namespace, input.At)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	var rejected []RejectedCoupon
+	seen := map[string]bool{}
+	for _, code := range input.Coupons {
+		code = normalizeCode(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		promotion, err := s.repo.GetByCode(ctx, namespace, code)
+		if errors.Is(err, domain.ErrCouponNotFound) {
+			rejected = append(rejected, RejectedCoupon{Code: code, Reason: err.Error(), Err: err})
+			continue
+		}
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		err = s.checkUsage(ctx, namespace, promotion, input)
+		if errors.Is(err, domain.ErrCouponUsageLimit) || errors.Is(err, domain.ErrCouponNotApplicable) {
+			rejected = append(rejected, RejectedCoupon{Code: code, Reason: err.Error(), Err: err})
+			continue
+		}
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	// The automatic promotions may have usage limits too. The slice of the repository is left untouched.
+	available := make([]*domain.Promotion, 0, len(promotions))
+	for _, p := range promotions {
+		if !p.IsCoupon() {
+			err := s.checkUsage(ctx, namespace, p, input)
+			if errors.Is(err, domain.ErrCouponUsageLimit) || errors.Is(err, domain.ErrCouponNotApplicable) {
+				continue
+			}
+			if err != nil {
+				span.RecordError(err)
+				return nil, err
+			}
+		}
+		available = append(available, p)
+	}
+
+	result := Evaluate(available, input)
+	result.Rejected = append(rejected, result.Rejected...)
+	return result, nil
+}
+
+// checkUsage returns domain.ErrCouponUsageLimit when the promotion cannot be redeemed anymore.
+// A promotion limited per customer requires one, the guests would otherwise share a single counter, so it returns
+// domain.ErrCouponNotApplicable when the input has no customer.
+func (s *Service) checkUsage(ctx context.Context, namespace string, p *domain.Promotion, input Input) error {
+	if p.UsageLimit == 0 && p.UsageLimitPerCustomer == 0 {
+		return nil
+	}
+	if p.UsageLimitPerCustomer > 0 && input.CustomerID == uuid.Nil {
+		return fmt.Errorf("%w: limited per customer, sign in to use it", domain.ErrCouponNotApplicable)
+	}
+
+	total, byCustomer, err := s.repo.CountUsage(ctx, namespace, p.ID, input.CustomerID)
+	if err != nil {
+		return err
+	}
+	if p.UsageLimit > 0 && total >= p.UsageLimit {
+		return domain.ErrCouponUsageLimit
+	}
+	if p.UsageLimitPerCustomer > 0 && byCustomer >= p.UsageLimitPerCustomer {
+		return domain.ErrCouponUsageLimit
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package promotion_test
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/promotion"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+
+	type setupParams struct {
+		repo *MockPromotionRepository
+	}
+
+	automatic := func() *domain.Promotion {
+		return &domain.Promotion{
+			ID:         uuid.New(),
+			Name:       "10% off",
+			Active:     true,
+			Action:     domain.PromotionAction{Type: domain.PromotionPercentage, PercentBps: 1000},
+			UsageLimit: 10,
+		}
+	}
+	coupon := &domain.Promotion{
+		ID:         uuid.New(),
+		Name:       "Welcome",
+		Code:       "WELCOME",
+		Active:     true,
+		Action:     domain.PromotionAction{Type: domain.PromotionPercentage, PercentBps: 500},
+		UsageLimit: 1,
+	}
+	limited, unlimited := automatic(), automatic()
+	unlimited.UsageLimit = 0
+	active := []*domain.Promotion{limited, unlimited}
+
+	input := promotion.Input{
+		Items:   []promotion.LineItem{{ProductID: uuid.New(), Quantity: 1, UnitPrice: 10000}},
+		Coupons: []string{"welcome"},
+	}
+
+	customerID := uuid.New()
+	perCustomer := &domain.Promotion{
+		ID:                    uuid.New(),
+		Name:                  "First purchase",
+		Code:                  "WELCOME",
+		Active:                true,
+		Action:                domain.PromotionAction{Type: domain.PromotionPercentage, PercentBps: 1500},
+		UsageLimitPerCustomer: 1,
+	}
+
+	tests := []struct {
+		name          string
+		customerID    uuid.UUID
+		setup         func(t setupParams)
+		expectedError error
+		expected      func(t *testing.T, result *promotion.Result)
+	}{
+		{
+			name: "automatic promotion over its limit is skipped",
+			setup: func(t setupParams) {
+				p := automatic()
+				t.repo.EXPECT().FindActive(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Promotion{p}, nil)
+				t.repo.EXPECT().GetByCode(gomock.Any(), "ns", "WELCOME").Return(coupon, nil)
+				t.repo.EXPECT().CountUsage(gomock.Any(), "ns", coupon.ID, gomock.Any()).Return(int64(0), int64(0), nil)
+				t.repo.EXPECT().CountUsage(gomock.Any(), "ns", p.ID, gomock.Any()).Return(int64(10), int64(0), nil)
+			},
+			expected: func(t *testing.T, result *promotion.Result) {
+				require.Len(t, result.Applied, 1)
+				assert.Equal(t, coupon.ID, result.Applied[0].PromotionID)
+			},
+		},
+		{
+			name: "coupon over its limit is rejected",
+			setup: func(t setupParams) {
+				t.repo.EXPECT().FindActive(gomock.Any(), "ns", gomock.Any()).Return(nil, nil)
+				t.repo.EXPECT().GetByCode(gomock.Any(), "ns", "WELCOME").Return(coupon, nil)
+				t.repo.EXPECT().CountUsage(gomock.Any(), "ns", coupon.ID, gomock.Any()).Return(int64(1), int64(0), nil)
+			},
+			expected: func(t *testing.T, result *promotion.Result) {
+				assert.Empty(t, result.Applied)
+				require.Len(t, result.Rejected, 1)
+				assert.ErrorIs(t, result.Rejected[0].Err, domain.ErrCouponUsageLimit)
+			},
+		},
+		{
+			name: "coupon limited per customer is rejected for a guest",
+			setup: func(t setupParams) {
+				t.repo.EXPECT().FindActive(gomock.Any(), "ns", gomock.Any()).Return(nil, nil)
+				t.repo.EXPECT().GetByCode(gomock.Any(), "ns", "WELCOME").Return(perCustomer, nil)
+			},
+			expected: func(t *testing.T, result *promotion.Result) {
+				assert.Empty(t, result.Applied)
+				require.Len(t, result.Rejected, 1)
+				assert.ErrorIs(t, result.Rejected[0].Err, domain.ErrCouponNotApplicable)
+			},
+		},
+		{
+			name:       "coupon limited per customer is counted for the customer",
+			customerID: customerID,
+			setup: func(t setupParams) {
+				t.repo.EXPECT().FindActive(gomock.Any(), "ns", gomock.Any()).Return(nil, nil)
+				t.repo.EXPECT().GetByCode(gomock.Any(), "ns", "WELCOME").Return(perCustomer, nil)
+				t.repo.EXPECT().CountUsage(gomock.Any(), "ns", perCustomer.ID, customerID).Return(int64(5), int64(0), nil)
+			},
+			expected: func(t *testing.T, result *promotion.Result) {
+				require.Len(t, result.Applied, 1)
+				assert.Equal(t, perCustomer.ID, result.Applied[0].PromotionID)
+			},
+		},
+		{
+			name: "coupon usage error",
+			setup: func(t setupParams) {
+				t.repo.EXPECT().FindActive(gomock.Any(), "ns", gomock.Any()).Return(nil, nil)
+				t.repo.EXPECT().GetByCode(gomock.Any(), "ns", "WELCOME").Return(coupon, nil)
+				t.repo.EXPECT().CountUsage(gomock.Any(), "ns", coupon.ID, gomock.Any()).Return(int64(0), int64(0), errors.New("connection lost"))
+			},
+			expectedError: errors.New("connection lost"),
+		},
+		{
+			name: "automatic promotion usage error",
+			setup: func(t setupParams) {
+				p := automatic()
+				t.repo.EXPECT().FindActive(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Promotion{p}, nil)
+				t.repo.EXPECT().GetByCode(gomock.Any(), "ns", "WELCOME").Return(coupon, nil)
+				t.repo.EXPECT().CountUsage(gomock.Any(), "ns", coupon.ID, gomock.Any()).Return(int64(0), int64(0), nil)
+				t.repo.EXPECT().CountUsage(gomock.Any(), "ns", p.ID, gomock.Any()).Return(int64(0), int64(0), errors.New("connection lost"))
+			},
+			expectedError: errors.New("connection lost"),
+		},
+		{
+			name: "repository slice is left untouched",
+			setup: func(t setupParams) {
+				t.repo.EXPECT().FindActive(gomock.Any(), "ns", gomock.Any()).Return(active, nil)
+				t.repo.EXPECT().GetByCode(gomock.Any(), "ns", "WELCOME").Return(coupon, nil)
+				t.repo.EXPECT().CountUsage(gomock.Any(), "ns", coupon.ID, gomock.Any()).Return(int64(1), int64(0), nil)
+				t.repo.EXPECT().CountUsage(gomock.Any(), "ns", limited.ID, gomock.Any()).Return(int64(10), int64(0), nil)
+			},
+			expected: func(t *testing.T, result *promotion.Result) {
+				require.Len(t, result.Applied, 1)
+				assert.Equal(t, unlimited.ID, result.Applied[0].PromotionID)
+				// the filtering does not overwrite the slice returned by the repository
+				assert.Equal(t, []*domain.Promotion{limited, unlimited}, active)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockPromotionRepository(ctrl)
+			service := promotion.NewService(mockRepo, NewMockAuthService(ctrl))
+
+			if tt.setup != nil {
+				tt.setup(setupParams{repo: mockRepo})
+			}
+
+			in := input
+			in.CustomerID = tt.customerID
+			result, err := service.Apply(context.Background(), "ns", in)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			require.NoError(t, err)
+			if tt.expected != nil {
+				tt.expected(t, result)
+			}
+		})
+	}
+}
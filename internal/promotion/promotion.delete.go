@@ -0,0 +1,30 @@
+package promotion
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+)
+
+func (s *Service) DeletePromotion(ctx context.Context, namespace string, id uuid.UUID) error {
+
+	ctx, span := observability.StartSpan(ctx, "promotion.DeletePromotion")
+	defer span.End()
+
+	userID, err := s.checkPermission(ctx, namespace, "promotion:delete")
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.Delete(ctx, namespace, id)
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "promotion deleted",
+		"promotion_id", id,
+		"deleted_by", userID,
+	)
+	return nil
+}
@@ -0,0 +1,46 @@
+package promotion
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// CreatePromotion validates and stores a new promotion, the coupon code is normalized to upper case.
+func (s *Service) CreatePromotion(ctx context.Context, namespace string, promotion *domain.Promotion) error {
+
+	ctx, span := observability.StartSpan(ctx, "promotion.CreatePromotion")
+	defer span.End()
+
+	userID, err := s.checkPermission(ctx, namespace, "promotion:create")
+	if err != nil {
+		return err
+	}
+
+	promotion.Code = normalizeCode(promotion.Code)
+	if err := Validate(promotion); err != nil {
+		return err
+	}
+
+	promotion.ID = uuid.New()
+	promotion.Namespace = namespace
+	promotion.CreatedAt = time.Now()
+	promotion.UpdatedAt = time.Now()
+
+	err = s.repo.Create(ctx, namespace, promotion)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "promotion created",
+		"promotion_id", promotion.ID,
+		"name", promotion.Name,
+		"code", promotion.Code,
+		"created_by", userID,
+	)
+	return nil
+}
@@ -0,0 +1,66 @@
+package promotion
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// Redeem records the usage of the applied promotions by the order, enforcing the usage limits.
+// When one of them fails the usages already recorded are released.
+func (s *Service) Redeem(ctx context.Context, namespace string, customerID, orderID uuid.UUID, result *Result) error {
+
+	ctx, span := observability.StartSpan(ctx, "promotion.Redeem")
+	defer span.End()
+
+	var redeemed []uuid.UUID
+	for _, applied := range result.Applied {
+		promotion, err := s.repo.GetByID(ctx, namespace, applied.PromotionID)
+		if err == nil {
+			err = s.repo.RecordUsage(ctx, namespace, promotion, &domain.PromotionUsage{
+				ID:          uuid.New(),
+				Namespace:   namespace,
+				PromotionID: applied.PromotionID,
+				CustomerID:  customerID,
+				OrderID:     orderID,
+				Discount:    applied.Discount + applied.ShippingDiscount,
+				RedeemedAt:  time.Now(),
+			})
+		}
+		if err != nil {
+			span.RecordError(err)
+			for _, id := range redeemed {
+				s.release(ctx, namespace, id, orderID)
+			}
+			return err
+		}
+		redeemed = append(redeemed, applied.PromotionID)
+	}
+
+	return nil
+}
+
+// Release removes the usages recorded for the order, for instance when the checkout is rolled back.
+func (s *Service) Release(ctx context.Context, namespace string, orderID uuid.UUID, result *Result) {
+
+	ctx, span := observability.StartSpan(ctx, "promotion.Release")
+	defer span.End()
+
+	for _, applied := range result.Applied {
+		s.release(ctx, namespace, applied.PromotionID, orderID)
+	}
+}
+
+func (s *Service) release(ctx context.Context, namespace string, promotionID, orderID uuid.UUID) {
+	err := s.repo.DeleteUsage(ctx, namespace, promotionID, orderID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to release promotion usage",
+			"promotion_id", promotionID,
+			"order_id", orderID,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,77 @@
+package promotion
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/google/uuid"
+	"time"
+)
+
+// PromotionRepository defines an interface for managing the promotions and their usage.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  promotion_test
+type PromotionRepository interface {
+
+	// FindActive retrieves the automatic promotions active at the given time.
+	FindActive(ctx context.Context, namespace string, at time.Time) ([]*domain.Promotion, error)
+
+	// GetByCode retrieves the promotion unlocked by the coupon code, returns domain.ErrCouponNotFound if missing.
+	GetByCode(ctx context.Context, namespace string, code string) (*domain.Promotion, error)
+
+	// GetByID retrieves a promotion by its unique identifier, returns domain.ErrPromotionNotFound if missing.
+	GetByID(ctx context.Context, namespace string, id uuid.UUID) (*domain.Promotion, error)
+
+	// Create adds a new promotion to the repository.
+	Create(ctx context.Context, namespace string, promotion *domain.Promotion) error
+
+	// Delete removes a promotion by the provided UUID.
+	Delete(ctx context.Context, namespace string, id uuid.UUID) error
+
+	// CountUsage returns the number of redemptions of the promotion, in total and by the customer.
+	CountUsage(ctx context.Context, namespace string, promotionID, customerID uuid.UUID) (total int64, byCustomer int64, err error)
+
+	// RecordUsage atomically records a redemption, returning domain.ErrCouponUsageLimit if the promotion
+	// usage limits would be exceeded.
+	RecordUsage(ctx context.Context, namespace string, promotion *domain.Promotion, usage *domain.PromotionUsage) error
+
+	// DeleteUsage removes the redemptions of the promotion by the order, releasing them.
+	DeleteUsage(ctx context.Context, namespace string, promotionID, orderID uuid.UUID) error
+}
+
+// AuthService returns information about the current command
+type AuthService interface {
+	// GetUserID retrieves the unique identifier (UUID) of the user from the provided context. Returns an error if retrieval fails.
+	GetUserID(ctx context.Context) (uuid.UUID, error)
+	// CheckPermissions verifies if a user has the required permissions for a specified namespace and action(s). It returns a boolean indicating access and an error if the operation fails.
+	CheckPermissions(ctx context.Context, userID uuid.UUID, namespace string, permission ...string) (bool, error)
+}
+
+type Service struct {
+	repo PromotionRepository
+	auth AuthService
+}
+
+func NewService(repo PromotionRepository, auth AuthService) *Service {
+	return &Service{
+		repo: repo,
+		auth: auth,
+	}
+}
+
+// checkPermission verifies that the user of the context has the permission in the namespace.
+func (s *Service) checkPermission(ctx context.Context, namespace string, permission ...string) (uuid.UUID, error) {
+	userID, err := s.auth.GetUserID(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+
+	perm, err := s.auth.CheckPermissions(ctx, userID, namespace, permission...)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", domain.ErrUnauthorized, err)
+	}
+	if !perm {
+		return uuid.Nil, domain.ErrUnauthorized
+	}
+	return userID, nil
+}
@@ -0,0 +1,69 @@
+package promotion
+
+import (
+	"github.com/HBeserra/GoShop/domain"
+	"strings"
+)
+
+// Validate checks the promotion, every violation is collected into a *domain.ValidationError.
+func Validate(p *domain.Promotion) error {
+	verr := new(domain.ValidationError)
+
+	if strings.TrimSpace(p.Name) == "" {
+		verr.Add("name", domain.ValidationCodeRequired, domain.ErrInvalidPromotion, nil)
+	}
+
+	if p.Code != "" && normalizeCode(p.Code) != p.Code {
+		verr.Add("code", domain.ValidationCodeInvalid, domain.ErrInvalidPromotion, map[string]any{
+			"expected": normalizeCode(p.Code),
+		})
+	}
+
+	if !p.Start.IsZero() && !p.End.IsZero() && !p.End.After(p.Start) {
+		verr.Add("end", domain.ValidationCodeMin, domain.ErrInvalidPromotion, map[string]any{"min": p.Start})
+	}
+
+	if p.Conditions.MinSubtotal < 0 {
+		verr.Add("conditions.min_subtotal", domain.ValidationCodeMin, domain.ErrInvalidPromotion, map[string]any{"min": 0})
+	}
+
+	switch p.Action.Type {
+	case domain.PromotionPercentage:
+		if p.Action.PercentBps < 1 || p.Action.PercentBps > fullPercentBps {
+			verr.Add("action.percent_bps", domain.ValidationCodeRange, domain.ErrInvalidPromotion, map[string]any{
+				"min": 1,
+				"max": fullPercentBps,
+			})
+		}
+	case domain.PromotionFixedAmount:
+		if p.Action.Amount <= 0 {
+			verr.Add("action.amount", domain.ValidationCodeMin, domain.ErrInvalidPromotion, map[string]any{"min": 0})
+		}
+	case domain.PromotionBuyXGetY:
+		if p.Action.BuyQuantity < 1 {
+			verr.Add("action.buy_quantity", domain.ValidationCodeMin, domain.ErrInvalidPromotion, map[string]any{"min": 1})
+		}
+		if p.Action.GetQuantity < 1 {
+			verr.Add("action.get_quantity", domain.ValidationCodeMin, domain.ErrInvalidPromotion, map[string]any{"min": 1})
+		}
+	case domain.PromotionFreeShipping:
+	default:
+		verr.Add("action.type", domain.ValidationCodeOneOf, domain.ErrInvalidPromotion, map[string]any{
+			"allowed": []domain.PromotionActionType{
+				domain.PromotionPercentage,
+				domain.PromotionFixedAmount,
+				domain.PromotionBuyXGetY,
+				domain.PromotionFreeShipping,
+			},
+		})
+	}
+
+	if p.UsageLimit < 0 {
+		verr.Add("usage_limit", domain.ValidationCodeMin, domain.ErrInvalidPromotion, map[string]any{"min": 0})
+	}
+	if p.UsageLimitPerCustomer < 0 {
+		verr.Add("usage_limit_per_customer", domain.ValidationCodeMin, domain.ErrInvalidPromotion, map[string]any{"min": 0})
+	}
+
+	return verr.Err()
+}
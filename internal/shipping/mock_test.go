@@ -0,0 +1,151 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination mock_test.go --package shipping_test
+//
+
+// Package shipping_test is a generated GoMock package.
+package shipping_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/HBeserra/GoShop/domain"
+	dto "github.com/HBeserra/GoShop/domain/dto"
+	shipping "github.com/HBeserra/GoShop/internal/shipping"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockShippingProvider is a mock of ShippingProvider interface.
+type MockShippingProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockShippingProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockShippingProviderMockRecorder is the mock recorder for MockShippingProvider.
+type MockShippingProviderMockRecorder struct {
+	mock *MockShippingProvider
+}
+
+// NewMockShippingProvider creates a new mock instance.
+func NewMockShippingProvider(ctrl *gomock.Controller) *MockShippingProvider {
+	mock := &MockShippingProvider{ctrl: ctrl}
+	mock.recorder = &MockShippingProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShippingProvider) EXPECT() *MockShippingProviderMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method.
+func (m *MockShippingProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockShippingProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockShippingProvider)(nil).Name))
+}
+
+// Quote mocks base method.
+func (m *MockShippingProvider) Quote(ctx context.Context, req shipping.QuoteRequest) ([]shipping.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", ctx, req)
+	ret0, _ := ret[0].([]shipping.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockShippingProviderMockRecorder) Quote(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockShippingProvider)(nil).Quote), ctx, req)
+}
+
+// MockAddresses is a mock of Addresses interface.
+type MockAddresses struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressesMockRecorder
+	isgomock struct{}
+}
+
+// MockAddressesMockRecorder is the mock recorder for MockAddresses.
+type MockAddressesMockRecorder struct {
+	mock *MockAddresses
+}
+
+// NewMockAddresses creates a new mock instance.
+func NewMockAddresses(ctrl *gomock.Controller) *MockAddresses {
+	mock := &MockAddresses{ctrl: ctrl}
+	mock.recorder = &MockAddressesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddresses) EXPECT() *MockAddressesMockRecorder {
+	return m.recorder
+}
+
+// State mocks base method.
+func (m *MockAddresses) State(ctx context.Context, cep domain.CEP) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "State", ctx, cep)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// State indicates an expected call of State.
+func (mr *MockAddressesMockRecorder) State(ctx, cep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "State", reflect.TypeOf((*MockAddresses)(nil).State), ctx, cep)
+}
+
+// MockProductCatalog is a mock of ProductCatalog interface.
+type MockProductCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductCatalogMockRecorder
+	isgomock struct{}
+}
+
+// MockProductCatalogMockRecorder is the mock recorder for MockProductCatalog.
+type MockProductCatalogMockRecorder struct {
+	mock *MockProductCatalog
+}
+
+// NewMockProductCatalog creates a new mock instance.
+func NewMockProductCatalog(ctrl *gomock.Controller) *MockProductCatalog {
+	mock := &MockProductCatalog{ctrl: ctrl}
+	mock.recorder = &MockProductCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductCatalog) EXPECT() *MockProductCatalogMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockProductCatalog) Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", ctx, namespace, filter)
+	ret0, _ := ret[0].([]*domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockProductCatalogMockRecorder) Lookup(ctx, namespace, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockProductCatalog)(nil).Lookup), ctx, namespace, filter)
+}
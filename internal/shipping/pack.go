@@ -0,0 +1,134 @@
+package shipping
+
+import (
+	"cmp"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/google/uuid"
+	"slices"
+)
+
+// PackItem is a quantity of a product, or one of its variants, to pack.
+type PackItem struct {
+	ProductID uuid.UUID
+	VariantID uuid.UUID
+	Quantity  int64
+	Profile   domain.ShippingProfile
+}
+
+// unit is a single unit of an item.
+type unit struct {
+	item    *PackItem
+	volume  int64
+	ordinal int
+}
+
+// openBox is a box being filled.
+type openBox struct {
+	box    domain.ShippingBox
+	volume int64
+	weight int64
+	units  []unit
+}
+
+// Pack splits the items in packages with a first fit decreasing heuristic. The largest units are packed
+// first, each in the first box it fits in, a new box being opened with the smallest box the unit fits in.
+// A unit fits in a box when its dimensions fit the inner dimensions, rotated unless it must stay upright,
+// and when the box has room left for its volume and weight. Every box is then replaced by the smallest box
+// holding its contents.
+//
+// The units shipping alone, and those fitting no box, are shipped in their own packaging.
+func Pack(items []PackItem, boxes []domain.ShippingBox) []domain.Package {
+	boxes = slices.Clone(boxes)
+	slices.SortStableFunc(boxes, func(a, b domain.ShippingBox) int {
+		return cmp.Compare(a.Inner.Volume(), b.Inner.Volume())
+	})
+
+	var units []unit
+	var packages []domain.Package
+	for i := range items {
+		item := &items[i]
+		for n := int64(0); n < item.Quantity; n++ {
+			u := unit{item: item, volume: item.Profile.Dimensions.Volume(), ordinal: len(units)}
+			if item.Profile.ShipsAlone || smallestBox(boxes, []unit{u}) < 0 {
+				packages = append(packages, domain.Package{
+					Dimensions: item.Profile.Dimensions,
+					Weight:     item.Profile.Weight,
+					Items:      []domain.PackageItem{{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: 1}},
+				})
+				continue
+			}
+			units = append(units, u)
+		}
+	}
+
+	slices.SortStableFunc(units, func(a, b unit) int {
+		return cmp.Or(cmp.Compare(b.volume, a.volume), cmp.Compare(b.item.Profile.Weight, a.item.Profile.Weight))
+	})
+
+	var open []*openBox
+	for _, u := range units {
+		idx := slices.IndexFunc(open, func(b *openBox) bool { return b.fits(u) })
+		if idx < 0 {
+			open = append(open, &openBox{box: boxes[smallestBox(boxes, []unit{u})]})
+			idx = len(open) - 1
+		}
+		open[idx].add(u)
+	}
+
+	for _, b := range open {
+		b.box = boxes[smallestBox(boxes, b.units)]
+		packages = append(packages, b.pack())
+	}
+	return packages
+}
+
+// smallestBox returns the index of the smallest box holding the units, -1 when none does.
+func smallestBox(boxes []domain.ShippingBox, units []unit) int {
+	return slices.IndexFunc(boxes, func(box domain.ShippingBox) bool {
+		b := &openBox{box: box}
+		for _, u := range units {
+			if !b.fits(u) {
+				return false
+			}
+			b.add(u)
+		}
+		return true
+	})
+}
+
+func (b *openBox) fits(u unit) bool {
+	profile := u.item.Profile
+	if !profile.Dimensions.Fits(b.box.Inner, profile.KeepUpright) {
+		return false
+	}
+	if b.volume+u.volume > b.box.Inner.Volume() {
+		return false
+	}
+	return b.box.MaxWeight <= 0 || b.weight+profile.Weight <= b.box.MaxWeight
+}
+
+func (b *openBox) add(u unit) {
+	b.volume += u.volume
+	b.weight += u.item.Profile.Weight
+	b.units = append(b.units, u)
+}
+
+// pack returns the package of the box, its items in the order they were given to Pack.
+func (b *openBox) pack() domain.Package {
+	dimensions := b.box.Outer
+	if dimensions.IsZero() {
+		dimensions = b.box.Inner
+	}
+	p := domain.Package{Box: b.box.Name, Dimensions: dimensions, Weight: b.box.Weight + b.weight}
+
+	slices.SortFunc(b.units, func(x, y unit) int { return cmp.Compare(x.ordinal, y.ordinal) })
+	for _, u := range b.units {
+		last := len(p.Items) - 1
+		if last >= 0 && p.Items[last].ProductID == u.item.ProductID && p.Items[last].VariantID == u.item.VariantID {
+			p.Items[last].Quantity++
+			continue
+		}
+		p.Items = append(p.Items, domain.PackageItem{ProductID: u.item.ProductID, VariantID: u.item.VariantID, Quantity: 1})
+	}
+	return p
+}
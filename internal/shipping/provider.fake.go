@@ -0,0 +1,30 @@
+package shipping
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/pkg/currency"
+)
+
+// FakeProvider is a deterministic ShippingProvider for tests and development. It quotes a "standard"
+// service at R$ 10,00 plus R$ 2,00 per started kilogram in 5 days, and an "express" one at R$ 20,00
+// plus R$ 5,00 per started kilogram in 2 days.
+type FakeProvider struct{}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Quote(_ context.Context, req QuoteRequest) ([]Quote, error) {
+	var kg int64
+	for _, pkg := range req.Packages {
+		kg += (pkg.BillableWeight(6000) + 999) / 1000
+	}
+	return []Quote{
+		{Service: "standard", Name: "Standard", Price: currency.NewFromFloat(10) + currency.NewFromFloat(2).MulQuantity(kg), TransitDays: 5},
+		{Service: "express", Name: "Express", Price: currency.NewFromFloat(20) + currency.NewFromFloat(5).MulQuantity(kg), TransitDays: 2},
+	}, nil
+}
@@ -0,0 +1,175 @@
+package shipping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"io/fs"
+	"slices"
+	"sync"
+)
+
+// RateTable holds the services of a TableProvider.
+type RateTable struct {
+	// VolumetricDivisor converts the volume of the packages to their volumetric weight, see Package.BillableWeight.
+	VolumetricDivisor int64          `json:"volumetric_divisor"`
+	Services          []TableService `json:"services"`
+}
+
+// TableService is a shipping service priced by zones of CEP ranges.
+type TableService struct {
+	Service string `json:"service"`
+	Name    string `json:"name"`
+	Zones   []Zone `json:"zones"`
+}
+
+// Zone prices the packages shipped to a range of CEPs, or to whole states, by weight band.
+type Zone struct {
+	// From and To are the first and last CEPs of the zone.
+	From domain.CEP `json:"from"`
+	To   domain.CEP `json:"to"`
+	// States are the abbreviations of the states of the zone, the CEP range is then optional.
+	States      []string `json:"states"`
+	TransitDays int      `json:"transit_days"`
+	// Bands are sorted by MaxWeight, a package is priced with the first band it fits in.
+	Bands []WeightBand `json:"bands"`
+	// ExtraPerKg prices each kilogram, or fraction, above the last band. The heavier packages are not
+	// shipped when it is zero.
+	ExtraPerKg currency.BRL `json:"extra_per_kg"`
+}
+
+// WeightBand is the price of the packages up to MaxWeight grams.
+type WeightBand struct {
+	MaxWeight int64        `json:"max_weight"`
+	Price     currency.BRL `json:"price"`
+}
+
+// TableProvider is a ShippingProvider pricing the packages with a RateTable loaded from a JSON file, such as
+//
+//	{"volumetric_divisor": 6000, "services": [{"service": "pac", "name": "PAC", "zones": [
+//	  {"from": "01000000", "to": "19999999", "transit_days": 3, "extra_per_kg": "2.50",
+//	   "bands": [{"max_weight": 1000, "price": "15.90"}, {"max_weight": 5000, "price": "24.90"}]}]}]}
+//
+// The zones are matched in order, the first one holding the destination CEP or its state is used.
+// The price of a service is the sum of the prices of the packages. The table is updated in place when the
+// carrier changes its prices, see Reload.
+type TableProvider struct {
+	name string
+	fsys fs.FS
+	file string
+
+	mu    sync.RWMutex
+	table *RateTable
+}
+
+// NewTableProvider loads the rate table of the named file, name identifies the provider in the options.
+func NewTableProvider(name string, fsys fs.FS, file string) (*TableProvider, error) {
+	p := &TableProvider{name: name, fsys: fsys, file: file}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload parses the rate table again. The quotes keep using the previous table when the new one does
+// not validate.
+func (p *TableProvider) Reload() error {
+	data, err := fs.ReadFile(p.fsys, p.file)
+	if err != nil {
+		return err
+	}
+
+	table := new(RateTable)
+	if err := json.Unmarshal(data, table); err != nil {
+		return fmt.Errorf("%w: %s: %w", domain.ErrInvalidShippingRates, p.file, err)
+	}
+	if err := table.validate(); err != nil {
+		return fmt.Errorf("%w: %s: %w", domain.ErrInvalidShippingRates, p.file, err)
+	}
+
+	p.mu.Lock()
+	p.table = table
+	p.mu.Unlock()
+	return nil
+}
+
+func (t *RateTable) validate() error {
+	for i, s := range t.Services {
+		if s.Service == "" {
+			return fmt.Errorf("services[%d]: missing service", i)
+		}
+		for j, z := range s.Zones {
+			if (z.From == "") != (z.To == "") || z.From > z.To || z.From == "" && len(z.States) == 0 || len(z.Bands) == 0 {
+				return fmt.Errorf("services[%d].zones[%d]: empty zone", i, j)
+			}
+			for _, state := range z.States {
+				if !domain.ValidState(state) {
+					return fmt.Errorf("services[%d].zones[%d]: %w: %q", i, j, domain.ErrInvalidState, state)
+				}
+			}
+			if !slices.IsSortedFunc(z.Bands, func(a, b WeightBand) int { return int(a.MaxWeight - b.MaxWeight) }) {
+				return fmt.Errorf("services[%d].zones[%d]: bands not sorted by max_weight", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *TableProvider) Name() string {
+	return p.name
+}
+
+func (p *TableProvider) Quote(_ context.Context, req QuoteRequest) ([]Quote, error) {
+	p.mu.RLock()
+	table := p.table
+	p.mu.RUnlock()
+
+	var quotes []Quote
+	for _, s := range table.Services {
+		idx := slices.IndexFunc(s.Zones, func(z Zone) bool { return z.contains(req.Destination, req.DestinationState) })
+		if idx < 0 {
+			continue
+		}
+		zone := s.Zones[idx]
+
+		quote := Quote{Service: s.Service, Name: s.Name, TransitDays: zone.TransitDays}
+		ok := true
+		for _, pkg := range req.Packages {
+			price, fits := zone.price(pkg.BillableWeight(table.VolumetricDivisor))
+			if !fits {
+				ok = false
+				break
+			}
+			quote.Price += price
+		}
+		if ok {
+			quotes = append(quotes, quote)
+		}
+	}
+	return quotes, nil
+}
+
+// contains reports whether the CEP, in the state, is in the zone.
+func (z Zone) contains(cep domain.CEP, state string) bool {
+	if z.From != "" && z.From <= cep && cep <= z.To {
+		return true
+	}
+	return slices.Contains(z.States, state)
+}
+
+// price returns the price of a package of the weight, false when it is above the bands and has no extra price.
+func (z Zone) price(weight int64) (currency.BRL, bool) {
+	for _, band := range z.Bands {
+		if weight <= band.MaxWeight {
+			return band.Price, true
+		}
+	}
+	if z.ExtraPerKg <= 0 {
+		return 0, false
+	}
+	last := z.Bands[len(z.Bands)-1]
+	extraKg := (weight - last.MaxWeight + 999) / 1000
+	return last.Price + z.ExtraPerKg.MulQuantity(extraKg), true
+}
@@ -0,0 +1,86 @@
+package shipping
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/currency"
+)
+
+type Config struct {
+	// Origin is the CEP the goods are shipped from.
+	Origin string `env:"SHIPPING_ORIGIN_CEP"`
+	// HandlingDays is the number of business days to pack the order, added to the delivery days of every option.
+	HandlingDays int `env:"SHIPPING_HANDLING_DAYS" envDefault:"1"`
+	// RatesPath is the JSON rate table of a TableProvider, quoted along the carriers.
+	RatesPath string `env:"SHIPPING_RATES"`
+	// FakeProvider quotes the fixed FakeProvider services along the carriers, for development only.
+	FakeProvider bool `env:"SHIPPING_FAKE_PROVIDER" envDefault:"false"`
+}
+
+// ShippingProvider quotes the shipping services of a carrier, or of a rate table.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  shipping_test
+type ShippingProvider interface {
+
+	// Name identifies the provider in the options it quotes.
+	Name() string
+
+	// Quote returns the services available for the packages. A provider serving no service to the
+	// destination returns no option, an error is returned only when the quote could not be made.
+	Quote(ctx context.Context, req QuoteRequest) ([]Quote, error)
+}
+
+// Addresses locates the CEPs.
+type Addresses interface {
+	// State returns the abbreviation of the state of the CEP.
+	State(ctx context.Context, cep domain.CEP) (string, error)
+}
+
+// ProductCatalog gives access to the catalog products, to read their shipping profile.
+type ProductCatalog interface {
+	// Lookup returns the products matching the filter without checking the permissions of the user,
+	// quotes are made for guests too.
+	Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error)
+}
+
+// QuoteRequest describes the packages to ship.
+type QuoteRequest struct {
+	Origin      domain.CEP
+	Destination domain.CEP
+	// DestinationState is the abbreviation of the state of the Destination.
+	DestinationState string
+	Packages         []domain.Package
+	// Value is the declared value of the goods, for the insurance.
+	Value currency.BRL
+}
+
+// Quote is a service quoted by a provider.
+type Quote struct {
+	Service string
+	Name    string
+	Price   currency.BRL
+	// TransitDays is the number of business days between the shipping and the delivery.
+	TransitDays int
+}
+
+// Service quotes the shipping of the carts and orders.
+type Service struct {
+	config    *Config
+	catalog   ProductCatalog
+	addresses Addresses
+	boxes     []domain.ShippingBox
+	providers []ShippingProvider
+}
+
+// NewService creates the shipping service, the items are packed in the boxes and quoted by every provider.
+// Without addresses, the destination state is located by the CEP ranges of the states.
+func NewService(config *Config, catalog ProductCatalog, addresses Addresses, boxes []domain.ShippingBox, providers ...ShippingProvider) *Service {
+	return &Service{
+		config:    config,
+		catalog:   catalog,
+		addresses: addresses,
+		boxes:     boxes,
+		providers: providers,
+	}
+}
@@ -0,0 +1,141 @@
+package shipping
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Quote packs the items and returns the shipping options of every provider to the destination CEP,
+// cheapest first. The providers failing to quote are skipped, domain.ErrShippingUnavailable is returned
+// when no option is left.
+func (s *Service) Quote(ctx context.Context, namespace string, destination domain.CEP, items []domain.CartItem) ([]domain.ShippingOption, error) {
+
+	ctx, span := observability.StartSpan(ctx, "shipping.Quote")
+	defer span.End()
+
+	if !destination.Valid() {
+		return nil, fmt.Errorf("%w: %q", domain.ErrInvalidCEP, string(destination))
+	}
+	origin, err := domain.ParseCEP(s.config.Origin)
+	if err != nil {
+		return nil, fmt.Errorf("origin: %w", err)
+	}
+	state, err := s.state(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	packItems, err := s.packItems(ctx, namespace, items)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	req := QuoteRequest{
+		Origin:           origin,
+		Destination:      destination,
+		DestinationState: state,
+		Packages:         Pack(packItems, s.boxes),
+	}
+	for _, item := range items {
+		req.Value += item.Total()
+	}
+
+	now := time.Now()
+	var options []domain.ShippingOption
+	for _, provider := range s.providers {
+		quotes, err := provider.Quote(ctx, req)
+		if err != nil {
+			span.RecordError(err)
+			slog.ErrorContext(ctx, "failed to quote shipping",
+				"provider", provider.Name(),
+				"destination", destination,
+				"error", err,
+			)
+			continue
+		}
+		for _, q := range quotes {
+			days := q.TransitDays + s.config.HandlingDays
+			options = append(options, domain.ShippingOption{
+				Provider:          provider.Name(),
+				Service:           q.Service,
+				Name:              q.Name,
+				Price:             q.Price,
+				DeliveryDays:      days,
+				EstimatedDelivery: addBusinessDays(now, days),
+			})
+		}
+	}
+	if len(options) == 0 {
+		return nil, domain.ErrShippingUnavailable
+	}
+
+	slices.SortStableFunc(options, func(a, b domain.ShippingOption) int {
+		return cmp.Or(cmp.Compare(a.Price, b.Price), cmp.Compare(a.DeliveryDays, b.DeliveryDays))
+	})
+	return options, nil
+}
+
+// packItems returns the items with the shipping profile of their product or variant.
+func (s *Service) packItems(ctx context.Context, namespace string, items []domain.CartItem) ([]PackItem, error) {
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		if !slices.Contains(ids, item.ProductID) {
+			ids = append(ids, item.ProductID)
+		}
+	}
+	found, err := s.catalog.Lookup(ctx, namespace, dto.ProductFilter{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	products := make(map[uuid.UUID]*domain.Product, len(found))
+	for _, p := range found {
+		products[p.ID] = p
+	}
+
+	packItems := make([]PackItem, 0, len(items))
+	for _, item := range items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, item.ProductID)
+		}
+		packItems = append(packItems, PackItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			Profile:   product.ShippingProfile(item.VariantID),
+		})
+	}
+	return packItems, nil
+}
+
+// state returns the state of the CEP.
+func (s *Service) state(ctx context.Context, cep domain.CEP) (string, error) {
+	if s.addresses != nil {
+		return s.addresses.State(ctx, cep)
+	}
+	state, ok := cep.State()
+	if !ok {
+		return "", fmt.Errorf("%w: %s is not assigned to a state", domain.ErrInvalidCEP, cep)
+	}
+	return state, nil
+}
+
+// addBusinessDays returns the date days business days after t, skipping the weekends.
+func addBusinessDays(t time.Time, days int) time.Time {
+	for days > 0 {
+		t = t.AddDate(0, 0, 1)
+		if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+			days--
+		}
+	}
+	return t
+}
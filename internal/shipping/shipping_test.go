@@ -0,0 +1,219 @@
+package shipping_test
+
+import (
+	"context"
+	"errors"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/shipping"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"os"
+	"testing"
+	"time"
+)
+
+var (
+	smallBox = domain.ShippingBox{Name: "small", Inner: domain.Dimensions{Length: 200, Width: 150, Height: 100}, Weight: 100, MaxWeight: 5000}
+	largeBox = domain.ShippingBox{Name: "large", Inner: domain.Dimensions{Length: 400, Width: 300, Height: 300}, Weight: 300, MaxWeight: 20000}
+)
+
+func TestPack(t *testing.T) {
+	mug := domain.ShippingProfile{Weight: 400, Dimensions: domain.Dimensions{Length: 120, Width: 90, Height: 100}}
+	lamp := domain.ShippingProfile{Weight: 1500, Dimensions: domain.Dimensions{Length: 350, Width: 150, Height: 150}, KeepUpright: true}
+	tv := domain.ShippingProfile{Weight: 12000, Dimensions: domain.Dimensions{Length: 1100, Width: 150, Height: 700}, ShipsAlone: true}
+	mugID, lampID, tvID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name     string
+		items    []shipping.PackItem
+		expected []domain.Package
+	}{
+		{
+			name:  "single unit in the smallest box",
+			items: []shipping.PackItem{{ProductID: mugID, Quantity: 1, Profile: mug}},
+			expected: []domain.Package{
+				{Box: "small", Dimensions: smallBox.Inner, Weight: 500, Items: []domain.PackageItem{{ProductID: mugID, Quantity: 1}}},
+			},
+		},
+		{
+			name:  "units sharing a box",
+			items: []shipping.PackItem{{ProductID: mugID, Quantity: 2, Profile: mug}},
+			expected: []domain.Package{
+				{Box: "small", Dimensions: smallBox.Inner, Weight: 900, Items: []domain.PackageItem{{ProductID: mugID, Quantity: 2}}},
+			},
+		},
+		{
+			name:  "units split across boxes",
+			items: []shipping.PackItem{{ProductID: mugID, Quantity: 3, Profile: mug}},
+			expected: []domain.Package{
+				{Box: "small", Dimensions: smallBox.Inner, Weight: 900, Items: []domain.PackageItem{{ProductID: mugID, Quantity: 2}}},
+				{Box: "small", Dimensions: smallBox.Inner, Weight: 500, Items: []domain.PackageItem{{ProductID: mugID, Quantity: 1}}},
+			},
+		},
+		{
+			name: "mixed items and own packaging",
+			items: []shipping.PackItem{
+				{ProductID: mugID, Quantity: 1, Profile: mug},
+				{ProductID: lampID, Quantity: 1, Profile: lamp},
+				{ProductID: tvID, Quantity: 1, Profile: tv},
+			},
+			expected: []domain.Package{
+				{Dimensions: tv.Dimensions, Weight: 12000, Items: []domain.PackageItem{{ProductID: tvID, Quantity: 1}}},
+				{Box: "large", Dimensions: largeBox.Inner, Weight: 2200, Items: []domain.PackageItem{{ProductID: mugID, Quantity: 1}, {ProductID: lampID, Quantity: 1}}},
+			},
+		},
+		{
+			name:  "unit fitting no box",
+			items: []shipping.PackItem{{ProductID: lampID, Quantity: 1, Profile: domain.ShippingProfile{Weight: 1500, Dimensions: domain.Dimensions{Length: 500, Width: 100, Height: 100}}}},
+			expected: []domain.Package{
+				{Dimensions: domain.Dimensions{Length: 500, Width: 100, Height: 100}, Weight: 1500, Items: []domain.PackageItem{{ProductID: lampID, Quantity: 1}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packages := shipping.Pack(tt.items, []domain.ShippingBox{largeBox, smallBox})
+			assert.Equal(t, tt.expected, packages)
+		})
+	}
+}
+
+func TestPackage_BillableWeight(t *testing.T) {
+	p := domain.Package{Dimensions: domain.Dimensions{Length: 400, Width: 300, Height: 300}, Weight: 2200}
+	assert.Equal(t, int64(6000), p.BillableWeight(6000))
+	assert.Equal(t, int64(2200), p.BillableWeight(0))
+}
+
+func TestTableProvider(t *testing.T) {
+	provider, err := shipping.NewTableProvider("table", os.DirFS("testdata"), "rates.json")
+	require.NoError(t, err)
+
+	light := domain.Package{Weight: 800}
+	heavy := domain.Package{Weight: 7200}
+
+	tests := []struct {
+		name        string
+		destination domain.CEP
+		state       string
+		packages    []domain.Package
+		expected    []shipping.Quote
+	}{
+		{
+			name:        "every service",
+			destination: "01310100",
+			packages:    []domain.Package{light},
+			expected: []shipping.Quote{
+				{Service: "pac", Name: "PAC", Price: currency.NewFromFloat(15.90), TransitDays: 3},
+				{Service: "sedex", Name: "SEDEX", Price: currency.NewFromFloat(25.90), TransitDays: 1},
+			},
+		},
+		{
+			name:        "extra kilograms above the last band",
+			destination: "01310100",
+			packages:    []domain.Package{light, heavy},
+			expected: []shipping.Quote{
+				// 15.90 + 24.90 + 3 started kilograms at 2.50
+				{Service: "pac", Name: "PAC", Price: currency.NewFromFloat(48.30), TransitDays: 3},
+			},
+		},
+		{
+			name:        "zone with fewer services",
+			destination: "40060000",
+			packages:    []domain.Package{light},
+			expected: []shipping.Quote{
+				{Service: "pac", Name: "PAC", Price: currency.NewFromFloat(29.90), TransitDays: 8},
+			},
+		},
+		{
+			name:        "zone of the state",
+			destination: "69900000",
+			state:       "AC",
+			packages:    []domain.Package{light},
+			expected: []shipping.Quote{
+				{Service: "pac", Name: "PAC", Price: currency.NewFromFloat(39.90), TransitDays: 12},
+			},
+		},
+		{
+			name:        "destination out of the zones",
+			destination: "29000000",
+			state:       "ES",
+			packages:    []domain.Package{light},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotes, err := provider.Quote(context.Background(), shipping.QuoteRequest{Destination: tt.destination, DestinationState: tt.state, Packages: tt.packages})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, quotes)
+		})
+	}
+}
+
+func TestNewTableProvider_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(dir+"/rates.json", []byte(`{"services": [{"service": "pac", "zones": [{"from": "0100", "to": "19999999", "bands": [{"max_weight": 1000, "price": "10"}]}]}]}`), 0o644))
+
+	_, err := shipping.NewTableProvider("table", os.DirFS(dir), "rates.json")
+	assert.ErrorIs(t, err, domain.ErrInvalidShippingRates)
+}
+
+func TestQuote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	catalog := NewMockProductCatalog(ctrl)
+	failing := NewMockShippingProvider(ctrl)
+	addresses := NewMockAddresses(ctrl)
+	svc := shipping.NewService(&shipping.Config{Origin: "01310-100", HandlingDays: 1}, catalog, addresses, []domain.ShippingBox{smallBox}, failing, shipping.NewFakeProvider())
+
+	product := &domain.Product{ID: uuid.New(), Shipping: domain.ShippingProfile{Weight: 400, Dimensions: domain.Dimensions{Length: 120, Width: 90, Height: 100}}}
+	items := []domain.CartItem{{ProductID: product.ID, Quantity: 2, UnitPrice: currency.NewFromFloat(30)}}
+
+	addresses.EXPECT().State(gomock.Any(), domain.CEP("40060000")).Return("BA", nil)
+	catalog.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+	failing.EXPECT().Name().Return("carrier").AnyTimes()
+	failing.EXPECT().Quote(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, req shipping.QuoteRequest) ([]shipping.Quote, error) {
+		assert.Equal(t, domain.CEP("01310100"), req.Origin)
+		assert.Equal(t, domain.CEP("40060000"), req.Destination)
+		assert.Equal(t, "BA", req.DestinationState)
+		assert.Equal(t, currency.NewFromFloat(60), req.Value)
+		assert.Len(t, req.Packages, 1)
+		return nil, errors.New("timeout")
+	})
+
+	options, err := svc.Quote(context.Background(), "ns", "40060000", items)
+	require.NoError(t, err)
+	require.Len(t, options, 2)
+
+	// a box of 900 g, volumetric weight of 500 g: a started kilogram
+	assert.Equal(t, "fake:standard", options[0].Code())
+	assert.Equal(t, currency.NewFromFloat(12), options[0].Price)
+	assert.Equal(t, 6, options[0].DeliveryDays)
+	assert.True(t, options[0].EstimatedDelivery.After(time.Now().AddDate(0, 0, 6)))
+	assert.Equal(t, "fake:express", options[1].Code())
+	assert.Equal(t, currency.NewFromFloat(25), options[1].Price)
+	assert.Equal(t, 3, options[1].DeliveryDays)
+}
+
+func TestQuote_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	catalog := NewMockProductCatalog(ctrl)
+	provider := NewMockShippingProvider(ctrl)
+	svc := shipping.NewService(&shipping.Config{Origin: "01310100"}, catalog, nil, nil, provider)
+
+	_, err := svc.Quote(context.Background(), "ns", "4006000", nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidCEP)
+
+	catalog.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return(nil, nil)
+	_, err = svc.Quote(context.Background(), "ns", "40060000", []domain.CartItem{{ProductID: uuid.New(), Quantity: 1}})
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+
+	product := &domain.Product{ID: uuid.New()}
+	catalog.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).Return([]*domain.Product{product}, nil)
+	provider.EXPECT().Quote(gomock.Any(), gomock.Any()).Return(nil, nil)
+	_, err = svc.Quote(context.Background(), "ns", "40060000", []domain.CartItem{{ProductID: product.ID, Quantity: 1}})
+	assert.ErrorIs(t, err, domain.ErrShippingUnavailable)
+}
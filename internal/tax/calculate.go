@@ -0,0 +1,237 @@
+package tax
+
+import (
+	"context"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/HBeserra/GoShop/pkg/observability"
+	"github.com/google/uuid"
+	"time"
+)
+
+// rounding is the rounding of the tax amounts, ABNT NBR 5891 rounds the ties to the even digit.
+const rounding = currency.RoundHalfEven
+
+// Request describes a sale to compute the taxes of.
+type Request struct {
+	// DestinationState is the abbreviation of the state the goods are delivered to, such as "BA".
+	// When empty, it is the state of the DestinationCEP.
+	DestinationState string
+	DestinationCEP   domain.CEP
+	// Contributor is true when the buyer is an ICMS taxpayer: the DIFAL is then due by the buyer
+	// and the IPI is not part of the ICMS base.
+	Contributor bool
+	Lines       []domain.OrderLine
+	// Freight is the shipping charged to the buyer, it is part of the tax base of the lines.
+	Freight currency.BRL
+	// At selects the rule table, the current time when zero.
+	At time.Time
+}
+
+// CalculateOrder computes the taxes of the order delivered to the destination state, the buyer being a final consumer.
+// An empty destination state is the state of the order shipping CEP.
+func (s *Service) CalculateOrder(ctx context.Context, namespace string, order *domain.Order, destinationState string) (*domain.TaxBreakdown, error) {
+	return s.Calculate(ctx, namespace, Request{
+		DestinationState: destinationState,
+		DestinationCEP:   order.ShippingCEP,
+		Lines:            order.Lines,
+		Freight:          order.Shipping - order.ShippingDiscount,
+		At:               order.CreatedAt,
+	})
+}
+
+// Calculate computes the ICMS, DIFAL, IPI, PIS and COFINS of each line and their totals, with the rule table
+// effective at the request time. The line amounts are rounded to the centavo and the totals are their sums.
+func (s *Service) Calculate(ctx context.Context, namespace string, req Request) (*domain.TaxBreakdown, error) {
+
+	ctx, span := observability.StartSpan(ctx, "tax.Calculate")
+	defer span.End()
+
+	if req.DestinationState == "" && req.DestinationCEP != "" {
+		state, err := s.state(ctx, req.DestinationCEP)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		req.DestinationState = state
+	}
+	if !domain.ValidState(s.config.State) || !domain.ValidState(req.DestinationState) {
+		return nil, domain.ErrInvalidState
+	}
+
+	at := req.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	table, err := s.rules.Table(ctx, at)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	products, err := s.products(ctx, namespace, req.Lines)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	breakdown, err := calculate(table, s.config.State, s.config.Regime, req, products)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return breakdown, nil
+}
+
+// state returns the state of the CEP.
+func (s *Service) state(ctx context.Context, cep domain.CEP) (string, error) {
+	if s.addresses != nil {
+		return s.addresses.State(ctx, cep)
+	}
+	state, ok := cep.State()
+	if !ok {
+		return "", fmt.Errorf("%w: %s is not assigned to a state", domain.ErrInvalidCEP, cep)
+	}
+	return state, nil
+}
+
+// products returns the catalog products of the lines by ID.
+func (s *Service) products(ctx context.Context, namespace string, lines []domain.OrderLine) (map[uuid.UUID]*domain.Product, error) {
+	ids := make([]uuid.UUID, 0, len(lines))
+	for _, l := range lines {
+		ids = append(ids, l.ProductID)
+	}
+	found, err := s.catalog.Lookup(ctx, namespace, dto.ProductFilter{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	products := make(map[uuid.UUID]*domain.Product, len(found))
+	for _, p := range found {
+		products[p.ID] = p
+	}
+	return products, nil
+}
+
+// calculate computes the tax breakdown of the request with the rule table.
+func calculate(table *RuleTable, origin, regime string, req Request, products map[uuid.UUID]*domain.Product) (*domain.TaxBreakdown, error) {
+	pisCofins, ok := table.PISCOFINS[regime]
+	if !ok {
+		return nil, fmt.Errorf("%w: pis_cofins.%s", domain.ErrTaxRateNotDefined, regime)
+	}
+
+	destination := req.DestinationState
+	interstate := origin != destination
+
+	internalRate, err := table.ICMS.InternalRate(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	freight, err := allocateFreight(req.Freight, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := &domain.TaxBreakdown{
+		RulesVersion:     table.Version,
+		OriginState:      origin,
+		DestinationState: destination,
+		Lines:            make([]domain.LineTax, 0, len(req.Lines)),
+	}
+
+	for i, l := range req.Lines {
+		product, ok := products[l.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, l.ProductID)
+		}
+		if !domain.ValidNCM(product.NCM) {
+			return nil, fmt.Errorf("%w: product %s", domain.ErrInvalidNCM, product.ID)
+		}
+
+		line := domain.LineTax{
+			ProductID: l.ProductID,
+			VariantID: l.VariantID,
+			NCM:       product.NCM,
+			Origin:    product.Origin,
+			Value:     l.Total() + freight[i],
+		}
+
+		if line.IPI, err = amount(line.Value, table.IPIRate(product.NCM)); err != nil {
+			return nil, err
+		}
+
+		// The IPI is part of the ICMS base when the goods are sold to a final consumer
+		icmsBase := line.Value
+		if !req.Contributor {
+			icmsBase += line.IPI.Amount
+		}
+
+		icmsRate := internalRate
+		if interstate {
+			if icmsRate, err = table.ICMS.InterstateRate(origin, destination, product.Origin); err != nil {
+				return nil, err
+			}
+			// The DIFAL of the sales to final consumers is the gap between the internal rate of the destination
+			// and the interstate rate, on the same base (EC 87/2015)
+			if gap := internalRate.Sub(icmsRate); !req.Contributor && gap.Sign() > 0 {
+				if line.DIFAL, err = amount(icmsBase, gap); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if line.ICMS, err = amount(icmsBase, icmsRate); err != nil {
+			return nil, err
+		}
+
+		// The ICMS is not part of the PIS and COFINS base (STF, RE 574.706)
+		base := line.Value - line.ICMS.Amount
+		if line.PIS, err = amount(base, pisCofins.PIS); err != nil {
+			return nil, err
+		}
+		if line.COFINS, err = amount(base, pisCofins.COFINS); err != nil {
+			return nil, err
+		}
+
+		breakdown.ICMS += line.ICMS.Amount
+		breakdown.DIFAL += line.DIFAL.Amount
+		breakdown.IPI += line.IPI.Amount
+		breakdown.PIS += line.PIS.Amount
+		breakdown.COFINS += line.COFINS.Amount
+		breakdown.Total += line.Total()
+		breakdown.Lines = append(breakdown.Lines, line)
+	}
+
+	return breakdown, nil
+}
+
+// allocateFreight splits the freight across the lines proportionally to their totals.
+func allocateFreight(freight currency.BRL, lines []domain.OrderLine) ([]currency.BRL, error) {
+	if freight == 0 || len(lines) == 0 {
+		return make([]currency.BRL, len(lines)), nil
+	}
+	ratios := make([]int64, len(lines))
+	var total int64
+	for i, l := range lines {
+		ratios[i] = max(l.Total().Cents(), 0)
+		total += ratios[i]
+	}
+	if total == 0 {
+		// only free lines, the freight is split evenly
+		for i := range ratios {
+			ratios[i] = 1
+		}
+	}
+	return freight.Allocate(ratios...)
+}
+
+// amount computes the tax of the base with the rate.
+func amount(base currency.BRL, rate currency.Rate) (domain.TaxAmount, error) {
+	tax, err := base.MulRate(rate, rounding)
+	if err != nil {
+		return domain.TaxAmount{}, err
+	}
+	return domain.TaxAmount{Base: base, Rate: rate, Amount: tax}, nil
+}
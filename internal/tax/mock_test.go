@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination mock_test.go --package tax_test
+//
+
+// Package tax_test is a generated GoMock package.
+package tax_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/HBeserra/GoShop/domain"
+	dto "github.com/HBeserra/GoShop/domain/dto"
+	tax "github.com/HBeserra/GoShop/internal/tax"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRuleSource is a mock of RuleSource interface.
+type MockRuleSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockRuleSourceMockRecorder is the mock recorder for MockRuleSource.
+type MockRuleSourceMockRecorder struct {
+	mock *MockRuleSource
+}
+
+// NewMockRuleSource creates a new mock instance.
+func NewMockRuleSource(ctrl *gomock.Controller) *MockRuleSource {
+	mock := &MockRuleSource{ctrl: ctrl}
+	mock.recorder = &MockRuleSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuleSource) EXPECT() *MockRuleSourceMockRecorder {
+	return m.recorder
+}
+
+// Table mocks base method.
+func (m *MockRuleSource) Table(ctx context.Context, at time.Time) (*tax.RuleTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Table", ctx, at)
+	ret0, _ := ret[0].(*tax.RuleTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Table indicates an expected call of Table.
+func (mr *MockRuleSourceMockRecorder) Table(ctx, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Table", reflect.TypeOf((*MockRuleSource)(nil).Table), ctx, at)
+}
+
+// MockProductCatalog is a mock of ProductCatalog interface.
+type MockProductCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductCatalogMockRecorder
+	isgomock struct{}
+}
+
+// MockProductCatalogMockRecorder is the mock recorder for MockProductCatalog.
+type MockProductCatalogMockRecorder struct {
+	mock *MockProductCatalog
+}
+
+// NewMockProductCatalog creates a new mock instance.
+func NewMockProductCatalog(ctrl *gomock.Controller) *MockProductCatalog {
+	mock := &MockProductCatalog{ctrl: ctrl}
+	mock.recorder = &MockProductCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductCatalog) EXPECT() *MockProductCatalogMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method.
+func (m *MockProductCatalog) Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", ctx, namespace, filter)
+	ret0, _ := ret[0].([]*domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockProductCatalogMockRecorder) Lookup(ctx, namespace, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockProductCatalog)(nil).Lookup), ctx, namespace, filter)
+}
+
+// MockAddresses is a mock of Addresses interface.
+type MockAddresses struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressesMockRecorder
+	isgomock struct{}
+}
+
+// MockAddressesMockRecorder is the mock recorder for MockAddresses.
+type MockAddressesMockRecorder struct {
+	mock *MockAddresses
+}
+
+// NewMockAddresses creates a new mock instance.
+func NewMockAddresses(ctrl *gomock.Controller) *MockAddresses {
+	mock := &MockAddresses{ctrl: ctrl}
+	mock.recorder = &MockAddressesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddresses) EXPECT() *MockAddressesMockRecorder {
+	return m.recorder
+}
+
+// State mocks base method.
+func (m *MockAddresses) State(ctx context.Context, cep domain.CEP) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "State", ctx, cep)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// State indicates an expected call of State.
+func (mr *MockAddressesMockRecorder) State(ctx, cep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "State", reflect.TypeOf((*MockAddresses)(nil).State), ctx, cep)
+}
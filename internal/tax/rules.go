@@ -0,0 +1,175 @@
+package tax
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed rules/*.json
+var embedded embed.FS
+
+// RuleTable holds the tax rates in effect from EffectiveAt until the next table.
+type RuleTable struct {
+	// Version identifies the table in the tax breakdowns, such as "2026.1".
+	Version     string    `json:"version"`
+	EffectiveAt time.Time `json:"effective_at"`
+
+	ICMS ICMSRules `json:"icms"`
+	// IPI maps an NCM code or prefix to the IPI rate, the longest matching prefix wins
+	// and the goods without a match are not subject to the IPI.
+	IPI map[string]currency.Rate `json:"ipi"`
+	// PISCOFINS maps the regime names, such as "cumulative" or "non_cumulative", to their rates.
+	PISCOFINS map[string]PISCOFINSRates `json:"pis_cofins"`
+}
+
+// ICMSRules holds the ICMS rates of the states.
+type ICMSRules struct {
+	// Internal maps each state to the rate of the operations within it, including the FCP when due.
+	Internal map[string]currency.Rate `json:"internal"`
+	// Interstate is the general interstate rate, 12%.
+	Interstate currency.Rate `json:"interstate"`
+	// InterstateReduced is the rate from the South and Southeast states, except Espírito Santo,
+	// to the North, Northeast and Center-West states and Espírito Santo, 7%.
+	InterstateReduced currency.Rate `json:"interstate_reduced"`
+	// Imported is the interstate rate of the imported goods, 4%.
+	Imported currency.Rate `json:"imported"`
+	// Overrides maps an "origin-destination" pair, such as "SP-BA", to a specific interstate rate.
+	Overrides map[string]currency.Rate `json:"overrides"`
+}
+
+// PISCOFINSRates holds the PIS and COFINS rates of a regime.
+type PISCOFINSRates struct {
+	PIS    currency.Rate `json:"pis"`
+	COFINS currency.Rate `json:"cofins"`
+}
+
+// southSoutheast lists the states whose interstate operations to the other regions use the reduced rate.
+var southSoutheast = []string{"MG", "PR", "RJ", "RS", "SC", "SP"}
+
+// InterstateRate returns the ICMS rate of an operation between two different states.
+func (r ICMSRules) InterstateRate(origin, destination string, goods domain.TaxOrigin) (currency.Rate, error) {
+	if goods.Imported() {
+		return required(r.Imported, "icms.imported")
+	}
+	if rate, ok := r.Overrides[origin+"-"+destination]; ok {
+		return rate, nil
+	}
+	if slices.Contains(southSoutheast, origin) && !slices.Contains(southSoutheast, destination) {
+		return required(r.InterstateReduced, "icms.interstate_reduced")
+	}
+	return required(r.Interstate, "icms.interstate")
+}
+
+// InternalRate returns the ICMS rate of the operations within the state.
+func (r ICMSRules) InternalRate(state string) (currency.Rate, error) {
+	return required(r.Internal[state], "icms.internal."+state)
+}
+
+// IPIRate returns the IPI rate of the NCM code, zero when the goods are not subject to the IPI.
+func (t *RuleTable) IPIRate(ncm string) currency.Rate {
+	best := -1
+	var rate currency.Rate
+	for prefix, r := range t.IPI {
+		if strings.HasPrefix(ncm, prefix) && len(prefix) > best {
+			best, rate = len(prefix), r
+		}
+	}
+	return rate
+}
+
+func required(rate currency.Rate, field string) (currency.Rate, error) {
+	if rate.IsZero() {
+		return currency.Rate{}, fmt.Errorf("%w: %s", domain.ErrTaxRateNotDefined, field)
+	}
+	return rate, nil
+}
+
+// FileRules is a RuleSource loaded from the JSON files of a directory, one RuleTable per file, such as
+// "2026-01.json". A new version is published by adding its file, the tables are read again on Reload.
+type FileRules struct {
+	fsys fs.FS
+	dir  string
+
+	mu     sync.RWMutex
+	tables []*RuleTable // sorted by EffectiveAt
+}
+
+// NewEmbeddedRules loads the rule tables shipped with the application, replaced by the directory of
+// Config.RulesPath when the rates change before a new release.
+func NewEmbeddedRules() (*FileRules, error) {
+	return NewFileRules(embedded, "rules")
+}
+
+// NewFileRules loads the rule tables of the directory.
+func NewFileRules(fsys fs.FS, dir string) (*FileRules, error) {
+	r := &FileRules{fsys: fsys, dir: dir}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload lists the directory and parses every table again. A single invalid file fails the whole reload,
+// the loaded tables keep being served until it is fixed.
+func (r *FileRules) Reload() error {
+	entries, err := fs.ReadDir(r.fsys, r.dir)
+	if err != nil {
+		return err
+	}
+
+	var tables []*RuleTable
+	versions := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		name := path.Join(r.dir, entry.Name())
+		data, err := fs.ReadFile(r.fsys, name)
+		if err != nil {
+			return err
+		}
+
+		table := new(RuleTable)
+		if err := json.Unmarshal(data, table); err != nil {
+			return fmt.Errorf("%w: %s: %w", domain.ErrInvalidTaxRules, name, err)
+		}
+		if table.Version == "" || table.EffectiveAt.IsZero() {
+			return fmt.Errorf("%w: %s: missing version or effective_at", domain.ErrInvalidTaxRules, name)
+		}
+		if other, ok := versions[table.Version]; ok {
+			return fmt.Errorf("%w: %s: version %s already defined in %s", domain.ErrInvalidTaxRules, name, table.Version, other)
+		}
+		versions[table.Version] = name
+		tables = append(tables, table)
+	}
+	slices.SortFunc(tables, func(a, b *RuleTable) int {
+		return a.EffectiveAt.Compare(b.EffectiveAt)
+	})
+
+	r.mu.Lock()
+	r.tables = tables
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *FileRules) Table(_ context.Context, at time.Time) (*RuleTable, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.tables) - 1; i >= 0; i-- {
+		if !r.tables[i].EffectiveAt.After(at) {
+			return r.tables[i], nil
+		}
+	}
+	return nil, domain.ErrTaxRulesNotFound
+}
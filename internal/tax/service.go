@@ -0,0 +1,61 @@
+package tax
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/domain/dto"
+	"time"
+)
+
+type Config struct {
+	// State is the abbreviation of the state the goods are shipped from, such as "SP".
+	State string `env:"TAX_STATE" envDefault:"SP"`
+	// Regime is the PIS/COFINS regime of the seller, a key of the RuleTable PISCOFINS regimes.
+	Regime string `env:"TAX_REGIME" envDefault:"non_cumulative"`
+	// RulesPath is a directory of rule tables replacing the embedded ones, see FileRules.
+	RulesPath string `env:"TAX_RULES"`
+}
+
+// RuleSource gives access to the versioned tax rule tables.
+//
+//go:generate mockgen -source=service.go -destination mock_test.go --package  tax_test
+type RuleSource interface {
+
+	// Table returns the rule table effective at the given time, or domain.ErrTaxRulesNotFound.
+	Table(ctx context.Context, at time.Time) (*RuleTable, error)
+}
+
+// ProductCatalog gives access to the catalog products, to read their NCM code and origin.
+type ProductCatalog interface {
+	// Lookup returns the products matching the filter without checking the permissions of the user,
+	// quotes are made for guests too.
+	Lookup(ctx context.Context, namespace string, filter dto.ProductFilter) ([]*domain.Product, error)
+}
+
+// Addresses locates the CEPs.
+type Addresses interface {
+	// State returns the abbreviation of the state of the CEP.
+	State(ctx context.Context, cep domain.CEP) (string, error)
+}
+
+// Service computes the taxes of the orders.
+type Service struct {
+	config    *Config
+	rules     RuleSource
+	catalog   ProductCatalog
+	addresses Addresses
+}
+
+func NewService(config *Config, rules RuleSource, catalog ProductCatalog) *Service {
+	return &Service{
+		config:  config,
+		rules:   rules,
+		catalog: catalog,
+	}
+}
+
+// SetAddresses sets the service locating the destination CEPs, without it the state of a CEP is found
+// by the CEP ranges of the states.
+func (s *Service) SetAddresses(addresses Addresses) {
+	s.addresses = addresses
+}
@@ -0,0 +1,223 @@
+package tax_test
+
+import (
+	"context"
+	"github.com/HBeserra/GoShop/domain"
+	"github.com/HBeserra/GoShop/internal/tax"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var at = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func TestFileRules(t *testing.T) {
+	rules, err := tax.NewFileRules(os.DirFS("testdata"), "rules")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected string
+		err      error
+	}{
+		{"before the first table", time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), "", domain.ErrTaxRulesNotFound},
+		{"first table", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), "2025.1", nil},
+		{"last table", at, "2026.1", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := rules.Table(context.Background(), tt.at)
+			require.ErrorIs(t, err, tt.err)
+			if tt.err == nil {
+				assert.Equal(t, tt.expected, table.Version)
+			}
+		})
+	}
+
+	t.Run("duplicated version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"rules/a.json": {Data: []byte(`{"version": "1", "effective_at": "2026-01-01T00:00:00Z"}`)},
+			"rules/b.json": {Data: []byte(`{"version": "1", "effective_at": "2026-02-01T00:00:00Z"}`)},
+		}
+		_, err := tax.NewFileRules(fsys, "rules")
+		assert.ErrorIs(t, err, domain.ErrInvalidTaxRules)
+	})
+}
+
+func TestInterstateRate(t *testing.T) {
+	rules, err := tax.NewFileRules(os.DirFS("testdata"), "rules")
+	require.NoError(t, err)
+	table, err := rules.Table(context.Background(), at)
+	require.NoError(t, err)
+
+	tests := []struct {
+		origin, destination string
+		goods               domain.TaxOrigin
+		expected            string
+	}{
+		{"SP", "RJ", domain.TaxOriginNational, "0.12"},
+		{"SP", "BA", domain.TaxOriginNational, "0.07"},
+		{"BA", "SP", domain.TaxOriginNational, "0.12"},
+		{"SP", "ES", domain.TaxOriginNational, "0.07"}, // override
+		{"SP", "BA", domain.TaxOriginForeignDirectImport, "0.04"},
+		{"SP", "BA", domain.TaxOriginForeignDirectNoSimilar, "0.07"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin+"-"+tt.destination, func(t *testing.T) {
+			rate, err := table.ICMS.InterstateRate(tt.origin, tt.destination, tt.goods)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rate.String())
+		})
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	notebook := &domain.Product{ID: uuid.New(), NCM: "84713012", Origin: domain.TaxOriginNational}
+	oven := &domain.Product{ID: uuid.New(), NCM: "85166000", Origin: domain.TaxOriginNational}
+	imported := &domain.Product{ID: uuid.New(), NCM: "84713012", Origin: domain.TaxOriginForeignDirectImport}
+	noNCM := &domain.Product{ID: uuid.New()}
+
+	rules, err := tax.NewFileRules(os.DirFS("testdata"), "rules")
+	require.NoError(t, err)
+
+	setup := func(t *testing.T, regime string) *tax.Service {
+		ctrl := gomock.NewController(t)
+		catalog := NewMockProductCatalog(ctrl)
+		catalog.EXPECT().Lookup(gomock.Any(), "ns", gomock.Any()).
+			Return([]*domain.Product{notebook, oven, imported, noNCM}, nil).AnyTimes()
+		return tax.NewService(&tax.Config{State: "SP", Regime: regime}, rules, catalog)
+	}
+
+	t.Run("intrastate sale with freight and IPI", func(t *testing.T) {
+		service := setup(t, "non_cumulative")
+		breakdown, err := service.Calculate(context.Background(), "ns", tax.Request{
+			DestinationState: "SP",
+			Lines: []domain.OrderLine{
+				{ProductID: notebook.ID, UnitPrice: 100000, Quantity: 1},
+				{ProductID: oven.ID, UnitPrice: 25000, Quantity: 2},
+			},
+			Freight: 3000,
+			At:      at,
+		})
+		require.NoError(t, err)
+		require.Len(t, breakdown.Lines, 2)
+		assert.Equal(t, "2026.1", breakdown.RulesVersion)
+
+		// the freight is split 2:1
+		notebookLine, ovenLine := breakdown.Lines[0], breakdown.Lines[1]
+		assert.Equal(t, currency.BRL(102000), notebookLine.Value)
+		assert.Equal(t, currency.BRL(51000), ovenLine.Value)
+
+		// 1020.00 × 18% = 183.60, PIS and COFINS on 1020.00 - 183.60
+		assert.Equal(t, currency.BRL(18360), notebookLine.ICMS.Amount)
+		assert.Equal(t, currency.BRL(0), notebookLine.IPI.Amount)
+		assert.Equal(t, currency.BRL(1380), notebookLine.PIS.Amount)
+		assert.Equal(t, currency.BRL(6357), notebookLine.COFINS.Amount)
+
+		// 510.00 × 3.25% = 16.575, rounded to the even 16.58, which is part of the ICMS base
+		assert.Equal(t, currency.BRL(1658), ovenLine.IPI.Amount)
+		assert.Equal(t, currency.BRL(52658), ovenLine.ICMS.Base)
+		assert.Equal(t, currency.BRL(9478), ovenLine.ICMS.Amount)
+		assert.Equal(t, currency.BRL(685), ovenLine.PIS.Amount)
+		assert.Equal(t, currency.BRL(3156), ovenLine.COFINS.Amount)
+
+		assert.Equal(t, currency.BRL(27838), breakdown.ICMS)
+		assert.Equal(t, currency.BRL(1658), breakdown.IPI)
+		assert.Equal(t, currency.BRL(2065), breakdown.PIS)
+		assert.Equal(t, currency.BRL(9513), breakdown.COFINS)
+		assert.Equal(t, currency.BRL(0), breakdown.DIFAL)
+		assert.Equal(t, currency.BRL(41074), breakdown.Total)
+	})
+
+	tests := []struct {
+		name        string
+		product     *domain.Product
+		destination string
+		contributor bool
+		icms        currency.BRL
+		difal       currency.BRL
+		pis         currency.BRL
+	}{
+		// 7% from the Southeast to the Northeast, DIFAL of 20.5% - 7%
+		{"interstate to final consumer", notebook, "BA", false, 7000, 13500, 1534},
+		// 4% for imported goods, DIFAL of 20% - 4%
+		{"imported goods", imported, "RJ", false, 4000, 16000, 1584},
+		// no DIFAL due by the seller
+		{"interstate to contributor", notebook, "RJ", true, 12000, 0, 1452},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := setup(t, "non_cumulative")
+			breakdown, err := service.Calculate(context.Background(), "ns", tax.Request{
+				DestinationState: tt.destination,
+				Contributor:      tt.contributor,
+				Lines:            []domain.OrderLine{{ProductID: tt.product.ID, UnitPrice: 100000, Quantity: 1}},
+				At:               at,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.icms, breakdown.ICMS)
+			assert.Equal(t, tt.difal, breakdown.DIFAL)
+			assert.Equal(t, tt.pis, breakdown.PIS)
+		})
+	}
+
+	t.Run("the rule table of the order date is used", func(t *testing.T) {
+		service := setup(t, "non_cumulative")
+		order := &domain.Order{
+			CreatedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			Lines:     []domain.OrderLine{{ProductID: oven.ID, UnitPrice: 10000, Quantity: 1}},
+		}
+		breakdown, err := service.CalculateOrder(context.Background(), "ns", order, "SP")
+		require.NoError(t, err)
+		assert.Equal(t, "2025.1", breakdown.RulesVersion)
+		// no IPI in the 2025 table
+		assert.Equal(t, currency.BRL(0), breakdown.IPI)
+	})
+
+	errorTests := []struct {
+		name        string
+		regime      string
+		product     *domain.Product
+		destination string
+		err         error
+	}{
+		{"unknown regime", "simples", notebook, "SP", domain.ErrTaxRateNotDefined},
+		{"missing internal rate", "non_cumulative", notebook, "AM", domain.ErrTaxRateNotDefined},
+		{"invalid state", "non_cumulative", notebook, "XX", domain.ErrInvalidState},
+		{"product without NCM", "non_cumulative", noNCM, "SP", domain.ErrInvalidNCM},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := setup(t, tt.regime)
+			_, err := service.Calculate(context.Background(), "ns", tax.Request{
+				DestinationState: tt.destination,
+				Lines:            []domain.OrderLine{{ProductID: tt.product.ID, UnitPrice: 100000, Quantity: 1}},
+				At:               at,
+			})
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestEmbeddedRules(t *testing.T) {
+	rules, err := tax.NewEmbeddedRules()
+	require.NoError(t, err)
+	table, err := rules.Table(context.Background(), at)
+	require.NoError(t, err)
+
+	// every state has its internal rate
+	for _, state := range []string{"AC", "AL", "AM", "AP", "BA", "CE", "DF", "ES", "GO", "MA", "MG", "MS", "MT", "PA",
+		"PB", "PE", "PI", "PR", "RJ", "RN", "RO", "RR", "RS", "SC", "SE", "SP", "TO"} {
+		_, err := table.ICMS.InternalRate(state)
+		assert.NoError(t, err, state)
+	}
+	_, ok := table.PISCOFINS["non_cumulative"]
+	assert.True(t, ok)
+}
@@ -0,0 +1,141 @@
+// Package boleto computes the barcode and the linha digitável of the boletos de cobrança,
+// following the FEBRABAN layout.
+package boleto
+
+import (
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	currencyCodeBRL = "9"
+	freeFieldLength = 25
+	maxAmount       = currency.BRL(99_999_999_99)
+)
+
+// dueDateBase is the day the due date factor counts from. The factor restarted from 1000 after
+// reaching 9999, on 2025-02-22.
+var dueDateBase = time.Date(1997, 10, 7, 0, 0, 0, 0, time.UTC)
+
+var (
+	ErrInvalidBank      = errors.New("boleto: bank code must have 3 digits")
+	ErrInvalidFreeField = errors.New("boleto: free field must have 25 digits")
+	ErrInvalidAmount    = errors.New("boleto: invalid amount")
+	ErrInvalidDueDate   = errors.New("boleto: invalid due date")
+)
+
+// Boleto holds the fields encoded in the barcode.
+type Boleto struct {
+	// Bank is the 3 digit code of the issuing bank, e.g. "001" for Banco do Brasil.
+	Bank    string
+	DueDate time.Time
+	Amount  currency.BRL
+	// FreeField is the 25 digit "campo livre", its layout is defined by each bank.
+	FreeField string
+}
+
+// Barcode returns the 44 digits encoded in the Interleaved 2 of 5 barcode.
+func (b Boleto) Barcode() (string, error) {
+	if len(b.Bank) != 3 || !isDigits(b.Bank) {
+		return "", ErrInvalidBank
+	}
+	if len(b.FreeField) != freeFieldLength || !isDigits(b.FreeField) {
+		return "", ErrInvalidFreeField
+	}
+	if b.Amount < 0 || b.Amount > maxAmount {
+		return "", ErrInvalidAmount
+	}
+	factor, err := DueDateFactor(b.DueDate)
+	if err != nil {
+		return "", err
+	}
+
+	body := b.Bank + currencyCodeBRL + fmt.Sprintf("%04d%010d", factor, b.Amount.Cents()) + b.FreeField
+	dv := Mod11(body)
+	return body[:4] + strconv.Itoa(dv) + body[4:], nil
+}
+
+// LinhaDigitavel returns the typeable line printed above the barcode, formatted as
+// "AAABC.CCCCX CCCCC.CCCCCY CCCCC.CCCCCZ K UUUUVVVVVVVVVV".
+func (b Boleto) LinhaDigitavel() (string, error) {
+	barcode, err := b.Barcode()
+	if err != nil {
+		return "", err
+	}
+	return LinhaDigitavel(barcode)
+}
+
+// LinhaDigitavel converts a 44 digit barcode into its typeable line.
+func LinhaDigitavel(barcode string) (string, error) {
+	if len(barcode) != 44 || !isDigits(barcode) {
+		return "", fmt.Errorf("boleto: invalid barcode %q", barcode)
+	}
+
+	free := barcode[19:]
+	field1 := barcode[:4] + free[:5]
+	field2 := free[5:15]
+	field3 := free[15:25]
+
+	field1 += strconv.Itoa(Mod10(field1))
+	field2 += strconv.Itoa(Mod10(field2))
+	field3 += strconv.Itoa(Mod10(field3))
+
+	return fmt.Sprintf("%s.%s %s.%s %s.%s %s %s",
+		field1[:5], field1[5:],
+		field2[:5], field2[5:],
+		field3[:5], field3[5:],
+		barcode[4:5],
+		barcode[5:19],
+	), nil
+}
+
+// DueDateFactor returns the number of days between the base date and the due date, restarting from
+// 1000 once 9999 is exceeded.
+func DueDateFactor(due time.Time) (int, error) {
+	due = time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(due.Sub(dueDateBase).Hours() / 24)
+	if days < 1000 {
+		return 0, ErrInvalidDueDate
+	}
+	return (days-1000)%9000 + 1000, nil
+}
+
+// Mod10 computes the check digit of the linha digitável fields: the digits are multiplied by 2 and 1
+// alternately from the right, the digits of the products are summed and the check digit completes
+// the sum to the next multiple of ten.
+func Mod10(digits string) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		p := int(digits[i]-'0') * weight
+		sum += p/10 + p%10
+		weight = 3 - weight
+	}
+	return (10 - sum%10) % 10
+}
+
+// Mod11 computes the general check digit of the barcode: the digits are multiplied by weights from 2 to 9
+// cycling from the right and the check digit is 11 minus the remainder of the sum by 11, or 1 when
+// the result is 0, 10 or 11.
+func Mod11(digits string) int {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	dv := 11 - sum%11
+	if dv == 0 || dv == 10 || dv == 11 {
+		return 1
+	}
+	return dv
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
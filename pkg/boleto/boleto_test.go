@@ -0,0 +1,125 @@
+package boleto
+
+import (
+	"errors"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"testing"
+	"time"
+)
+
+func TestDueDateFactor(t *testing.T) {
+	tests := []struct {
+		due  time.Time
+		want int
+	}{
+		{time.Date(2000, 7, 3, 0, 0, 0, 0, time.UTC), 1000},
+		{time.Date(2025, 2, 21, 0, 0, 0, 0, time.UTC), 9999},
+		{time.Date(2025, 2, 22, 0, 0, 0, 0, time.UTC), 1000},
+		{time.Date(2026, 10, 19, 15, 30, 0, 0, time.UTC), 1604},
+	}
+	for _, tt := range tests {
+		got, err := DueDateFactor(tt.due)
+		if err != nil {
+			t.Fatalf("DueDateFactor(%s) error = %v", tt.due, err)
+		}
+		if got != tt.want {
+			t.Errorf("DueDateFactor(%s) = %d, want %d", tt.due.Format(time.DateOnly), got, tt.want)
+		}
+	}
+
+	if _, err := DueDateFactor(dueDateBase); !errors.Is(err, ErrInvalidDueDate) {
+		t.Errorf("DueDateFactor() error = %v, want %v", err, ErrInvalidDueDate)
+	}
+}
+
+func TestLinhaDigitavel(t *testing.T) {
+	tests := []struct {
+		name    string
+		barcode string
+		want    string
+	}{
+		{
+			name:    "banco do brasil",
+			barcode: "00193373700000001000500940144816060680935031",
+			want:    "00190.50095 40144.816069 06809.350314 3 37370000000100",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LinhaDigitavel(tt.barcode)
+			if err != nil {
+				t.Fatalf("LinhaDigitavel() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("LinhaDigitavel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBarcode(t *testing.T) {
+	tests := []struct {
+		name    string
+		boleto  Boleto
+		want    string
+		wantErr error
+	}{
+		{
+			name: "banco do brasil",
+			boleto: Boleto{
+				Bank:      "001",
+				DueDate:   time.Date(2007, 12, 31, 0, 0, 0, 0, time.UTC),
+				Amount:    currency.NewFromFloat(1),
+				FreeField: "0500940144816060680935031",
+			},
+			want: "00193373700000001000500940144816060680935031",
+		},
+		{
+			name:    "invalid bank",
+			boleto:  Boleto{Bank: "1", DueDate: time.Now(), FreeField: "0500940144816060680935031"},
+			wantErr: ErrInvalidBank,
+		},
+		{
+			name:    "invalid free field",
+			boleto:  Boleto{Bank: "001", DueDate: time.Now(), FreeField: "123"},
+			wantErr: ErrInvalidFreeField,
+		},
+		{
+			name:    "amount too large",
+			boleto:  Boleto{Bank: "001", DueDate: time.Now(), Amount: maxAmount + 1, FreeField: "0500940144816060680935031"},
+			wantErr: ErrInvalidAmount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.boleto.Barcode()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Barcode() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Barcode() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDigits(t *testing.T) {
+	tests := []struct {
+		digits string
+		mod10  int
+		mod11  int
+	}{
+		{"001905009", 5, 7},
+		{"4014481606", 9, 6},
+		{"0680935031", 4, 8},
+		{"0000000000", 0, 1},
+	}
+	for _, tt := range tests {
+		if got := Mod10(tt.digits); got != tt.mod10 {
+			t.Errorf("Mod10(%s) = %d, want %d", tt.digits, got, tt.mod10)
+		}
+		if got := Mod11(tt.digits); got != tt.mod11 {
+			t.Errorf("Mod11(%s) = %d, want %d", tt.digits, got, tt.mod11)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package currency
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 )
 
 // BRL represents a monetary value in Brazilian Reais stored as an integer in centavos.
@@ -10,45 +11,53 @@ type BRL int64
 
 // MarshalJSON customizes the JSON encoding for the BRL type, representing it as a string in the format "major.minor".
 func (b BRL) MarshalJSON() ([]byte, error) {
-
-	value := b / 100
-	cents := b % 100
-	if cents < 0 {
-		cents = -cents
-	}
-
-	return []byte(fmt.Sprintf(`"%d.%02d"`, value, cents)), nil
+	return []byte(`"` + b.String() + `"`), nil
 }
 
-// UnmarshalJSON implements the json.Unmarshaler interface for the BRL type, parsing a stringified monetary value in cents.
+// UnmarshalJSON implements the json.Unmarshaler interface for the BRL type. It accepts a string in any format
+// read by ParseBRL, such as "1234.56" or "R$ 1.234,56", or a JSON number such as 1234.56. Values with more
+// than two decimal places are rejected rather than rounded.
 func (b *BRL) UnmarshalJSON(data []byte) error {
-	var str string
-	if err := json.Unmarshal(data, &str); err != nil {
-		return err
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		parsed, err := ParseBRL(str)
+		if err != nil {
+			return err
+		}
+		*b = parsed
+		return nil
 	}
 
-	// Parse string in the format "%d.%02d" back to BRL (integer representation in cents).
-	var major, minor int
-	if _, err := fmt.Sscanf(str, "%d.%02d", &major, &minor); err != nil {
-		return err
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, data)
 	}
-	if major < 0 {
-		minor = -minor // Ensure the minor part has the same sign as the major part
+	cents, ok := new(big.Rat).SetString(number.String())
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, data)
 	}
-	*b = BRL(major*100 + minor)
+	cents.Mul(cents, big.NewRat(100, 1))
+	if !cents.IsInt() {
+		return fmt.Errorf("%w: %s: more than two decimal places", ErrInvalidAmount, data)
+	}
+	if !cents.Num().IsInt64() {
+		return fmt.Errorf("%w: %s", ErrOverflow, data)
+	}
+	*b = BRL(cents.Num().Int64())
 	return nil
 }
 
 // String formats the BRL value into a human-readable string using the format "major.minor" (e.g., "123.45").
+// See Format for the display format.
 func (b BRL) String() string {
-
-	value := b / 100
-	cents := b % 100
-	if cents < 0 {
-		cents = -cents
-	}
-
-	return fmt.Sprintf("%d.%02d", value, cents)
+	return b.Money().Decimal()
 }
 
 // Cents returns the integer representation of the BRL amount in cents.
@@ -56,26 +65,57 @@ func (b BRL) Cents() int64 {
 	return int64(b)
 }
 
+// Money returns the BRL value as a Money in the BRL currency.
+func (b BRL) Money() Money {
+	return Money{Amount: int64(b), Currency: CurrencyBRL}
+}
+
+// FromMoney converts a Money in the BRL currency, any other currency must be converted first.
+func FromMoney(m Money) (BRL, error) {
+	if m.Currency != CurrencyBRL {
+		return 0, fmt.Errorf("%w: %s is not BRL", ErrCurrencyMismatch, m.Currency)
+	}
+	return BRL(m.Amount), nil
+}
+
 // IsZero checks if the BRL value is equal to zero.
 func (b BRL) IsZero() bool {
 	return b == 0
 }
 
-// Add returns the sum of the current BRL value and another BRL value.
+// Add returns the sum of the current BRL value and another BRL value. It wraps around on overflow, see CheckedAdd.
 func (b BRL) Add(other BRL) BRL {
 	return b + other
 }
 
 // Sub subtracts the value of the given BRL from the receiver and returns the resulting BRL.
+// It wraps around on overflow, see CheckedSub.
 func (b BRL) Sub(other BRL) BRL {
 	return b - other
 }
 
 // Mul multiplies the current BRL value by the given BRL value and returns the resulting BRL value.
+//
+// Deprecated: the product of two amounts is not an amount, use MulQuantity or MulRate.
 func (b BRL) Mul(other BRL) BRL {
 	return b * other
 }
 
+// MulQuantity returns the BRL value multiplied by a quantity, such as the total of a line item.
+// It wraps around on overflow, see CheckedMulQuantity.
+func (b BRL) MulQuantity(quantity int64) BRL {
+	return b * BRL(quantity)
+}
+
+// MulRate returns the BRL value multiplied by a decimal rate, rounded to the centavo with the mode, or ErrOverflow.
+func (b BRL) MulRate(rate Rate, mode RoundingMode) (BRL, error) {
+	m, err := b.Money().MulRate(rate, mode)
+	if err != nil {
+		return 0, err
+	}
+	return BRL(m.Amount), nil
+}
+
 // Div divides the receiver BRL value by the provided BRL value and returns the result or an error if division by zero occurs.
 func (b BRL) Div(other BRL) (BRL, error) {
 	if other == 0 {
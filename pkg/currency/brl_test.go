@@ -38,7 +38,14 @@ func TestUnmarshalJSON(t *testing.T) {
 		{"valid value", `"1234.56"`, BRL(123456), false},
 		{"zero value", `"0.00"`, BRL(0), false},
 		{"negative value", `"-123.45"`, BRL(-12345), false},
-		{"invalid format", `"1234,56"`, 0, true},
+		{"one decimal digit", `"10.5"`, BRL(1050), false},
+		{"pt-BR format", `"R$ 1.234,56"`, BRL(123456), false},
+		{"pt-BR decimal comma", `"1234,56"`, BRL(123456), false},
+		{"json number", `1234.56`, BRL(123456), false},
+		{"json integer", `-10`, BRL(-1000), false},
+		{"null", `null`, BRL(0), false},
+		{"ambiguous separator", `"1,234"`, 0, true},
+		{"too many decimal places", `12.345`, 0, true},
 		{"not a number", `"abc"`, 0, true},
 	}
 	for _, test := range tests {
@@ -70,6 +77,7 @@ func TestString(t *testing.T) {
 		{"integer value", BRL(123456), "1234.56"},
 		{"zero value", BRL(0), "0.00"},
 		{"negative value", BRL(-12345), "-123.45"},
+		{"negative cents", BRL(-50), "-0.50"},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
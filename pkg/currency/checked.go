@@ -0,0 +1,115 @@
+package currency
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sort"
+)
+
+var ErrInvalidRatios = errors.New("invalid allocation ratios")
+
+// CheckedAdd returns the sum of the BRL values, or ErrOverflow if it does not fit in an int64.
+func (b BRL) CheckedAdd(other BRL) (BRL, error) {
+	sum, ok := addInt64(int64(b), int64(other))
+	if !ok {
+		return 0, ErrOverflow
+	}
+	return BRL(sum), nil
+}
+
+// CheckedSub returns the difference of the BRL values, or ErrOverflow if it does not fit in an int64.
+func (b BRL) CheckedSub(other BRL) (BRL, error) {
+	diff, ok := subInt64(int64(b), int64(other))
+	if !ok {
+		return 0, ErrOverflow
+	}
+	return BRL(diff), nil
+}
+
+// CheckedMulQuantity returns the BRL value multiplied by a quantity, or ErrOverflow if it does not fit in an int64.
+func (b BRL) CheckedMulQuantity(quantity int64) (BRL, error) {
+	product, ok := mulInt64(int64(b), quantity)
+	if !ok {
+		return 0, ErrOverflow
+	}
+	return BRL(product), nil
+}
+
+// Allocate splits the BRL amount proportionally to the ratios with exact cents: each part gets the floor
+// of its share and the cents left go to the largest remainders first, ties to the first part. The parts
+// always sum to the amount and a zero ratio always gets a zero part. Allocate(1, 1, 1) is the same as Split(3).
+// It returns ErrInvalidRatios when there is no ratio, a ratio is negative or all of them are zero.
+func (b BRL) Allocate(ratios ...int64) ([]BRL, error) {
+	total := new(big.Int)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, ErrInvalidRatios
+		}
+		total.Add(total, big.NewInt(r))
+	}
+	if total.Sign() == 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	// A negative amount is allocated as its absolute value, so the cents left are also given away from zero
+	amount := new(big.Int).Abs(big.NewInt(int64(b)))
+
+	type remainder struct {
+		index int
+		value *big.Int
+	}
+	parts := make([]BRL, len(ratios))
+	remainders := make([]remainder, 0, len(ratios))
+	allocated := new(big.Int)
+	for i, r := range ratios {
+		if r == 0 {
+			continue
+		}
+		share := new(big.Int).Mul(amount, big.NewInt(r))
+		q, rem := share.QuoRem(share, total, new(big.Int))
+		parts[i] = BRL(q.Int64())
+		allocated.Add(allocated, q)
+		remainders = append(remainders, remainder{index: i, value: rem})
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value.Cmp(remainders[j].value) > 0
+	})
+	left := new(big.Int).Sub(amount, allocated).Int64() // less than the number of parts
+	for i := int64(0); i < left; i++ {
+		parts[remainders[i].index]++
+	}
+
+	if b < 0 {
+		for i := range parts {
+			parts[i] = -parts[i]
+		}
+	}
+	return parts, nil
+}
+
+func addInt64(a, b int64) (int64, bool) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, false
+	}
+	return a + b, true
+}
+
+func subInt64(a, b int64) (int64, bool) {
+	if (b < 0 && a > math.MaxInt64+b) || (b > 0 && a < math.MinInt64+b) {
+		return 0, false
+	}
+	return a - b, true
+}
+
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return product, true
+}
@@ -0,0 +1,150 @@
+package currency
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"slices"
+	"testing"
+	"testing/quick"
+)
+
+func TestCheckedArithmetic(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       func() (BRL, error)
+		expected BRL
+		err      error
+	}{
+		{"add", func() (BRL, error) { return BRL(100).CheckedAdd(50) }, 150, nil},
+		{"add overflow", func() (BRL, error) { return BRL(math.MaxInt64).CheckedAdd(1) }, 0, ErrOverflow},
+		{"add underflow", func() (BRL, error) { return BRL(math.MinInt64).CheckedAdd(-1) }, 0, ErrOverflow},
+		{"sub", func() (BRL, error) { return BRL(100).CheckedSub(150) }, -50, nil},
+		{"sub overflow", func() (BRL, error) { return BRL(math.MaxInt64).CheckedSub(-1) }, 0, ErrOverflow},
+		{"sub underflow", func() (BRL, error) { return BRL(math.MinInt64).CheckedSub(1) }, 0, ErrOverflow},
+		{"mul", func() (BRL, error) { return BRL(1999).CheckedMulQuantity(3) }, 5997, nil},
+		{"mul overflow", func() (BRL, error) { return BRL(math.MaxInt64 / 2).CheckedMulQuantity(3) }, 0, ErrOverflow},
+		{"mul min by minus one", func() (BRL, error) { return BRL(math.MinInt64).CheckedMulQuantity(-1) }, 0, ErrOverflow},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.op()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if result != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   BRL
+		ratios   []int64
+		expected []BRL
+		err      error
+	}{
+		{"even", 1000, []int64{1, 1}, []BRL{500, 500}, nil},
+		{"same as split", 1001, []int64{1, 1, 1, 1}, []BRL{251, 250, 250, 250}, nil},
+		{"largest remainder first", 100, []int64{1, 2}, []BRL{33, 67}, nil},
+		{"zero ratio", 100, []int64{0, 3, 0}, []BRL{0, 100, 0}, nil},
+		{"negative amount", -100, []int64{1, 2}, []BRL{-33, -67}, nil},
+		{"huge ratios", 100, []int64{math.MaxInt64, math.MaxInt64}, []BRL{50, 50}, nil},
+		{"no ratios", 100, nil, nil, ErrInvalidRatios},
+		{"zero ratios", 100, []int64{0, 0}, nil, ErrInvalidRatios},
+		{"negative ratio", 100, []int64{1, -1}, nil, ErrInvalidRatios},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts, err := test.amount.Allocate(test.ratios...)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if !slices.Equal(parts, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, parts)
+			}
+		})
+	}
+}
+
+// The properties are checked on random inputs, including the int64 extremes.
+
+func TestAllocate_PreservesSum(t *testing.T) {
+	property := func(amount int64, ratios []uint32) bool {
+		r := make([]int64, len(ratios))
+		nonZero := false
+		for i, v := range ratios {
+			r[i] = int64(v % 1000)
+			nonZero = nonZero || r[i] > 0
+		}
+
+		parts, err := BRL(amount).Allocate(r...)
+		if !nonZero {
+			return errors.Is(err, ErrInvalidRatios)
+		}
+		if err != nil || len(parts) != len(r) {
+			return false
+		}
+
+		sum := new(big.Int)
+		for i, p := range parts {
+			if r[i] == 0 && p != 0 {
+				return false
+			}
+			// parts share the sign of the amount
+			if (amount > 0 && p < 0) || (amount < 0 && p > 0) {
+				return false
+			}
+			sum.Add(sum, big.NewInt(int64(p)))
+		}
+		return sum.Cmp(big.NewInt(amount)) == 0
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+	for _, amount := range []int64{math.MaxInt64, math.MinInt64, 1, -1} {
+		if !property(amount, []uint32{1, 2, 3, 999}) || !property(amount, []uint32{0, 7}) {
+			t.Errorf("allocation of %d does not preserve the sum", amount)
+		}
+	}
+}
+
+func TestCheckedArithmetic_MatchesExactResult(t *testing.T) {
+	check := func(result BRL, err error, exact *big.Int) bool {
+		if exact.IsInt64() {
+			return err == nil && int64(result) == exact.Int64()
+		}
+		return errors.Is(err, ErrOverflow)
+	}
+
+	add := func(a, b int64) bool {
+		result, err := BRL(a).CheckedAdd(BRL(b))
+		return check(result, err, new(big.Int).Add(big.NewInt(a), big.NewInt(b)))
+	}
+	sub := func(a, b int64) bool {
+		result, err := BRL(a).CheckedSub(BRL(b))
+		return check(result, err, new(big.Int).Sub(big.NewInt(a), big.NewInt(b)))
+	}
+	mul := func(a, b int64) bool {
+		result, err := BRL(a).CheckedMulQuantity(b)
+		return check(result, err, new(big.Int).Mul(big.NewInt(a), big.NewInt(b)))
+	}
+	// small operands, so the results are not always out of range
+	mulSmall := func(a int64, b int32) bool { return mul(a>>32, int64(b)) }
+
+	for name, property := range map[string]any{"add": add, "sub": sub, "mul": mul, "mul small": mulSmall} {
+		if err := quick.Check(property, &quick.Config{MaxCount: 5000}); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	for _, a := range []int64{math.MaxInt64, math.MinInt64, -1, 0, 1} {
+		for _, b := range []int64{math.MaxInt64, math.MinInt64, -1, 0, 1} {
+			if !add(a, b) || !sub(a, b) || !mul(a, b) {
+				t.Errorf("checked arithmetic of %d and %d does not match the exact result", a, b)
+			}
+		}
+	}
+}
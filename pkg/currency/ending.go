@@ -0,0 +1,30 @@
+package currency
+
+// PriceEnding is a "psychological" price rounding: the amount is raised to the next value ending in Ending,
+// counted in minor units within each Step. PriceEnding{Ending: 90, Step: 100} turns 12.34 into 12.90 and
+// 12.95 into 13.90, while PriceEnding{Ending: 980, Step: 1000} on a currency without minor units turns
+// 1234 into 1980. The zero value leaves the amounts unchanged.
+type PriceEnding struct {
+	Ending int64 `json:"ending"`
+	Step   int64 `json:"step"`
+}
+
+// IsZero reports whether the ending is unset.
+func (e PriceEnding) IsZero() bool {
+	return e.Step <= 0
+}
+
+// Apply returns the smallest amount not below m that has the ending. Amounts that are not positive
+// are returned unchanged, as are the amounts when the ending does not fit in the step.
+func (e PriceEnding) Apply(m Money) Money {
+	if e.IsZero() || e.Ending < 0 || e.Ending >= e.Step || m.Amount <= 0 {
+		return m
+	}
+
+	base := m.Amount - m.Amount%e.Step
+	amount := base + e.Ending
+	if amount < m.Amount {
+		amount += e.Step
+	}
+	return Money{Amount: amount, Currency: m.Currency}
+}
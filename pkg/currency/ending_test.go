@@ -0,0 +1,30 @@
+package currency
+
+import "testing"
+
+func TestPriceEnding_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		ending   PriceEnding
+		amount   Money
+		expected int64
+	}{
+		{"raised to .90", PriceEnding{Ending: 90, Step: 100}, New(1234, CurrencyUSD), 1290},
+		{"already ending", PriceEnding{Ending: 90, Step: 100}, New(1290, CurrencyUSD), 1290},
+		{"next unit", PriceEnding{Ending: 90, Step: 100}, New(1295, CurrencyUSD), 1390},
+		{"round unit", PriceEnding{Ending: 90, Step: 100}, New(1300, CurrencyUSD), 1390},
+		{"x9 ending", PriceEnding{Ending: 9, Step: 10}, New(1231, CurrencyEUR), 1239},
+		{"currency without minor units", PriceEnding{Ending: 980, Step: 1000}, New(1234, "JPY"), 1980},
+		{"zero value", PriceEnding{}, New(1234, CurrencyUSD), 1234},
+		{"ending outside the step", PriceEnding{Ending: 100, Step: 100}, New(1234, CurrencyUSD), 1234},
+		{"zero amount", PriceEnding{Ending: 90, Step: 100}, New(0, CurrencyUSD), 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.ending.Apply(test.amount)
+			if result.Amount != test.expected || result.Currency != test.amount.Currency {
+				t.Errorf("expected %d %s, got %v", test.expected, test.amount.Currency, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package currency
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrInvalidInstallmentCount = errors.New("invalid installment count")
+	ErrInstallmentBelowMinimum = errors.New("installment below the minimum value")
+	ErrInvalidInterestRate     = errors.New("invalid interest rate")
+)
+
+// InstallmentPlan splits an amount into monthly installments ("parcelamento").
+type InstallmentPlan struct {
+	Count int `json:"count"`
+	// Installments holds the value of each installment, they differ by one cent at most and sum to Total.
+	Installments []BRL `json:"installments"`
+	// Amount is the financed amount, Total is what the customer pays and Interest the difference.
+	Amount   BRL `json:"amount"`
+	Total    BRL `json:"total"`
+	Interest BRL `json:"interest"`
+	// MonthlyRate is the compound monthly interest rate, e.g. 0.0199 for 1.99%, zero for interest-free plans.
+	MonthlyRate float64 `json:"monthly_rate"`
+	// CETMonthly and CETAnnual are the effective cost ("Custo Efetivo Total") of the plan, the rate which
+	// discounts the rounded installments back to the amount.
+	CETMonthly float64 `json:"cet_monthly"`
+	CETAnnual  float64 `json:"cet_annual"`
+}
+
+// InterestFree reports whether the customer pays the amount without interest ("sem juros").
+func (p InstallmentPlan) InterestFree() bool {
+	return p.Total == p.Amount
+}
+
+// Installment returns the value of the first, and largest, installment.
+func (p InstallmentPlan) Installment() BRL {
+	if len(p.Installments) == 0 {
+		return 0
+	}
+	return p.Installments[0]
+}
+
+// Installments computes a plan of count monthly installments. Without interest the amount is split evenly,
+// otherwise the installment follows the Price table: PMT = amount × r / (1 - (1 + r)^-count), the total
+// being PMT × count rounded to the cent.
+func (b BRL) Installments(count int, monthlyRate float64) (InstallmentPlan, error) {
+	if count < 1 {
+		return InstallmentPlan{}, ErrInvalidInstallmentCount
+	}
+	if monthlyRate < 0 || math.IsNaN(monthlyRate) || math.IsInf(monthlyRate, 0) {
+		return InstallmentPlan{}, ErrInvalidInterestRate
+	}
+
+	total := b
+	if monthlyRate > 0 {
+		n := float64(count)
+		pmt := float64(b) * monthlyRate / (1 - math.Pow(1+monthlyRate, -n))
+		total = BRL(math.Round(pmt * n))
+	}
+
+	plan := InstallmentPlan{
+		Count:        count,
+		Installments: total.Split(count),
+		Amount:       b,
+		Total:        total,
+		Interest:     total - b,
+		MonthlyRate:  monthlyRate,
+	}
+	plan.CETMonthly = effectiveRate(b, plan.Installments)
+	plan.CETAnnual = math.Pow(1+plan.CETMonthly, 12) - 1
+	return plan, nil
+}
+
+// effectiveRate returns the monthly rate r for which the installments, due one month apart starting a month
+// from now, are worth the amount: Σ installment_k / (1 + r)^k = amount. It is found by bisection.
+func effectiveRate(amount BRL, installments []BRL) float64 {
+	presentValue := func(r float64) float64 {
+		pv := 0.0
+		for k, inst := range installments {
+			pv += float64(inst) / math.Pow(1+r, float64(k+1))
+		}
+		return pv
+	}
+
+	if amount <= 0 || presentValue(0) <= float64(amount) {
+		return 0
+	}
+
+	low, high := 0.0, 1.0
+	for presentValue(high) > float64(amount) {
+		high *= 2
+	}
+	for range 100 {
+		mid := (low + high) / 2
+		if presentValue(mid) > float64(amount) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+// InstallmentPolicy defines the installment plans offered to the customers.
+type InstallmentPolicy struct {
+	// MaxInstallments is the largest installment count offered.
+	MaxInstallments int `json:"max_installments" env:"INSTALLMENTS_MAX" envDefault:"12"`
+	// InterestFree is the largest installment count offered without interest.
+	InterestFree int `json:"interest_free" env:"INSTALLMENTS_INTEREST_FREE" envDefault:"1"`
+	// MonthlyRate is the compound monthly interest of the plans with more installments than InterestFree.
+	MonthlyRate float64 `json:"monthly_rate" env:"INSTALLMENTS_MONTHLY_RATE" envDefault:"0"`
+	// MinInstallment is the smallest installment value, plans with smaller installments are not offered.
+	MinInstallment BRL `json:"min_installment" env:"INSTALLMENTS_MIN_VALUE" envDefault:"500"`
+}
+
+// Plan computes the plan of count installments for the amount, applying the interest beyond the
+// interest-free count. The single installment plan is always allowed, whatever the minimum value.
+func (p InstallmentPolicy) Plan(amount BRL, count int) (InstallmentPlan, error) {
+	if count < 1 || count > max(p.MaxInstallments, 1) {
+		return InstallmentPlan{}, ErrInvalidInstallmentCount
+	}
+
+	rate := p.MonthlyRate
+	if count <= p.InterestFree {
+		rate = 0
+	}
+
+	plan, err := amount.Installments(count, rate)
+	if err != nil {
+		return InstallmentPlan{}, err
+	}
+	if count > 1 && plan.Installments[count-1] < p.MinInstallment {
+		return InstallmentPlan{}, ErrInstallmentBelowMinimum
+	}
+	return plan, nil
+}
+
+// Plans lists every plan allowed for the amount, by increasing installment count.
+func (p InstallmentPolicy) Plans(amount BRL) []InstallmentPlan {
+	var plans []InstallmentPlan
+	for count := 1; count <= max(p.MaxInstallments, 1); count++ {
+		plan, err := p.Plan(amount, count)
+		if err != nil {
+			continue
+		}
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+// Best returns the plan advertised with the product: the largest interest-free installment count,
+// or the largest count with interest when only the single installment is interest-free.
+func (p InstallmentPolicy) Best(amount BRL) (InstallmentPlan, bool) {
+	plans := p.Plans(amount)
+	if len(plans) == 0 {
+		return InstallmentPlan{}, false
+	}
+
+	for i := len(plans) - 1; i > 0; i-- {
+		if plans[i].InterestFree() {
+			return plans[i], true
+		}
+	}
+	return plans[len(plans)-1], true
+}
@@ -0,0 +1,160 @@
+package currency
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestInstallments(t *testing.T) {
+	tests := []struct {
+		name         string
+		amount       BRL
+		count        int
+		rate         float64
+		expected     []BRL
+		total        BRL
+		cetMonthly   float64
+		expectedErr  error
+		interestFree bool
+	}{
+		{
+			name:         "interest free",
+			amount:       BRL(99999),
+			count:        12,
+			expected:     []BRL{8334, 8334, 8334, 8333, 8333, 8333, 8333, 8333, 8333, 8333, 8333, 8333},
+			total:        BRL(99999),
+			interestFree: true,
+		},
+		{
+			name:       "price table",
+			amount:     BRL(100000),
+			count:      3,
+			rate:       0.0199,
+			expected:   []BRL{34669, 34669, 34668},
+			total:      BRL(104006),
+			cetMonthly: 0.0199,
+		},
+		{
+			name:       "price table with rounding spread",
+			amount:     BRL(100000),
+			count:      12,
+			rate:       0.0199,
+			expected:   []BRL{9451, 9451, 9450, 9450, 9450, 9450, 9450, 9450, 9450, 9450, 9450, 9450},
+			total:      BRL(113402),
+			cetMonthly: 0.0199,
+		},
+		{
+			name:         "single installment",
+			amount:       BRL(1050),
+			count:        1,
+			rate:         0.0199,
+			expected:     []BRL{1071},
+			total:        BRL(1071),
+			cetMonthly:   0.0199,
+			interestFree: false,
+		},
+		{name: "zero installments", amount: BRL(1000), count: 0, expectedErr: ErrInvalidInstallmentCount},
+		{name: "negative rate", amount: BRL(1000), count: 2, rate: -0.01, expectedErr: ErrInvalidInterestRate},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan, err := test.amount.Installments(test.count, test.rate)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			if len(plan.Installments) != len(test.expected) {
+				t.Fatalf("expected %d installments, got %d", len(test.expected), len(plan.Installments))
+			}
+			var sum BRL
+			for i, inst := range plan.Installments {
+				if inst != test.expected[i] {
+					t.Errorf("installment %d: expected %d, got %d", i+1, test.expected[i], inst)
+				}
+				sum += inst
+			}
+			if sum != plan.Total || plan.Total != test.total {
+				t.Errorf("expected total %d, got %d (installments sum %d)", test.total, plan.Total, sum)
+			}
+			if plan.Interest != plan.Total-test.amount {
+				t.Errorf("expected interest %d, got %d", plan.Total-test.amount, plan.Interest)
+			}
+			if plan.InterestFree() != test.interestFree {
+				t.Errorf("expected interest free %v, got %v", test.interestFree, plan.InterestFree())
+			}
+			if math.Abs(plan.CETMonthly-test.cetMonthly) > 0.0002 {
+				t.Errorf("expected monthly CET %.4f, got %.6f", test.cetMonthly, plan.CETMonthly)
+			}
+			if annual := math.Pow(1+plan.CETMonthly, 12) - 1; math.Abs(plan.CETAnnual-annual) > 1e-9 {
+				t.Errorf("expected annual CET %.6f, got %.6f", annual, plan.CETAnnual)
+			}
+		})
+	}
+}
+
+func TestInstallmentPolicy(t *testing.T) {
+	policy := InstallmentPolicy{
+		MaxInstallments: 12,
+		InterestFree:    6,
+		MonthlyRate:     0.0199,
+		MinInstallment:  BRL(1000),
+	}
+
+	tests := []struct {
+		name       string
+		amount     BRL
+		plans      int
+		bestCount  int
+		bestFree   bool
+		bestAmount BRL
+	}{
+		{name: "all plans", amount: BRL(120000), plans: 12, bestCount: 6, bestFree: true, bestAmount: BRL(20000)},
+		{name: "limited by the minimum installment", amount: BRL(4500), plans: 4, bestCount: 4, bestFree: true, bestAmount: BRL(1125)},
+		{name: "below the minimum", amount: BRL(900), plans: 1, bestCount: 1, bestFree: true, bestAmount: BRL(900)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plans := policy.Plans(test.amount)
+			if len(plans) != test.plans {
+				t.Errorf("expected %d plans, got %d", test.plans, len(plans))
+			}
+			for _, plan := range plans {
+				if plan.Count > 1 && plan.Installments[plan.Count-1] < policy.MinInstallment {
+					t.Errorf("plan %dx has an installment below the minimum", plan.Count)
+				}
+				if plan.InterestFree() != (plan.Count <= policy.InterestFree) {
+					t.Errorf("plan %dx: unexpected interest", plan.Count)
+				}
+			}
+
+			best, ok := policy.Best(test.amount)
+			if !ok {
+				t.Fatalf("expected a best plan")
+			}
+			if best.Count != test.bestCount || best.InterestFree() != test.bestFree || best.Installment() != test.bestAmount {
+				t.Errorf("expected best %dx of %d (free %v), got %dx of %d (free %v)",
+					test.bestCount, test.bestAmount, test.bestFree, best.Count, best.Installment(), best.InterestFree())
+			}
+		})
+	}
+
+	if _, err := policy.Plan(BRL(120000), 13); !errors.Is(err, ErrInvalidInstallmentCount) {
+		t.Errorf("expected ErrInvalidInstallmentCount, got %v", err)
+	}
+	if _, err := policy.Plan(BRL(4500), 5); !errors.Is(err, ErrInstallmentBelowMinimum) {
+		t.Errorf("expected ErrInstallmentBelowMinimum, got %v", err)
+	}
+
+	t.Run("only interest-bearing plans", func(t *testing.T) {
+		best, _ := InstallmentPolicy{MaxInstallments: 10, InterestFree: 1, MonthlyRate: 0.0299, MinInstallment: BRL(500)}.Best(BRL(50000))
+		if best.Count != 10 || best.InterestFree() {
+			t.Errorf("expected the 10x plan with interest, got %dx", best.Count)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package currency
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// Currency is an ISO 4217 alphabetic currency code, such as "BRL".
+type Currency string
+
+const (
+	CurrencyBRL Currency = "BRL"
+	CurrencyUSD Currency = "USD"
+	CurrencyEUR Currency = "EUR"
+)
+
+// currencyInfo holds the ISO 4217 metadata of a currency.
+type currencyInfo struct {
+	numeric    string
+	minorUnits int
+}
+
+// currencies lists the supported ISO 4217 currencies.
+var currencies = map[Currency]currencyInfo{
+	"ARS":       {numeric: "032", minorUnits: 2},
+	"AUD":       {numeric: "036", minorUnits: 2},
+	"BHD":       {numeric: "048", minorUnits: 3},
+	CurrencyBRL: {numeric: "986", minorUnits: 2},
+	"CAD":       {numeric: "124", minorUnits: 2},
+	"CHF":       {numeric: "756", minorUnits: 2},
+	"CLP":       {numeric: "152", minorUnits: 0},
+	"CNY":       {numeric: "156", minorUnits: 2},
+	"COP":       {numeric: "170", minorUnits: 2},
+	CurrencyEUR: {numeric: "978", minorUnits: 2},
+	"GBP":       {numeric: "826", minorUnits: 2},
+	"JPY":       {numeric: "392", minorUnits: 0},
+	"KWD":       {numeric: "414", minorUnits: 3},
+	"MXN":       {numeric: "484", minorUnits: 2},
+	"PYG":       {numeric: "600", minorUnits: 0},
+	CurrencyUSD: {numeric: "840", minorUnits: 2},
+	"UYU":       {numeric: "858", minorUnits: 2},
+}
+
+// ParseCurrency returns the currency of the code, case-insensitive, or ErrUnknownCurrency.
+func ParseCurrency(code string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if !c.Valid() {
+		return "", ErrUnknownCurrency
+	}
+	return c, nil
+}
+
+// Valid reports whether the currency is a supported ISO 4217 code.
+func (c Currency) Valid() bool {
+	_, ok := currencies[c]
+	return ok
+}
+
+// MinorUnits returns the number of decimal places of the currency, e.g. 2 for BRL and 0 for JPY.
+func (c Currency) MinorUnits() int {
+	return currencies[c].minorUnits
+}
+
+// Numeric returns the ISO 4217 numeric code of the currency, e.g. "986" for BRL.
+func (c Currency) Numeric() string {
+	return currencies[c].numeric
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
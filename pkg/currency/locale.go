@@ -0,0 +1,184 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var ErrAmbiguousAmount = errors.New("ambiguous amount")
+
+// Locale selects the separators used to read and write amounts.
+type Locale string
+
+const (
+	// LocalePtBR writes amounts as "R$ 1.234,56".
+	LocalePtBR Locale = "pt-BR"
+	// LocaleEnUS writes amounts as "R$1,234.56".
+	LocaleEnUS Locale = "en-US"
+)
+
+// separators returns the decimal and thousands separators of the locale, pt-BR when it is unknown.
+func (l Locale) separators() (decimal, thousands byte) {
+	if l == LocaleEnUS {
+		return '.', ','
+	}
+	return ',', '.'
+}
+
+// Format formats the amount for display with the currency symbol, e.g. "R$ 1.234,56" in pt-BR
+// and "R$1,234.56" in en-US. An empty locale means pt-BR.
+func (b BRL) Format(locale Locale) string {
+	decimal, thousands := locale.separators()
+
+	digits := b.Money().Decimal()
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	major, minor, _ := strings.Cut(digits, ".")
+
+	var sb strings.Builder
+	sb.WriteString(sign)
+	sb.WriteString("R$")
+	if locale != LocaleEnUS {
+		sb.WriteByte(' ')
+	}
+	for i := range major {
+		if i > 0 && (len(major)-i)%3 == 0 {
+			sb.WriteByte(thousands)
+		}
+		sb.WriteByte(major[i])
+	}
+	sb.WriteByte(decimal)
+	sb.WriteString(minor)
+	return sb.String()
+}
+
+// ParseBRL parses an amount written in pt-BR or en-US, such as "1234.56", "10,5", "R$ 1.234,56" or
+// "BRL 1,234.56". The separators are told apart by their position: the last one is the decimal separator
+// when both are present, and a single separator followed by one or two digits is a decimal separator.
+// A single separator followed by three digits, such as "1.234", is rejected with ErrAmbiguousAmount,
+// use ParseBRLLocale when the locale is known.
+func ParseBRL(s string) (BRL, error) {
+	return parseBRL(s, "")
+}
+
+// ParseBRLLocale parses an amount written with the separators of the locale, see ParseBRL.
+func ParseBRLLocale(s string, locale Locale) (BRL, error) {
+	if locale != LocalePtBR && locale != LocaleEnUS {
+		return 0, fmt.Errorf("currency: unsupported locale %q", locale)
+	}
+	return parseBRL(s, locale)
+}
+
+func parseBRL(input string, locale Locale) (BRL, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %q: %s", ErrInvalidAmount, input, reason)
+	}
+
+	s, negative := trimSymbol(input)
+	if s == "" {
+		return 0, invalid("no digits")
+	}
+
+	var decimal, thousands byte
+	if locale != "" {
+		decimal, thousands = locale.separators()
+	} else {
+		var err error
+		if decimal, thousands, err = detectSeparators(s); err != nil {
+			return 0, fmt.Errorf("%w: %q: %w", ErrInvalidAmount, input, err)
+		}
+	}
+
+	major, minor := s, ""
+	if i := strings.LastIndexByte(s, decimal); i >= 0 && decimal != 0 {
+		major, minor = s[:i], s[i+1:]
+		if minor == "" || len(minor) > 2 || !isDigits(minor) {
+			return 0, invalid("the decimal part must have one or two digits")
+		}
+	}
+	if major == "" {
+		return 0, invalid("missing integer part")
+	}
+
+	if thousands != 0 && strings.IndexByte(major, thousands) >= 0 {
+		groups := strings.Split(major, string(thousands))
+		if len(groups[0]) == 0 || len(groups[0]) > 3 {
+			return 0, invalid("misplaced thousands separator")
+		}
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return 0, invalid("misplaced thousands separator")
+			}
+		}
+		major = strings.Join(groups, "")
+	}
+	if !isDigits(major) {
+		return 0, invalid("unexpected character")
+	}
+
+	value, _ := new(big.Int).SetString(major+minor+strings.Repeat("0", 2-len(minor)), 10)
+	if negative {
+		value.Neg(value)
+	}
+	if !value.IsInt64() {
+		return 0, fmt.Errorf("%w: %q", ErrOverflow, input)
+	}
+	return BRL(value.Int64()), nil
+}
+
+// trimSymbol removes the spaces, the sign and the "R$" or "BRL" symbol around the amount.
+func trimSymbol(s string) (string, bool) {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "\u00a0", " "))
+	negative := false
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		s, negative = strings.TrimSpace(rest), true
+	}
+	for _, symbol := range []string{"R$", "BRL"} {
+		if rest, ok := strings.CutPrefix(s, symbol); ok {
+			s = strings.TrimSpace(rest)
+			break
+		}
+		if rest, ok := strings.CutSuffix(s, symbol); ok {
+			s = strings.TrimSpace(rest)
+			break
+		}
+	}
+	if !negative {
+		if rest, ok := strings.CutPrefix(s, "-"); ok {
+			s, negative = strings.TrimSpace(rest), true
+		}
+	}
+	return s, negative
+}
+
+// detectSeparators guesses the decimal and thousands separators of an amount, a zero separator means absent.
+func detectSeparators(s string) (decimal, thousands byte, err error) {
+	dots, commas := strings.Count(s, "."), strings.Count(s, ",")
+	switch {
+	case dots > 0 && commas > 0:
+		// the last separator is the decimal one, e.g. "1.234,56" or "1,234.56"
+		if strings.LastIndexByte(s, '.') > strings.LastIndexByte(s, ',') {
+			return '.', ',', nil
+		}
+		return ',', '.', nil
+	case dots == 0 && commas == 0:
+		return 0, 0, nil
+	}
+
+	sep, other, count := byte('.'), byte(','), dots
+	if commas > 0 {
+		sep, other, count = ',', '.', commas
+	}
+	if count > 1 {
+		// repeated separators can only group thousands, e.g. "1.234.567"
+		return 0, sep, nil
+	}
+	if len(s)-strings.IndexByte(s, sep)-1 == 3 {
+		return 0, 0, fmt.Errorf("%w: %q may be a decimal or a thousands separator", ErrAmbiguousAmount, sep)
+	}
+	return sep, other, nil
+}
@@ -0,0 +1,149 @@
+package currency
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    BRL
+		locale   Locale
+		expected string
+	}{
+		{123456, LocalePtBR, "R$ 1.234,56"},
+		{123456, "", "R$ 1.234,56"},
+		{123456, LocaleEnUS, "R$1,234.56"},
+		{5, LocalePtBR, "R$ 0,05"},
+		{-123456789, LocalePtBR, "-R$ 1.234.567,89"},
+		{100000, LocaleEnUS, "R$1,000.00"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if result := test.input.Format(test.locale); result != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseBRL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected BRL
+		err      error
+	}{
+		{"1234.56", 123456, nil},
+		{"10.5", 1050, nil},
+		{"10,5", 1050, nil},
+		{"R$ 1.234,56", 123456, nil},
+		{"R$1,234.56", 123456, nil},
+		{"R$\u00a01.234,56", 123456, nil},
+		{"BRL 1,234.56", 123456, nil},
+		{"1.234,56 BRL", 123456, nil},
+		{"-R$ 0,50", -50, nil},
+		{"R$ -0,50", -50, nil},
+		{"1.234.567", 123456700, nil},
+		{"1,234,567.8", 123456780, nil},
+		{"  42  ", 4200, nil},
+		{"1.234", 0, ErrAmbiguousAmount},
+		{"1,234", 0, ErrAmbiguousAmount},
+		{"1.23.4", 0, ErrInvalidAmount},
+		{"12,34.56", 0, ErrInvalidAmount},
+		{"1.2345,67", 0, ErrInvalidAmount},
+		{"1,234,56", 0, ErrInvalidAmount},
+		{"1.234,567", 0, ErrInvalidAmount},
+		{",50", 0, ErrInvalidAmount},
+		{"R$", 0, ErrInvalidAmount},
+		{"US$ 10", 0, ErrInvalidAmount},
+		{"--1", 0, ErrInvalidAmount},
+		{"1e3", 0, ErrInvalidAmount},
+		{"99999999999999999999", 0, ErrOverflow},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			result, err := ParseBRL(test.input)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if err == nil && result != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseBRLLocale(t *testing.T) {
+	tests := []struct {
+		input    string
+		locale   Locale
+		expected BRL
+		err      error
+	}{
+		{"1.234", LocalePtBR, 123400, nil},
+		{"1.234", LocaleEnUS, 0, ErrInvalidAmount},
+		{"1,234", LocaleEnUS, 123400, nil},
+		{"1,5", LocalePtBR, 150, nil},
+		{"1234.56", LocalePtBR, 0, ErrInvalidAmount},
+		{"R$1,234.56", LocaleEnUS, 123456, nil},
+	}
+	for _, test := range tests {
+		t.Run(string(test.locale)+" "+test.input, func(t *testing.T) {
+			result, err := ParseBRLLocale(test.input, test.locale)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if err == nil && result != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, result)
+			}
+		})
+	}
+
+	if _, err := ParseBRLLocale("1,00", "fr-FR"); err == nil {
+		t.Error("expected an error for an unsupported locale")
+	}
+}
+
+func FuzzFormatParse(f *testing.F) {
+	for _, seed := range []int64{0, 5, -50, 123456, -123456789, math.MaxInt64, math.MinInt64} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, cents int64) {
+		b := BRL(cents)
+		for _, locale := range []Locale{LocalePtBR, LocaleEnUS} {
+			formatted := b.Format(locale)
+			if parsed, err := ParseBRLLocale(formatted, locale); err != nil || parsed != b {
+				t.Errorf("ParseBRLLocale(%q, %s) = %d, %v, expected %d", formatted, locale, parsed, err, b)
+			}
+			if parsed, err := ParseBRL(formatted); err != nil || parsed != b {
+				t.Errorf("ParseBRL(%q) = %d, %v, expected %d", formatted, parsed, err, b)
+			}
+		}
+
+		data, err := b.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded BRL
+		if err := decoded.UnmarshalJSON(data); err != nil || decoded != b {
+			t.Errorf("UnmarshalJSON(%s) = %d, %v, expected %d", data, decoded, err, b)
+		}
+	})
+}
+
+func FuzzParseBRL(f *testing.F) {
+	for _, seed := range []string{"1234.56", "R$ 1.234,56", "1.234", "-R$ 0,50", "1,234,567.8", "abc"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		b, err := ParseBRL(input)
+		if err != nil {
+			return
+		}
+		// any accepted amount is read back from its display format
+		if parsed, err := ParseBRL(b.Format(LocalePtBR)); err != nil || parsed != b {
+			t.Errorf("ParseBRL(%q) = %d, its format %q is read as %d, %v", input, b, b.Format(LocalePtBR), parsed, err)
+		}
+	})
+}
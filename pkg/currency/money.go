@@ -0,0 +1,245 @@
+package currency
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrOverflow         = errors.New("amount overflow")
+)
+
+// Money is an amount in the minor unit of its currency, e.g. Money{Amount: 1050, Currency: "BRL"} is R$ 10,50.
+// The operations between two amounts refuse different currencies.
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// New returns the amount, expressed in the minor unit, of the currency.
+func New(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse parses a decimal amount such as "-1234.56" in the currency, with at most its minor unit digits.
+func Parse(amount string, currency Currency) (Money, error) {
+	if !currency.Valid() {
+		return Money{}, ErrUnknownCurrency
+	}
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	major, minor, hasMinor := strings.Cut(s, ".")
+	units := currency.MinorUnits()
+	if major == "" || !isDigits(major) || (hasMinor && (minor == "" || !isDigits(minor))) || len(minor) > units {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	value, _ := new(big.Int).SetString(major+minor+strings.Repeat("0", units-len(minor)), 10)
+	if negative {
+		value.Neg(value)
+	}
+	if !value.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %q", ErrOverflow, amount)
+	}
+	return Money{Amount: value.Int64(), Currency: currency}, nil
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add returns the sum of the amounts, ErrCurrencyMismatch if their currencies differ or ErrOverflow.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	sum, ok := addInt64(m.Amount, other.Amount)
+	if !ok {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of the amounts, ErrCurrencyMismatch if their currencies differ or ErrOverflow.
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	diff, ok := subInt64(m.Amount, other.Amount)
+	if !ok {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: diff, Currency: m.Currency}, nil
+}
+
+// Cmp compares the amounts, returning -1, 0 or +1, or ErrCurrencyMismatch if their currencies differ.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Neg returns the opposite amount, or ErrOverflow for the smallest int64 amount which has no opposite.
+func (m Money) Neg() (Money, error) {
+	neg, ok := subInt64(0, m.Amount)
+	if !ok {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: neg, Currency: m.Currency}, nil
+}
+
+// MulQuantity returns the amount multiplied by a quantity, such as the total of a line item, or ErrOverflow.
+func (m Money) MulQuantity(quantity int64) (Money, error) {
+	product, ok := mulInt64(m.Amount, quantity)
+	if !ok {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// MulRate returns the amount multiplied by a decimal rate, rounded to the minor unit with the mode, or ErrOverflow.
+func (m Money) MulRate(rate Rate, mode RoundingMode) (Money, error) {
+	r := rate.Rat()
+	num := new(big.Int).Mul(big.NewInt(m.Amount), r.Num())
+	result := round(num, r.Denom(), mode)
+	if !result.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: result.Int64(), Currency: m.Currency}, nil
+}
+
+// Convert returns the amount in another currency, rate being the price of one unit of m.Currency in to,
+// e.g. 5.25 to convert USD to BRL. The result is rounded to the minor unit of the target currency.
+func (m Money) Convert(to Currency, rate Rate, mode RoundingMode) (Money, error) {
+	if !to.Valid() {
+		return Money{}, ErrUnknownCurrency
+	}
+	r := rate.Rat()
+	if r.Sign() <= 0 {
+		return Money{}, ErrInvalidRate
+	}
+
+	// amount × rate × 10^(to minor units) / 10^(from minor units)
+	num := new(big.Int).Mul(big.NewInt(m.Amount), r.Num())
+	num.Mul(num, pow10(to.MinorUnits()))
+	den := new(big.Int).Mul(r.Denom(), pow10(m.Currency.MinorUnits()))
+
+	result := round(num, den, mode)
+	if !result.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: result.Int64(), Currency: to}, nil
+}
+
+// Decimal formats the amount with the minor unit digits of the currency, e.g. "-1234.56".
+func (m Money) Decimal() string {
+	units := m.Currency.MinorUnits()
+	abs := new(big.Int).Abs(big.NewInt(m.Amount)).String()
+	if len(abs) <= units {
+		abs = strings.Repeat("0", units-len(abs)+1) + abs
+	}
+
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+	}
+	if units == 0 {
+		return sign + abs
+	}
+	return sign + abs[:len(abs)-units] + "." + abs[len(abs)-units:]
+}
+
+// String formats the amount followed by its currency code, e.g. "1234.56 BRL".
+func (m Money) String() string {
+	return m.Decimal() + " " + string(m.Currency)
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
+
+// moneyJSON is the JSON representation of Money, the amount is a decimal string to keep its precision.
+type moneyJSON struct {
+	Amount   string   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// MarshalJSON encodes the money as {"amount": "1234.56", "currency": "BRL"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+// UnmarshalJSON decodes the representation of MarshalJSON.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v moneyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	parsed, err := Parse(v.Amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, storing the money as its String representation, e.g. "1234.56 BRL".
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan implements sql.Scanner for the values stored by Value.
+func (m *Money) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("currency: cannot scan %T into Money", src)
+	}
+
+	amount, code, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	c, err := ParseCurrency(code)
+	if err != nil {
+		return err
+	}
+	parsed, err := Parse(amount, c)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
@@ -0,0 +1,255 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		code       string
+		minorUnits int
+		err        error
+	}{
+		{"BRL", 2, nil},
+		{"usd", 2, nil},
+		{"JPY", 0, nil},
+		{"KWD", 3, nil},
+		{"XXX", 0, ErrUnknownCurrency},
+		{"", 0, ErrUnknownCurrency},
+	}
+	for _, test := range tests {
+		t.Run(test.code, func(t *testing.T) {
+			c, err := ParseCurrency(test.code)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if err == nil && c.MinorUnits() != test.minorUnits {
+				t.Errorf("expected %d minor units, got %d", test.minorUnits, c.MinorUnits())
+			}
+		})
+	}
+}
+
+func TestMoney_CurrencyMismatch(t *testing.T) {
+	brl := New(1000, CurrencyBRL)
+	usd := New(1000, CurrencyUSD)
+
+	if _, err := brl.Add(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add: expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := brl.Sub(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sub: expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := brl.Cmp(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp: expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := FromMoney(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("FromMoney: expected ErrCurrencyMismatch, got %v", err)
+	}
+
+	sum, err := brl.Add(New(250, CurrencyBRL))
+	if err != nil || sum != New(1250, CurrencyBRL) {
+		t.Errorf("expected 12.50 BRL, got %v (%v)", sum, err)
+	}
+}
+
+func TestMoney_MulRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		rate     string
+		mode     RoundingMode
+		expected int64
+	}{
+		{"exact", 1000, "0.15", RoundHalfEven, 150},
+		{"half even rounds down to even", 25, "0.5", RoundHalfEven, 12},
+		{"half even rounds up to even", 35, "0.5", RoundHalfEven, 18},
+		{"half up", 25, "0.5", RoundHalfUp, 13},
+		{"half up away from zero", -25, "0.5", RoundHalfUp, -13},
+		{"half even negative", -25, "0.5", RoundHalfEven, -12},
+		{"floor", 19, "0.5", RoundFloor, 9},
+		{"floor negative", -19, "0.5", RoundFloor, -10},
+		{"above half", 1999, "0.0725", RoundHalfEven, 145},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := New(test.amount, CurrencyBRL).MulRate(MustParseRate(test.rate), test.mode)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Amount != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, result.Amount)
+			}
+		})
+	}
+
+	if _, err := New(math.MaxInt64, CurrencyBRL).MulRate(MustParseRate("1.5"), RoundHalfEven); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected ErrOverflow, got %v", err)
+	}
+	if result, err := New(1000, CurrencyBRL).MulRate(Rate{}, RoundHalfEven); err != nil || result.Amount != 0 {
+		t.Errorf("expected the zero rate to give 0, got %v, %v", result, err)
+	}
+}
+
+func TestMoney_MulQuantity(t *testing.T) {
+	result, err := New(1999, CurrencyBRL).MulQuantity(3)
+	if err != nil || result != New(5997, CurrencyBRL) {
+		t.Errorf("expected 5997 BRL, got %v, %v", result, err)
+	}
+	if _, err = New(math.MaxInt64/2, CurrencyBRL).MulQuantity(3); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected ErrOverflow, got %v", err)
+	}
+}
+
+func TestMoney_Neg(t *testing.T) {
+	result, err := New(1050, CurrencyUSD).Neg()
+	if err != nil || result != New(-1050, CurrencyUSD) {
+		t.Errorf("expected -1050 USD, got %v, %v", result, err)
+	}
+	if _, err = New(math.MinInt64, CurrencyUSD).Neg(); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected ErrOverflow, got %v", err)
+	}
+}
+
+func TestMoney_Convert(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     Money
+		to       Currency
+		rate     string
+		expected Money
+	}{
+		{"usd to brl", New(1000, CurrencyUSD), CurrencyBRL, "5.4321", New(5432, CurrencyBRL)},
+		{"brl to jpy drops the cents", New(10000, CurrencyBRL), "JPY", "27.8", New(2780, "JPY")},
+		{"jpy to brl", New(2780, "JPY"), CurrencyBRL, "0.036", New(10008, CurrencyBRL)},
+		{"usd to kwd", New(100, CurrencyUSD), "KWD", "0.3071", New(307, "KWD")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.from.Convert(test.to, MustParseRate(test.rate), RoundHalfEven)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+
+	if _, err := New(100, CurrencyUSD).Convert(CurrencyBRL, NewRate(0, 1), RoundHalfEven); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("expected ErrInvalidRate, got %v", err)
+	}
+	if _, err := New(100, CurrencyUSD).Convert(CurrencyBRL, Rate{}, RoundHalfEven); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("expected ErrInvalidRate for the zero value, got %v", err)
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		money    Money
+		expected string
+	}{
+		{New(123456, CurrencyBRL), "1234.56 BRL"},
+		{New(-5, CurrencyBRL), "-0.05 BRL"},
+		{New(-50, CurrencyUSD), "-0.50 USD"},
+		{New(1500, "JPY"), "1500 JPY"},
+		{New(1, "KWD"), "0.001 KWD"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if result := test.money.String(); result != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		currency Currency
+		expected int64
+		err      error
+	}{
+		{"1234.56", CurrencyBRL, 123456, nil},
+		{"-0.5", CurrencyBRL, -50, nil},
+		{"10", CurrencyBRL, 1000, nil},
+		{"1500", "JPY", 1500, nil},
+		{"1.234", CurrencyBRL, 0, ErrInvalidAmount},
+		{"1.5", "JPY", 0, ErrInvalidAmount},
+		{"1,50", CurrencyBRL, 0, ErrInvalidAmount},
+		{".50", CurrencyBRL, 0, ErrInvalidAmount},
+		{"1.", CurrencyBRL, 0, ErrInvalidAmount},
+		{"99999999999999999999", CurrencyBRL, 0, ErrOverflow},
+		{"1.00", "XXX", 0, ErrUnknownCurrency},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			result, err := Parse(test.input, test.currency)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if err == nil && result.Amount != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, result.Amount)
+			}
+		})
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	m := New(-123456, CurrencyUSD)
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"amount":"-1234.56","currency":"USD"}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+
+	var decoded Money
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded != m {
+		t.Errorf("expected %v, got %v", m, decoded)
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount":"1.001","currency":"BRL"}`), &decoded); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestMoney_SQL(t *testing.T) {
+	m := New(9990, "JPY")
+	value, err := m.Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "9990 JPY" {
+		t.Errorf("unexpected value: %v", value)
+	}
+
+	var scanned Money
+	if err := scanned.Scan([]byte(value.(string))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scanned != m {
+		t.Errorf("expected %v, got %v", m, scanned)
+	}
+
+	if err := scanned.Scan(int64(10)); err == nil {
+		t.Error("expected an error scanning an integer")
+	}
+}
+
+func TestBRL_MulQuantity(t *testing.T) {
+	if result := BRL(1999).MulQuantity(3); result != 5997 {
+		t.Errorf("expected 5997, got %d", result)
+	}
+	if result, err := BRL(1999).MulRate(MustParseRate("0.1"), RoundHalfUp); err != nil || result != 200 {
+		t.Errorf("expected 200, got %d, %v", result, err)
+	}
+}
@@ -0,0 +1,184 @@
+package currency
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrInvalidRate = errors.New("invalid rate")
+
+// RoundingMode defines how a result is rounded to the minor unit.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest value, ties to the even one ("banker's rounding").
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest value, ties away from zero.
+	RoundHalfUp
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+)
+
+// Rate is an exact decimal factor, such as an exchange or tax rate. The zero value is a rate of zero.
+type Rate struct {
+	rat *big.Rat
+}
+
+// ParseRate parses a decimal rate such as "1.0575" or "0.18". Fractions such as "1/3" are accepted too.
+func ParseRate(s string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Rate{}, ErrInvalidRate
+	}
+	return Rate{rat: r}, nil
+}
+
+// MustParseRate is like ParseRate but panics if the rate is invalid, it is meant for constants.
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// NewRate returns the rate num/den, den must not be zero.
+func NewRate(num, den int64) Rate {
+	return Rate{rat: big.NewRat(num, den)}
+}
+
+// Rat returns the rate as a big.Rat.
+func (r Rate) Rat() *big.Rat {
+	if r.rat == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(r.rat)
+}
+
+// IsZero reports whether the rate is zero, which is the case of an unset one.
+func (r Rate) IsZero() bool {
+	return r.rat == nil || r.rat.Sign() == 0
+}
+
+// Add returns the sum r + other, such as the internal rate from an interstate rate and its gap.
+func (r Rate) Add(other Rate) Rate {
+	rat := r.Rat()
+	return Rate{rat: rat.Add(rat, other.Rat())}
+}
+
+// Sub returns the difference r - other, such as the gap between two tax rates.
+func (r Rate) Sub(other Rate) Rate {
+	rat := r.Rat()
+	return Rate{rat: rat.Sub(rat, other.Rat())}
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the rate.
+func (r Rate) Sign() int {
+	return r.Rat().Sign()
+}
+
+// Inverse returns 1/r, such as the BRL to USD rate from the USD to BRL one. It fails on a zero rate.
+func (r Rate) Inverse() (Rate, error) {
+	rat := r.Rat()
+	if rat.Sign() == 0 {
+		return Rate{}, ErrInvalidRate
+	}
+	return Rate{rat: rat.Inv(rat)}, nil
+}
+
+// String formats the rate as a decimal, such as "5.4321", or as a fraction when it has no finite decimal expansion.
+func (r Rate) String() string {
+	rat := r.Rat()
+	if digits, ok := decimalDigits(rat.Denom()); ok {
+		return rat.FloatString(digits)
+	}
+	return rat.RatString()
+}
+
+// MarshalText encodes the rate as its String representation, so no precision is lost.
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes a rate accepted by ParseRate.
+func (r *Rate) UnmarshalText(text []byte) error {
+	parsed, err := ParseRate(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidRate, text)
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, storing the rate as text.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner for the values stored by Value.
+func (r *Rate) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return r.UnmarshalText([]byte(v))
+	case []byte:
+		return r.UnmarshalText(v)
+	}
+	return fmt.Errorf("currency: cannot scan %T into Rate", src)
+}
+
+// decimalDigits returns the number of decimal places needed to write 1/den exactly,
+// which is only possible when den has no prime factors other than 2 and 5.
+func decimalDigits(den *big.Int) (int, bool) {
+	d := new(big.Int).Set(den)
+	two, five := big.NewInt(2), big.NewInt(5)
+	var twos, fives int
+	m := new(big.Int)
+	for d.Cmp(big.NewInt(1)) > 0 {
+		switch {
+		case m.Mod(d, two).Sign() == 0:
+			d.Quo(d, two)
+			twos++
+		case m.Mod(d, five).Sign() == 0:
+			d.Quo(d, five)
+			fives++
+		default:
+			return 0, false
+		}
+	}
+	return max(twos, fives), true
+}
+
+// round divides num by den and rounds the quotient to an integer with the mode.
+func round(num, den *big.Int, mode RoundingMode) *big.Int {
+	if den.Sign() < 0 {
+		num, den = new(big.Int).Neg(num), new(big.Int).Neg(den)
+	}
+
+	// Floor division: q = floor(num / den) and 0 <= rem < den
+	q, rem := new(big.Int).DivMod(num, den, new(big.Int))
+	if rem.Sign() == 0 || mode == RoundFloor {
+		return q
+	}
+
+	// Compare the remainder with the half of the divisor
+	cmp := new(big.Int).Lsh(rem, 1).Cmp(den)
+	switch {
+	case cmp > 0:
+		q.Add(q, big.NewInt(1))
+	case cmp == 0:
+		switch mode {
+		case RoundHalfEven:
+			if q.Bit(0) == 1 {
+				q.Add(q, big.NewInt(1))
+			}
+		case RoundHalfUp:
+			// away from zero: the floored quotient of a negative tie is already the value away from zero
+			if num.Sign() >= 0 {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+	return q
+}
@@ -0,0 +1,69 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestRate_String(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"5.4321", "5.4321"},
+		{"0.18", "0.18"},
+		{"2", "2"},
+		{"1/8", "0.125"},
+		{"1/3", "1/3"},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			if result := MustParseRate(test.input).String(); result != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestRate_Inverse(t *testing.T) {
+	inverse, err := MustParseRate("0.2").Inverse()
+	if err != nil || inverse.String() != "5" {
+		t.Errorf("expected 5, got %v (%v)", inverse, err)
+	}
+	if _, err := NewRate(0, 1).Inverse(); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("expected ErrInvalidRate, got %v", err)
+	}
+}
+
+func TestRate_Zero(t *testing.T) {
+	var zero Rate
+	if !zero.IsZero() || zero.Sign() != 0 || zero.String() != "0" {
+		t.Errorf("expected the zero value to be 0, got %s", zero)
+	}
+	if sum := zero.Add(MustParseRate("0.18")); sum.String() != "0.18" {
+		t.Errorf("expected 0.18, got %s", sum)
+	}
+	if _, err := zero.Inverse(); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("expected ErrInvalidRate, got %v", err)
+	}
+}
+
+func TestRate_JSON(t *testing.T) {
+	var v struct {
+		Rate Rate `json:"rate"`
+	}
+	if err := json.Unmarshal([]byte(`{"rate":"5.4321"}`), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"rate":"5.4321"}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"rate":"abc"}`), &v); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("expected ErrInvalidRate, got %v", err)
+	}
+}
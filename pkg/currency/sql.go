@@ -0,0 +1,91 @@
+package currency
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// StorageMode defines how BRL values are written to the database.
+type StorageMode int32
+
+const (
+	// StorageCents stores the amount as an integer number of centavos, in a BIGINT column.
+	StorageCents StorageMode = iota
+	// StorageDecimal stores the amount as a decimal string such as "1234.56", in a DECIMAL(20,2) column.
+	StorageDecimal
+)
+
+var storageMode atomic.Int32
+
+// SetStorageMode selects how every BRL value is written to the database, StorageCents by default.
+// It is meant to be called once at startup, before the schema is migrated.
+func SetStorageMode(mode StorageMode) {
+	storageMode.Store(int32(mode))
+}
+
+// CurrentStorageMode returns the mode selected by SetStorageMode.
+func CurrentStorageMode() StorageMode {
+	return StorageMode(storageMode.Load())
+}
+
+// GormDataType returns the column type of the storage mode, it is used by GORM when migrating the schema.
+func (BRL) GormDataType() string {
+	if CurrentStorageMode() == StorageDecimal {
+		return "decimal(20,2)"
+	}
+	return "bigint"
+}
+
+// Value implements driver.Valuer, writing the amount with the storage mode.
+func (b BRL) Value() (driver.Value, error) {
+	if CurrentStorageMode() == StorageDecimal {
+		return b.String(), nil
+	}
+	return int64(b), nil
+}
+
+// Scan implements sql.Scanner. It reads both storage modes regardless of the current one, so a column
+// can be migrated from one mode to the other: integers are centavos, strings and floats are decimal reais.
+// In StorageCents mode a string without a decimal point is centavos too, as some drivers return the
+// BIGINT columns as text.
+func (b *BRL) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*b = 0
+		return nil
+	case int64:
+		*b = BRL(v)
+		return nil
+	case float64:
+		return b.scanDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	case string:
+		return b.scanText(v)
+	case []byte:
+		return b.scanText(string(v))
+	}
+	return fmt.Errorf("currency: cannot scan %T into BRL", src)
+}
+
+func (b *BRL) scanText(s string) error {
+	if CurrentStorageMode() == StorageCents && !strings.Contains(s, ".") {
+		cents, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+		*b = BRL(cents)
+		return nil
+	}
+	return b.scanDecimal(s)
+}
+
+func (b *BRL) scanDecimal(s string) error {
+	m, err := Parse(s, CurrencyBRL)
+	if err != nil {
+		return err
+	}
+	*b = BRL(m.Amount)
+	return nil
+}
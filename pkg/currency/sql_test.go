@@ -0,0 +1,96 @@
+package currency
+
+import "testing"
+
+func TestBRL_Value(t *testing.T) {
+	defer SetStorageMode(StorageCents)
+
+	tests := []struct {
+		name     string
+		mode     StorageMode
+		input    BRL
+		expected any
+		dataType string
+	}{
+		{"cents", StorageCents, 123456, int64(123456), "bigint"},
+		{"decimal", StorageDecimal, 123456, "1234.56", "decimal(20,2)"},
+		{"negative decimal", StorageDecimal, -5, "-0.05", "decimal(20,2)"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			SetStorageMode(test.mode)
+			value, err := test.input.Value()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value != test.expected {
+				t.Errorf("expected %#v, got %#v", test.expected, value)
+			}
+			if dataType := test.input.GormDataType(); dataType != test.dataType {
+				t.Errorf("expected data type %s, got %s", test.dataType, dataType)
+			}
+		})
+	}
+}
+
+func TestBRL_Scan(t *testing.T) {
+	defer SetStorageMode(StorageCents)
+
+	tests := []struct {
+		name     string
+		mode     StorageMode
+		src      any
+		expected BRL
+		hasError bool
+	}{
+		{"cents", StorageCents, int64(123456), 123456, false},
+		{"cents as text", StorageCents, []byte("123456"), 123456, false},
+		{"negative cents as text", StorageCents, "-5", -5, false},
+		{"decimal string", StorageCents, "1234.56", 123456, false},
+		{"decimal bytes", StorageDecimal, []byte("-0.05"), -5, false},
+		{"decimal without cents", StorageDecimal, []byte("12"), 1200, false},
+		{"float", StorageDecimal, 1234.5, 123450, false},
+		{"null", StorageCents, nil, 0, false},
+		{"too many decimal places", StorageDecimal, "1.234", 0, true},
+		{"invalid cents", StorageCents, "12a", 0, true},
+		{"float with fractions of cents", StorageDecimal, 0.001, 0, true},
+		{"unsupported type", StorageCents, true, 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			SetStorageMode(test.mode)
+			result := BRL(99)
+			err := result.Scan(test.src)
+			if test.hasError {
+				if err == nil {
+					t.Errorf("expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestBRL_ValueScan(t *testing.T) {
+	defer SetStorageMode(StorageCents)
+
+	for _, mode := range []StorageMode{StorageCents, StorageDecimal} {
+		SetStorageMode(mode)
+		for _, b := range []BRL{0, 1, -1, 123456, -9223372036854775808, 9223372036854775807} {
+			value, err := b.Value()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var scanned BRL
+			if err := scanned.Scan(value); err != nil || scanned != b {
+				t.Errorf("mode %d: %d stored as %#v is scanned as %d, %v", mode, b, value, scanned, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package nfe
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidAccessKey = errors.New("invalid access key")
+
+// stateCodes maps the states to their IBGE codes, the cUF of the NF-e.
+var stateCodes = map[string]string{
+	"RO": "11", "AC": "12", "AM": "13", "RR": "14", "PA": "15", "AP": "16", "TO": "17",
+	"MA": "21", "PI": "22", "CE": "23", "RN": "24", "PB": "25", "PE": "26", "AL": "27", "SE": "28", "BA": "29",
+	"MG": "31", "ES": "32", "RJ": "33", "SP": "35",
+	"PR": "41", "SC": "42", "RS": "43",
+	"MS": "50", "MT": "51", "GO": "52", "DF": "53",
+}
+
+// StateCode returns the IBGE code of the state, such as "35" for "SP".
+func StateCode(uf string) (string, bool) {
+	code, ok := stateCodes[uf]
+	return code, ok
+}
+
+// AccessKey holds the fields of the chave de acesso, the 44 digits identifying an NF-e.
+type AccessKey struct {
+	State  string // UF of the issuer, such as "SP"
+	Issued time.Time
+	CNPJ   string // 14 digits
+	Model  int    // 55 for the NF-e
+	Series int
+	Number int64
+	// EmissionType is the tpEmis, 1 for the normal emission.
+	EmissionType int
+	// Code is the cNF, a random number of 8 digits chosen by the issuer.
+	Code int
+}
+
+// String returns the 44 digits of the key, the last one being the check digit.
+func (k AccessKey) String() (string, error) {
+	uf, ok := StateCode(k.State)
+	if !ok {
+		return "", fmt.Errorf("%w: unknown state %q", ErrInvalidAccessKey, k.State)
+	}
+	if len(k.CNPJ) != 14 || !digits(k.CNPJ) {
+		return "", fmt.Errorf("%w: invalid CNPJ %q", ErrInvalidAccessKey, k.CNPJ)
+	}
+	if k.Series < 0 || k.Series > 999 || k.Number < 1 || k.Number > 999999999 || k.Code < 0 || k.Code > 99999999 {
+		return "", fmt.Errorf("%w: series, number or code out of range", ErrInvalidAccessKey)
+	}
+
+	key := fmt.Sprintf("%s%s%s%02d%03d%09d%d%08d", uf, k.Issued.Format("0601"), k.CNPJ, k.Model, k.Series, k.Number, k.EmissionType, k.Code)
+	return key + string('0'+CheckDigit(key)), nil
+}
+
+// CheckDigit computes the modulo 11 check digit of the 43 first digits of an access key: the digits are
+// weighted from 2 to 9 from the right, and a remainder of 0 or 1 gives the digit 0.
+func CheckDigit(key string) byte {
+	sum, weight := 0, 2
+	for i := len(key) - 1; i >= 0; i-- {
+		sum += int(key[i]-'0') * weight
+		if weight++; weight > 9 {
+			weight = 2
+		}
+	}
+	if rem := sum % 11; rem > 1 {
+		return byte(11 - rem)
+	}
+	return 0
+}
+
+// ValidAccessKey reports whether the key has 44 digits and a valid check digit.
+func ValidAccessKey(key string) bool {
+	return len(key) == 44 && digits(key) && key[43]-'0' == CheckDigit(key[:43])
+}
+
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// KeyOf returns the access key of an NF-e document, read from the Id of its infNFe.
+func KeyOf(document []byte) (string, error) {
+	root, err := parse(document)
+	if err != nil {
+		return "", err
+	}
+	inf := root.find("infNFe")
+	if inf == nil {
+		return "", fmt.Errorf("%w: infNFe not found", ErrInvalidAccessKey)
+	}
+	key := strings.TrimPrefix(inf.attribute("Id"), "NFe")
+	if !ValidAccessKey(key) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidAccessKey, key)
+	}
+	return key, nil
+}
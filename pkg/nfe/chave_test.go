@@ -0,0 +1,57 @@
+package nfe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		expected byte
+	}{
+		// example of the Manual de Orientação do Contribuinte
+		{"manual example", "5206043300991100250655012000000780026730161", 5},
+		{"remainder below two", "3526100000000000019155001000000001100000001", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := CheckDigit(test.key); result != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestAccessKey(t *testing.T) {
+	issued := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	key := AccessKey{State: "SP", Issued: issued, CNPJ: "00000000000191", Model: 55, Series: 1, Number: 1, EmissionType: 1, Code: 1}
+
+	result, err := key.String()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "35261000000000000191550010000000011000000010" {
+		t.Errorf("unexpected key %s", result)
+	}
+	if !ValidAccessKey(result) {
+		t.Errorf("expected %s to be valid", result)
+	}
+	if ValidAccessKey(result[:43] + "1") {
+		t.Error("expected a wrong check digit to be invalid")
+	}
+
+	invalid := []AccessKey{
+		{State: "XX", Issued: issued, CNPJ: "00000000000191", Model: 55, Number: 1},
+		{State: "SP", Issued: issued, CNPJ: "123", Model: 55, Number: 1},
+		{State: "SP", Issued: issued, CNPJ: "00000000000191", Model: 55, Number: 0},
+		{State: "SP", Issued: issued, CNPJ: "00000000000191", Model: 55, Number: 1, Series: 1000},
+	}
+	for _, k := range invalid {
+		if _, err := k.String(); !errors.Is(err, ErrInvalidAccessKey) {
+			t.Errorf("expected ErrInvalidAccessKey for %+v, got %v", k, err)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+// Package nfe generates and signs the XML of the NF-e 4.00, the Brazilian electronic invoice of goods.
+// The signed XML is validated against the bundled XSDs with libxml2, so the package is built with cgo.
+package nfe
+
+import (
+	"fmt"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"math/big"
+	"time"
+)
+
+const (
+	Namespace = "http://www.portalfiscal.inf.br/nfe"
+	Version   = "4.00"
+	Model     = 55
+
+	// HomologationName replaces the recipient name in the homologation environment, as required by SEFAZ.
+	HomologationName = "NF-E EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL"
+)
+
+// Environment is the tpAmb of the NF-e.
+type Environment int
+
+const (
+	EnvironmentProduction   Environment = 1
+	EnvironmentHomologation Environment = 2
+)
+
+// Tax regimes of the issuer, the CRT of the NF-e.
+const (
+	CRTSimplesNacional = 1
+	CRTRegimeNormal    = 3
+)
+
+// Payment methods, the tPag of the NF-e.
+const (
+	PaymentCash       = "01"
+	PaymentCreditCard = "03"
+	PaymentBoleto     = "15"
+	PaymentPix        = "17"
+	PaymentOther      = "99"
+)
+
+// Address is the address of the issuer or of the recipient.
+type Address struct {
+	Street     string // xLgr
+	Number     string // nro
+	Complement string // xCpl
+	District   string // xBairro
+	CityCode   string // cMun, the IBGE code of the city
+	City       string // xMun
+	State      string // UF
+	ZipCode    string // CEP, 8 digits
+	Phone      string // fone
+}
+
+// Issuer is the company issuing the NF-e.
+type Issuer struct {
+	CNPJ      string
+	Name      string
+	TradeName string
+	IE        string // state registration
+	CRT       int
+	Address   Address
+}
+
+// Recipient is the buyer, identified either by CPF or by CNPJ.
+type Recipient struct {
+	CPF   string
+	CNPJ  string
+	Name  string
+	Email string
+	// IE is the state registration of a Contributor.
+	IE          string
+	Contributor bool
+	Address     Address
+}
+
+// Tax is a tax of an item.
+type Tax struct {
+	Base   currency.BRL
+	Rate   currency.Rate
+	Amount currency.BRL
+}
+
+// Item is a line of the NF-e.
+type Item struct {
+	Code        string // cProd
+	EAN         string // cEAN, "SEM GTIN" when empty
+	Description string // xProd
+	NCM         string
+	CFOP        string
+	Unit        string // uCom, "UN" when empty
+	Quantity    int64
+	UnitPrice   currency.BRL
+	Discount    currency.BRL
+	Freight     currency.BRL
+	Origin      int
+
+	ICMS   Tax
+	IPI    Tax
+	PIS    Tax
+	COFINS Tax
+	// DIFAL is the ICMS due to the destination state, with the internal rate of the destination state as Rate.
+	DIFAL Tax
+	// InterstateRate is the ICMS rate of an interstate operation, reported with the DIFAL.
+	InterstateRate currency.Rate
+}
+
+// Total returns the gross value of the item, vProd.
+func (i Item) Total() currency.BRL {
+	return i.UnitPrice.MulQuantity(i.Quantity)
+}
+
+// Payment is a payment of the NF-e.
+type Payment struct {
+	Method string // tPag
+	Amount currency.BRL
+}
+
+// Freight modes, the modFrete of the NF-e.
+const (
+	FreightByIssuer = 0
+	FreightNone     = 9
+)
+
+// Document is an NF-e to be generated.
+type Document struct {
+	Environment Environment
+	Issuer      Issuer
+	Recipient   Recipient
+	Series      int
+	Number      int64
+	// Code is the cNF, a random number of 8 digits which must differ from the Number.
+	Code      int
+	Issued    time.Time
+	Operation string // natOp, such as "Venda de mercadoria"
+	Items     []Item
+	Payments  []Payment
+	// FreightMode is the modFrete, FreightByIssuer when zero.
+	FreightMode    int
+	AdditionalInfo string
+	// Software identifies the application issuing the NF-e, the verProc.
+	Software string
+}
+
+// Totals holds the totals of the NF-e, the ICMSTot group.
+type Totals struct {
+	ICMSBase currency.BRL
+	ICMS     currency.BRL
+	DIFAL    currency.BRL
+	Products currency.BRL
+	Freight  currency.BRL
+	Discount currency.BRL
+	IPI      currency.BRL
+	PIS      currency.BRL
+	COFINS   currency.BRL
+	// Invoice is the vNF: products - discount + freight + IPI.
+	Invoice currency.BRL
+}
+
+// Totals computes the totals of the items.
+func (d *Document) Totals() Totals {
+	var t Totals
+	for _, i := range d.Items {
+		t.ICMSBase += i.ICMS.Base
+		t.ICMS += i.ICMS.Amount
+		t.DIFAL += i.DIFAL.Amount
+		t.Products += i.Total()
+		t.Freight += i.Freight
+		t.Discount += i.Discount
+		t.IPI += i.IPI.Amount
+		t.PIS += i.PIS.Amount
+		t.COFINS += i.COFINS.Amount
+	}
+	t.Invoice = t.Products - t.Discount + t.Freight + t.IPI
+	return t
+}
+
+// AccessKey returns the chave de acesso of the document.
+func (d *Document) AccessKey() (string, error) {
+	return AccessKey{
+		State:        d.Issuer.Address.State,
+		Issued:       d.Issued,
+		CNPJ:         d.Issuer.CNPJ,
+		Model:        Model,
+		Series:       d.Series,
+		Number:       d.Number,
+		EmissionType: 1,
+		Code:         d.Code,
+	}.String()
+}
+
+// Interstate reports whether the recipient is in another state than the issuer.
+func (d *Document) Interstate() bool {
+	return d.Issuer.Address.State != d.Recipient.Address.State
+}
+
+// XML validates the document and returns its unsigned XML, see Sign.
+func (d *Document) XML() ([]byte, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	key, err := d.AccessKey()
+	if err != nil {
+		return nil, err
+	}
+
+	inf := element("infNFe", d.ide(key), d.emit(), d.dest())
+	inf.attr("Id", "NFe"+key).attr("versao", Version)
+	for i, item := range d.Items {
+		inf.add(d.det(i+1, item))
+	}
+	inf.add(d.total(), d.transp(), d.pag())
+	if d.AdditionalInfo != "" {
+		inf.add(element("infAdic", leaf("infCpl", d.AdditionalInfo)))
+	}
+
+	root := element("NFe", inf)
+	root.ns = Namespace
+	return root.setNS(Namespace).canonical(""), nil
+}
+
+func (d *Document) ide(key string) *node {
+	uf, _ := StateCode(d.Issuer.Address.State)
+	idDest := "1"
+	if d.Interstate() {
+		idDest = "2"
+	}
+	final := "1"
+	if d.Recipient.Contributor {
+		final = "0"
+	}
+	return element("ide",
+		leaf("cUF", uf),
+		leaf("cNF", fmt.Sprintf("%08d", d.Code)),
+		leaf("natOp", d.Operation),
+		leaf("mod", fmt.Sprint(Model)),
+		leaf("serie", fmt.Sprint(d.Series)),
+		leaf("nNF", fmt.Sprint(d.Number)),
+		leaf("dhEmi", d.Issued.Format("2006-01-02T15:04:05-07:00")),
+		leaf("tpNF", "1"),
+		leaf("idDest", idDest),
+		leaf("cMunFG", d.Issuer.Address.CityCode),
+		leaf("tpImp", "1"),
+		leaf("tpEmis", "1"),
+		leaf("cDV", key[43:]),
+		leaf("tpAmb", fmt.Sprint(int(d.Environment))),
+		leaf("finNFe", "1"),
+		leaf("indFinal", final),
+		leaf("indPres", "2"), // internet sale
+		leaf("indIntermed", "0"),
+		leaf("procEmi", "0"),
+		leaf("verProc", d.Software),
+	)
+}
+
+func (d *Document) emit() *node {
+	i := d.Issuer
+	return element("emit",
+		leaf("CNPJ", i.CNPJ),
+		leaf("xNome", i.Name),
+		optional("xFant", i.TradeName),
+		address("enderEmit", i.Address),
+		leaf("IE", i.IE),
+		leaf("CRT", fmt.Sprint(i.CRT)),
+	)
+}
+
+func (d *Document) dest() *node {
+	r := d.Recipient
+	name := r.Name
+	if d.Environment == EnvironmentHomologation {
+		name = HomologationName
+	}
+
+	dest := element("dest")
+	if r.CNPJ != "" {
+		dest.add(leaf("CNPJ", r.CNPJ))
+	} else {
+		dest.add(leaf("CPF", r.CPF))
+	}
+	dest.add(leaf("xNome", name), address("enderDest", r.Address))
+	if r.Contributor {
+		dest.add(leaf("indIEDest", "1"), leaf("IE", r.IE))
+	} else {
+		dest.add(leaf("indIEDest", "9"))
+	}
+	return dest.add(optional("email", r.Email))
+}
+
+func address(name string, a Address) *node {
+	return element(name,
+		leaf("xLgr", a.Street),
+		leaf("nro", a.Number),
+		optional("xCpl", a.Complement),
+		leaf("xBairro", a.District),
+		leaf("cMun", a.CityCode),
+		leaf("xMun", a.City),
+		leaf("UF", a.State),
+		leaf("CEP", a.ZipCode),
+		leaf("cPais", "1058"),
+		leaf("xPais", "Brasil"),
+		optional("fone", a.Phone),
+	)
+}
+
+func (d *Document) det(n int, i Item) *node {
+	ean, unit := i.EAN, i.Unit
+	if ean == "" {
+		ean = "SEM GTIN"
+	}
+	if unit == "" {
+		unit = "UN"
+	}
+	quantity := fmt.Sprintf("%d.0000", i.Quantity)
+
+	prod := element("prod",
+		leaf("cProd", i.Code),
+		leaf("cEAN", ean),
+		leaf("xProd", i.Description),
+		leaf("NCM", i.NCM),
+		leaf("CFOP", i.CFOP),
+		leaf("uCom", unit),
+		leaf("qCom", quantity),
+		leaf("vUnCom", i.UnitPrice.String()),
+		leaf("vProd", i.Total().String()),
+		leaf("cEANTrib", ean),
+		leaf("uTrib", unit),
+		leaf("qTrib", quantity),
+		leaf("vUnTrib", i.UnitPrice.String()),
+	)
+	if i.Freight > 0 {
+		prod.add(leaf("vFrete", i.Freight.String()))
+	}
+	if i.Discount > 0 {
+		prod.add(leaf("vDesc", i.Discount.String()))
+	}
+	prod.add(leaf("indTot", "1"))
+
+	imposto := element("imposto", d.icms(i))
+	if i.IPI.Amount > 0 {
+		imposto.add(element("IPI",
+			leaf("cEnq", "999"),
+			element("IPITrib",
+				leaf("CST", "50"),
+				leaf("vBC", i.IPI.Base.String()),
+				leaf("pIPI", percent(i.IPI.Rate, 4)),
+				leaf("vIPI", i.IPI.Amount.String()),
+			),
+		))
+	}
+	imposto.add(d.contribution("PIS", i.PIS), d.contribution("COFINS", i.COFINS))
+	if i.DIFAL.Amount > 0 {
+		imposto.add(element("ICMSUFDest",
+			leaf("vBCUFDest", i.DIFAL.Base.String()),
+			leaf("pICMSUFDest", percent(i.DIFAL.Rate, 4)),
+			leaf("pICMSInter", percent(i.InterstateRate, 2)),
+			leaf("pICMSInterPart", "100.0000"),
+			leaf("vICMSUFDest", i.DIFAL.Amount.String()),
+			leaf("vICMSUFRemet", "0.00"),
+		))
+	}
+
+	return element("det", prod, imposto).attr("nItem", fmt.Sprint(n))
+}
+
+func (d *Document) icms(i Item) *node {
+	origin := leaf("orig", fmt.Sprint(i.Origin))
+	if d.Issuer.CRT == CRTSimplesNacional {
+		return element("ICMS", element("ICMSSN102", origin, leaf("CSOSN", "102")))
+	}
+	if i.ICMS.Amount == 0 {
+		return element("ICMS", element("ICMS40", origin, leaf("CST", "40")))
+	}
+	return element("ICMS", element("ICMS00",
+		origin,
+		leaf("CST", "00"),
+		leaf("modBC", "3"),
+		leaf("vBC", i.ICMS.Base.String()),
+		leaf("pICMS", percent(i.ICMS.Rate, 4)),
+		leaf("vICMS", i.ICMS.Amount.String()),
+	))
+}
+
+// contribution returns the PIS or COFINS group of the tax.
+func (d *Document) contribution(name string, t Tax) *node {
+	switch {
+	case d.Issuer.CRT == CRTSimplesNacional:
+		return element(name, element(name+"Outr",
+			leaf("CST", "99"),
+			leaf("vBC", "0.00"),
+			leaf("p"+name, "0.0000"),
+			leaf("v"+name, "0.00"),
+		))
+	case t.Amount == 0:
+		return element(name, element(name+"NT", leaf("CST", "07")))
+	}
+	return element(name, element(name+"Aliq",
+		leaf("CST", "01"),
+		leaf("vBC", t.Base.String()),
+		leaf("p"+name, percent(t.Rate, 4)),
+		leaf("v"+name, t.Amount.String()),
+	))
+}
+
+func (d *Document) total() *node {
+	t := d.Totals()
+	tot := element("ICMSTot",
+		leaf("vBC", t.ICMSBase.String()),
+		leaf("vICMS", t.ICMS.String()),
+		leaf("vICMSDeson", "0.00"),
+	)
+	if t.DIFAL > 0 {
+		tot.add(leaf("vFCPUFDest", "0.00"), leaf("vICMSUFDest", t.DIFAL.String()), leaf("vICMSUFRemet", "0.00"))
+	}
+	tot.add(
+		leaf("vFCP", "0.00"),
+		leaf("vBCST", "0.00"),
+		leaf("vST", "0.00"),
+		leaf("vFCPST", "0.00"),
+		leaf("vFCPSTRet", "0.00"),
+		leaf("vProd", t.Products.String()),
+		leaf("vFrete", t.Freight.String()),
+		leaf("vSeg", "0.00"),
+		leaf("vDesc", t.Discount.String()),
+		leaf("vII", "0.00"),
+		leaf("vIPI", t.IPI.String()),
+		leaf("vIPIDevol", "0.00"),
+		leaf("vPIS", t.PIS.String()),
+		leaf("vCOFINS", t.COFINS.String()),
+		leaf("vOutro", "0.00"),
+		leaf("vNF", t.Invoice.String()),
+	)
+	return element("total", tot)
+}
+
+func (d *Document) transp() *node {
+	return element("transp", leaf("modFrete", fmt.Sprint(d.FreightMode)))
+}
+
+func (d *Document) pag() *node {
+	pag := element("pag")
+	for _, p := range d.Payments {
+		det := element("detPag", leaf("tPag", p.Method), leaf("vPag", p.Amount.String()))
+		if p.Method == PaymentCreditCard || p.Method == PaymentPix {
+			// payment not integrated with the invoicing system
+			det.add(element("card", leaf("tpIntegra", "2")))
+		}
+		pag.add(det)
+	}
+	return pag
+}
+
+// percent formats the rate as a percentage with the decimal places, such as "18.0000".
+func percent(rate currency.Rate, decimals int) string {
+	r := new(big.Rat).Set(rate.Rat())
+	if rate.IsZero() {
+		r.SetInt64(0)
+	}
+	return r.Mul(r, big.NewRat(100, 1)).FloatString(decimals)
+}
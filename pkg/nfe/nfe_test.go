@@ -0,0 +1,305 @@
+package nfe
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"math/big"
+	"os"
+	"path/filepath"
+	"software.sslmate.com/src/go-pkcs12"
+	"strings"
+	"testing"
+	"time"
+)
+
+var issued = time.Date(2026, 10, 19, 10, 30, 0, 0, time.FixedZone("BRT", -3*60*60))
+
+func testDocument() *Document {
+	return &Document{
+		Environment: EnvironmentHomologation,
+		Issuer: Issuer{
+			CNPJ: "00000000000191",
+			Name: "GoShop Comercio Ltda",
+			IE:   "123456789012",
+			CRT:  CRTRegimeNormal,
+			Address: Address{
+				Street: "Avenida Paulista", Number: "1000", District: "Bela Vista",
+				CityCode: "3550308", City: "Sao Paulo", State: "SP", ZipCode: "01310100",
+			},
+		},
+		Recipient: Recipient{
+			CPF:  "12345678909",
+			Name: "Maria & Filhos",
+			Address: Address{
+				Street: "Avenida Sete de Setembro", Number: "10", District: "Centro",
+				CityCode: "2927408", City: "Salvador", State: "BA", ZipCode: "40060000",
+			},
+		},
+		Series:    1,
+		Number:    42,
+		Code:      12345678,
+		Issued:    issued,
+		Operation: "Venda de mercadoria",
+		Software:  "GoShop 1.0",
+		Items: []Item{{
+			Code:           "NB-01",
+			Description:    "Notebook <14\">",
+			NCM:            "84713012",
+			CFOP:           "6108",
+			Quantity:       1,
+			UnitPrice:      100000,
+			Discount:       5000,
+			Freight:        2000,
+			ICMS:           Tax{Base: 97000, Rate: currency.MustParseRate("0.07"), Amount: 6790},
+			PIS:            Tax{Base: 90210, Rate: currency.MustParseRate("0.0165"), Amount: 1488},
+			COFINS:         Tax{Base: 90210, Rate: currency.MustParseRate("0.076"), Amount: 6856},
+			DIFAL:          Tax{Base: 97000, Rate: currency.MustParseRate("0.205"), Amount: 13095},
+			InterstateRate: currency.MustParseRate("0.07"),
+		}},
+		Payments: []Payment{{Method: PaymentPix, Amount: 97000}},
+	}
+}
+
+func TestDocument_XML(t *testing.T) {
+	doc := testDocument()
+	data, err := doc.XML()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	xml := string(data)
+
+	expected := []string{
+		`<NFe xmlns="http://www.portalfiscal.inf.br/nfe"><infNFe Id="NFe35261000000000000191550010000000421123456783" versao="4.00"><ide><cUF>35</cUF><cNF>12345678</cNF>`,
+		`<dhEmi>2026-10-19T10:30:00-03:00</dhEmi>`,
+		`<idDest>2</idDest>`,
+		`<cDV>3</cDV><tpAmb>2</tpAmb>`,
+		`<xNome>` + HomologationName + `</xNome>`,
+		`<indIEDest>9</indIEDest>`,
+		`<xProd>Notebook &lt;14"&gt;</xProd>`,
+		`<vProd>1000.00</vProd>`,
+		`<vFrete>20.00</vFrete><vDesc>50.00</vDesc><indTot>1</indTot>`,
+		`<ICMS00><orig>0</orig><CST>00</CST><modBC>3</modBC><vBC>970.00</vBC><pICMS>7.0000</pICMS><vICMS>67.90</vICMS></ICMS00>`,
+		`<PISAliq><CST>01</CST><vBC>902.10</vBC><pPIS>1.6500</pPIS><vPIS>14.88</vPIS></PISAliq>`,
+		`<ICMSUFDest><vBCUFDest>970.00</vBCUFDest><pICMSUFDest>20.5000</pICMSUFDest><pICMSInter>7.00</pICMSInter>`,
+		`<vICMSUFDest>130.95</vICMSUFDest>`,
+		`<vNF>970.00</vNF>`,
+		`<detPag><tPag>17</tPag><vPag>970.00</vPag><card><tpIntegra>2</tpIntegra></card></detPag>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(xml, e) {
+			t.Errorf("expected the XML to contain %s", e)
+		}
+	}
+	if strings.Contains(xml, "<IPI>") {
+		t.Error("expected no IPI group without IPI")
+	}
+
+	key, _ := doc.AccessKey()
+	if !ValidAccessKey(key) {
+		t.Errorf("invalid access key %s", key)
+	}
+}
+
+func TestDocument_Validate(t *testing.T) {
+	doc := testDocument()
+	doc.Issuer.CNPJ = "123"
+	doc.Recipient.Address.ZipCode = "40060-000"
+	doc.Items[0].CFOP = "5102"
+	doc.Items[0].NCM = "8471"
+	doc.Code = 42
+	doc.Payments = nil
+
+	err := doc.Validate()
+	if !errors.Is(err, ErrSchema) {
+		t.Fatalf("expected ErrSchema, got %v", err)
+	}
+	for _, field := range []string{"ide.cNF", "emit.CNPJ", "dest.enderDest.CEP", "det[1].prod.NCM", "det[1].prod.CFOP", "pag.detPag"} {
+		if !strings.Contains(err.Error(), field+":") {
+			t.Errorf("expected a violation of %s in %v", field, err)
+		}
+	}
+	if _, err := doc.XML(); !errors.Is(err, ErrSchema) {
+		t.Errorf("expected XML to validate the document, got %v", err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	cert := testCertificate(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	data, err := testDocument().XML()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	signed, err := Sign(data, cert, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(signed, data[:len(data)-len("</NFe>")]) || !bytes.HasSuffix(signed, []byte("</Signature></NFe>")) {
+		t.Error("expected the signature to be appended to the NFe")
+	}
+	if !bytes.Contains(signed, []byte(`<Reference URI="#NFe35261000000000000191550010000000421123456783">`)) {
+		t.Error("expected the signature to reference the infNFe")
+	}
+
+	leaf, err := Verify(signed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if leaf.Subject.CommonName != cert.Leaf.Subject.CommonName {
+		t.Errorf("unexpected certificate %s", leaf.Subject)
+	}
+
+	t.Run("tampered document", func(t *testing.T) {
+		tampered := bytes.Replace(signed, []byte("<vNF>970.00</vNF>"), []byte("<vNF>9.70</vNF>"), 1)
+		if _, err := Verify(tampered); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("expected ErrInvalidSignature, got %v", err)
+		}
+	})
+
+	t.Run("indented document", func(t *testing.T) {
+		// the whitespace outside the infNFe is not signed
+		indented := bytes.Replace(signed, []byte("</infNFe><Signature"), []byte("</infNFe>\n  <Signature"), 1)
+		if _, err := Verify(indented); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("already signed", func(t *testing.T) {
+		if _, err := Sign(signed, cert, time.Now()); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("expected ErrInvalidSignature, got %v", err)
+		}
+	})
+
+	t.Run("expired certificate", func(t *testing.T) {
+		if _, err := Sign(data, cert, time.Now().Add(2*time.Hour)); !errors.Is(err, ErrCertificateExpired) {
+			t.Errorf("expected ErrCertificateExpired, got %v", err)
+		}
+	})
+}
+
+func TestLoadCertificate(t *testing.T) {
+	cert := testCertificate(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	pfx, err := pkcs12.Modern.Encode(cert.Key, cert.Leaf, nil, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "a1.pfx")
+	if err := os.WriteFile(path, pfx, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadCertificate(path, "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !loaded.Key.Equal(cert.Key) || !loaded.Leaf.Equal(cert.Leaf) {
+		t.Error("expected the loaded certificate to match")
+	}
+
+	if _, err := LoadCertificate(path, "wrong"); !errors.Is(err, ErrInvalidCertificate) {
+		t.Errorf("expected ErrInvalidCertificate for a wrong password, got %v", err)
+	}
+}
+
+func TestParseCertificate(t *testing.T) {
+	cert := testCertificate(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	key, err := x509.MarshalPKCS8PrivateKey(cert.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Leaf.Raw}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key})...)
+	parsed, err := ParseCertificate(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !parsed.Key.Equal(cert.Key) || !parsed.Leaf.Equal(cert.Leaf) {
+		t.Error("expected the parsed certificate to match")
+	}
+
+	other := testCertificate(t, time.Now(), time.Now().Add(time.Hour))
+	mismatch := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: other.Leaf.Raw}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key})...)
+	if _, err := ParseCertificate(mismatch); !errors.Is(err, ErrInvalidCertificate) {
+		t.Errorf("expected ErrInvalidCertificate, got %v", err)
+	}
+}
+
+func TestValidateXML(t *testing.T) {
+	cert := testCertificate(t, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+	simples := testDocument()
+	simples.Issuer.CRT = CRTSimplesNacional
+	simples.Recipient.Address = simples.Issuer.Address
+	simples.Items[0].CFOP = "5102"
+	simples.Items[0].DIFAL = Tax{}
+
+	ipi := testDocument()
+	ipi.Items[0].IPI = Tax{Base: 97000, Rate: currency.MustParseRate("0.05"), Amount: 4850}
+	ipi.Payments = []Payment{{Method: PaymentCreditCard, Amount: 101850}}
+
+	tests := []struct {
+		name     string
+		document *Document
+	}{
+		{"interstate with difal", testDocument()},
+		{"simples nacional", simples},
+		{"ipi", ipi},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := test.document.XML()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			signed, err := Sign(data, cert, time.Now())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err = ValidateXML(signed); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !xsdValidation {
+				t.Skip("the XSD validation requires cgo")
+			}
+
+			if err = ValidateXML(data); !errors.Is(err, ErrSchema) {
+				t.Errorf("expected ErrSchema without the signature, got %v", err)
+			}
+			invalid := bytes.Replace(signed, []byte("<indTot>1</indTot>"), []byte("<indTot>2</indTot>"), 1)
+			if err = ValidateXML(invalid); !errors.Is(err, ErrSchema) {
+				t.Errorf("expected ErrSchema, got %v", err)
+			}
+		})
+	}
+}
+
+// testCertificate returns a self-signed certificate standing for an A1 certificate.
+func testCertificate(t *testing.T, notBefore, notAfter time.Time) *Certificate {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "GOSHOP COMERCIO LTDA:00000000000191"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Certificate{Leaf: leaf, Key: key}
+}
@@ -0,0 +1,80 @@
+//go:build cgo
+
+package nfe
+
+import (
+	"embed"
+	"fmt"
+	"github.com/terminalstatic/go-xsd-validate"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// The XSDs of the NF-e 4.00, validated with libxml2. They are written from the leiaute of the schema package
+// published by SEFAZ (PL_009_V4) and declare only the groups the package generates, see leiaute.xsd: they are
+// not the files of that package. The rules SEFAZ checks beyond the schema are in Document.Validate.
+//
+//go:embed schemas/*.xsd
+var schemas embed.FS
+
+const schemaEntry = "nfe.xsd"
+
+// xsdValidation reports whether ValidateXML checks the documents, it requires cgo, see schema_nocgo.go.
+const xsdValidation = true
+
+var (
+	schemaOnce    sync.Once
+	schemaHandler *xsdvalidate.XsdHandler
+	schemaErr     error
+)
+
+// ValidateXML checks a signed NF-e against the XSD of the NF-e 4.00. The violations are wrapped in ErrSchema.
+// The validation uses libxml2 and is only built with cgo.
+func ValidateXML(document []byte) error {
+	schemaOnce.Do(func() {
+		schemaHandler, schemaErr = loadSchema()
+	})
+	if schemaErr != nil {
+		return schemaErr
+	}
+
+	if err := schemaHandler.ValidateMem(document, xsdvalidate.ValidErrDefault); err != nil {
+		return fmt.Errorf("%w: %w", ErrSchema, err)
+	}
+	return nil
+}
+
+// loadSchema compiles the embedded XSDs. libxml2 resolves the includes from the file system, so they are
+// copied to a temporary directory removed once the schema is compiled.
+func loadSchema() (*xsdvalidate.XsdHandler, error) {
+	if err := xsdvalidate.Init(); err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "nfe-schemas")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	files, err := schemas.ReadDir("schemas")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := schemas.ReadFile("schemas/" + file.Name())
+		if err != nil {
+			return nil, err
+		}
+		if err = os.WriteFile(filepath.Join(dir, file.Name()), data, 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	handler, err := xsdvalidate.NewXsdHandlerUrl(filepath.Join(dir, schemaEntry), xsdvalidate.ParsErrDefault)
+	if err != nil {
+		return nil, fmt.Errorf("nfe: load the schemas: %w", err)
+	}
+	return handler, nil
+}
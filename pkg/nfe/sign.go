@@ -0,0 +1,220 @@
+package nfe
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"software.sslmate.com/src/go-pkcs12"
+	"strings"
+	"time"
+)
+
+const (
+	dsigNamespace = "http://www.w3.org/2000/09/xmldsig#"
+	algC14N       = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	algEnveloped  = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	algRSASHA1    = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+	algSHA1       = "http://www.w3.org/2000/09/xmldsig#sha1"
+)
+
+var (
+	ErrInvalidCertificate = errors.New("invalid certificate")
+	ErrCertificateExpired = errors.New("certificate expired")
+	ErrInvalidSignature   = errors.New("invalid signature")
+)
+
+// Certificate is an A1 digital certificate of the ICP-Brasil: the certificate of the issuer and its RSA key.
+type Certificate struct {
+	Leaf *x509.Certificate
+	Key  *rsa.PrivateKey
+}
+
+// LoadCertificate reads an A1 certificate from the PKCS#12 (.pfx) file it is distributed as, protected by
+// the password.
+func LoadCertificate(path, password string) (*Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePKCS12(data, password)
+}
+
+// ParsePKCS12 decodes an A1 certificate from PKCS#12 data, see LoadCertificate.
+func ParsePKCS12(data []byte, password string) (*Certificate, error) {
+	key, leaf, _, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCertificate, err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: the key is not an RSA key", ErrInvalidCertificate)
+	}
+	return newCertificate(leaf, rsaKey)
+}
+
+// ParseCertificate reads an A1 certificate from PEM data holding the certificate and its private key, such
+// as the output of "openssl pkcs12 -in certificate.pfx -nodes".
+func ParseCertificate(data []byte) (*Certificate, error) {
+	cert := new(Certificate)
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			// the first certificate is the one of the issuer, the others are the chain
+			if cert.Leaf == nil {
+				leaf, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrInvalidCertificate, err)
+				}
+				cert.Leaf = leaf
+			}
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidCertificate, err)
+			}
+			rsaKey, ok := key.(*rsa.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("%w: the key is not an RSA key", ErrInvalidCertificate)
+			}
+			cert.Key = rsaKey
+		case "RSA PRIVATE KEY":
+			key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidCertificate, err)
+			}
+			cert.Key = key
+		}
+	}
+
+	return newCertificate(cert.Leaf, cert.Key)
+}
+
+func newCertificate(leaf *x509.Certificate, key *rsa.PrivateKey) (*Certificate, error) {
+	if leaf == nil || key == nil {
+		return nil, fmt.Errorf("%w: a certificate and a private key are required", ErrInvalidCertificate)
+	}
+	if !key.PublicKey.Equal(leaf.PublicKey) {
+		return nil, fmt.Errorf("%w: the private key does not match the certificate", ErrInvalidCertificate)
+	}
+	return &Certificate{Leaf: leaf, Key: key}, nil
+}
+
+// Sign adds the enveloped XML-DSig signature of the infNFe element to the NF-e, as required by SEFAZ:
+// Canonical XML 1.0, RSA-SHA1 and the certificate in the KeyInfo. The signed document is checked against
+// the XSD, see ValidateXML.
+func Sign(document []byte, cert *Certificate, now time.Time) ([]byte, error) {
+	if now.Before(cert.Leaf.NotBefore) || now.After(cert.Leaf.NotAfter) {
+		return nil, ErrCertificateExpired
+	}
+
+	root, err := parse(document)
+	if err != nil {
+		return nil, err
+	}
+	inf := root.find("infNFe")
+	if root.name != "NFe" || inf == nil {
+		return nil, fmt.Errorf("%w: infNFe not found", ErrSchema)
+	}
+	if root.find("Signature") != nil {
+		return nil, fmt.Errorf("%w: the document is already signed", ErrInvalidSignature)
+	}
+
+	digest := sha1.Sum(inf.canonical(""))
+	signedInfo := element("SignedInfo",
+		element("CanonicalizationMethod").attr("Algorithm", algC14N),
+		element("SignatureMethod").attr("Algorithm", algRSASHA1),
+		element("Reference",
+			element("Transforms",
+				element("Transform").attr("Algorithm", algEnveloped),
+				element("Transform").attr("Algorithm", algC14N),
+			),
+			element("DigestMethod").attr("Algorithm", algSHA1),
+			leaf("DigestValue", base64.StdEncoding.EncodeToString(digest[:])),
+		).attr("URI", "#"+inf.attribute("Id")),
+	)
+	signedInfo.ns = dsigNamespace
+	signedInfo.setNS(dsigNamespace)
+
+	hashed := sha1.Sum(signedInfo.canonical(""))
+	signature, err := rsa.SignPKCS1v15(nil, cert.Key, crypto.SHA1, hashed[:])
+	if err != nil {
+		return nil, err
+	}
+
+	sig := element("Signature",
+		signedInfo,
+		leaf("SignatureValue", base64.StdEncoding.EncodeToString(signature)),
+		element("KeyInfo", element("X509Data", leaf("X509Certificate", base64.StdEncoding.EncodeToString(cert.Leaf.Raw)))),
+	)
+	sig.ns = dsigNamespace
+	root.add(sig.setNS(dsigNamespace))
+
+	signed := root.canonical("")
+	if err := ValidateXML(signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// Verify checks the digest and the signature of a signed NF-e with the certificate of its KeyInfo,
+// and returns that certificate. The certificate chain is not verified.
+func Verify(document []byte) (*x509.Certificate, error) {
+	root, err := parse(document)
+	if err != nil {
+		return nil, err
+	}
+	inf, sig := root.find("infNFe"), root.find("Signature")
+	if inf == nil || sig == nil {
+		return nil, fmt.Errorf("%w: infNFe or Signature not found", ErrInvalidSignature)
+	}
+	signedInfo, reference := sig.find("SignedInfo"), sig.find("Reference")
+	digestValue, signatureValue, certificate := sig.find("DigestValue"), sig.find("SignatureValue"), sig.find("X509Certificate")
+	if signedInfo == nil || reference == nil || digestValue == nil || signatureValue == nil || certificate == nil {
+		return nil, fmt.Errorf("%w: incomplete signature", ErrInvalidSignature)
+	}
+	if reference.attribute("URI") != "#"+inf.attribute("Id") {
+		return nil, fmt.Errorf("%w: the reference is not the infNFe", ErrInvalidSignature)
+	}
+
+	// the infNFe does not hold the Signature, so the enveloped signature transform is a no-op
+	digest := sha1.Sum(inf.canonical(""))
+	expected, err := base64.StdEncoding.DecodeString(strings.TrimSpace(digestValue.value()))
+	if err != nil || !bytes.Equal(digest[:], expected) {
+		return nil, fmt.Errorf("%w: digest mismatch", ErrInvalidSignature)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(certificate.value()), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCertificate, err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCertificate, err)
+	}
+	key, ok := leaf.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: the key is not an RSA key", ErrInvalidCertificate)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(signatureValue.value()), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	hashed := sha1.Sum(signedInfo.canonical(""))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA1, hashed[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	return leaf, nil
+}
@@ -0,0 +1,127 @@
+package nfe
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrSchema = errors.New("nfe: schema violation")
+
+// The patterns of the simple types of the NF-e 4.00 schema used by the generated fields.
+var (
+	patternCNPJ = regexp.MustCompile(`^[0-9]{14}$`)
+	patternCPF  = regexp.MustCompile(`^[0-9]{11}$`)
+	patternIE   = regexp.MustCompile(`^(ISENTO|[0-9]{2,14})$`)
+	patternCMun = regexp.MustCompile(`^[0-9]{7}$`)
+	patternCEP  = regexp.MustCompile(`^[0-9]{8}$`)
+	patternNCM  = regexp.MustCompile(`^([0-9]{2}|[0-9]{8})$`)
+	patternCFOP = regexp.MustCompile(`^[1-3,5-7][0-9]{3}$`)
+	patternTPag = regexp.MustCompile(`^[0-9]{2}$`)
+)
+
+// Validate checks the document against the rules of the NF-e 4.00 schema (leiaute 4.00) for the fields
+// it generates: the required fields, their length and patterns, and a few SEFAZ validation rules such as
+// the CFOP of interstate operations. Every violation is reported, wrapped in ErrSchema.
+func (d *Document) Validate() error {
+	var v violations
+
+	v.check(d.Environment == EnvironmentProduction || d.Environment == EnvironmentHomologation, "ide.tpAmb", "must be 1 or 2")
+	v.length("ide.natOp", d.Operation, 1, 60)
+	v.check(d.Series >= 0 && d.Series <= 999, "ide.serie", "must be between 0 and 999")
+	v.check(d.Number >= 1 && d.Number <= 999999999, "ide.nNF", "must be between 1 and 999999999")
+	v.check(d.Code >= 0 && d.Code <= 99999999 && int64(d.Code) != d.Number, "ide.cNF", "must have 8 digits and differ from nNF")
+	v.check(!d.Issued.IsZero(), "ide.dhEmi", "is required")
+	v.length("ide.verProc", d.Software, 1, 20)
+
+	v.match("emit.CNPJ", d.Issuer.CNPJ, patternCNPJ)
+	v.length("emit.xNome", d.Issuer.Name, 2, 60)
+	v.match("emit.IE", d.Issuer.IE, patternIE)
+	v.check(d.Issuer.CRT == CRTSimplesNacional || d.Issuer.CRT == CRTRegimeNormal, "emit.CRT", "must be 1 or 3")
+	v.address("emit.enderEmit", d.Issuer.Address)
+
+	r := d.Recipient
+	switch {
+	case r.CNPJ != "" && r.CPF != "":
+		v.add("dest", "either CPF or CNPJ is allowed")
+	case r.CNPJ != "":
+		v.match("dest.CNPJ", r.CNPJ, patternCNPJ)
+	default:
+		v.match("dest.CPF", r.CPF, patternCPF)
+	}
+	v.length("dest.xNome", r.Name, 2, 60)
+	v.address("dest.enderDest", r.Address)
+	if r.Contributor {
+		v.match("dest.IE", r.IE, patternIE)
+	}
+	if r.Email != "" {
+		v.length("dest.email", r.Email, 1, 60)
+	}
+
+	v.check(len(d.Items) >= 1 && len(d.Items) <= 990, "det", "must have between 1 and 990 items")
+	cfopPrefix := "5"
+	if d.Interstate() {
+		cfopPrefix = "6"
+	}
+	for n, i := range d.Items {
+		field := fmt.Sprintf("det[%d].prod.", n+1)
+		v.length(field+"cProd", i.Code, 1, 60)
+		v.length(field+"xProd", i.Description, 1, 120)
+		v.match(field+"NCM", i.NCM, patternNCM)
+		v.match(field+"CFOP", i.CFOP, patternCFOP)
+		v.check(strings.HasPrefix(i.CFOP, cfopPrefix), field+"CFOP", "must start with "+cfopPrefix+" for the operation destination")
+		v.check(utf8.RuneCountInString(i.Unit) <= 6, field+"uCom", "must have at most 6 characters")
+		v.check(i.Quantity > 0, field+"qCom", "must be positive")
+		v.check(i.UnitPrice >= 0 && i.Discount >= 0 && i.Freight >= 0, field+"vProd", "values must not be negative")
+		v.check(i.Discount <= i.Total(), field+"vDesc", "must not exceed vProd")
+		v.check(i.Origin >= 0 && i.Origin <= 8, field+"orig", "must be between 0 and 8")
+	}
+
+	v.check(len(d.Payments) >= 1 && len(d.Payments) <= 100, "pag.detPag", "must have between 1 and 100 payments")
+	for n, p := range d.Payments {
+		v.match(fmt.Sprintf("pag.detPag[%d].tPag", n+1), p.Method, patternTPag)
+	}
+
+	return v.err()
+}
+
+type violations []string
+
+func (v *violations) add(field, msg string) {
+	*v = append(*v, field+": "+msg)
+}
+
+func (v *violations) check(ok bool, field, msg string) {
+	if !ok {
+		v.add(field, msg)
+	}
+}
+
+func (v *violations) length(field, value string, min, max int) {
+	n := utf8.RuneCountInString(value)
+	v.check(n >= min && n <= max, field, fmt.Sprintf("must have between %d and %d characters", min, max))
+}
+
+func (v *violations) match(field, value string, pattern *regexp.Regexp) {
+	v.check(pattern.MatchString(value), field, "does not match "+pattern.String())
+}
+
+func (v *violations) address(field string, a Address) {
+	v.length(field+".xLgr", a.Street, 2, 60)
+	v.length(field+".nro", a.Number, 1, 60)
+	v.length(field+".xBairro", a.District, 2, 60)
+	v.match(field+".cMun", a.CityCode, patternCMun)
+	v.length(field+".xMun", a.City, 2, 60)
+	v.match(field+".CEP", a.ZipCode, patternCEP)
+	_, ok := StateCode(a.State)
+	v.check(ok, field+".UF", "unknown state")
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrSchema, strings.Join(v, "; "))
+}
@@ -0,0 +1,222 @@
+package nfe
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+)
+
+// node is an XML element. It is serialized in the Canonical XML 1.0 form required by the signature:
+// no XML declaration, no empty-element tags, sorted attributes and the namespace declared where it changes.
+type node struct {
+	name     string
+	ns       string
+	attrs    []xml.Attr
+	children []*node
+	text     string // text nodes have no name
+}
+
+func element(name string, children ...*node) *node {
+	return &node{name: name, children: children}
+}
+
+// leaf returns an element holding a text.
+func leaf(name, text string) *node {
+	return &node{name: name, children: []*node{{text: text}}}
+}
+
+// optional returns the leaf, or nil when the text is empty.
+func optional(name, text string) *node {
+	if text == "" {
+		return nil
+	}
+	return leaf(name, text)
+}
+
+func (n *node) attr(name, value string) *node {
+	n.attrs = append(n.attrs, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+	return n
+}
+
+func (n *node) add(children ...*node) *node {
+	n.children = append(n.children, children...)
+	return n
+}
+
+// find returns the first descendant element with the name, depth first.
+func (n *node) find(name string) *node {
+	for _, c := range n.children {
+		if c == nil {
+			continue
+		}
+		if c.name == name {
+			return c
+		}
+		if found := c.find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// attribute returns the value of the attribute, empty when missing.
+func (n *node) attribute(name string) string {
+	for _, a := range n.attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// value returns the text of the element.
+func (n *node) value() string {
+	var sb strings.Builder
+	for _, c := range n.children {
+		if c != nil && c.name == "" {
+			sb.WriteString(c.text)
+		}
+	}
+	return sb.String()
+}
+
+// canonical serializes the element, parentNS being the default namespace in scope.
+func (n *node) canonical(parentNS string) []byte {
+	var buf bytes.Buffer
+	n.write(&buf, parentNS)
+	return buf.Bytes()
+}
+
+func (n *node) write(buf *bytes.Buffer, parentNS string) {
+	if n.name == "" {
+		escapeText(buf, n.text)
+		return
+	}
+
+	buf.WriteByte('<')
+	buf.WriteString(n.name)
+	if n.ns != parentNS {
+		buf.WriteString(` xmlns="`)
+		escapeAttr(buf, n.ns)
+		buf.WriteByte('"')
+	}
+	attrs := slices.Clone(n.attrs)
+	slices.SortFunc(attrs, func(a, b xml.Attr) int { return strings.Compare(a.Name.Local, b.Name.Local) })
+	for _, a := range attrs {
+		buf.WriteByte(' ')
+		buf.WriteString(a.Name.Local)
+		buf.WriteString(`="`)
+		escapeAttr(buf, a.Value)
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('>')
+	for _, c := range n.children {
+		if c != nil {
+			c.write(buf, n.ns)
+		}
+	}
+	buf.WriteString("</")
+	buf.WriteString(n.name)
+	buf.WriteByte('>')
+}
+
+// setNS sets the namespace of the element and of its descendants without one.
+func (n *node) setNS(ns string) *node {
+	if n.name == "" {
+		return n
+	}
+	if n.ns == "" {
+		n.ns = ns
+	}
+	for _, c := range n.children {
+		if c != nil {
+			c.setNS(n.ns)
+		}
+	}
+	return n
+}
+
+func escapeText(buf *bytes.Buffer, s string) {
+	for _, r := range s {
+		switch r {
+		case '&':
+			buf.WriteString("&amp;")
+		case '<':
+			buf.WriteString("&lt;")
+		case '>':
+			buf.WriteString("&gt;")
+		case '\r':
+			buf.WriteString("&#xD;")
+		default:
+			buf.WriteRune(r)
+		}
+	}
+}
+
+func escapeAttr(buf *bytes.Buffer, s string) {
+	for _, r := range s {
+		switch r {
+		case '&':
+			buf.WriteString("&amp;")
+		case '<':
+			buf.WriteString("&lt;")
+		case '"':
+			buf.WriteString("&quot;")
+		case '\t':
+			buf.WriteString("&#x9;")
+		case '\n':
+			buf.WriteString("&#xA;")
+		case '\r':
+			buf.WriteString("&#xD;")
+		default:
+			buf.WriteRune(r)
+		}
+	}
+}
+
+// parse reads an XML document into nodes. Only the default namespaces are supported, as in the NF-e.
+func parse(data []byte) (*node, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var root *node
+	var stack []*node
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name.Local, ns: t.Name.Space}
+			for _, a := range t.Attr {
+				if a.Name.Space == "" && a.Name.Local != "xmlns" {
+					n.attrs = append(n.attrs, xml.Attr{Name: xml.Name{Local: a.Name.Local}, Value: a.Value})
+				}
+			}
+			if len(stack) == 0 {
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, &node{text: string(t)})
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("nfe: empty document")
+	}
+	return root, nil
+}
@@ -0,0 +1,157 @@
+// Package pix generates the Pix BR Code, the EMV payload encoded in the Pix QR code and pasted by the
+// customers as "Pix copia e cola", following the Banco Central do Brasil specification.
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+const (
+	gui               = "br.gov.bcb.pix"
+	maxMerchantName   = 25
+	maxMerchantCity   = 15
+	maxTxID           = 25
+	maxFieldLength    = 99
+	defaultTxID       = "***"
+	currencyCodeBRL   = "986"
+	countryCodeBrazil = "BR"
+)
+
+var (
+	ErrMissingKey          = errors.New("pix: missing key")
+	ErrMissingMerchantName = errors.New("pix: missing merchant name")
+	ErrMissingMerchantCity = errors.New("pix: missing merchant city")
+	ErrInvalidTxID         = errors.New("pix: invalid transaction id")
+	ErrInvalidAmount       = errors.New("pix: invalid amount")
+	ErrFieldTooLong        = errors.New("pix: field too long")
+)
+
+// BRCode holds the fields of a Pix payment request.
+type BRCode struct {
+	// Key is the Pix key receiving the payment: a CPF, CNPJ, e-mail, phone or random key.
+	Key string
+	// Description is an optional message shown to the payer.
+	Description  string
+	MerchantName string
+	MerchantCity string
+	// Amount is optional, the payer chooses the amount when it is zero.
+	Amount currency.BRL
+	// TxID identifies the payment in the receiver bank statement, up to 25 letters and digits.
+	TxID string
+	// Unique marks the code as valid for a single payment.
+	Unique bool
+}
+
+// Payload returns the EMV "copia e cola" string, terminated by its CRC16 checksum.
+// The merchant name and city are stripped of accents and truncated to their maximum length.
+func (c BRCode) Payload() (string, error) {
+	if c.Key == "" {
+		return "", ErrMissingKey
+	}
+	name := sanitize(c.MerchantName, maxMerchantName)
+	if name == "" {
+		return "", ErrMissingMerchantName
+	}
+	city := sanitize(c.MerchantCity, maxMerchantCity)
+	if city == "" {
+		return "", ErrMissingMerchantCity
+	}
+	if c.Amount < 0 {
+		return "", ErrInvalidAmount
+	}
+	txID := c.TxID
+	if txID == "" {
+		txID = defaultTxID
+	} else if len(txID) > maxTxID || strings.IndexFunc(txID, isNotAlphanumeric) >= 0 {
+		return "", ErrInvalidTxID
+	}
+
+	account := field("00", gui) + field("01", c.Key)
+	if c.Description != "" {
+		account += field("02", c.Description)
+	}
+	if len(account) > maxFieldLength {
+		return "", ErrFieldTooLong
+	}
+
+	var sb strings.Builder
+	sb.WriteString(field("00", "01"))
+	if c.Unique {
+		sb.WriteString(field("01", "12"))
+	}
+	sb.WriteString(field("26", account))
+	sb.WriteString(field("52", "0000"))
+	sb.WriteString(field("53", currencyCodeBRL))
+	if c.Amount > 0 {
+		sb.WriteString(field("54", c.Amount.String()))
+	}
+	sb.WriteString(field("58", countryCodeBrazil))
+	sb.WriteString(field("59", name))
+	sb.WriteString(field("60", city))
+	sb.WriteString(field("62", field("05", txID)))
+
+	// The checksum covers the payload including the CRC field id and length
+	payload := sb.String() + "6304"
+	return payload + fmt.Sprintf("%04X", CRC16(payload)), nil
+}
+
+// QRCode renders the payload as a PNG QR code of size by size pixels.
+func (c BRCode) QRCode(size int) ([]byte, error) {
+	payload, err := c.Payload()
+	if err != nil {
+		return nil, err
+	}
+	return QRCode(payload, size)
+}
+
+// QRCode renders an existing payload as a PNG QR code of size by size pixels.
+func QRCode(payload string, size int) ([]byte, error) {
+	return qrcode.Encode(payload, qrcode.Medium, size)
+}
+
+// CRC16 computes the CRC-16/CCITT-FALSE checksum (polynomial 0x1021, initial value 0xFFFF) used by the BR Code.
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// field encodes an EMV TLV field, its id followed by the two digit length of the value.
+func field(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// sanitize removes the accents and non-printable characters and truncates the value to max bytes.
+func sanitize(value string, max int) string {
+	var sb strings.Builder
+	for _, r := range norm.NFD.String(strings.TrimSpace(value)) {
+		if unicode.Is(unicode.Mn, r) || r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	s := sb.String()
+	if len(s) > max {
+		s = strings.TrimSpace(s[:max])
+	}
+	return s
+}
+
+func isNotAlphanumeric(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+}
@@ -0,0 +1,87 @@
+package pix
+
+import (
+	"bytes"
+	"errors"
+	"github.com/HBeserra/GoShop/pkg/currency"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	if got := CRC16("123456789"); got != 0x29B1 {
+		t.Errorf("CRC16() = %04X, want 29B1", got)
+	}
+}
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    BRCode
+		want    string
+		wantErr error
+	}{
+		{
+			// Example of the Banco Central BR Code manual
+			name: "static code without amount",
+			code: BRCode{
+				Key:          "123e4567-e12b-12d1-a456-426655440000",
+				MerchantName: "Fulano de Tal",
+				MerchantCity: "BRASILIA",
+			},
+			want: "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		},
+		{
+			name: "amount, transaction id and accents",
+			code: BRCode{
+				Key:          "pix@goshop.com.br",
+				MerchantName: "Loja São João",
+				MerchantCity: "São Paulo",
+				Amount:       currency.NewFromFloat(123.45),
+				TxID:         "PEDIDO123",
+				Unique:       true,
+			},
+			want: "00020101021226390014br.gov.bcb.pix0117pix@goshop.com.br5204000053039865406123.45" +
+				"5802BR5913Loja Sao Joao6009Sao Paulo62130509PEDIDO1236304FA06",
+		},
+		{
+			name:    "missing key",
+			code:    BRCode{MerchantName: "Loja", MerchantCity: "Recife"},
+			wantErr: ErrMissingKey,
+		},
+		{
+			name:    "invalid transaction id",
+			code:    BRCode{Key: "key", MerchantName: "Loja", MerchantCity: "Recife", TxID: "pedido-1"},
+			wantErr: ErrInvalidTxID,
+		},
+		{
+			name:    "negative amount",
+			code:    BRCode{Key: "key", MerchantName: "Loja", MerchantCity: "Recife", Amount: -1},
+			wantErr: ErrInvalidAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.code.Payload()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Payload() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Payload() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQRCode(t *testing.T) {
+	png, err := BRCode{Key: "key", MerchantName: "Loja", MerchantCity: "Recife", Amount: 1000}.QRCode(256)
+	if err != nil {
+		t.Fatalf("QRCode() error = %v", err)
+	}
+	if !bytes.HasPrefix(png, []byte("\x89PNG")) {
+		t.Errorf("QRCode() is not a PNG")
+	}
+}